@@ -1,16 +1,20 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
-	"log"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	pasapi "github.com/infamousjoeg/cybr-cli/pkg/cybr/api"
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/queries"
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/requests"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/responses"
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/shared"
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/prettyprint"
-	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/util"
 	"github.com/spf13/cobra"
 )
 
@@ -80,6 +84,51 @@ var (
 
 	// NewPassword to set on account
 	NewPassword string
+
+	// ImportFile containing the accounts to import
+	ImportFile string
+
+	// ReportFile the import results are written to
+	ReportFile string
+
+	// DryRun validates without making changes
+	DryRun bool
+
+	// Concurrency is the number of requests sent at the same time
+	Concurrency int
+
+	// SafeFrom is the safe whose accounts are moved
+	SafeFrom string
+
+	// LinkType is the type of a linked account, e.g. logon or reconcile
+	LinkType string
+
+	// LinkSafe is the safe of a linked account
+	LinkSafe string
+
+	// LinkName is the name of a linked account
+	LinkName string
+
+	// LinkFolder is the folder of a linked account
+	LinkFolder string
+
+	// Links are the accounts linked to an account when it is added, e.g. logon=SafeName/AccountName
+	Links []string
+
+	// SetProperties are the account properties set by an update, e.g. platformAccountProperties.Port=2222
+	SetProperties []string
+
+	// RemoveProperties are the account properties removed by an update
+	RemoveProperties []string
+
+	// PatchFile is a file of JSON Patch operations applied to an account
+	PatchFile string
+
+	// ShowTemporary includes the temporary versions of a secret
+	ShowTemporary bool
+
+	// ListVersions lists the versions of a secret instead of retrieving it
+	ListVersions bool
 )
 
 var accountsCmd = &cobra.Command{
@@ -99,11 +148,12 @@ var listAccountsCmd = &cobra.Command{
 	Long: `List all accounts the logged on user can read from PAS REST API.
 	
 	Example Usage:
-	$ cybr accounts list`,
+	$ cybr accounts list
+	$ cybr accounts list --all`,
 	Run: func(cmd *cobra.Command, args []string) {
-		client, err := pasapi.GetConfigWithLogger(getLogger())
+		client, err := getClient()
 		if err != nil {
-			log.Fatalf("Failed to read configuration file. %s", err)
+			fatalf("Failed to read configuration file. %s", err)
 			return
 		}
 
@@ -116,13 +166,26 @@ var listAccountsCmd = &cobra.Command{
 			Filter:     Filter,
 		}
 
+		if All {
+			// Request the largest page size unless a limit was provided
+			if !cmd.Flags().Changed("limit") {
+				query.Limit = 1000
+			}
+			accounts := client.IterateAccounts(query)
+			err = printAll(accounts, func() interface{} { return accounts.Account() }, "value", "count", prettyprint.AccountsTable)
+			if err != nil {
+				fatalf("Failed to retrieve a list of all accounts. %s", err)
+			}
+			return
+		}
+
 		apps, err := client.ListAccounts(query)
 		if err != nil {
-			log.Fatalf("Failed to retrieve a list of all accounts. %s", err)
+			fatalf("Failed to retrieve a list of all accounts. %s", err)
 			return
 		}
 
-		prettyprint.PrintJSON(apps)
+		printOutput(apps, prettyprint.AccountsTable)
 	},
 }
 
@@ -134,19 +197,36 @@ var getAccountsCmd = &cobra.Command{
 	Example Usage:
 	$ cybr accounts get -i 24_1`,
 	Run: func(cmd *cobra.Command, args []string) {
-		client, err := pasapi.GetConfigWithLogger(getLogger())
+		client, err := getClient()
 		if err != nil {
-			log.Fatalf("Failed to read configuration file. %s", err)
+			fatalf("Failed to read configuration file. %s", err)
 			return
 		}
 
 		apps, err := client.GetAccount(AccountID)
 		if err != nil {
-			log.Fatalf("Failed to retrieve account '%s'. %s", AccountID, err)
+			fatalf("Failed to retrieve account '%s'. %s", AccountID, err)
 			return
 		}
 
-		prettyprint.PrintJSON(apps)
+		// The account is printed without its linked accounts when they cannot be listed, e.g.
+		// because the PAS version does not support it or the user is not authorized
+		linkedAccounts, err := client.ListLinkedAccounts(AccountID)
+		if apiError, ok := pasapi.AsAPIError(err); err != nil && !(ok && apiError.StatusCode == 404) {
+			fmt.Fprintf(os.Stderr, "Failed to retrieve linked accounts of account '%s'. %s\n", AccountID, err)
+		}
+		if err == nil {
+			apps.LinkedAccounts = linkedAccounts.LinkedAccounts
+		}
+
+		printOutput(apps, prettyprint.AccountTable)
+
+		// The table formats print the linked accounts in a separate table
+		format := getPrinter().Format
+		if len(apps.LinkedAccounts) > 0 && (format == prettyprint.FormatTable || format == prettyprint.FormatWide) {
+			fmt.Println()
+			printOutput(linkedAccounts, prettyprint.LinkedAccountsTable)
+		}
 	},
 }
 
@@ -155,18 +235,47 @@ var addAccountsCmd = &cobra.Command{
 	Short: "Add an account",
 	Long: `Add an account to PAS.
 	
+	The secret is read from a file with --secret @path, from stdin with --secret - or from an
+	environment variable with --secret env:VAR. It is prompted for when --secret is not given.
+
+	Accounts are linked to the account added with --link type=SafeName/AccountName, where the type is
+	logon, enable, reconcile or extra1 to extra3.
+
 	Example Usage:
-	$ cybr accounts add -s SafeName -p platformID -u username -a 10.0.0.1 -t password -c SuperSecret`,
+	$ cybr accounts add -s SafeName -p platformID -u username -a 10.0.0.1 -t password
+	$ cybr accounts add -s SafeName -p platformID -u username -a 10.0.0.1 -t password -c env:ACCOUNT_PASSWORD
+	$ cybr accounts add -s SafeName -p UnixSSH -u root -a 10.0.0.1 -t key -c @$HOME/.ssh/id_rsa
+	$ cybr accounts add -s SafeName -p UnixSSH -u root -a 10.0.0.1 -t password -c SuperSecret --link logon=SafeName/logon-user --link reconcile=SafeName/reconcile-user`,
 	Run: func(cmd *cobra.Command, args []string) {
-		client, err := pasapi.GetConfigWithLogger(getLogger())
+		client, err := getClient()
 		if err != nil {
-			log.Fatalf("Failed to read configuration file. %s", err)
+			fatalf("Failed to read configuration file. %s", err)
 			return
 		}
 
 		platformProps, err := keyValueStringToMap(PlatformProperties)
 		if err != nil {
-			log.Fatalf("Failed to parse platform properties. %s", err)
+			fatalf("Failed to parse platform properties. %s", err)
+		}
+
+		// Accounts may be added without a secret, e.g. for the CPM to set it
+		secret := ""
+		if cmd.Flags().Changed("secret") {
+			secret, err = readSecret("secret", Secret)
+			if err != nil {
+				fatalf("%s", err)
+				return
+			}
+		}
+
+		links := []requests.LinkAccount{}
+		for _, link := range Links {
+			linkAccount, err := parseLink(link)
+			if err != nil {
+				fatalf("%s", err)
+				return
+			}
+			links = append(links, linkAccount)
 		}
 
 		newAccount := requests.AddAccount{
@@ -176,7 +285,7 @@ var addAccountsCmd = &cobra.Command{
 			PlatformID: PlatformID,
 			SafeName:   Safe,
 			SecretType: SecretType,
-			Secret:     Secret,
+			Secret:     secret,
 			SecretManagement: shared.SecretManagement{
 				AutomaticManagementEnabled: AutomaticManagementEnabled,
 				ManualManagementReason:     ManualManagementReason,
@@ -186,14 +295,279 @@ var addAccountsCmd = &cobra.Command{
 
 		apps, err := client.AddAccount(newAccount)
 		if err != nil {
-			log.Fatalf("Failed to add account. %s", err)
+			fatalf("Failed to add account. %s", err)
+			return
+		}
+
+		for _, link := range links {
+			err = client.LinkAccount(apps.ID, link)
+			if err != nil {
+				fatalf("Account '%s' was added but not linked. %s", apps.ID, err)
+				return
+			}
+		}
+
+		printOutput(apps, prettyprint.AccountsTable)
+	},
+}
+
+var importAccountsCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Add accounts from a CSV, JSON or YAML file",
+	Long: `Add accounts from a CSV, JSON or YAML file to PAS.
+
+	CSV columns are matched to the account fields name, address, userName, platformId,
+	safeName, secretType, secret, automaticManagementEnabled and manualManagementReason.
+	Any other column is a platform account property. JSON and YAML files contain a
+	list of accounts using the same field names.
+
+	Every row is validated against its platform before being added. The result of each row
+	is written to a report in the same format, by default next to the import file. Import the
+	report again to retry the rows that were not created.
+	
+	Example Usage:
+	$ cybr accounts import -f accounts.csv --dry-run
+	$ cybr accounts import -f accounts.csv --concurrency 8
+	$ cybr accounts import -f accounts.results.csv`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		rows, err := pasapi.ReadAccountImportFile(ImportFile)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		reportFile := ReportFile
+		if reportFile == "" {
+			reportFile = getReportFile(ImportFile)
+		}
+
+		results := client.ImportAccounts(rows, pasapi.AccountImportOptions{
+			Concurrency: Concurrency,
+			DryRun:      DryRun,
+			Progress: func(row pasapi.AccountImportRow) {
+				message := fmt.Sprintf("Row %d %s", row.Row, row.Status)
+				if row.Error != "" {
+					message += ". " + row.Error
+				}
+				fmt.Fprintln(os.Stderr, message)
+			},
+		})
+
+		err = pasapi.WriteAccountImportReport(reportFile, results)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+		printOutput(results, prettyprint.AccountImportTable)
+
+		failed := 0
+		for _, row := range results {
+			if row.Status == pasapi.ImportStatusFailed || row.Status == pasapi.ImportStatusInvalid {
+				failed++
+			}
+		}
+		if failed > 0 {
+			fatalf("%d of %d rows failed. Import '%s' again to retry them", failed, len(results), reportFile)
+			return
+		}
+		fmt.Fprintf(os.Stderr, "Successfully processed %d rows. Results written to '%s'\n", len(results), reportFile)
+	},
+}
+
+// getReportFile returns the path of the results report of an import file, e.g.
+// accounts.results.csv for accounts.csv. A report is overwritten when imported again
+func getReportFile(importFile string) string {
+	ext := filepath.Ext(importFile)
+	base := strings.TrimSuffix(importFile, ext)
+	if strings.HasSuffix(base, ".results") {
+		return importFile
+	}
+	return base + ".results" + ext
+}
+
+var updateAccountCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Update an account",
+	Long: `Update the properties of an account. Properties are given as paths of the account separated
+	by dots with --set and --remove, or as JSON Patch (RFC 6902) operations in the JSON file given
+	with --patch. Only the properties that differ from those of the account are changed.
+
+	Values of --set are strings, except true and false and JSON objects or arrays.
+
+	Example Usage:
+	$ cybr accounts update -i 24_1 --set address=10.0.0.2 --set platformAccountProperties.Port=2222
+	$ cybr accounts update -i 24_1 --set secretManagement.automaticManagementEnabled=false --set secretManagement.manualManagementReason="Managed by App1"
+	$ cybr accounts update -i 24_1 --remove platformAccountProperties.LogonDomain
+	$ cybr accounts update -i 24_1 --patch patch.json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ops, err := getPatchOps()
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+		if len(ops) == 0 {
+			fatalf("At least one of --set, --remove or --patch is required")
+			return
+		}
+
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		account, err := client.UpdateAccount(AccountID, ops)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		printOutput(account, prettyprint.AccountsTable)
+	},
+}
+
+// getPatchOps returns the operations of the --patch file followed by those of --set and --remove
+func getPatchOps() ([]requests.PatchOp, error) {
+	ops := []requests.PatchOp{}
+	if PatchFile != "" {
+		content, err := ioutil.ReadFile(PatchFile)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read patch file '%s'. %s", PatchFile, err)
+		}
+		err = json.Unmarshal(content, &ops)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse patch file '%s'. Expected a JSON array of operations. %s", PatchFile, err)
+		}
+	}
+
+	for _, property := range SetProperties {
+		parts := strings.SplitN(property, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("Invalid property '%s'. Expected path=value", property)
+		}
+		ops = append(ops, requests.PatchOp{Op: "replace", Path: propertyPointer(parts[0]), Value: propertyValue(parts[1])})
+	}
+	for _, property := range RemoveProperties {
+		ops = append(ops, requests.PatchOp{Op: "remove", Path: propertyPointer(property)})
+	}
+	return ops, nil
+}
+
+// propertyPointer returns the JSON Pointer of a property path separated by dots. Paths starting
+// with '/' are already pointers
+func propertyPointer(path string) string {
+	if strings.HasPrefix(path, "/") {
+		return path
+	}
+	tokens := strings.Split(path, ".")
+	for i, token := range tokens {
+		tokens[i] = strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+	}
+	return "/" + strings.Join(tokens, "/")
+}
+
+// propertyValue returns the value of a --set property. true, false and JSON objects and arrays
+// are decoded and every other value is a string
+func propertyValue(value string) interface{} {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "true" || trimmed == "false" || strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		var decoded interface{}
+		if json.Unmarshal([]byte(trimmed), &decoded) == nil {
+			return decoded
+		}
+	}
+	return value
+}
+
+var listAccountActivitiesCmd = &cobra.Command{
+	Use:   "activities",
+	Short: "List the activities of an account",
+	Long: `List the actions performed on an account, such as retrieving, changing or verifying its secret.
+
+	Example Usage:
+	$ cybr accounts activities -i 24_1
+	$ cybr accounts activities -i 24_1 --from 7d --output table`,
+	Run: func(cmd *cobra.Command, args []string) {
+		fromTime, toTime, err := parseTimeRange()
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		activities, err := client.ListAccountActivities(AccountID)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		selected := activities.Activities[:0]
+		for _, activity := range activities.Activities {
+			if inTimeRange(activity.Date, fromTime, toTime) {
+				selected = append(selected, activity)
+			}
+		}
+		activities.Activities = selected
+
+		printOutput(activities, prettyprint.AccountActivitiesTable)
+	},
+}
+
+var listSecretVersionsCmd = &cobra.Command{
+	Use:   "versions",
+	Short: "List the versions of the secret of an account",
+	Long: `List the versions of the secret of an account. A version is retrieved with get-password --version.
+
+	Example Usage:
+	$ cybr accounts versions -i 24_1
+	$ cybr accounts versions -i 24_1 --show-temporary --from 2021-01-01 --output table`,
+	Run: func(cmd *cobra.Command, args []string) {
+		fromTime, toTime, err := parseTimeRange()
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
 			return
 		}
 
-		prettyprint.PrintJSON(apps)
+		versions, err := client.ListSecretVersions(AccountID, &queries.ListSecretVersions{ShowTemporary: ShowTemporary})
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		selected := versions.Versions[:0]
+		for _, version := range versions.Versions {
+			if inTimeRange(version.ModificationDate, fromTime, toTime) {
+				selected = append(selected, version)
+			}
+		}
+		versions.Versions = selected
+
+		printOutput(versions, prettyprint.SecretVersionsTable)
 	},
 }
 
+// inTimeRange returns true if the unix time is between from and to. A zero from or to is not a bound
+func inTimeRange(time int, from int, to int) bool {
+	return (from == 0 || time >= from) && (to == 0 || time <= to)
+}
+
 var deleteAccountsCmd = &cobra.Command{
 	Use:   "delete",
 	Short: "Delete a specific account",
@@ -202,15 +576,15 @@ var deleteAccountsCmd = &cobra.Command{
 	Example Usage:
 	$ cybr accounts delete -i 24_1`,
 	Run: func(cmd *cobra.Command, args []string) {
-		client, err := pasapi.GetConfigWithLogger(getLogger())
+		client, err := getClient()
 		if err != nil {
-			log.Fatalf("Failed to read configuration file. %s", err)
+			fatalf("Failed to read configuration file. %s", err)
 			return
 		}
 
 		err = client.DeleteAccount(AccountID)
 		if err != nil {
-			log.Fatalf("Failed to delete account '%s'. %s", AccountID, err)
+			fatalf("Failed to delete account '%s'. %s", AccountID, err)
 			return
 		}
 
@@ -222,16 +596,57 @@ var getPasswordAccountCmd = &cobra.Command{
 	Use:   "get-password",
 	Short: "Get password of a specific account",
 	Long: `This method enables users to retrieve the password or SSH key of an existing account that is identified by its Account ID. It enables users to specify a reason and ticket ID, if required.
+
+	For accounts protected by dual control, --wait-for-approval requests access to the account,
+	or reuses an open access request, and waits until it is confirmed before retrieving the password.
+	
+	Previous versions of the password are retrieved with --version. --list-versions lists the
+	versions that can be retrieved instead of retrieving the password.
 	
 	Example Usage:
-	$ cybr accounts get-password -i 24_1`,
+	$ cybr accounts get-password -i 24_1
+	$ cybr accounts get-password -i 24_1 --list-versions
+	$ cybr accounts get-password -i 24_1 --version 3
+	$ cybr accounts get-password -i 24_1 --reason "Maintenance" --wait-for-approval --wait-timeout 30m`,
 	Run: func(cmd *cobra.Command, args []string) {
-		client, err := pasapi.GetConfigWithLogger(getLogger())
+		client, err := getClient()
 		if err != nil {
-			log.Fatalf("Failed to read configuration file. %s", err)
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		if ListVersions {
+			versions, err := client.ListSecretVersions(AccountID, nil)
+			if err != nil {
+				fatalf("%s", err)
+				return
+			}
+			printOutput(versions, prettyprint.SecretVersionsTable)
 			return
 		}
 
+		if WaitForApproval {
+			status := ""
+			_, err = client.WaitForAccountAccess(requests.CreateAccessRequest{
+				AccountID:           AccountID,
+				Reason:              Reason,
+				TicketingSystemName: TicketingSystemName,
+				TicketID:            TicketID,
+			}, pasapi.WaitForAccessOptions{
+				Timeout: WaitTimeout,
+				Progress: func(request *responses.AccessRequest) {
+					if request.StatusTitle != status {
+						status = request.StatusTitle
+						fmt.Fprintf(os.Stderr, "Access request '%s': %s\n", request.RequestID, status)
+					}
+				},
+			})
+			if err != nil {
+				fatalf("%s", err)
+				return
+			}
+		}
+
 		request := requests.GetAccountPassword{
 			Reason:              Reason,
 			TicketingSystemName: TicketingSystemName,
@@ -240,8 +655,12 @@ var getPasswordAccountCmd = &cobra.Command{
 		}
 
 		response, err := client.GetAccountPassword(AccountID, request)
+		if err != nil && Version != 0 {
+			fatalf("%s. Use --list-versions to list the versions of the password", err)
+			return
+		}
 		if err != nil {
-			log.Fatalf("%s", err)
+			fatalf("%s", err)
 			return
 		}
 
@@ -257,15 +676,15 @@ var verifyAccountCmd = &cobra.Command{
 	Example Usage:
 	$ cybr accounts verify -i 24_1`,
 	Run: func(cmd *cobra.Command, args []string) {
-		client, err := pasapi.GetConfigWithLogger(getLogger())
+		client, err := getClient()
 		if err != nil {
-			log.Fatalf("Failed to read configuration file. %s", err)
+			fatalf("Failed to read configuration file. %s", err)
 			return
 		}
 
 		err = client.VerifyAccountCredentials(AccountID)
 		if err != nil {
-			log.Fatalf("%s", err)
+			fatalf("%s", err)
 			return
 		}
 
@@ -287,22 +706,22 @@ var changeAccountCmd = &cobra.Command{
 	$ cybr accounts change -i 24_1 -s set -p $(openssl rand -base64 12)
 	+ Change password in Vault only:
 	$ cybr accounts change -i 24_1 -s vault
-	$ cybr accounts change -i 24_1 -s vault -p $(openssl rand -base64 12)`,
+	$ cybr accounts change -i 24_1 -s vault -p $(openssl rand -base64 12)
+	+ Read the new password from a file, stdin or an environment variable:
+	$ cybr accounts change -i 24_1 -s set -p @new-password.txt
+	$ openssl rand -base64 12 | cybr accounts change -i 24_1 -s set -p -
+	$ cybr accounts change -i 24_1 -s vault -p env:NEW_PASSWORD`,
 	Run: func(cmd *cobra.Command, args []string) {
-		client, err := pasapi.GetConfigWithLogger(getLogger())
+		client, err := getClient()
 		if err != nil {
-			log.Fatalf("Failed to read configuration file. %s", err)
+			fatalf("Failed to read configuration file. %s", err)
 			return
 		}
 
-		if NewPassword == "" && (strings.ToLower(Scope) == "set" || strings.ToLower(Scope) == "vault") {
-			NewPassword, err = util.ReadPassword()
-			if NewPassword == "" {
-				log.Fatalf("Password cannot be empty")
-				return
-			}
+		if strings.ToLower(Scope) == "set" || strings.ToLower(Scope) == "vault" {
+			NewPassword, err = readSecret("password", NewPassword)
 			if err != nil {
-				log.Fatalf("Failed to read password. %s", err)
+				fatalf("Failed to read password. %s", err)
 				return
 			}
 		}
@@ -316,7 +735,7 @@ var changeAccountCmd = &cobra.Command{
 			err = client.ChangeAccountCredentials(AccountID, ChangeEntireGroup, "vault", NewPassword)
 		}
 		if err != nil {
-			log.Fatalf("%s", err)
+			fatalf("%s", err)
 			return
 		}
 
@@ -332,15 +751,15 @@ var reconcileAccountCmd = &cobra.Command{
 	Example Usage:
 	$ cybr accounts reconcile -i 24_1`,
 	Run: func(cmd *cobra.Command, args []string) {
-		client, err := pasapi.GetConfigWithLogger(getLogger())
+		client, err := getClient()
 		if err != nil {
-			log.Fatalf("Failed to read configuration file. %s", err)
+			fatalf("Failed to read configuration file. %s", err)
 			return
 		}
 
 		err = client.ReconileAccountCredentials(AccountID)
 		if err != nil {
-			log.Fatalf("%s", err)
+			fatalf("%s", err)
 			return
 		}
 
@@ -351,51 +770,68 @@ var reconcileAccountCmd = &cobra.Command{
 var moveAccountCmd = &cobra.Command{
 	Use:   "move",
 	Short: "Move an account to a different safe",
-	Long: `Move an account to a different safe
+	Long: `Move an account to a different safe.
 
+	The account is copied to the safe with its secret, platform properties, remote machine
+	access and linked accounts. The copy is verified before the account is deleted, and is
+	deleted again if any step fails. Running an interrupted move again completes it.
+
+	Use --safe-from to move all accounts of a safe, optionally limited by --search and --filter.
+	
 	Example Usage:
-	$ cybr accounts move -i 24_1 -s newSafeName`,
+	$ cybr accounts move -i 24_1 -s newSafeName
+	$ cybr accounts move --safe-from oldSafeName --search root -s newSafeName`,
 	Run: func(cmd *cobra.Command, args []string) {
-		client, err := pasapi.GetConfigWithLogger(getLogger())
-		if err != nil {
-			log.Fatalf("Failed to read configuration file. %s", err)
+		if (AccountID == "") == (SafeFrom == "") {
+			fatalf("Either --account-id or --safe-from is required")
 			return
 		}
 
-		account, err := client.GetAccount(AccountID)
+		client, err := getClient()
 		if err != nil {
-			log.Fatalf("%s", err)
+			fatalf("Failed to read configuration file. %s", err)
+			return
 		}
 
-		secret, err := client.GetAccountPassword(AccountID, requests.GetAccountPassword{})
-		if err != nil {
-			log.Fatalf("%s", err)
+		options := pasapi.MoveAccountOptions{Reason: Reason, TicketID: TicketID}
+		if AccountID != "" {
+			movedAccount, err := client.MoveAccount(AccountID, Safe, options)
+			if err != nil {
+				fatalf("Failed to move account '%s'. %s", AccountID, err)
+				return
+			}
+			printOutput(movedAccount, prettyprint.AccountsTable)
+			return
 		}
 
-		newAccount := requests.AddAccount{
-			Name:                      account.Name,
-			Address:                   account.Address,
-			UserName:                  account.UserName,
-			PlatformID:                account.PlatformID,
-			SafeName:                  Safe,
-			SecretType:                account.SecretType,
-			Secret:                    secret,
-			PlatformAccountProperties: account.PlatformAccountProperties,
-			SecretManagement:          account.SecretManagement,
+		filter := fmt.Sprintf("safeName eq %s", SafeFrom)
+		if Filter != "" {
+			filter += " AND " + Filter
 		}
-
-		createdAccount, err := client.AddAccount(newAccount)
+		query := &queries.ListAccounts{Search: Search, Filter: filter, Limit: 1000}
+		results, err := client.MoveAccounts(query, Safe, options, func(result pasapi.MoveAccountResult) {
+			message := fmt.Sprintf("Account '%s' %s", result.SourceID, result.Status)
+			if result.Error != "" {
+				message += ". " + result.Error
+			}
+			fmt.Fprintln(os.Stderr, message)
+		})
 		if err != nil {
-			log.Fatalf("%s", err)
+			fatalf("Failed to list accounts of safe '%s'. %s", SafeFrom, err)
+			return
 		}
+		printOutput(results, prettyprint.MoveAccountsTable)
 
-		err = client.DeleteAccount(AccountID)
-		if err != nil {
-			log.Fatalf("%s", err)
+		failed := 0
+		for _, result := range results {
+			if result.Status == pasapi.MoveStatusFailed {
+				failed++
+			}
+		}
+		if failed > 0 {
+			fatalf("%d of %d accounts failed to move", failed, len(results))
 			return
 		}
-
-		prettyprint.PrintJSON(createdAccount)
 	},
 }
 
@@ -407,15 +843,15 @@ var unlockAccountCmd = &cobra.Command{
 	Example Usage:
 	$ cybr accounts unlock -i 24_1`,
 	Run: func(cmd *cobra.Command, args []string) {
-		client, err := pasapi.GetConfigWithLogger(getLogger())
+		client, err := getClient()
 		if err != nil {
-			log.Fatalf("Failed to read configuration file. %s", err)
+			fatalf("Failed to read configuration file. %s", err)
 			return
 		}
 
 		err = client.Unlock(AccountID)
 		if err != nil {
-			log.Fatalf("%s", err)
+			fatalf("%s", err)
 			return
 		}
 
@@ -431,15 +867,15 @@ var checkInAccountCmd = &cobra.Command{
 	Example Usage:
 	$ cybr accounts checkin -i 24_1`,
 	Run: func(cmd *cobra.Command, args []string) {
-		client, err := pasapi.GetConfigWithLogger(getLogger())
+		client, err := getClient()
 		if err != nil {
-			log.Fatalf("Failed to read configuration file. %s", err)
+			fatalf("Failed to read configuration file. %s", err)
 			return
 		}
 
 		err = client.CheckIn(AccountID)
 		if err != nil {
-			log.Fatalf("%s", err)
+			fatalf("%s", err)
 			return
 		}
 
@@ -447,6 +883,96 @@ var checkInAccountCmd = &cobra.Command{
 	},
 }
 
+var linkAccountCmd = &cobra.Command{
+	Use:   "link",
+	Short: "Link an account to an account",
+	Long: `Link a logon, enable, reconcile or extra account to an account. The type is logon, enable,
+	reconcile or extra1 to extra3. An account already linked with the type is replaced.
+
+	Example Usage:
+	$ cybr accounts link -i 24_1 --type logon --link-safe SafeName --link-name logon-user
+	$ cybr accounts link -i 24_1 --type reconcile --link-safe ReconcileSafe --link-name reconcile-user`,
+	Run: func(cmd *cobra.Command, args []string) {
+		index, err := pasapi.GetLinkIndex(LinkType)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		err = client.LinkAccount(AccountID, requests.LinkAccount{
+			Safe:               LinkSafe,
+			ExtraPasswordIndex: index,
+			Name:               LinkName,
+			Folder:             LinkFolder,
+		})
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		fmt.Printf("Successfully linked account '%s' to account '%s' as %s account\n", LinkName, AccountID, LinkType)
+	},
+}
+
+var unlinkAccountCmd = &cobra.Command{
+	Use:   "unlink",
+	Short: "Unlink an account from an account",
+	Long: `Remove the logon, enable, reconcile or extra account linked to an account.
+
+	Example Usage:
+	$ cybr accounts unlink -i 24_1 --type logon`,
+	Run: func(cmd *cobra.Command, args []string) {
+		index, err := pasapi.GetLinkIndex(LinkType)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		err = client.UnlinkAccount(AccountID, index)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		fmt.Printf("Successfully unlinked %s account from account '%s'\n", LinkType, AccountID)
+	},
+}
+
+// parseLink returns the account linked by a --link value of the form type=SafeName/AccountName
+func parseLink(link string) (requests.LinkAccount, error) {
+	parts := strings.SplitN(link, "=", 2)
+	if len(parts) != 2 {
+		return requests.LinkAccount{}, fmt.Errorf("Invalid link '%s'. Expected type=SafeName/AccountName", link)
+	}
+	index, err := pasapi.GetLinkIndex(parts[0])
+	if err != nil {
+		return requests.LinkAccount{}, err
+	}
+	account := strings.SplitN(parts[1], "/", 2)
+	if len(account) != 2 || account[0] == "" || account[1] == "" {
+		return requests.LinkAccount{}, fmt.Errorf("Invalid link '%s'. Expected type=SafeName/AccountName", link)
+	}
+
+	return requests.LinkAccount{
+		Safe:               account[0],
+		ExtraPasswordIndex: index,
+		Name:               account[1],
+		Folder:             "Root",
+	}, nil
+}
+
 func init() {
 	// Listing an account
 	listAccountsCmd.Flags().StringVarP(&Search, "search", "s", "", "List of keywords to search for in accounts, separated by a space")
@@ -455,6 +981,7 @@ func init() {
 	listAccountsCmd.Flags().IntVarP(&Offset, "offset", "o", 0, "Offset of the first account that is returned in the collection of results")
 	listAccountsCmd.Flags().IntVarP(&Limit, "limit", "l", 50, "Maximum number of returned accounts. If not specified, the default value is 50. The maximum number that can be specified is 1000")
 	listAccountsCmd.Flags().StringVarP(&Filter, "filter", "f", "", "Search for accounts filtered by safeName or modificationTime")
+	listAccountsCmd.Flags().BoolVar(&All, "all", false, "Retrieve all pages of accounts starting at offset instead of a single page")
 
 	// Getting an account
 	getAccountsCmd.Flags().StringVarP(&AccountID, "account-id", "i", "", "Account ID to list from")
@@ -468,11 +995,47 @@ func init() {
 	addAccountsCmd.Flags().StringVarP(&Safe, "safe", "s", "", "Safe name of the account object")
 	addAccountsCmd.Flags().StringVarP(&SecretType, "secret-type", "t", "", "Secret type of the account object. e.g. password, accessKey, sshKey")
 	addAccountsCmd.MarkFlagRequired("secret-type")
-	addAccountsCmd.Flags().StringVarP(&Secret, "secret", "c", "", "Secret of the account object")
-	addAccountsCmd.MarkFlagRequired("secret")
+	addAccountsCmd.Flags().StringVarP(&Secret, "secret", "c", "", "Secret of the account object, @file, - for stdin or env:VAR. The account has no secret if not given")
 	addAccountsCmd.Flags().StringVarP(&PlatformProperties, "platform-properties", "e", "", "Extra platform properties. e.g. port=22,UseSudoOnReconcile=yes,CustomField=custom")
 	addAccountsCmd.Flags().BoolVarP(&AutomaticManagementEnabled, "automatic-management", "m", false, "If set will automatically managed the onboarded account")
 	addAccountsCmd.Flags().StringVarP(&ManualManagementReason, "manual-management-reason", "r", "", "The reason the account object is not being managed")
+	addAccountsCmd.Flags().StringArrayVar(&Links, "link", []string{}, "Account linked to the account object. e.g. logon=SafeName/AccountName. Can be given more than once")
+
+	// Updating an account
+	updateAccountCmd.Flags().StringVarP(&AccountID, "account-id", "i", "", "Account ID to update")
+	updateAccountCmd.MarkFlagRequired("account-id")
+	updateAccountCmd.Flags().StringArrayVar(&SetProperties, "set", []string{}, "Property set on the account. e.g. address=10.0.0.2 or platformAccountProperties.Port=2222. Can be given more than once")
+	updateAccountCmd.Flags().StringArrayVar(&RemoveProperties, "remove", []string{}, "Property removed from the account. e.g. platformAccountProperties.LogonDomain. Can be given more than once")
+	updateAccountCmd.Flags().StringVar(&PatchFile, "patch", "", "JSON file of JSON Patch (RFC 6902) operations applied to the account")
+
+	// Listing activities and secret versions
+	for _, command := range []*cobra.Command{listAccountActivitiesCmd, listSecretVersionsCmd} {
+		command.Flags().StringVarP(&AccountID, "account-id", "i", "", "Account ID to list from")
+		command.MarkFlagRequired("account-id")
+		command.Flags().StringVar(&FromTime, "from", "", "Start of the time range, e.g. 2021-01-01 or 7d")
+		command.Flags().StringVar(&ToTime, "to", "", "End of the time range, e.g. 2021-02-01 or 1d")
+	}
+	listSecretVersionsCmd.Flags().BoolVar(&ShowTemporary, "show-temporary", false, "Include the temporary versions of passwords set during a change that did not complete")
+
+	// Linking accounts
+	for _, command := range []*cobra.Command{linkAccountCmd, unlinkAccountCmd} {
+		command.Flags().StringVarP(&AccountID, "account-id", "i", "", "Account ID the account is linked to")
+		command.MarkFlagRequired("account-id")
+		command.Flags().StringVar(&LinkType, "type", "", "Type of the linked account. Valid values: logon, enable, reconcile, extra1, extra2 or extra3")
+		command.MarkFlagRequired("type")
+	}
+	linkAccountCmd.Flags().StringVar(&LinkSafe, "link-safe", "", "Safe of the linked account")
+	linkAccountCmd.MarkFlagRequired("link-safe")
+	linkAccountCmd.Flags().StringVar(&LinkName, "link-name", "", "Name of the linked account")
+	linkAccountCmd.MarkFlagRequired("link-name")
+	linkAccountCmd.Flags().StringVar(&LinkFolder, "link-folder", "Root", "Folder of the linked account")
+
+	// Importing accounts
+	importAccountsCmd.Flags().StringVarP(&ImportFile, "file", "f", "", "CSV, JSON or YAML file containing the accounts to add")
+	importAccountsCmd.MarkFlagRequired("file")
+	importAccountsCmd.Flags().StringVarP(&ReportFile, "report", "r", "", "File the results are written to. Defaults to the import file with a .results extension")
+	importAccountsCmd.Flags().BoolVar(&DryRun, "dry-run", false, "Validate the accounts without adding them")
+	importAccountsCmd.Flags().IntVarP(&Concurrency, "concurrency", "c", 4, "Number of accounts added at the same time")
 
 	// Delete an account
 	deleteAccountsCmd.Flags().StringVarP(&AccountID, "account-id", "i", "", "Account ID to delete")
@@ -482,9 +1045,12 @@ func init() {
 	getPasswordAccountCmd.Flags().StringVarP(&AccountID, "account-id", "i", "", "Account ID to retrieve password value of")
 	getPasswordAccountCmd.MarkFlagRequired("account-id")
 	getPasswordAccountCmd.Flags().IntVarP(&Version, "version", "v", 0, "Version of the account password")
+	getPasswordAccountCmd.Flags().BoolVar(&ListVersions, "list-versions", false, "List the versions of the account password instead of retrieving it")
 	getPasswordAccountCmd.Flags().StringVarP(&Reason, "reason", "r", "", "Reason for retriving account password")
 	getPasswordAccountCmd.Flags().StringVarP(&TicketingSystemName, "ticketing-system", "s", "", "Ticketing system name")
 	getPasswordAccountCmd.Flags().StringVarP(&TicketID, "ticket-id", "t", "", "The ticket ID related to the ticketing system")
+	getPasswordAccountCmd.Flags().BoolVar(&WaitForApproval, "wait-for-approval", false, "Request access to the account and wait until the request is confirmed")
+	getPasswordAccountCmd.Flags().DurationVar(&WaitTimeout, "wait-timeout", time.Hour, "Maximum time to wait for the access request to be confirmed, or 0 to wait until it is confirmed, rejected or expires")
 
 	// verify account
 	verifyAccountCmd.Flags().StringVarP(&AccountID, "account-id", "i", "", "Account ID to verify")
@@ -495,7 +1061,7 @@ func init() {
 	changeAccountCmd.MarkFlagRequired("account-id")
 	changeAccountCmd.Flags().StringVarP(&Scope, "scope", "s", "", "Scope of change. Valid values: Immediate (Default) or Set")
 	changeAccountCmd.Flags().BoolVarP(&ChangeEntireGroup, "change-entire-group", "c", false, "If account is part of account group, change the entire group")
-	changeAccountCmd.Flags().StringVarP(&NewPassword, "password", "p", "", "New password to set on account")
+	changeAccountCmd.Flags().StringVarP(&NewPassword, "password", "p", "", "New password to set on account, @file, - for stdin or env:VAR. Prompted for if not given")
 
 	// reconcile
 	reconcileAccountCmd.Flags().StringVarP(&AccountID, "account-id", "i", "", "Account ID to reconcile")
@@ -503,9 +1069,13 @@ func init() {
 
 	// move
 	moveAccountCmd.Flags().StringVarP(&AccountID, "account-id", "i", "", "Account ID to move")
-	moveAccountCmd.MarkFlagRequired("account-id")
 	moveAccountCmd.Flags().StringVarP(&Safe, "safe", "s", "", "Safe name in which the account will be moved into")
 	moveAccountCmd.MarkFlagRequired("safe")
+	moveAccountCmd.Flags().StringVar(&SafeFrom, "safe-from", "", "Move all accounts of this safe instead of a single account")
+	moveAccountCmd.Flags().StringVar(&Search, "search", "", "List of keywords the accounts moved with --safe-from must match, separated by a space")
+	moveAccountCmd.Flags().StringVarP(&Filter, "filter", "f", "", "Additional filter of the accounts moved with --safe-from, e.g. 'modificationTime gte 1600000000'")
+	moveAccountCmd.Flags().StringVarP(&Reason, "reason", "r", "", "Reason for retrieving the secret of the accounts moved")
+	moveAccountCmd.Flags().StringVarP(&TicketID, "ticket-id", "t", "", "The ticket ID related to the ticketing system")
 
 	// unlock
 	unlockAccountCmd.Flags().StringVarP(&AccountID, "account-id", "i", "", "Account ID to unlock")
@@ -519,6 +1089,8 @@ func init() {
 	accountsCmd.AddCommand(listAccountsCmd)
 	accountsCmd.AddCommand(getAccountsCmd)
 	accountsCmd.AddCommand(addAccountsCmd)
+	accountsCmd.AddCommand(importAccountsCmd)
+	accountsCmd.AddCommand(updateAccountCmd)
 	accountsCmd.AddCommand(deleteAccountsCmd)
 	accountsCmd.AddCommand(getPasswordAccountCmd)
 	accountsCmd.AddCommand(verifyAccountCmd)
@@ -527,6 +1099,10 @@ func init() {
 	accountsCmd.AddCommand(moveAccountCmd)
 	accountsCmd.AddCommand(unlockAccountCmd)
 	accountsCmd.AddCommand(checkInAccountCmd)
+	accountsCmd.AddCommand(linkAccountCmd)
+	accountsCmd.AddCommand(unlinkAccountCmd)
+	accountsCmd.AddCommand(listAccountActivitiesCmd)
+	accountsCmd.AddCommand(listSecretVersionsCmd)
 
 	// Add accounts cmd to root
 	rootCmd.AddCommand(accountsCmd)
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	pasapi "github.com/infamousjoeg/cybr-cli/pkg/cybr/api"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/credstore"
+)
+
+func TestAddAccountWithoutSecret(t *testing.T) {
+	oldEnv := map[string]string{}
+	for _, key := range []string{"HOME", pasapi.ProfileEnvKey, credstore.StoreEnvKey} {
+		oldEnv[key] = os.Getenv(key)
+	}
+	defer func() {
+		for key, value := range oldEnv {
+			os.Setenv(key, value)
+		}
+	}()
+	os.Setenv("HOME", t.TempDir())
+	os.Unsetenv(pasapi.ProfileEnvKey)
+	os.Setenv(credstore.StoreEnvKey, credstore.PlaintextBackend)
+
+	// Without a terminal, reading the secret from stdin would fail
+	stdin, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatalf("Failed to open %s. %s", os.DevNull, err)
+	}
+	defer func(stdin *os.File) { os.Stdin = stdin }(os.Stdin)
+	os.Stdin = stdin
+
+	added := []map[string]interface{}{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/passwordvault/api/Accounts" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		account := map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&account)
+		added = append(added, account)
+		w.Write([]byte(`{"id":"12_1","userName":"root","address":"10.0.0.1","safeName":"Safe1"}`))
+	}))
+	defer server.Close()
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark", SessionToken: "token"}
+	err = client.SetConfig()
+	if err != nil {
+		t.Fatalf("Failed to save configuration. %s", err)
+	}
+
+	rootCmd.SetArgs([]string{"accounts", "add", "-s", "Safe1", "-p", "UnixSSH", "-u", "root", "-a", "10.0.0.1", "-t", "password"})
+	err = rootCmd.Execute()
+	if err != nil {
+		t.Fatalf("Failed to add account. %s", err)
+	}
+
+	if len(added) != 1 || added[0]["userName"] != "root" {
+		t.Fatalf("Expected the account to be added. %v", added)
+	}
+	if secret, ok := added[0]["secret"]; ok && secret != "" {
+		t.Errorf("Expected the account to be added without a secret but got '%v'", secret)
+	}
+}
@@ -2,9 +2,7 @@ package cmd
 
 import (
 	"fmt"
-	"log"
 
-	pasapi "github.com/infamousjoeg/cybr-cli/pkg/cybr/api"
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/requests"
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/prettyprint"
 	"github.com/spf13/cobra"
@@ -66,19 +64,19 @@ var listApplicationsCmd = &cobra.Command{
 	$ cybr applications list`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Get config file written to local file system
-		client, err := pasapi.GetConfigWithLogger(getLogger())
+		client, err := getClient()
 		if err != nil {
-			log.Fatalf("Failed to read configuration file. %s", err)
+			fatalf("Failed to read configuration file. %s", err)
 			return
 		}
 		// List All Safes
 		apps, err := client.ListApplications(Location)
 		if err != nil {
-			log.Fatalf("Failed to retrieve a list of all applications. %s", err)
+			fatalf("Failed to retrieve a list of all applications. %s", err)
 			return
 		}
 		// Pretty print returned object as JSON blob
-		prettyprint.PrintJSON(apps)
+		printOutput(apps, prettyprint.ApplicationsTable)
 	},
 }
 
@@ -92,19 +90,19 @@ var listMethodsCmd = &cobra.Command{
 	$ cybr applications list-authn -a AppID`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Get config file written to local file system
-		client, err := pasapi.GetConfigWithLogger(getLogger())
+		client, err := getClient()
 		if err != nil {
-			log.Fatalf("Failed to read configuration file. %s", err)
+			fatalf("Failed to read configuration file. %s", err)
 			return
 		}
 		// List all Safe Members for specific safe ""
 		methods, err := client.ListApplicationAuthenticationMethods(AppID)
 		if err != nil {
-			log.Fatalf("Failed to retrieve a list of all application methods for %s. %s", Safe, err)
+			fatalf("Failed to retrieve a list of all application methods for %s. %s", Safe, err)
 			return
 		}
 		// Pretty print returned object as JSON blob
-		prettyprint.PrintJSON(methods)
+		printOutput(methods, prettyprint.ApplicationAuthenticationMethodsTable)
 	},
 }
 
@@ -116,9 +114,9 @@ var addApplicationCmd = &cobra.Command{
 	Example Usage:
 	$ cybr applications add -a AppID -l "\\"`,
 	Run: func(cmd *cobra.Command, args []string) {
-		client, err := pasapi.GetConfigWithLogger(getLogger())
+		client, err := getClient()
 		if err != nil {
-			log.Fatalf("Failed to read configuration file. %s", err)
+			fatalf("Failed to read configuration file. %s", err)
 			return
 		}
 
@@ -140,7 +138,7 @@ var addApplicationCmd = &cobra.Command{
 
 		err = client.AddApplication(newApplication)
 		if err != nil {
-			log.Fatalf("Failed to add application '%s'. %s", newApplication.Application.AppID, err)
+			fatalf("Failed to add application '%s'. %s", newApplication.Application.AppID, err)
 			return
 		}
 
@@ -156,15 +154,15 @@ var deleteApplicationCmd = &cobra.Command{
 	Example Usage:
 	$ cybr applications delete -a AppID`,
 	Run: func(cmd *cobra.Command, args []string) {
-		client, err := pasapi.GetConfigWithLogger(getLogger())
+		client, err := getClient()
 		if err != nil {
-			log.Fatalf("Failed to read configuration file. %s", err)
+			fatalf("Failed to read configuration file. %s", err)
 			return
 		}
 
 		err = client.DeleteApplication(AppID)
 		if err != nil {
-			log.Fatalf("Failed to delete application '%s'. %s", AppID, err)
+			fatalf("Failed to delete application '%s'. %s", AppID, err)
 			return
 		}
 
@@ -180,9 +178,9 @@ var addApplicationAuthenticationMethodCmd = &cobra.Command{
 	Example Usage:
 	$ cybr applications add-authn -a AppID -t path -v /some/path`,
 	Run: func(cmd *cobra.Command, args []string) {
-		client, err := pasapi.GetConfigWithLogger(getLogger())
+		client, err := getClient()
 		if err != nil {
-			log.Fatalf("Failed to read configuration file. %s", err)
+			fatalf("Failed to read configuration file. %s", err)
 			return
 		}
 
@@ -197,7 +195,7 @@ var addApplicationAuthenticationMethodCmd = &cobra.Command{
 
 		err = client.AddApplicationAuthenticationMethod(AppID, newAppAuthnMethod)
 		if err != nil {
-			log.Fatalf("Failed to add application authentication method to application '%s'. %s", AppID, err)
+			fatalf("Failed to add application authentication method to application '%s'. %s", AppID, err)
 			return
 		}
 
@@ -213,15 +211,15 @@ var deleteApplicationAuthenticationMethodCmd = &cobra.Command{
 	Example Usage:
 	$ cybr applications delete-authn -a AppID -i 1`,
 	Run: func(cmd *cobra.Command, args []string) {
-		client, err := pasapi.GetConfigWithLogger(getLogger())
+		client, err := getClient()
 		if err != nil {
-			log.Fatalf("Failed to read configuration file. %s", err)
+			fatalf("Failed to read configuration file. %s", err)
 			return
 		}
 
 		err = client.DeleteApplicationAuthenticationMethod(AppID, AppAuthnMethodID)
 		if err != nil {
-			log.Fatalf("Failed to delete application authentication method to application '%s'. %s", AppID, err)
+			fatalf("Failed to delete application authentication method to application '%s'. %s", AppID, err)
 			return
 		}
 
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	pasapi "github.com/infamousjoeg/cybr-cli/pkg/cybr/api"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/prettyprint"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// ManifestFile is the YAML or JSON file describing the desired safes and safe members
+	ManifestFile string
+	// Prune removes safe members that are not in the manifest
+	Prune bool
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show the changes required to converge safes to a manifest",
+	Long: `Compare the safes and safe members described by a YAML or JSON manifest
+	to PAS and show the changes 'cybr apply' would make. The exit code is 2 when
+	there are changes and 0 when the safes match the manifest.
+
+	Use --output to print the plan in another format.
+
+	Example Usage:
+	$ cybr diff -f safes.yaml
+	$ cybr diff -f safes.yaml --prune
+	$ cybr diff -f safes.yaml --output table`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		plan, ok := planManifest(client)
+		if !ok {
+			return
+		}
+
+		if cmd.Flags().Changed("output") {
+			printOutput(plan, prettyprint.SafePlanTable)
+		} else {
+			fmt.Print(plan.String())
+		}
+
+		if plan.HasChanges() {
+			os.Exit(ExitCodeDiff)
+		}
+	},
+}
+
+var applyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Converge safes and safe members to a manifest",
+	Long: `Create and update the safes described by a YAML or JSON manifest and
+	add, update and, with --prune, remove their members. Safes are never deleted.
+	Predefined users and the managing CPM user are never removed from a safe.
+
+	Each member is given the permissions of its role, e.g. EndUser, Auditor or
+	SafeManager, combined with any permissions listed for it.
+
+	Example manifest:
+	version: 1
+	safes:
+	- name: PIN-APP-EXAMPLE
+	  description: Example application accounts
+	  managingCPM: PasswordManager
+	  numberOfDaysRetention: 7
+	  members:
+	  - name: Vault Admins
+	    memberType: Group
+	    role: SafeManager
+	  - name: example-app
+	    role: EndUser
+	    permissions:
+	      RetrieveAccounts: true
+	      UseAccounts: false
+
+	Example Usage:
+	$ cybr apply -f safes.yaml
+	$ cybr apply -f safes.yaml --prune`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		plan, ok := planManifest(client)
+		if !ok {
+			return
+		}
+
+		fmt.Print(plan.String())
+		if !plan.HasChanges() {
+			return
+		}
+
+		err = client.ApplySafePlan(plan, func(change pasapi.SafeChange) {
+			fmt.Fprintf(os.Stderr, "Applied %s\n", change)
+		})
+		if err != nil {
+			fatalf("Failed to apply manifest. %s", err)
+			return
+		}
+		fmt.Printf("Successfully applied %d changes.\n", len(plan.Changes))
+	},
+}
+
+// planManifest reads the manifest file and returns the changes required to converge to it
+func planManifest(client pasapi.Client) (*pasapi.SafePlan, bool) {
+	manifest, err := pasapi.ReadSafeManifest(ManifestFile)
+	if err != nil {
+		fatalf("%s", err)
+		return nil, false
+	}
+
+	plan, err := client.PlanSafes(*manifest, Prune)
+	if err != nil {
+		fatalf("Failed to plan changes. %s", err)
+		return nil, false
+	}
+	return plan, true
+}
+
+func init() {
+	for _, command := range []*cobra.Command{diffCmd, applyCmd} {
+		command.Flags().StringVarP(&ManifestFile, "file", "f", "", "YAML or JSON manifest describing the safes and their members")
+		command.MarkFlagRequired("file")
+		command.Flags().BoolVar(&Prune, "prune", false, "Remove safe members that are not in the manifest")
+		rootCmd.AddCommand(command)
+	}
+}
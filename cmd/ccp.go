@@ -2,11 +2,9 @@ package cmd
 
 import (
 	"fmt"
-	"log"
 	"strings"
 
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/ccp"
-	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/prettyprint"
 	"github.com/spf13/cobra"
 )
 
@@ -54,10 +52,10 @@ var ccpGetAccountCmd = &cobra.Command{
 	$ cybr ccp get-account -b https://ccp.company.local -i AppID -s SafeName -o ObjectName -f Username`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if ClientCert != "" && ClientKey == "" {
-			log.Fatalf("Client certificate was provided with no client private key")
+			fatalf("Client certificate was provided with no client private key")
 		}
 		if ClientKey != "" && ClientCert == "" {
-			log.Fatalf("Client private key was provided with no client certificate")
+			fatalf("Client private key was provided with no client certificate")
 		}
 
 		query := &ccp.RetrieveAccountQuery{
@@ -81,15 +79,16 @@ var ccpGetAccountCmd = &cobra.Command{
 			ClientCert:      ClientCert,
 			ClientKey:       ClientKey,
 			Query:           query,
+			Timeout:         RequestTimeout,
 		}
 
 		account, err := ccp.RetrieveAccount(request)
 		if err != nil {
-			log.Fatalf("%s", err)
+			fatalf("%s", err)
 		}
 
 		if Field == "" {
-			prettyprint.PrintJSON(account)
+			printOutput(account, nil)
 			return
 		}
 
@@ -100,7 +99,7 @@ var ccpGetAccountCmd = &cobra.Command{
 			}
 		}
 
-		log.Fatalf("Failed to parse field '%s' from account returned", Field)
+		fatalf("Failed to parse field '%s' from account returned", Field)
 	},
 }
 
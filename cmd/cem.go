@@ -84,7 +84,7 @@ var cemLoginCmd = &cobra.Command{
 		}
 
 		if apikey == "" {
-			log.Fatalf("Provided API Key is empty")
+			fatalf("Provided API Key is empty")
 		}
 
 		token, err := cem.Login(CemOrganization, apikey)
@@ -112,7 +112,7 @@ var cemGetAccountsCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		token, err := local_cem.GetToken(CemSessionTokenPath)
 		if err != nil {
-			log.Fatalf("Failed to retrieve token file at %s. %s\n", CemSessionTokenPath, err)
+			fatalf("Failed to retrieve token file at %s. %s\n", CemSessionTokenPath, err)
 		}
 
 		cemResult, err := cem.GetAccounts(token)
@@ -121,7 +121,7 @@ var cemGetAccountsCmd = &cobra.Command{
 			return
 		}
 
-		prettyprint.PrintJSON(cemResult)
+		printOutput(cemResult, prettyprint.CEMAccountsTable)
 	},
 }
 
@@ -136,7 +136,7 @@ var cemGetRemediationsCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		token, err := local_cem.GetToken(CemSessionTokenPath)
 		if err != nil {
-			log.Fatalf("Failed to retrieve token file at %s. %s\n", CemSessionTokenPath, err)
+			fatalf("Failed to retrieve token file at %s. %s\n", CemSessionTokenPath, err)
 		}
 
 		cemQuery := &cem.EntityQuery{
@@ -151,7 +151,7 @@ var cemGetRemediationsCmd = &cobra.Command{
 			return
 		}
 
-		prettyprint.PrintJSON(cemResult)
+		printOutput(cemResult, prettyprint.CEMRecommendationsTable)
 
 	},
 }
@@ -167,7 +167,7 @@ var cemGetRecommendationsCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		token, err := local_cem.GetToken(CemSessionTokenPath)
 		if err != nil {
-			log.Fatalf("Failed to retrieve token file at %s. %s\n", CemSessionTokenPath, err)
+			fatalf("Failed to retrieve token file at %s. %s\n", CemSessionTokenPath, err)
 		}
 
 		cemQuery := &cem.EntityQuery{
@@ -182,7 +182,7 @@ var cemGetRecommendationsCmd = &cobra.Command{
 			return
 		}
 
-		prettyprint.PrintJSON(cemResult)
+		printOutput(cemResult, prettyprint.CEMRecommendationsTable)
 
 	},
 }
@@ -198,7 +198,7 @@ var cemGetEntityDetailCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		token, err := local_cem.GetToken(CemSessionTokenPath)
 		if err != nil {
-			log.Fatalf("Failed to retrieve token file at %s. %s\n", CemSessionTokenPath, err)
+			fatalf("Failed to retrieve token file at %s. %s\n", CemSessionTokenPath, err)
 		}
 
 		cemQuery := &cem.EntityQuery{
@@ -213,7 +213,7 @@ var cemGetEntityDetailCmd = &cobra.Command{
 			return
 		}
 
-		prettyprint.PrintJSON(cemResult)
+		printOutput(cemResult, prettyprint.CEMEntityTable)
 	},
 }
 
@@ -228,7 +228,7 @@ var cemGetEntitiesCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		token, err := local_cem.GetToken(CemSessionTokenPath)
 		if err != nil {
-			log.Fatalf("Failed to retrieve token file at %s. %s\n", CemSessionTokenPath, err)
+			fatalf("Failed to retrieve token file at %s. %s\n", CemSessionTokenPath, err)
 		}
 
 		if CemNonFullAdmin && CemFullAdmin {
@@ -269,7 +269,7 @@ var cemGetEntitiesCmd = &cobra.Command{
 			return
 		}
 
-		prettyprint.PrintJSON(cemResult)
+		printOutput(cemResult, prettyprint.CEMEntitiesTable)
 	},
 }
 
@@ -1,7 +1,7 @@
 package cmd
 
 import (
-	"bufio"
+	"bytes"
 	"fmt"
 	"io/ioutil"
 	"log"
@@ -33,6 +33,12 @@ var (
 	// PolicyFilePath path to policy file
 	PolicyFilePath string
 
+	// PolicyModeName mode the policy is compared in, append, update or replace
+	PolicyModeName string
+
+	// AssumeYes loads a policy without confirming the roles and resources it deletes
+	AssumeYes bool
+
 	// VariableID variable ID of a secret
 	VariableID string
 
@@ -57,58 +63,104 @@ func isInputFromPipe() bool {
 	return (stat.Mode() & os.ModeCharDevice) == 0
 }
 
-func loadPolicyFile(policyBranch string, policyFilePath string, policyMode conjurapi.PolicyMode) {
+// readPolicy returns the policy piped to stdin, or the content of the policy file otherwise
+func readPolicy(policyFilePath string) []byte {
+	if isInputFromPipe() {
+		policy, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			fatalf("%s %s", stdinErrMsg, err)
+		}
+		return policy
+	}
+
 	if policyFilePath == "" {
 		log.Fatal("Policy file path is required")
 	}
-
-	client, _, err := conjur.GetConjurClient()
+	policy, err := ioutil.ReadFile(policyFilePath)
 	if err != nil {
-		log.Fatalf("Failed to initialize conjur client. %s", err)
+		fatalf("Failed to read policy file '%s'. %s", policyFilePath, err)
 	}
+	return policy
+}
 
-	file, err := os.Open(policyFilePath)
+// parsePolicy parses and validates the policy, printing every error of the policy
+func parsePolicy(policy []byte) *conjur.Policy {
+	parsed, err := conjur.ParsePolicy(policy)
 	if err != nil {
-		log.Fatalf("Failed to read policy file '%s'. %s", policyFilePath, err)
+		fatalf("Invalid policy.\n%s", err)
 	}
+	return parsed
+}
 
-	response, err := client.LoadPolicy(policyMode, policyBranch, bufio.NewReader(file))
+// planPolicy returns the roles and resources loading the policy into the branch would create or delete
+func planPolicy(client *conjurapi.Client, policyBranch string, policy []byte, policyMode conjurapi.PolicyMode) *conjur.PolicyPlan {
+	plan, err := conjur.PlanPolicy(client, policyBranch, parsePolicy(policy), policyMode)
 	if err != nil {
-		log.Fatalf("Failed to load policy. %v. %s", response, err)
+		fatalf("Failed to plan policy. %s", err)
 	}
-	prettyprint.PrintJSON(response)
+	return plan
 }
 
-func loadPolicyPipe(policyBranch, policyContent string, policyMode conjurapi.PolicyMode) {
+func loadPolicy(policyBranch string, policy []byte, policyMode conjurapi.PolicyMode) {
 	client, _, err := conjur.GetConjurClient()
 	if err != nil {
-		log.Fatalf("Failed to initialize conjur client. %s", err)
+		fatalf("Failed to initialize conjur client. %s", err)
+	}
+
+	// Replacing a policy deletes everything it no longer declares, so deletions must be confirmed.
+	// Roles and resources the current identity cannot see may be deleted too, which is only a warning
+	if policyMode == conjurapi.PolicyModePut && !AssumeYes {
+		plan := planPolicy(client, policyBranch, policy, policyMode)
+		deletions := plan.Deletions()
+		if len(deletions) == 0 && !plan.Complete {
+			fmt.Fprintf(os.Stderr, "Warning: Only the roles and resources visible to the current identity were compared. Replacing policy '%s' also deletes the roles and resources it cannot see and the policy does not declare.\n", policyBranch)
+		}
+		if len(deletions) > 0 {
+			fmt.Fprint(os.Stderr, plan.String())
+			if !terminal.IsTerminal(int(syscall.Stdin)) {
+				fatalf("Replacing policy '%s' deletes %d roles and resources. Use --yes to confirm them when stdin is not a terminal", policyBranch, len(deletions))
+			}
+			ok, err := confirm(fmt.Sprintf("Replace policy '%s' and delete %d visible roles and resources?", policyBranch, len(deletions)))
+			if err != nil {
+				fatalf("%s", err)
+			}
+			if !ok {
+				fatalf("Policy '%s' was not replaced", policyBranch)
+			}
+		}
 	}
 
-	response, err := client.LoadPolicy(policyMode, policyBranch, strings.NewReader(policyContent))
+	response, err := client.LoadPolicy(policyMode, policyBranch, bytes.NewReader(policy))
 	if err != nil {
-		log.Fatalf("Failed to load policy. %v. %s", response, err)
+		fatalf("Failed to load policy. %v. %s", response, err)
 	}
-	prettyprint.PrintJSON(response)
+	printOutput(response, nil)
 }
 
 func removeFile(path string) {
 	err := os.Remove(path)
 	if err != nil {
-		log.Fatalf("Failed to remove file '%s'. %s", path, err)
+		fatalf("Failed to remove file '%s'. %s", path, err)
 	}
 }
 
 func readPassword() []byte {
 	// Convert Password variable to byte array
 	byteSecretVal := []byte(Password)
+	if Password != "" {
+		password, err := readSecret("password", Password)
+		if err != nil {
+			fatalf("%s", err)
+		}
+		byteSecretVal = []byte(password)
+	}
 
 	// If password is not provided, prompt for password
 	if len(byteSecretVal) == 0 {
 		fmt.Print("Enter password: ")
 		byteSecretVal, err := terminal.ReadPassword(int(syscall.Stdin))
 		if err != nil {
-			log.Fatalf("An error occurred trying to read password from " +
+			fatalf("An error occurred trying to read password from " +
 				"Stdin. Exiting...")
 		}
 		fmt.Println()
@@ -138,7 +190,7 @@ var conjurLogonCmd = &cobra.Command{
 
 		homeDir, err := conjur.GetHomeDirectory()
 		if err != nil {
-			log.Fatalf("%s\n", err)
+			fatalf("%s\n", err)
 		}
 
 		netrcPath := conjur.GetNetRcPath(homeDir)
@@ -151,7 +203,7 @@ var conjurLogonCmd = &cobra.Command{
 
 		err = conjur.CreateConjurRc(Account, BaseURL, InsecureTLS, AuthnLDAP)
 		if err != nil {
-			log.Fatalf("Failed to create ~/.conjurrc file. %s\n", err)
+			fatalf("Failed to create ~/.conjurrc file. %s\n", err)
 		}
 
 		authnURL := authenticators.GetAuthURL(BaseURL, "authn", "")
@@ -161,12 +213,12 @@ var conjurLogonCmd = &cobra.Command{
 
 		apiKey, err := conjur.Login(authnURL, Account, Username, byteSecretVal, certPath)
 		if err != nil {
-			log.Fatalf("Failed to login and retrieve api key. %s", err)
+			fatalf("Failed to login and retrieve api key. %s", err)
 		}
 
 		err = conjur.CreateNetRc(Username, string(apiKey))
 		if err != nil {
-			log.Fatalf("Failed to create ~/.netrc file. %s\n", err)
+			fatalf("Failed to save conjur credentials. %s\n", err)
 		}
 
 		config := conjurapi.Config{
@@ -184,7 +236,7 @@ var conjurLogonCmd = &cobra.Command{
 		client, err := conjurapi.NewClientFromKey(config, loginPair)
 		_, err = client.Authenticate(loginPair)
 		if err != nil {
-			log.Fatalf("Failed to authenticate to conjur. %s", err)
+			fatalf("Failed to authenticate to conjur. %s", err)
 		}
 
 		fmt.Println("Successfully logged into conjur")
@@ -202,12 +254,12 @@ var conjurNonInteractiveLogonCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		client, loginPair, err := conjur.GetConjurClient()
 		if err != nil {
-			log.Fatalf("Failed to initialize conjur client. %s", err)
+			fatalf("Failed to initialize conjur client. %s", err)
 		}
 
 		_, err = client.Authenticate(*loginPair)
 		if err != nil {
-			log.Fatalf("Failed to authenticate to conjur. %s", err)
+			fatalf("Failed to authenticate to conjur. %s", err)
 		}
 
 		fmt.Println("Successfully logged into conjur")
@@ -218,20 +270,22 @@ var conjurNonInteractiveLogonCmd = &cobra.Command{
 var conjurLogoffCmd = &cobra.Command{
 	Use:   "logoff",
 	Short: "Logoff to Conjur",
-	Long: `Logoff to conjur and remove the ~/.netrc and ~/.conjurrc files
+	Long: `Logoff to conjur and remove the saved credentials and the ~/.conjurrc file
 	
 	Example Usage:
 	$ cybr conjur logoff`,
 	Run: func(cmd *cobra.Command, args []string) {
 		homeDir, err := conjur.GetHomeDirectory()
 		if err != nil {
-			log.Fatalf("%s\n", err)
+			fatalf("%s\n", err)
 		}
 
-		netrcPath := fmt.Sprintf("%s/.netrc", homeDir)
-		conjurrcPath := fmt.Sprintf("%s/.conjurrc", homeDir)
+		conjurrcPath := conjur.GetConjurRcPath(homeDir)
 
-		removeFile(netrcPath)
+		err = conjur.RemoveCredentials(conjur.GetURLFromConjurRc(conjurrcPath))
+		if err != nil {
+			fatalf("Failed to remove conjur credentials. %s", err)
+		}
 		removeFile(conjurrcPath)
 
 		fmt.Println("Logged off conjur")
@@ -245,18 +299,10 @@ var conjurAppendPolicyCmd = &cobra.Command{
 	Any policy objects that exist on the server but are omitted from the policy file will not be deleted and any explicit deletions in the policy file will result in an error.  
 	
 	Example Usage:
-	$ cybr conjur append-policy --branch root --file ./path/to/root.yml`,
+	$ cybr conjur append-policy --branch root --file ./path/to/root.yml
+	$ cat root.yml | cybr conjur append-policy --branch root`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if isInputFromPipe() {
-			// Read from stdin
-			policy, err := ioutil.ReadAll(os.Stdin)
-			if err != nil {
-				log.Fatalf("%s %s", stdinErrMsg, err)
-			}
-			loadPolicyPipe(PolicyBranch, string(policy), conjurapi.PolicyModePost)
-		} else {
-			loadPolicyFile(PolicyBranch, PolicyFilePath, conjurapi.PolicyModePost)
-		}
+		loadPolicy(PolicyBranch, readPolicy(PolicyFilePath), conjurapi.PolicyModePost)
 	},
 }
 
@@ -267,18 +313,10 @@ var conjurUpdatePolicyCmd = &cobra.Command{
 	Unlike “replace” mode, no data is ever implicitly deleted.
 	
 	Example Usage:
-	$ cybr conjur update-policy --branch root --file ./path/to/root.yml`,
+	$ cybr conjur update-policy --branch root --file ./path/to/root.yml
+	$ cat root.yml | cybr conjur update-policy --branch root`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if isInputFromPipe() {
-			// Read from stdin
-			policy, err := ioutil.ReadAll(os.Stdin)
-			if err != nil {
-				log.Fatalf("%s %s", stdinErrMsg, err)
-			}
-			loadPolicyPipe(PolicyBranch, string(policy), conjurapi.PolicyModePut)
-		} else {
-			loadPolicyFile(PolicyBranch, PolicyFilePath, conjurapi.PolicyModePatch)
-		}
+		loadPolicy(PolicyBranch, readPolicy(PolicyFilePath), conjurapi.PolicyModePatch)
 	},
 }
 
@@ -287,19 +325,68 @@ var conjurReplacePolicyCmd = &cobra.Command{
 	Short: "Replace policy to conjur",
 	Long: `Loads or replaces a Conjur policy document.
 	Any policy data which already exists on the server but is not explicitly specified in the new policy file will be deleted.
+	The roles and resources that would be deleted are listed and must be confirmed, unless --yes is given.
+	Only the roles and resources visible to the current identity can be listed, so a warning is shown that
+	roles and resources it cannot see may be deleted too.
 	
 	Example Usage:
-	$ cybr conjur replace-policy --branch root --file ./path/to/root.yml`,
+	$ cybr conjur replace-policy --branch root --file ./path/to/root.yml
+	$ cat root.yml | cybr conjur replace-policy --branch root --yes`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if isInputFromPipe() {
-			// Read from stdin
-			policy, err := ioutil.ReadAll(os.Stdin)
-			if err != nil {
-				log.Fatalf("%s %s", stdinErrMsg, err)
-			}
-			loadPolicyPipe(PolicyBranch, string(policy), conjurapi.PolicyModePut)
+		loadPolicy(PolicyBranch, readPolicy(PolicyFilePath), conjurapi.PolicyModePut)
+	},
+}
+
+var conjurValidatePolicyCmd = &cobra.Command{
+	Use:   "validate-policy",
+	Short: "Validate a conjur policy file",
+	Long: `Parses a Conjur policy document locally and reports the errors of its statements with their line numbers,
+	such as unknown statements or attributes, missing IDs and references that are not tagged with a record type.
+	Nothing is sent to Conjur.
+	
+	Example Usage:
+	$ cybr conjur validate-policy --file ./path/to/root.yml
+	$ cat root.yml | cybr conjur validate-policy`,
+	Run: func(cmd *cobra.Command, args []string) {
+		policy := parsePolicy(readPolicy(PolicyFilePath))
+		fmt.Printf("Policy is valid. It declares %d roles and resources and deletes %d.\n", len(policy.Records), len(policy.Deletions))
+	},
+}
+
+var conjurDiffPolicyCmd = &cobra.Command{
+	Use:   "diff-policy",
+	Short: "Show the roles and resources a policy would create or delete",
+	Long: `Compares a Conjur policy document to the roles and resources of a branch and shows what loading it
+	would create or delete. Replacing a policy deletes the roles and resources of the branch it does not declare.
+	Permissions and role grants are not compared, nor the roles and resources the current identity cannot see.
+	The exit code is 2 when there are changes and 0 when the branch matches the policy.
+	
+	Use --output to print the plan in another format.
+	
+	Example Usage:
+	$ cybr conjur diff-policy --branch root --file ./path/to/root.yml
+	$ cybr conjur diff-policy --branch apps --file ./path/to/apps.yml --mode update`,
+	Run: func(cmd *cobra.Command, args []string) {
+		policyMode, err := conjur.GetPolicyMode(PolicyModeName)
+		if err != nil {
+			fatalf("%s", err)
+		}
+		policy := readPolicy(PolicyFilePath)
+
+		client, _, err := conjur.GetConjurClient()
+		if err != nil {
+			fatalf("Failed to initialize conjur client. %s", err)
+		}
+
+		plan := planPolicy(client, PolicyBranch, policy, policyMode)
+		if cmd.Flags().Changed("output") {
+			printOutput(plan, prettyprint.ConjurPolicyPlanTable)
 		} else {
-			loadPolicyFile(PolicyBranch, PolicyFilePath, conjurapi.PolicyModePut)
+			fmt.Print(plan.String())
+		}
+
+		if plan.HasChanges() {
+			os.Exit(ExitCodeDiff)
 		}
 	},
 }
@@ -316,12 +403,12 @@ var conjurGetSecretCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		client, _, err := conjur.GetConjurClient()
 		if err != nil {
-			log.Fatalf("Failed to initialize conjur client. %s", err)
+			fatalf("Failed to initialize conjur client. %s", err)
 		}
 
 		content, err := client.RetrieveSecret(VariableID)
 		if err != nil {
-			log.Fatalf("Failed to retrieve secret variable '%s'. %s", VariableID, err)
+			fatalf("Failed to retrieve secret variable '%s'. %s", VariableID, err)
 		}
 
 		padding := "\n"
@@ -335,19 +422,28 @@ var conjurGetSecretCmd = &cobra.Command{
 var conjurSetSecretCmd = &cobra.Command{
 	Use:   "set-secret",
 	Short: "Set secret in conjur",
-	Long: `Sets a secret value for the specified Variable.
+	Long: `Sets a secret value for the specified Variable. The value is read from a file with -v @path,
+	from stdin with -v - or from an environment variable with -v env:VAR. It is prompted for when
+	-v is not given. Multi-line values, such as certificates and SSH keys, are kept unchanged.
 	
 	Example Usage:
-	$ cybr conjur set-secret -i id/to/variable -v "P@$$word"`,
+	$ cybr conjur set-secret -i id/to/variable -v "P@$$word"
+	$ cybr conjur set-secret -i id/to/variable -v @server.key
+	$ cybr conjur set-secret -i id/to/variable -v env:DB_PASSWORD`,
 	Run: func(cmd *cobra.Command, args []string) {
 		client, _, err := conjur.GetConjurClient()
 		if err != nil {
-			log.Fatalf("Failed to initialize conjur client. %s", err)
+			fatalf("Failed to initialize conjur client. %s", err)
 		}
 
-		err = client.AddSecret(VariableID, SecretValue)
+		secretValue, err := readSecret("secret value", SecretValue)
 		if err != nil {
-			log.Fatalf("Failed to set secret variable '%s'. %s", VariableID, err)
+			fatalf("%s", err)
+		}
+
+		err = client.AddSecret(VariableID, secretValue)
+		if err != nil {
+			fatalf("Failed to set secret variable '%s'. %s", VariableID, err)
 		}
 	},
 }
@@ -362,7 +458,7 @@ var conjurEnableAuthnCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		err := conjur.EnableAuthenticator(ServiceID)
 		if err != nil {
-			log.Fatalf("%s", err)
+			fatalf("%s", err)
 		}
 		fmt.Printf("Successfully enabled authenticator '%s'\n", ServiceID)
 	},
@@ -378,9 +474,9 @@ var conjurInfoCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		result, err := conjur.Info()
 		if err != nil {
-			log.Fatalf("%s", err)
+			fatalf("%s", err)
 		}
-		prettyprint.PrintJSON(result)
+		printOutput(result, nil)
 	},
 }
 
@@ -394,9 +490,9 @@ var conjurWhoamiCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		result, err := conjur.Whoami()
 		if err != nil {
-			log.Fatalf("%s", err)
+			fatalf("%s", err)
 		}
-		prettyprint.PrintJSON(result)
+		printOutput(result, nil)
 	},
 }
 
@@ -410,7 +506,7 @@ var conjurListResourcesCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		client, _, err := conjur.GetConjurClient()
 		if err != nil {
-			log.Fatalf("Failed to initialize conjur client. %s", err)
+			fatalf("Failed to initialize conjur client. %s", err)
 		}
 
 		filter := conjurapi.ResourceFilter{
@@ -422,11 +518,11 @@ var conjurListResourcesCmd = &cobra.Command{
 
 		resources, err := client.Resources(&filter)
 		if err != nil {
-			log.Fatalf("Failed to list resources. %s", err)
+			fatalf("Failed to list resources. %s", err)
 		}
 
 		if InspectResources {
-			prettyprint.PrintJSON(resources)
+			printOutput(resources, prettyprint.ConjurResourcesTable)
 			return
 		}
 
@@ -435,7 +531,7 @@ var conjurListResourcesCmd = &cobra.Command{
 		for _, r := range resources {
 			ids = append(ids, r["id"].(string))
 		}
-		prettyprint.PrintJSON(ids)
+		printOutput(ids, prettyprint.ConjurResourceIDsTable)
 	},
 }
 
@@ -453,7 +549,7 @@ var conjurRotateAPIKeyCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		client, loginPair, err := conjur.GetConjurClient()
 		if err != nil {
-			log.Fatalf("Failed to initialize conjur client. %s", err)
+			fatalf("Failed to initialize conjur client. %s", err)
 		}
 
 		login := Username
@@ -472,7 +568,7 @@ var conjurRotateAPIKeyCmd = &cobra.Command{
 
 		newAPIKey, err := client.RotateAPIKey(login)
 		if err != nil {
-			log.Fatalf("Failed to rotate api key for '%s'. %s", login, err)
+			fatalf("Failed to rotate api key for '%s'. %s", login, err)
 		}
 
 		fmt.Println(string(newAPIKey))
@@ -483,7 +579,7 @@ func init() {
 	// Logon command
 	conjurLogonCmd.Flags().StringVarP(&Username, "login", "l", "", "Conjur login name")
 	conjurLogonCmd.MarkFlagRequired("login")
-	conjurLogonCmd.Flags().StringVarP(&Password, "password", "p", "", "Conjur password")
+	conjurLogonCmd.Flags().StringVarP(&Password, "password", "p", "", "Conjur password, @file, - for stdin or env:VAR. Prompted for if not given")
 	conjurLogonCmd.Flags().StringVarP(&Account, "account", "a", "", "Conjur account")
 	conjurLogonCmd.MarkFlagRequired("account")
 	conjurLogonCmd.Flags().StringVarP(&BaseURL, "base-url", "b", "", "Conjur appliance URL")
@@ -500,13 +596,21 @@ func init() {
 	conjurUpdatePolicyCmd.Flags().StringVarP(&PolicyBranch, "branch", "b", "", "The policy branch in which policy is being loaded")
 	conjurUpdatePolicyCmd.MarkFlagRequired("branch")
 	conjurUpdatePolicyCmd.Flags().StringVarP(&PolicyFilePath, "file", "f", "", "The policy file that will be loaded into the branch")
-	conjurUpdatePolicyCmd.MarkFlagRequired("file")
 
 	// replace-policy
 	conjurReplacePolicyCmd.Flags().StringVarP(&PolicyBranch, "branch", "b", "", "The policy branch in which policy is being loaded")
 	conjurReplacePolicyCmd.MarkFlagRequired("branch")
 	conjurReplacePolicyCmd.Flags().StringVarP(&PolicyFilePath, "file", "f", "", "The policy file that will be loaded into the branch")
-	conjurReplacePolicyCmd.MarkFlagRequired("file")
+	conjurReplacePolicyCmd.Flags().BoolVarP(&AssumeYes, "yes", "y", false, "Replace the policy without confirming the roles and resources it deletes")
+
+	// validate-policy
+	conjurValidatePolicyCmd.Flags().StringVarP(&PolicyFilePath, "file", "f", "", "The policy file that will be validated")
+
+	// diff-policy
+	conjurDiffPolicyCmd.Flags().StringVarP(&PolicyBranch, "branch", "b", "", "The policy branch the policy is compared to")
+	conjurDiffPolicyCmd.MarkFlagRequired("branch")
+	conjurDiffPolicyCmd.Flags().StringVarP(&PolicyFilePath, "file", "f", "", "The policy file that will be compared to the branch")
+	conjurDiffPolicyCmd.Flags().StringVarP(&PolicyModeName, "mode", "m", "replace", "How the policy would be loaded. append, update or replace")
 
 	// retrieve-secret
 	conjurGetSecretCmd.Flags().StringVarP(&VariableID, "id", "i", "", "The variable ID containing the secret")
@@ -516,8 +620,7 @@ func init() {
 	// set-secret
 	conjurSetSecretCmd.Flags().StringVarP(&VariableID, "id", "i", "", "The variable ID being updated")
 	conjurSetSecretCmd.MarkFlagRequired("ID")
-	conjurSetSecretCmd.Flags().StringVarP(&SecretValue, "secret-value", "v", "", "The new value of the secret")
-	conjurSetSecretCmd.MarkFlagRequired("secret-value")
+	conjurSetSecretCmd.Flags().StringVarP(&SecretValue, "secret-value", "v", "", "The new value of the secret, @file, - for stdin or env:VAR. Prompted for if not given")
 
 	// enable-authn
 	conjurEnableAuthnCmd.Flags().StringVarP(&ServiceID, "service-id", "s", "", "The authenticator service ID. e.g. authn-iam/prod or authn-k8s/k8s-cluster-1")
@@ -538,6 +641,8 @@ func init() {
 	conjurCmd.AddCommand(conjurAppendPolicyCmd)
 	conjurCmd.AddCommand(conjurUpdatePolicyCmd)
 	conjurCmd.AddCommand(conjurReplacePolicyCmd)
+	conjurCmd.AddCommand(conjurValidatePolicyCmd)
+	conjurCmd.AddCommand(conjurDiffPolicyCmd)
 	conjurCmd.AddCommand(conjurGetSecretCmd)
 	conjurCmd.AddCommand(conjurSetSecretCmd)
 	conjurCmd.AddCommand(conjurEnableAuthnCmd)
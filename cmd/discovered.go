@@ -0,0 +1,298 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	pasapi "github.com/infamousjoeg/cybr-cli/pkg/cybr/api"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/queries"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/requests"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/responses"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/shared"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/prettyprint"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// DiscoveredID is the ID of a discovered account
+	DiscoveredID string
+
+	// Privileged selects privileged or non-privileged discovered accounts
+	Privileged bool
+)
+
+var discoveredCmd = &cobra.Command{
+	Use:   "discovered",
+	Short: "Discovered accounts actions for PAS REST API",
+	Long: `All actions on the accounts found by Accounts Discovery that can be taken via PAS REST API.
+
+	Example Usage:
+	List privileged discovered accounts: $ cybr discovered list --privileged
+	Onboard a discovered account: $ cybr discovered onboard -i 8f9e4d3c-0a1b -s SafeName -p WinServerLocal
+	Delete a discovered account: $ cybr discovered delete -i 8f9e4d3c-0a1b`,
+	Aliases: []string{"discovered-accounts", "pending"},
+}
+
+var listDiscoveredCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List discovered accounts",
+	Long: `List the accounts found by Accounts Discovery that have not been onboarded.
+
+	PAS cannot filter on the discovery date, so --from and --to are applied by cybr to
+	every page of discovered accounts.
+
+	Example Usage:
+	$ cybr discovered list --platform-type "Windows Server Local" --privileged
+	$ cybr discovered list --from 7d --output table`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		fromTime, toTime, err := parseTimeRange()
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		query := &queries.ListDiscoveredAccounts{
+			Search: Search,
+			Filter: discoveredFilter(cmd),
+			Offset: Offset,
+			Limit:  Limit,
+		}
+
+		if All || fromTime != 0 || toTime != 0 {
+			accounts := &discoveredAccounts{client.IterateDiscoveredAccounts(query), fromTime, toTime}
+			err = printAll(accounts, func() interface{} { return accounts.Account() }, "value", "count", prettyprint.DiscoveredAccountsTable)
+			if err != nil {
+				fatalf("Failed to list discovered accounts. %s", err)
+			}
+			return
+		}
+
+		accounts, err := client.ListDiscoveredAccounts(query)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		printOutput(accounts, prettyprint.DiscoveredAccountsTable)
+	},
+}
+
+var getDiscoveredCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Get a specific discovered account",
+	Long: `Get the details of a discovered account, including its dependencies.
+
+	Example Usage:
+	$ cybr discovered get -i 8f9e4d3c-0a1b`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		account, err := client.GetDiscoveredAccount(DiscoveredID)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		printOutput(account, prettyprint.DiscoveredAccountsTable)
+	},
+}
+
+var deleteDiscoveredCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete a discovered account",
+	Long: `Remove an account from the discovered accounts without onboarding it.
+
+	Example Usage:
+	$ cybr discovered delete -i 8f9e4d3c-0a1b`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		err = client.DeleteDiscoveredAccount(DiscoveredID)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		fmt.Printf("Successfully deleted discovered account '%s'\n", DiscoveredID)
+	},
+}
+
+var onboardDiscoveredCmd = &cobra.Command{
+	Use:   "onboard",
+	Short: "Onboard discovered accounts",
+	Long: `Add discovered accounts to a safe with a platform and remove them from the discovered accounts.
+
+	A single account is onboarded with --discovered-id. Otherwise every discovered account matching
+	--search, --filter, --platform-type, --privileged, --from and --to is onboarded. Use --all to
+	onboard every discovered account.
+
+	Example Usage:
+	$ cybr discovered onboard -i 8f9e4d3c-0a1b -s SafeName -p WinServerLocal
+	$ cybr discovered onboard --platform-type "Windows Server Local" --privileged --from 7d -s SafeName -p WinServerLocal -m`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if DiscoveredID == "" && !All && Search == "" && discoveredFilter(cmd) == "" && FromTime == "" && ToTime == "" {
+			fatalf("Either --discovered-id, a filter of the discovered accounts or --all is required")
+			return
+		}
+
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		platformProps, err := keyValueStringToMap(PlatformProperties)
+		if err != nil {
+			fatalf("Failed to parse platform properties. %s", err)
+			return
+		}
+
+		template := requests.AddAccount{
+			PlatformID: PlatformID,
+			SafeName:   Safe,
+			SecretType: SecretType,
+			SecretManagement: shared.SecretManagement{
+				AutomaticManagementEnabled: AutomaticManagementEnabled,
+				ManualManagementReason:     ManualManagementReason,
+			},
+			PlatformAccountProperties: platformProps,
+		}
+
+		if DiscoveredID != "" {
+			discovered, err := client.GetDiscoveredAccount(DiscoveredID)
+			if err != nil {
+				fatalf("%s", err)
+				return
+			}
+			account, err := client.OnboardDiscoveredAccount(*discovered, template)
+			if err != nil {
+				fatalf("%s", err)
+				return
+			}
+			printOutput(account, prettyprint.AccountsTable)
+			return
+		}
+
+		fromTime, toTime, err := parseTimeRange()
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		// All discovered accounts are listed before onboarding any, since onboarding removes them from the pages
+		query := &queries.ListDiscoveredAccounts{Search: Search, Filter: discoveredFilter(cmd), Limit: 1000}
+		selected := []responses.DiscoveredAccount{}
+		accounts := &discoveredAccounts{client.IterateDiscoveredAccounts(query), fromTime, toTime}
+		for accounts.Next() {
+			selected = append(selected, accounts.Account())
+		}
+		if accounts.Err() != nil {
+			fatalf("Failed to list discovered accounts. %s", accounts.Err())
+			return
+		}
+
+		results := client.OnboardDiscoveredAccounts(selected, template, func(result pasapi.OnboardDiscoveredAccountResult) {
+			message := fmt.Sprintf("Discovered account '%s' %s", result.DiscoveredID, result.Status)
+			if result.Error != "" {
+				message += ". " + result.Error
+			}
+			fmt.Fprintln(os.Stderr, message)
+		})
+		printOutput(results, prettyprint.OnboardDiscoveredAccountsTable)
+
+		failed := 0
+		for _, result := range results {
+			if result.Status == pasapi.OnboardStatusFailed {
+				failed++
+			}
+		}
+		if failed > 0 {
+			fatalf("%d of %d discovered accounts failed to onboard", failed, len(results))
+			return
+		}
+	},
+}
+
+// discoveredAccounts iterates over the discovered accounts discovered between from and to.
+// A zero from or to is not a bound
+type discoveredAccounts struct {
+	*pasapi.DiscoveredAccountIterator
+	from int
+	to   int
+}
+
+func (it *discoveredAccounts) Next() bool {
+	for it.DiscoveredAccountIterator.Next() {
+		discovered := it.Account().DiscoveryDateTime
+		if (it.from == 0 || discovered >= it.from) && (it.to == 0 || discovered <= it.to) {
+			return true
+		}
+	}
+	return false
+}
+
+// discoveredFilter returns the PAS filter of the --platform-type, --privileged and --filter flags
+func discoveredFilter(cmd *cobra.Command) string {
+	filters := []string{}
+	if PlatformType != "" {
+		filters = append(filters, fmt.Sprintf("platformType eq %s", PlatformType))
+	}
+	if cmd.Flags().Changed("privileged") {
+		filters = append(filters, fmt.Sprintf("privileged eq %t", Privileged))
+	}
+	if Filter != "" {
+		filters = append(filters, Filter)
+	}
+	return strings.Join(filters, " AND ")
+}
+
+func init() {
+	for _, command := range []*cobra.Command{listDiscoveredCmd, onboardDiscoveredCmd} {
+		command.Flags().StringVar(&Search, "search", "", "List of keywords to search for in discovered accounts, separated by a space")
+		command.Flags().StringVarP(&Filter, "filter", "f", "", "Additional filter of the discovered accounts, e.g. 'accountEnabled eq true'")
+		command.Flags().StringVar(&PlatformType, "platform-type", "", "Platform type of the discovered accounts, e.g. 'Windows Server Local'")
+		command.Flags().BoolVar(&Privileged, "privileged", false, "Select privileged discovered accounts. Use --privileged=false for non-privileged accounts")
+		command.Flags().StringVar(&FromTime, "from", "", "Select accounts discovered after this time, e.g. 2021-01-01 or 7d")
+		command.Flags().StringVar(&ToTime, "to", "", "Select accounts discovered before this time, e.g. 2021-02-01 or 1d")
+	}
+	listDiscoveredCmd.Flags().IntVarP(&Offset, "offset", "o", 0, "Offset of the first discovered account that is returned in the collection of results")
+	listDiscoveredCmd.Flags().IntVarP(&Limit, "limit", "l", 0, "Maximum number of returned discovered accounts")
+	listDiscoveredCmd.Flags().BoolVar(&All, "all", false, "Retrieve all pages of discovered accounts starting at offset instead of a single page")
+
+	for _, command := range []*cobra.Command{getDiscoveredCmd, deleteDiscoveredCmd} {
+		command.Flags().StringVarP(&DiscoveredID, "discovered-id", "i", "", "ID of the discovered account")
+		command.MarkFlagRequired("discovered-id")
+	}
+
+	onboardDiscoveredCmd.Flags().StringVarP(&DiscoveredID, "discovered-id", "i", "", "ID of the discovered account to onboard")
+	onboardDiscoveredCmd.Flags().BoolVar(&All, "all", false, "Onboard every discovered account")
+	onboardDiscoveredCmd.Flags().StringVarP(&Safe, "safe", "s", "", "Safe the accounts are added to")
+	onboardDiscoveredCmd.MarkFlagRequired("safe")
+	onboardDiscoveredCmd.Flags().StringVarP(&PlatformID, "platform-id", "p", "", "Platform ID of the accounts added")
+	onboardDiscoveredCmd.MarkFlagRequired("platform-id")
+	onboardDiscoveredCmd.Flags().StringVarP(&SecretType, "secret-type", "t", "", "Secret type of the accounts added. Defaults to password")
+	onboardDiscoveredCmd.Flags().StringVarP(&PlatformProperties, "platform-properties", "e", "", "Extra platform properties. e.g. port=22,UseSudoOnReconcile=yes,CustomField=custom")
+	onboardDiscoveredCmd.Flags().BoolVarP(&AutomaticManagementEnabled, "automatic-management", "m", false, "If set will automatically manage the onboarded accounts")
+	onboardDiscoveredCmd.Flags().StringVarP(&ManualManagementReason, "manual-management-reason", "r", "", "The reason the onboarded accounts are not being managed")
+
+	discoveredCmd.AddCommand(listDiscoveredCmd)
+	discoveredCmd.AddCommand(getDiscoveredCmd)
+	discoveredCmd.AddCommand(onboardDiscoveredCmd)
+	discoveredCmd.AddCommand(deleteDiscoveredCmd)
+	rootCmd.AddCommand(discoveredCmd)
+}
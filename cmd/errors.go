@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"os"
+
+	pasapi "github.com/infamousjoeg/cybr-cli/pkg/cybr/api"
+)
+
+// Exit codes returned by cybr for each class of error. These values are stable
+// so scripts can react to a failure without parsing the error message
+const (
+	ExitCodeGeneral      = 1
+	ExitCodeDiff         = 2
+	ExitCodeConnection   = 3
+	ExitCodeBadRequest   = 4
+	ExitCodeUnauthorized = 5
+	ExitCodeForbidden    = 6
+	ExitCodeNotFound     = 7
+	ExitCodeConflict     = 8
+	ExitCodeThrottled    = 9
+	ExitCodeServerError  = 10
+)
+
+// exitCode returns the exit code for the class of err
+func exitCode(err error) int {
+	if apiError, ok := pasapi.AsAPIError(err); ok {
+		switch {
+		case apiError.StatusCode == http.StatusUnauthorized:
+			return ExitCodeUnauthorized
+		case apiError.StatusCode == http.StatusForbidden:
+			return ExitCodeForbidden
+		case apiError.StatusCode == http.StatusNotFound:
+			return ExitCodeNotFound
+		case apiError.StatusCode == http.StatusConflict:
+			return ExitCodeConflict
+		case apiError.StatusCode == http.StatusTooManyRequests:
+			return ExitCodeThrottled
+		case apiError.StatusCode >= 500:
+			return ExitCodeServerError
+		case apiError.StatusCode >= 400:
+			return ExitCodeBadRequest
+		}
+		return ExitCodeGeneral
+	}
+
+	var netError net.Error
+	if errors.As(err, &netError) {
+		return ExitCodeConnection
+	}
+
+	return ExitCodeGeneral
+}
+
+// fatalf is log.Fatalf for command errors. The details and request ID of an APIError
+// passed as argument are logged, and the exit code is chosen from the class of the error
+func fatalf(format string, v ...interface{}) {
+	log.Printf(format, v...)
+
+	code := ExitCodeGeneral
+	for _, arg := range v {
+		err, ok := arg.(error)
+		if !ok {
+			continue
+		}
+		code = exitCode(err)
+
+		apiError, ok := pasapi.AsAPIError(err)
+		if !ok {
+			continue
+		}
+		if apiError.Details != nil {
+			details, _ := json.Marshal(apiError.Details)
+			log.Printf("Details: %s", details)
+		}
+		if apiError.RequestID != "" {
+			log.Printf("Request ID: %s", apiError.RequestID)
+		}
+	}
+
+	os.Exit(code)
+}
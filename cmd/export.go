@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	pasapi "github.com/infamousjoeg/cybr-cli/pkg/cybr/api"
+	"github.com/spf13/cobra"
+)
+
+// SnapshotFile is the JSON or YAML file the vault inventory is exported to
+var SnapshotFile string
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the vault inventory to a JSON or YAML snapshot",
+	Long: `Export the safes, safe members, account metadata, applications with their
+	authentication methods, users and platforms of the vault to a versioned snapshot.
+	Secrets are never exported.
+
+	Safes, members, accounts, applications and users are written using the fields of
+	the requests that add them. The format is selected by the extension of --file.
+	Without --file the snapshot is printed as JSON, or YAML with '--output yaml'.
+
+	Example Usage:
+	$ cybr export -f vault.yaml
+	$ cybr export --location "\\Applications" --output yaml > vault.yaml`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		snapshot, err := client.Export(pasapi.ExportOptions{ApplicationLocation: Location})
+		if err != nil {
+			fatalf("Failed to export vault. %s", err)
+			return
+		}
+
+		if SnapshotFile == "" {
+			err = pasapi.WriteSnapshot(os.Stdout, Output, snapshot)
+		} else {
+			err = pasapi.WriteSnapshotFile(SnapshotFile, snapshot)
+		}
+		if err != nil {
+			fatalf("Failed to write snapshot. %s", err)
+			return
+		}
+
+		accounts, members := 0, 0
+		for _, safe := range snapshot.Safes {
+			accounts += len(safe.Accounts)
+			members += len(safe.Members)
+		}
+		fmt.Fprintf(os.Stderr, "Exported %d safes, %d safe members, %d accounts, %d applications, %d users and %d platforms\n",
+			len(snapshot.Safes), members, accounts, len(snapshot.Applications), len(snapshot.Users), len(snapshot.Platforms))
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringVarP(&SnapshotFile, "file", "f", "", "JSON or YAML file the snapshot is written to")
+	exportCmd.Flags().StringVarP(&Location, "location", "l", "\\", "Location of the exported applications")
+	rootCmd.AddCommand(exportCmd)
+}
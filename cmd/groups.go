@@ -0,0 +1,299 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	pasapi "github.com/infamousjoeg/cybr-cli/pkg/cybr/api"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/queries"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/requests"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/prettyprint"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// GroupID is the id of a group
+	GroupID int
+
+	// GroupName is the name of a group
+	GroupName string
+
+	// GroupType is the type of the groups listed, vault or directory
+	GroupType string
+
+	// IncludeMembers includes the members of the groups listed
+	IncludeMembers bool
+
+	// DomainName is the domain of a directory user
+	DomainName string
+)
+
+var groupsCmd = &cobra.Command{
+	Use:   "groups",
+	Short: "Group actions for PAS REST API",
+	Long: `All group actions that can be taken via PAS REST API.
+
+	Example Usage:
+	List directory groups: $ cybr groups list --type directory
+	Add a group: $ cybr groups add -g AppOwners --description "Application owners"
+	Add a user to a group: $ cybr groups add-member -g AppOwners -m userName`,
+	Aliases: []string{"group"},
+}
+
+var listGroupsCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List groups",
+	Long: `List the vault groups and the groups mapped from an LDAP directory.
+
+	Example Usage:
+	$ cybr groups list
+	$ cybr groups list --type directory --output table
+	$ cybr groups list --search App --members`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		filter := Filter
+		if GroupType != "" {
+			groupType, err := parseGroupType(GroupType)
+			if err != nil {
+				fatalf("%s", err)
+				return
+			}
+			filter = fmt.Sprintf("groupType eq %s", groupType)
+		}
+
+		query := &queries.ListGroups{
+			Search:         Search,
+			Filter:         filter,
+			Sort:           Sort,
+			IncludeMembers: IncludeMembers,
+		}
+
+		groups, err := client.ListGroups(query)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		printOutput(groups, prettyprint.GroupsTable)
+	},
+}
+
+var getGroupCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Get a specific group",
+	Long: `Get the details and members of a group.
+
+	Example Usage:
+	$ cybr groups get -i 5
+	$ cybr groups get -g AppOwners`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		groupID, err := getGroupID(client)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		group, err := client.GetGroup(groupID)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		printOutput(group, prettyprint.GroupsTable)
+	},
+}
+
+var addGroupCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add a vault group",
+	Long: `Add a group to the vault.
+
+	Example Usage:
+	$ cybr groups add -g AppOwners --description "Application owners" --location "\\Applications"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		group, err := client.AddGroup(requests.AddGroup{
+			GroupName:   GroupName,
+			Description: Description,
+			Location:    Location,
+		})
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		printOutput(group, prettyprint.GroupsTable)
+	},
+}
+
+var deleteGroupCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete a group",
+	Long: `Delete a group from the vault.
+
+	Example Usage:
+	$ cybr groups delete -i 5
+	$ cybr groups delete -g AppOwners`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		groupID, err := getGroupID(client)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		err = client.DeleteGroup(groupID)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		fmt.Printf("Successfully deleted group with id '%d'\n", groupID)
+	},
+}
+
+var addGroupMemberCmd = &cobra.Command{
+	Use:   "add-member",
+	Short: "Add a user to a group",
+	Long: `Add a vault user, or a directory user with --domain, to a group.
+
+	Example Usage:
+	$ cybr groups add-member -g AppOwners -m userName
+	$ cybr groups add-member -i 5 -m john.doe --domain cyberark.local`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		groupID, err := getGroupID(client)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		member := requests.AddGroupMember{MemberID: MemberName, MemberType: "vault"}
+		if DomainName != "" {
+			member.MemberType = "domain"
+			member.DomainName = DomainName
+		}
+
+		err = client.AddGroupMember(groupID, member)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		fmt.Printf("Successfully added member '%s' to group with id '%d'\n", MemberName, groupID)
+	},
+}
+
+var removeGroupMemberCmd = &cobra.Command{
+	Use:   "remove-member",
+	Short: "Remove a user from a group",
+	Long: `Remove a user from a group.
+
+	Example Usage:
+	$ cybr groups remove-member -g AppOwners -m userName`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		groupID, err := getGroupID(client)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		err = client.RemoveGroupMember(groupID, MemberName)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		fmt.Printf("Successfully removed member '%s' from group with id '%d'\n", MemberName, groupID)
+	},
+}
+
+// getGroupID returns the --group-id flag, or the id of the group named by --group
+func getGroupID(client pasapi.Client) (int, error) {
+	if (GroupID == 0) == (GroupName == "") {
+		return 0, fmt.Errorf("Either --group-id or --group is required")
+	}
+	if GroupID != 0 {
+		return GroupID, nil
+	}
+
+	group, err := client.FindGroup(GroupName)
+	if err != nil {
+		return 0, err
+	}
+	return group.ID, nil
+}
+
+// parseGroupType returns the PAS group type of vault or directory
+func parseGroupType(groupType string) (string, error) {
+	for _, valid := range []string{pasapi.GroupTypeVault, pasapi.GroupTypeDirectory} {
+		if strings.EqualFold(groupType, valid) {
+			return valid, nil
+		}
+	}
+	return "", fmt.Errorf("Invalid group type '%s'. Valid values: vault or directory", groupType)
+}
+
+func init() {
+	listGroupsCmd.Flags().StringVarP(&Search, "search", "s", "", "List of keywords to search for in groups, separated by a space")
+	listGroupsCmd.Flags().StringVarP(&Filter, "filter", "f", "", "Filter of the groups listed, e.g. 'groupType eq Directory'")
+	listGroupsCmd.Flags().StringVarP(&GroupType, "type", "t", "", "Type of the groups listed. Valid values: vault or directory")
+	listGroupsCmd.Flags().StringVarP(&Sort, "sort", "r", "", "Property by which to sort returned groups, followed by asc (default) or desc")
+	listGroupsCmd.Flags().BoolVarP(&IncludeMembers, "members", "m", false, "Include the members of the groups")
+
+	for _, command := range []*cobra.Command{getGroupCmd, deleteGroupCmd, addGroupMemberCmd, removeGroupMemberCmd} {
+		command.Flags().IntVarP(&GroupID, "group-id", "i", 0, "ID of the group")
+		command.Flags().StringVarP(&GroupName, "group", "g", "", "Name of the group. Ignored if --group-id is given")
+	}
+
+	addGroupCmd.Flags().StringVarP(&GroupName, "group", "g", "", "Name of the group")
+	addGroupCmd.MarkFlagRequired("group")
+	addGroupCmd.Flags().StringVarP(&Description, "description", "d", "", "Description of the group")
+	addGroupCmd.Flags().StringVarP(&Location, "location", "l", "", "Location of the group in the vault hierarchy. Defaults to the root")
+
+	for _, command := range []*cobra.Command{addGroupMemberCmd, removeGroupMemberCmd} {
+		command.Flags().StringVarP(&MemberName, "member", "m", "", "Username of the member")
+		command.MarkFlagRequired("member")
+	}
+	addGroupMemberCmd.Flags().StringVar(&DomainName, "domain", "", "Domain of a directory user, e.g. cyberark.local")
+
+	groupsCmd.AddCommand(listGroupsCmd)
+	groupsCmd.AddCommand(getGroupCmd)
+	groupsCmd.AddCommand(addGroupCmd)
+	groupsCmd.AddCommand(deleteGroupCmd)
+	groupsCmd.AddCommand(addGroupMemberCmd)
+	groupsCmd.AddCommand(removeGroupMemberCmd)
+	rootCmd.AddCommand(groupsCmd)
+}
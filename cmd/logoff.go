@@ -2,9 +2,7 @@ package cmd
 
 import (
 	"fmt"
-	"log"
 
-	pasapi "github.com/infamousjoeg/cybr-cli/pkg/cybr/api"
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/identity"
 	"github.com/spf13/cobra"
 )
@@ -18,14 +16,14 @@ var logoffCmd = &cobra.Command{
 	$ cybr logoff`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Get config file written to local file system
-		client, err := pasapi.GetConfigWithLogger(getLogger())
+		client, err := getClient()
 		if err != nil {
-			log.Fatalf("Failed to read configuration file. %s", err)
+			fatalf("Failed to read configuration file. %s", err)
 		}
 		// Remove the config file written to local file system
 		err = client.RemoveConfig()
 		if err != nil {
-			log.Fatalf("Failed to remove configuration file. %s", err)
+			fatalf("Failed to remove configuration file. %s", err)
 		}
 		// Logoff the PAS REST API
 		if client.TenantID != "" {
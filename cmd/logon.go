@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"log"
 	"os"
 	"strconv"
 	"strings"
@@ -88,7 +87,8 @@ func logonToPAS(c pasapi.Client, username, password string, nonInteractive, conc
 			return fmt.Errorf("Failed to respond to challenge. Possible timeout occurred. %s", err)
 		}
 	}
-	// Set client config
+	// Set client config. The username is saved to logon again when the session token expires
+	c.Username = username
 	err = c.SetConfig()
 	if err != nil {
 		return fmt.Errorf("Failed to create configuration file. %s", err)
@@ -144,18 +144,23 @@ var logonCmd = &cobra.Command{
 			BaseURL:     BaseURL,
 			AuthType:    AuthenticationType,
 			InsecureTLS: InsecureTLS,
+			Profile:     pasapi.ResolveProfile(Profile),
 		}
+		if err := pasapi.ValidateProfileName(c.Profile); err != nil {
+			fatalf("%s", err)
+		}
+		c.SetTransport(getTransport(c.InsecureTLS))
 
 		// Check if auth type is "identity" and get TenantID if true
 		if c.AuthType == "identity" {
 			platformDiscovery, err := ispss.PlatformDiscovery(c.BaseURL)
 			if err != nil {
-				log.Fatalf("Failed to get platform discovery. %s", err)
+				fatalf("Failed to get platform discovery. %s", err)
 			}
 			c.TenantID, err = util.GetSubDomain(platformDiscovery.IdentityUserPortal.API)
 			c.BaseURL = platformDiscovery.Pcloud.API
 			if err != nil {
-				log.Fatalf("Failed to get tenant ID. %s", err)
+				fatalf("Failed to get tenant ID. %s", err)
 			}
 			if Verbose {
 				prettyprint.PrintColor("cyan", fmt.Sprintf("Tenant ID: %s", c.TenantID))
@@ -163,21 +168,28 @@ var logonCmd = &cobra.Command{
 			}
 		}
 
-		// Get password from environment variable PAS_PASSWORD
-		Password := os.Getenv("PAS_PASSWORD")
+		// Get password from --password, or from environment variable PAS_PASSWORD
+		password := os.Getenv("PAS_PASSWORD")
+		if Password != "" {
+			var err error
+			password, err = readSecret("password", Password)
+			if err != nil {
+				fatalf("%s", err)
+			}
+		}
 
 		// Handle authentication depending on auth type
 		if c.AuthType != "identity" {
-			err := logonToPAS(c, Username, Password, NonInteractive, ConcurrentSession)
+			err := logonToPAS(c, Username, password, NonInteractive, ConcurrentSession)
 			if err != nil {
-				log.Fatalf("%s", err)
+				fatalf("%s", err)
 			}
 			// Handle Identity authentication
 		} else {
 			// Start authentication
 			startResponse, err := startAuthIdentity(c, Username)
 			if err != nil {
-				log.Fatalf("%s", err)
+				fatalf("%s", err)
 			}
 			if Verbose {
 				prettyprint.PrintColor("cyan", fmt.Sprintf("Start Authentication Response: %+v", startResponse))
@@ -196,7 +208,7 @@ var logonCmd = &cobra.Command{
 					// Get password from Stdin
 					Password, err = util.ReadPassword()
 					if err != nil {
-						log.Fatalf("An error occurred trying to read password from Stdin. Exiting")
+						fatalf("An error occurred trying to read password from Stdin. Exiting")
 					}
 					// Create AdvanceAuthentication struct
 					AnswerChallenge.SessionID = startResponse.Result.SessionID
@@ -214,7 +226,7 @@ var logonCmd = &cobra.Command{
 					// Answer challenge
 					advanceResponse, err = identity.AdvanceAuthentication(c, AnswerChallenge)
 					if err != nil {
-						log.Fatalf("%s %s", identityFailedAnswer, err)
+						fatalf("%s %s", identityFailedAnswer, err)
 					}
 					if Verbose {
 						prettyprint.PrintColor("cyan", fmt.Sprintf("%s %+v", advanceResponseKey, advanceResponse))
@@ -224,7 +236,7 @@ var logonCmd = &cobra.Command{
 						break
 					}
 					if !advanceResponse.Success {
-						log.Fatalf("%s %s", identityUnsuccessfulResponse, *advanceResponse.Message)
+						fatalf("%s %s", identityUnsuccessfulResponse, *advanceResponse.Message)
 					}
 
 					attempts++
@@ -241,7 +253,7 @@ var logonCmd = &cobra.Command{
 				input_loop:
 					strInput, err := util.ReadInput("Select a challenge")
 					if err != nil {
-						log.Fatalf("An error occurred trying to read input from Stdin. Exiting")
+						fatalf("An error occurred trying to read input from Stdin. Exiting")
 					}
 					intInput, err := strconv.Atoi(strInput)
 					if err != nil || intInput < 1 || intInput > len(startResponse.Result.Challenges[1].Mechanisms) {
@@ -276,7 +288,7 @@ var logonCmd = &cobra.Command{
 							// Answer challenge
 							challengeResponse, err := identity.AdvanceAuthentication(c, StartOobChallenge)
 							if err != nil {
-								log.Fatalf("%s %s", identityFailedAnswer, err)
+								fatalf("%s %s", identityFailedAnswer, err)
 							}
 							if Verbose {
 								prettyprint.PrintColor("cyan", fmt.Sprintf("%s %+v", advanceResponseKey, challengeResponse))
@@ -286,7 +298,7 @@ var logonCmd = &cobra.Command{
 								break
 							}
 							if !challengeResponse.Success {
-								log.Fatalf("%s %s", identityUnsuccessfulResponse, *advanceResponse.Message)
+								fatalf("%s %s", identityUnsuccessfulResponse, *advanceResponse.Message)
 							}
 
 							PollOOBChallenge.SessionID = startResponse.Result.SessionID
@@ -312,7 +324,7 @@ var logonCmd = &cobra.Command{
 								// Answer challenge
 								answerOOBResponse, err := identity.AdvanceAuthentication(c, AnswerOOBChallenge)
 								if err != nil {
-									log.Fatalf("%s %s", identityFailedAnswer, err)
+									fatalf("%s %s", identityFailedAnswer, err)
 								}
 								if Verbose {
 									prettyprint.PrintColor("cyan", fmt.Sprintf("%s %+v", advanceResponseKey, answerOOBResponse))
@@ -322,9 +334,9 @@ var logonCmd = &cobra.Command{
 									break
 								}
 								if advanceResponse.Message != nil {
-									log.Fatalf("%s %s", identityUnsuccessfulResponse, *advanceResponse.Message)
+									fatalf("%s %s", identityUnsuccessfulResponse, *advanceResponse.Message)
 								} else {
-									log.Fatalf("Identity returned unsuccessful response, but the message is unavailable.")
+									fatalf("Identity returned unsuccessful response, but the message is unavailable.")
 								}
 							}
 
@@ -332,7 +344,7 @@ var logonCmd = &cobra.Command{
 								c.SessionToken = fmt.Sprintf("%s %s", bearer, code)
 								break
 							} else {
-								log.Fatalf("Failed to get OOB code. Exiting")
+								fatalf("Failed to get OOB code. Exiting")
 								os.Exit(1)
 							}
 						}
@@ -342,18 +354,18 @@ var logonCmd = &cobra.Command{
 
 			// Maximum attempts reached
 			if c.SessionToken == "" {
-				log.Fatalf("Failed to get non-empty token after %d attempts. Exiting", maxAttempts)
+				fatalf("Failed to get non-empty token after %d attempts. Exiting", maxAttempts)
 			}
 
 			// Set client config
 			err = c.SetConfig()
 			if err != nil {
-				log.Fatalf("Failed to create configuration file. %s", err)
+				fatalf("Failed to create configuration file. %s", err)
 			}
 		}
 
 		// Logon success message
-		prettyprint.PrintColor("green", fmt.Sprintf("\nSuccessfully logged onto PAS as user %s.", Username))
+		prettyprint.PrintColor("green", fmt.Sprintf("\nSuccessfully logged onto PAS as user %s (profile: %s).", Username, c.Profile))
 	},
 }
 
@@ -367,7 +379,7 @@ func init() {
 	logonCmd.Flags().StringVarP(&BaseURL, "base-url", "b", "", "Base URL to send Logon request to [https://pvwa.example.com]")
 	logonCmd.MarkFlagRequired("base-url")
 	logonCmd.Flags().BoolVar(&NonInteractive, "non-interactive", false, "If detected, will retrieve the password from the PAS_PASSWORD environment variable")
-	logonCmd.Flags().StringVarP(&Password, "password", "p", "", "Password to logon to PAS REST API, only supported when using --non-interactive flag")
+	logonCmd.Flags().StringVarP(&Password, "password", "p", "", "Password to logon to PAS REST API, @file, - for stdin or env:VAR. Only supported when using --non-interactive flag")
 	logonCmd.Flags().BoolVar(&ConcurrentSession, "concurrent", false, "If detected, will create a concurrent session to the PAS API")
 
 	// Add 'logon' command to root command
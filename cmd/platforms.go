@@ -1,10 +1,14 @@
 package cmd
 
 import (
-	"log"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 
 	pasapi "github.com/infamousjoeg/cybr-cli/pkg/cybr/api"
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/queries"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/requests"
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/prettyprint"
 	"github.com/spf13/cobra"
 )
@@ -18,6 +22,9 @@ var (
 
 	// PlatformName specifies the name of the platform to list
 	PlatformName string
+
+	// PlatformKind is the type of a platform: target, dependent, group or rotational-group
+	PlatformKind string
 )
 
 var platformsCmd = &cobra.Command{
@@ -27,7 +34,9 @@ var platformsCmd = &cobra.Command{
 	
 	Example Usage:
 	List all platforms: $ cybr platforms list
-	Get a Platforms details: $ cybr platforms get -i WinDomain`,
+	Get a Platforms details: $ cybr platforms get -i WinDomain
+	Export a platform: $ cybr platforms export -p WinDomain -o WinDomain.zip
+	Import a platform: $ cybr platforms import -f WinDomain.zip`,
 	Aliases: []string{"platform"},
 }
 
@@ -37,11 +46,12 @@ var listPlatformsCmd = &cobra.Command{
 	Long: `List all platforms.
 	
 	Example Usage:
-	$ cybr platforms list`,
+	$ cybr platforms list
+	$ cybr platforms list --all`,
 	Run: func(cmd *cobra.Command, args []string) {
-		client, err := pasapi.GetConfigWithLogger(getLogger())
+		client, err := getClient()
 		if err != nil {
-			log.Fatalf("Failed to read configuration file. %s", err)
+			fatalf("Failed to read configuration file. %s", err)
 			return
 		}
 
@@ -51,13 +61,22 @@ var listPlatformsCmd = &cobra.Command{
 			PlatformName: PlatformName,
 		}
 
+		if All {
+			platforms := client.IteratePlatforms(query)
+			err = printAll(platforms, func() interface{} { return platforms.Platform() }, "Platforms", "Total", prettyprint.PlatformsTable)
+			if err != nil {
+				fatalf("Failed to retrieve a list of all platforms. %s", err)
+			}
+			return
+		}
+
 		apps, err := client.ListPlatforms(query)
 		if err != nil {
-			log.Fatalf("Failed to retrieve a list of all platforms. %s", err)
+			fatalf("Failed to retrieve a list of all platforms. %s", err)
 			return
 		}
 
-		prettyprint.PrintJSON(apps)
+		printOutput(apps, prettyprint.PlatformsTable)
 	},
 }
 
@@ -69,19 +88,212 @@ var getPlatformsCmd = &cobra.Command{
 	Example Usage:
 	$ cybr platforms get -i WinDomain`,
 	Run: func(cmd *cobra.Command, args []string) {
-		client, err := pasapi.GetConfigWithLogger(getLogger())
+		client, err := getClient()
 		if err != nil {
-			log.Fatalf("Failed to read configuration file. %s", err)
+			fatalf("Failed to read configuration file. %s", err)
 			return
 		}
 
 		apps, err := client.GetPlatform(PlatformID)
 		if err != nil {
-			log.Fatalf("Failed to retrieve account '%s'. %s", PlatformID, err)
+			fatalf("Failed to retrieve account '%s'. %s", PlatformID, err)
+			return
+		}
+
+		printOutput(apps, prettyprint.PlatformTable)
+	},
+}
+
+var exportPlatformCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export a platform package",
+	Long: `Export a platform as a zip package that can be imported into another vault. Without
+	--output-file the package is written to the current directory using the file name returned by PAS.
+	Use '--output-file -' to write the package to stdout.
+
+	Example Usage:
+	$ cybr platforms export -p WinDomain
+	$ cybr platforms export -p WinDomain -o WinDomain.zip`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		if DownloadFile == "-" {
+			_, err = client.ExportPlatform(PlatformID, os.Stdout)
+			if err != nil {
+				fatalf("%s", err)
+			}
+			return
+		}
+
+		path, err := downloadToFile(DownloadFile, PlatformID+".zip", func(w io.Writer) (string, error) {
+			return client.ExportPlatform(PlatformID, w)
+		})
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		fmt.Fprintf(os.Stderr, "Successfully exported platform '%s' to '%s'.\n", PlatformID, path)
+	},
+}
+
+var importPlatformCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import a platform package",
+	Long: `Import a platform zip package, such as one exported with 'cybr platforms export'.
+
+	Example Usage:
+	$ cybr platforms import -f WinDomain.zip`,
+	Run: func(cmd *cobra.Command, args []string) {
+		file, err := os.Open(ImportFile)
+		if err != nil {
+			fatalf("Failed to read platform package '%s'. %s", ImportFile, err)
+			return
+		}
+		defer file.Close()
+
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		platform, err := client.ImportPlatform(filepath.Base(ImportFile), file)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		fmt.Printf("Successfully imported platform '%s'\n", platform.PlatformID)
+	},
+}
+
+var duplicatePlatformCmd = &cobra.Command{
+	Use:   "duplicate",
+	Short: "Duplicate a platform",
+	Long: `Create a platform from an existing target, dependent, group or rotational group platform.
+
+	Example Usage:
+	$ cybr platforms duplicate -p WinDomain -n "Windows Domain App1" -d "Domain accounts of App1"
+	$ cybr platforms duplicate -t group -p SampleGroup -n "App1 Group"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		platform, err := client.FindPlatform(PlatformKind, PlatformID)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		duplicate, err := client.DuplicatePlatform(PlatformKind, platform.ID, requests.DuplicatePlatform{
+			Name:        Name,
+			Description: Description,
+		})
+		if err != nil {
+			fatalf("%s", err)
 			return
 		}
 
-		prettyprint.PrintJSON(apps)
+		printOutput(duplicate, prettyprint.PlatformSummaryTable)
+	},
+}
+
+var activatePlatformCmd = &cobra.Command{
+	Use:   "activate",
+	Short: "Activate a target platform",
+	Long: `Activate a target platform so its accounts are managed.
+
+	Example Usage:
+	$ cybr platforms activate -p WinDomain`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		platform, err := client.FindPlatform(pasapi.PlatformTypeTarget, PlatformID)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		err = client.ActivatePlatform(platform.ID)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		fmt.Printf("Successfully activated platform '%s'\n", platform.PlatformID)
+	},
+}
+
+var deactivatePlatformCmd = &cobra.Command{
+	Use:   "deactivate",
+	Short: "Deactivate a target platform",
+	Long: `Deactivate a target platform. The accounts of an inactive platform are not managed.
+
+	Example Usage:
+	$ cybr platforms deactivate -p WinDomain`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		platform, err := client.FindPlatform(pasapi.PlatformTypeTarget, PlatformID)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		err = client.DeactivatePlatform(platform.ID)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		fmt.Printf("Successfully deactivated platform '%s'\n", platform.PlatformID)
+	},
+}
+
+var deletePlatformCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete a platform",
+	Long: `Delete a target, dependent, group or rotational group platform. Platforms with accounts cannot be deleted.
+
+	Example Usage:
+	$ cybr platforms delete -p "Windows Domain App1"
+	$ cybr platforms delete -t rotational-group -p App1Rotation`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		platform, err := client.FindPlatform(PlatformKind, PlatformID)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		err = client.DeletePlatform(PlatformKind, platform.ID)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		fmt.Printf("Successfully deleted platform '%s'\n", platform.PlatformID)
 	},
 }
 
@@ -90,14 +302,40 @@ func init() {
 	listPlatformsCmd.Flags().BoolVarP(&Active, "active", "a", false, "Filter according to whether the platform is active or not.")
 	listPlatformsCmd.Flags().StringVarP(&PlatformType, "platform-type", "t", "", "Filter according to the platform type. Valid values: Group or Regular")
 	listPlatformsCmd.Flags().StringVarP(&PlatformName, "platform-name", "n", "", "Filter according to the platform name. Partial matches are supported.")
+	listPlatformsCmd.Flags().BoolVar(&All, "all", false, "Retrieve all pages of platforms")
 
 	// Getting a platform
 	getPlatformsCmd.Flags().StringVarP(&PlatformID, "platform-id", "i", "", "Platform ID to list from")
 	getPlatformsCmd.MarkFlagRequired("platform-id")
 
+	// Exporting and importing platforms
+	exportPlatformCmd.Flags().StringVarP(&PlatformID, "platform-id", "p", "", "Platform ID to export")
+	exportPlatformCmd.MarkFlagRequired("platform-id")
+	exportPlatformCmd.Flags().StringVarP(&DownloadFile, "output-file", "o", "", "File the platform package is written to, or '-' for stdout. Defaults to the file name returned by PAS")
+	importPlatformCmd.Flags().StringVarP(&ImportFile, "file", "f", "", "Platform package zip file to import")
+	importPlatformCmd.MarkFlagRequired("file")
+
+	// Duplicating, activating, deactivating and deleting platforms
+	for _, command := range []*cobra.Command{duplicatePlatformCmd, activatePlatformCmd, deactivatePlatformCmd, deletePlatformCmd} {
+		command.Flags().StringVarP(&PlatformID, "platform-id", "p", "", "Platform ID, or numeric ID, of the platform")
+		command.MarkFlagRequired("platform-id")
+	}
+	for _, command := range []*cobra.Command{duplicatePlatformCmd, deletePlatformCmd} {
+		command.Flags().StringVarP(&PlatformKind, "type", "t", pasapi.PlatformTypeTarget, "Type of the platform. Valid values: target, dependent, group or rotational-group")
+	}
+	duplicatePlatformCmd.Flags().StringVarP(&Name, "name", "n", "", "Name of the new platform")
+	duplicatePlatformCmd.MarkFlagRequired("name")
+	duplicatePlatformCmd.Flags().StringVarP(&Description, "description", "d", "", "Description of the new platform")
+
 	// Add cmd to platform cmd
 	platformsCmd.AddCommand(listPlatformsCmd)
 	platformsCmd.AddCommand(getPlatformsCmd)
+	platformsCmd.AddCommand(exportPlatformCmd)
+	platformsCmd.AddCommand(importPlatformCmd)
+	platformsCmd.AddCommand(duplicatePlatformCmd)
+	platformsCmd.AddCommand(activatePlatformCmd)
+	platformsCmd.AddCommand(deactivatePlatformCmd)
+	platformsCmd.AddCommand(deletePlatformCmd)
 
 	// Add platforms cmd to root
 	rootCmd.AddCommand(platformsCmd)
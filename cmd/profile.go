@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+
+	pasapi "github.com/infamousjoeg/cybr-cli/pkg/cybr/api"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/prettyprint"
+	"github.com/spf13/cobra"
+)
+
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Connection profile actions",
+	Long: `Manage the connection profiles created by 'cybr logon'.
+	Each profile stores its own base URL, authentication type, tenant ID,
+	TLS settings and session token.
+
+	The profile used by a command is selected with the --profile flag,
+	then the ` + pasapi.ProfileEnvKey + ` environment variable and lastly the
+	profile selected with 'cybr profile use'.
+	
+	Example Usage:
+	Logon to a new profile: $ cybr logon --profile prod -u $USERNAME -a cyberark -b https://pvwa.example.com
+	List all profiles: $ cybr profile list
+	Switch the active profile: $ cybr profile use prod`,
+	Aliases: []string{"profiles"},
+}
+
+var listProfilesCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all profiles",
+	Long: `List all connection profiles stored on the local file system.
+	
+	Example Usage:
+	$ cybr profile list`,
+	Run: func(cmd *cobra.Command, args []string) {
+		profiles, err := pasapi.ListProfiles()
+		if err != nil {
+			fatalf("Failed to list profiles. %s", err)
+		}
+
+		summaries := []pasapi.ProfileSummary{}
+		for _, profile := range profiles {
+			summary, err := pasapi.GetProfileSummary(profile)
+			if err != nil {
+				fatalf("Failed to read profile '%s'. %s", profile, err)
+			}
+			summaries = append(summaries, summary)
+		}
+
+		printOutput(summaries, prettyprint.ProfilesTable)
+	},
+}
+
+var useProfileCmd = &cobra.Command{
+	Use:   "use [profile]",
+	Short: "Set the active profile",
+	Long: `Set the profile used when neither --profile nor ` + pasapi.ProfileEnvKey + ` are provided.
+	
+	Example Usage:
+	$ cybr profile use prod`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := args[0]
+		if !pasapi.ProfileExists(profile) {
+			fatalf("Profile '%s' does not exist. Create it with 'cybr logon --profile %s'", profile, profile)
+		}
+
+		err := pasapi.SetActiveProfile(profile)
+		if err != nil {
+			fatalf("Failed to set active profile. %s", err)
+		}
+
+		fmt.Printf("Successfully set active profile to '%s'\n", profile)
+	},
+}
+
+var showProfileCmd = &cobra.Command{
+	Use:   "show [profile]",
+	Short: "Show a profile",
+	Long: `Show the details of a profile. The session token is never displayed.
+	If no profile is provided the selected profile is shown.
+	
+	Example Usage:
+	$ cybr profile show
+	$ cybr profile show prod`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := pasapi.ResolveProfile(Profile)
+		if len(args) == 1 {
+			profile = args[0]
+		}
+
+		summary, err := pasapi.GetProfileSummary(profile)
+		if err != nil {
+			fatalf("Failed to read profile '%s'. %s", profile, err)
+		}
+
+		printOutput(summary, prettyprint.ProfilesTable)
+	},
+}
+
+var deleteProfileCmd = &cobra.Command{
+	Use:   "delete [profile]",
+	Short: "Delete a profile",
+	Long: `Delete a profile from the local file system. The session is not logged off,
+	use 'cybr logoff --profile NAME' to end the session and remove the profile.
+	
+	Example Usage:
+	$ cybr profile delete dev`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := args[0]
+		err := pasapi.RemoveProfile(profile)
+		if err != nil {
+			fatalf("Failed to delete profile '%s'. %s", profile, err)
+		}
+
+		fmt.Printf("Successfully deleted profile '%s'\n", profile)
+	},
+}
+
+func init() {
+	profileCmd.AddCommand(listProfilesCmd)
+	profileCmd.AddCommand(useProfileCmd)
+	profileCmd.AddCommand(showProfileCmd)
+	profileCmd.AddCommand(deleteProfileCmd)
+	rootCmd.AddCommand(profileCmd)
+}
@@ -0,0 +1,249 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/queries"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/requests"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/prettyprint"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// RequestID is the ID of an access request
+	RequestID string
+
+	// MultipleAccess requests access to the account for a time range instead of a single use
+	MultipleAccess bool
+
+	// OnlyWaiting lists only access requests waiting for confirmation
+	OnlyWaiting bool
+
+	// Expired lists expired access requests
+	Expired bool
+
+	// WaitForApproval requests access to an account and waits until the request is confirmed
+	WaitForApproval bool
+
+	// WaitTimeout is the maximum time to wait for an access request to be confirmed
+	WaitTimeout time.Duration
+)
+
+var accessRequestsCmd = &cobra.Command{
+	Use:   "requests",
+	Short: "Access request actions for PAS REST API",
+	Long: `All access request actions for accounts protected by dual control that can be taken via PAS REST API.
+
+	Example Usage:
+	Request access to an account: $ cybr requests create -i 24_1 --reason "Maintenance"
+	List your access requests: $ cybr requests list-mine
+	List access requests waiting for your confirmation: $ cybr requests list-incoming --only-waiting
+	Confirm an access request: $ cybr requests confirm -i SafeName_24_1 --reason "Approved"`,
+	Aliases: []string{"request"},
+}
+
+var createAccessRequestCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Request access to an account",
+	Long: `Request access to an account protected by dual control. With --multiple-access the
+	account can be used repeatedly between --from and --to once the request is confirmed.
+
+	Example Usage:
+	$ cybr requests create -i 24_1 --reason "Maintenance"
+	$ cybr requests create -i 24_1 --reason "Upgrade" --multiple-access --from 2021-03-01 --to 2021-03-02`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		fromTime, toTime, err := parseTimeRange()
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		request := requests.CreateAccessRequest{
+			AccountID:              AccountID,
+			Reason:                 Reason,
+			TicketingSystemName:    TicketingSystemName,
+			TicketID:               TicketID,
+			MultipleAccessRequired: MultipleAccess,
+			FromDate:               fromTime,
+			ToDate:                 toTime,
+		}
+
+		response, err := client.CreateAccessRequest(request)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		printOutput(response, prettyprint.AccessRequestTable)
+	},
+}
+
+var listMyAccessRequestsCmd = &cobra.Command{
+	Use:   "list-mine",
+	Short: "List your access requests",
+	Long: `List the access requests of the logged on user.
+
+	Example Usage:
+	$ cybr requests list-mine
+	$ cybr requests list-mine --only-waiting --output table`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		query := &queries.ListAccessRequests{
+			OnlyWaiting: OnlyWaiting,
+			Expired:     Expired,
+		}
+
+		response, err := client.ListMyAccessRequests(query)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		printOutput(response, prettyprint.MyAccessRequestsTable)
+	},
+}
+
+var listIncomingAccessRequestsCmd = &cobra.Command{
+	Use:   "list-incoming",
+	Short: "List access requests you can confirm",
+	Long: `List the access requests the logged on user can confirm or reject.
+
+	Example Usage:
+	$ cybr requests list-incoming
+	$ cybr requests list-incoming --only-waiting --output table`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		query := &queries.ListAccessRequests{
+			OnlyWaiting: OnlyWaiting,
+			Expired:     Expired,
+		}
+
+		response, err := client.ListIncomingAccessRequests(query)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		printOutput(response, prettyprint.IncomingAccessRequestsTable)
+	},
+}
+
+var confirmAccessRequestCmd = &cobra.Command{
+	Use:   "confirm",
+	Short: "Confirm an incoming access request",
+	Long: `Confirm an access request of another user.
+
+	Example Usage:
+	$ cybr requests confirm -i SafeName_24_1 --reason "Approved for change 1234"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		err = client.ConfirmAccessRequest(RequestID, requests.ReviewAccessRequest{Reason: Reason})
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		fmt.Printf("Successfully confirmed access request '%s'.\n", RequestID)
+	},
+}
+
+var rejectAccessRequestCmd = &cobra.Command{
+	Use:   "reject",
+	Short: "Reject an incoming access request",
+	Long: `Reject an access request of another user.
+
+	Example Usage:
+	$ cybr requests reject -i SafeName_24_1 --reason "No change ticket"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		err = client.RejectAccessRequest(RequestID, requests.ReviewAccessRequest{Reason: Reason})
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		fmt.Printf("Successfully rejected access request '%s'.\n", RequestID)
+	},
+}
+
+var deleteAccessRequestCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete one of your access requests",
+	Long: `Delete an access request of the logged on user.
+
+	Example Usage:
+	$ cybr requests delete -i SafeName_24_1`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		err = client.DeleteAccessRequest(RequestID)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		fmt.Printf("Successfully deleted access request '%s'.\n", RequestID)
+	},
+}
+
+func init() {
+	createAccessRequestCmd.Flags().StringVarP(&AccountID, "account-id", "i", "", "ID of the account to request access to")
+	createAccessRequestCmd.MarkFlagRequired("account-id")
+	createAccessRequestCmd.Flags().StringVarP(&Reason, "reason", "r", "", "Reason for accessing the account")
+	createAccessRequestCmd.Flags().StringVarP(&TicketingSystemName, "ticketing-system", "s", "", "Ticketing system name")
+	createAccessRequestCmd.Flags().StringVarP(&TicketID, "ticket-id", "t", "", "The ticket ID related to the ticketing system")
+	createAccessRequestCmd.Flags().BoolVarP(&MultipleAccess, "multiple-access", "m", false, "Request access for a time range instead of a single use")
+	createAccessRequestCmd.Flags().StringVar(&FromTime, "from", "", "Start of the access with --multiple-access, e.g. 2021-03-01 or 2021-03-01T08:00:00Z")
+	createAccessRequestCmd.Flags().StringVar(&ToTime, "to", "", "End of the access with --multiple-access, e.g. 2021-03-02 or 2021-03-01T18:00:00Z")
+
+	for _, command := range []*cobra.Command{listMyAccessRequestsCmd, listIncomingAccessRequestsCmd} {
+		command.Flags().BoolVarP(&OnlyWaiting, "only-waiting", "w", false, "List only access requests waiting for confirmation")
+		command.Flags().BoolVarP(&Expired, "expired", "e", false, "Include expired access requests")
+	}
+
+	for _, command := range []*cobra.Command{confirmAccessRequestCmd, rejectAccessRequestCmd, deleteAccessRequestCmd} {
+		command.Flags().StringVarP(&RequestID, "request-id", "i", "", "ID of the access request")
+		command.MarkFlagRequired("request-id")
+	}
+	confirmAccessRequestCmd.Flags().StringVarP(&Reason, "reason", "r", "", "Reason for confirming the access request")
+	rejectAccessRequestCmd.Flags().StringVarP(&Reason, "reason", "r", "", "Reason for rejecting the access request")
+
+	accessRequestsCmd.AddCommand(createAccessRequestCmd)
+	accessRequestsCmd.AddCommand(listMyAccessRequestsCmd)
+	accessRequestsCmd.AddCommand(listIncomingAccessRequestsCmd)
+	accessRequestsCmd.AddCommand(confirmAccessRequestCmd)
+	accessRequestsCmd.AddCommand(rejectAccessRequestCmd)
+	accessRequestsCmd.AddCommand(deleteAccessRequestCmd)
+	rootCmd.AddCommand(accessRequestsCmd)
+}
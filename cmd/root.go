@@ -3,7 +3,12 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
+	pasapi "github.com/infamousjoeg/cybr-cli/pkg/cybr/api"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/httpjson"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/prettyprint"
 	"github.com/infamousjoeg/cybr-cli/pkg/logger"
 	"github.com/spf13/cobra"
 )
@@ -13,6 +18,21 @@ var cfgFile string
 var (
 	// Verbose logging
 	Verbose bool
+
+	// Profile is the connection profile used for PAS REST API commands
+	Profile string
+
+	// RequestTimeout is the timeout of a single HTTP request
+	RequestTimeout time.Duration
+
+	// All retrieves every page of a list instead of a single page
+	All bool
+
+	// Output is the output format of commands returning resources
+	Output string
+
+	// Reauthenticate logs on again with PAS_PASSWORD when the session token expires
+	Reauthenticate bool
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -23,6 +43,11 @@ var rootCmd = &cobra.Command{
 wraps the PAS REST API and eases the user experience for automators
 and automation to easily interact with CyberArk Privileged Access
 Security.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Validate the output format before any request is sent
+		_, err := prettyprint.NewPrinter(Output)
+		return err
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -42,8 +67,57 @@ func getLogger() logger.CMD {
 	}
 }
 
+// getClient returns the PAS REST API client stored in the selected profile
+func getClient() (pasapi.Client, error) {
+	client, err := pasapi.GetProfileConfigWithLogger(Profile, getLogger())
+	client.SetTransport(getTransport(client.InsecureTLS))
+
+	// Logon again when the session token expires, so long-running scripts do not fail midway.
+	// Profiles saved by older versions do not contain the username
+	password := getReauthenticationPassword(client.Profile)
+	if err == nil && password != "" && client.Username != "" && client.AuthType != "identity" {
+		err = client.EnableReauthentication(password)
+	}
+	return client, err
+}
+
+// getReauthenticationPassword returns the password used to logon again as the user of profile.
+// PAS_PASSWORD is not tied to a profile, so it is only used with --reauthenticate
+func getReauthenticationPassword(profile string) string {
+	if password := os.Getenv(getProfilePasswordEnvKey(profile)); password != "" {
+		return password
+	}
+	if Reauthenticate {
+		return os.Getenv("PAS_PASSWORD")
+	}
+	return ""
+}
+
+// getProfilePasswordEnvKey returns the environment variable holding the password of profile,
+// e.g. PAS_PASSWORD_PROD_EU for the profile prod-eu
+func getProfilePasswordEnvKey(profile string) string {
+	key := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, profile)
+	return "PAS_PASSWORD_" + strings.ToUpper(key)
+}
+
+// getTransport returns an HTTP transport using the --timeout flag
+func getTransport(insecureTLS bool) *httpjson.Transport {
+	transport := httpjson.NewTransport(insecureTLS)
+	transport.Timeout = RequestTimeout
+	return transport
+}
+
 func init() {
 	rootCmd.PersistentFlags().BoolVar(&Verbose, "verbose", false, "To enable verbose logging")
+	rootCmd.PersistentFlags().DurationVar(&RequestTimeout, "timeout", httpjson.DefaultTimeout, "Timeout of each HTTP request, e.g. 30s or 2m")
+	rootCmd.PersistentFlags().StringVar(&Output, "output", prettyprint.FormatJSON, "Output format. One of: "+strings.Join(prettyprint.OutputFormats, ", "))
+	rootCmd.PersistentFlags().BoolVar(&Reauthenticate, "reauthenticate", false, "Logon again with the PAS_PASSWORD environment variable when the session token expires")
+	rootCmd.PersistentFlags().StringVar(&Profile, "profile", "", "Connection profile to use. Defaults to the "+pasapi.ProfileEnvKey+" environment variable or the profile selected with 'cybr profile use'")
 }
 
 // GetCMD returns the root cmd
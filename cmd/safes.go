@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"log"
 
 	pasapi "github.com/infamousjoeg/cybr-cli/pkg/cybr/api"
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/queries"
@@ -93,6 +92,8 @@ var (
 	Group string
 	// MemberType is the type of member being added to the safe
 	MemberType string
+	// ExpandMembers replaces group members with the users of the groups
+	ExpandMembers bool
 )
 
 var safesCmd = &cobra.Command{
@@ -117,19 +118,20 @@ var listSafesCmd = &cobra.Command{
 	Example Usage:
 	$ cybr safes list
 	$ cybr safes list -u UserName
-	$ cybr safes list -g GroupName`,
+	$ cybr safes list -g GroupName
+	$ cybr safes list --all`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Get config file written to local file system
-		client, err := pasapi.GetConfigWithLogger(getLogger())
+		client, err := getClient()
 		if err != nil {
-			log.Fatalf("Failed to read configuration file. %s", err)
+			fatalf("Failed to read configuration file. %s", err)
 			return
 		}
 
 		if User != "" {
 			safeNames, err := client.FilterSafes("memberType eq user AND includePredefinedUsers eq true", User)
 			if err != nil {
-				log.Fatalf("Failed to list safes for user %s. %s", User, err)
+				fatalf("Failed to list safes for user %s. %s", User, err)
 				return
 			}
 			for _, safeName := range safeNames {
@@ -139,7 +141,7 @@ var listSafesCmd = &cobra.Command{
 		} else if Group != "" {
 			safeNames, err := client.FilterSafes("memberType eq group AND includePredefinedUsers eq true", Group)
 			if err != nil {
-				log.Fatalf("Failed to list safes for group %s. %s", Group, err)
+				fatalf("Failed to list safes for group %s. %s", Group, err)
 				return
 			}
 			for _, safeName := range safeNames {
@@ -148,14 +150,23 @@ var listSafesCmd = &cobra.Command{
 			return
 		}
 
+		if All {
+			safes := client.IterateSafes(&queries.ListSafes{Limit: 1000})
+			err = printAll(safes, func() interface{} { return safes.Safe() }, "value", "count", prettyprint.SafesTable)
+			if err != nil {
+				fatalf("Failed to retrieve a list of all safes. %s", err)
+			}
+			return
+		}
+
 		// List All Safes
 		safes, err := client.ListSafes()
 		if err != nil {
-			log.Fatalf("Failed to retrieve a list of all safes. %s", err)
+			fatalf("Failed to retrieve a list of all safes. %s", err)
 			return
 		}
 		// Pretty print returned object as JSON blob
-		prettyprint.PrintJSON(safes)
+		printOutput(safes, prettyprint.SafesTable)
 	},
 }
 
@@ -168,13 +179,15 @@ var listMembersCmd = &cobra.Command{
 	Example Usage:
 	$ cybr safes list-members -s SafeName
 	$ cybr safes list-members -s SafeName -u UserName
-	$ cybr safes list-members -s SafeName -g GroupName`,
+	$ cybr safes list-members -s SafeName -g GroupName
+	$ cybr safes list-members -s SafeName --all
+	$ cybr safes list-members -s SafeName --members`,
 	Aliases: []string{"list-member"},
 	Run: func(cmd *cobra.Command, args []string) {
 		// Get config file written to local file system
-		client, err := pasapi.GetConfigWithLogger(getLogger())
+		client, err := getClient()
 		if err != nil {
-			log.Fatalf("Failed to read configuration file. %s", err)
+			fatalf("Failed to read configuration file. %s", err)
 			return
 		}
 
@@ -197,14 +210,39 @@ var listMembersCmd = &cobra.Command{
 			Filter: Filter,
 		}
 
+		if ExpandMembers {
+			if !cmd.Flags().Changed("limit") {
+				query.Limit = 1000
+			}
+			members, err := client.ListEffectiveSafeMembers(Safe, query)
+			if err != nil {
+				fatalf("Failed to retrieve a list of all safe members for %s. %s", Safe, err)
+				return
+			}
+			printOutput(members, prettyprint.EffectiveSafeMembersTable)
+			return
+		}
+
+		if All {
+			if !cmd.Flags().Changed("limit") {
+				query.Limit = 1000
+			}
+			members := client.IterateSafeMembers(Safe, query)
+			err = printAll(members, func() interface{} { return members.Member() }, "value", "count", prettyprint.SafeMembersTable)
+			if err != nil {
+				fatalf("Failed to retrieve a list of all safe members for %s. %s", Safe, err)
+			}
+			return
+		}
+
 		// Add a safe with the configuration options given via CLI subcommands
 		members, err := client.ListSafeMembers(Safe, query)
 		if err != nil {
-			log.Fatalf("Failed to retrieve a list of all safe members for %s. %s", Safe, err)
+			fatalf("Failed to retrieve a list of all safe members for %s. %s", Safe, err)
 			return
 		}
 		// Pretty print returned object as JSON blob
-		prettyprint.PrintJSON(members)
+		printOutput(members, prettyprint.SafeMembersTable)
 	},
 }
 
@@ -237,9 +275,9 @@ var addMembersCmd = &cobra.Command{
 	$ cybr safes add-member -s SafeName -m MemberName --role ApplicationIdentity --member-type group`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Get config file written to local file system
-		client, err := pasapi.GetConfigWithLogger(getLogger())
+		client, err := getClient()
 		if err != nil {
-			log.Fatalf("Failed to read configuration file. %s", err)
+			fatalf("Failed to read configuration file. %s", err)
 			return
 		}
 
@@ -249,7 +287,7 @@ var addMembersCmd = &cobra.Command{
 			RolePermissionsString = fmt.Sprintf("UseAccounts=%v,RetrieveAccounts=%v,ListAccounts=%v,AddAccounts=%v,UpdateAccountContent=%v,UpdateAccountProperties=%v,InitiateCPMAccountManagementOperations=%v,SpecifyNextAccountContent=%v,RenameAccounts=%v,DeleteAccounts=%v,UnlockAccounts=%v,ManageSafe=%v,ManageSafeMembers=%v,BackupSafe=%v,ViewAuditLog=%v,ViewSafeMembers=%v,AccessWithoutConfirmation=%v,CreateFolders=%v,DeleteFolders=%v,MoveAccountsAndFolders=%v,RequestsAuthorizationLevel1=%v,RequestsAuthorizationLevel2=%v", UseAccounts, RetrieveAccounts, ListAccounts, AddAccounts, UpdateAccountContent, UpdateAccountProperties, InitiateCPMAccountManagementOperations, SpecifyNextAccountContent, RenameAccounts, DeleteAccounts, UnlockAccounts, ManageSafe, ManageSafeMembers, BackupSafe, ViewAuditLog, ViewSafeMembers, AccessWithoutConfirmation, CreateFolders, DeleteFolders, MoveAccountsAndFolders, RequestsAuthorizationLevel1, RequestsAuthorizationLevel2)
 			RolePermissions, err = keyValueStringToMap(RolePermissionsString)
 			if err != nil {
-				log.Fatalf("Failed to parse role permissions. %s", err)
+				fatalf("Failed to parse role permissions. %s", err)
 				return
 			}
 		}
@@ -258,7 +296,7 @@ var addMembersCmd = &cobra.Command{
 		if Role != "" {
 			RolePermissions, err = pasapi.GetRolePermissions(Role)
 			if err != nil {
-				log.Fatalf("Failed to load safe permissions for role defined. %s", err)
+				fatalf("Failed to load safe permissions for role defined. %s", err)
 				return
 			}
 		}
@@ -274,7 +312,7 @@ var addMembersCmd = &cobra.Command{
 		// Add a safe with the configuration options given via CLI subcommands
 		err = client.AddSafeMember(Safe, newMember)
 		if err != nil {
-			log.Fatalf("Failed to add member '%s' to safe '%s'. %s", MemberName, Safe, err)
+			fatalf("Failed to add member '%s' to safe '%s'. %s", MemberName, Safe, err)
 			return
 		}
 		fmt.Printf("Successfully added member '%s' to safe '%s'\n", MemberName, Safe)
@@ -291,16 +329,16 @@ var removeMembersCmd = &cobra.Command{
 	$ cybr safes remove-member -s SafeName -m MemberName`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Get config file written to local file system
-		client, err := pasapi.GetConfigWithLogger(getLogger())
+		client, err := getClient()
 		if err != nil {
-			log.Fatalf("Failed to read configuration file. %s", err)
+			fatalf("Failed to read configuration file. %s", err)
 			return
 		}
 
 		// Add a safe with the configuration options given via CLI subcommands
 		err = client.RemoveSafeMember(Safe, MemberName)
 		if err != nil {
-			log.Fatalf("Failed to add member '%s' to safe '%s'. %s", MemberName, Safe, err)
+			fatalf("Failed to add member '%s' to safe '%s'. %s", MemberName, Safe, err)
 			return
 		}
 
@@ -318,9 +356,9 @@ var addSafeCmd = &cobra.Command{
 	$ cybr safes add -s SafeName -d Description --cpm ManagingCPM --days 0`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Get config file written to local file system
-		client, err := pasapi.GetConfigWithLogger(getLogger())
+		client, err := getClient()
 		if err != nil {
-			log.Fatalf("Failed to read configuration file. %s", err)
+			fatalf("Failed to read configuration file. %s", err)
 			return
 		}
 		// Build body of the request
@@ -329,14 +367,14 @@ var addSafeCmd = &cobra.Command{
 			Description:           Description,
 			OLACEnabled:           OLACEnabled,
 			ManagingCPM:           ManagingCPM,
-			NumberOfDaysRetention: NumberOfDaysRetention,
+			NumberOfDaysRetention: &NumberOfDaysRetention,
 			AutoPurgeEnabled:      AutoPurgeEnabled,
 			SafeLocation:          SafeLocation,
 		}
 		// Add the safe with config declared above
 		err = client.AddSafe(body)
 		if err != nil {
-			log.Fatalf("Failed to add the safe named %s. %s", SafeName, err)
+			fatalf("Failed to add the safe named %s. %s", SafeName, err)
 			return
 		}
 		fmt.Printf("Successfully added safe %s.\n", SafeName)
@@ -354,15 +392,15 @@ var deleteSafeCmd = &cobra.Command{
 	$ cybr safes delete -s SafeName`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Get config file written to local file system
-		client, err := pasapi.GetConfigWithLogger(getLogger())
+		client, err := getClient()
 		if err != nil {
-			log.Fatalf("Failed to read configuration file. %s", err)
+			fatalf("Failed to read configuration file. %s", err)
 			return
 		}
 		// Delete the safe
 		err = client.DeleteSafe(SafeName)
 		if err != nil {
-			log.Fatalf("Failed to delete the safe named %s. %s", SafeName, err)
+			fatalf("Failed to delete the safe named %s. %s", SafeName, err)
 			return
 		}
 
@@ -379,9 +417,9 @@ var updateSafeCmd = &cobra.Command{
 	$ cybr safes update -t TargetSafeName -s NewSafeName -d NewDesc`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Get config file written to local file system
-		client, err := pasapi.GetConfigWithLogger(getLogger())
+		client, err := getClient()
 		if err != nil {
-			log.Fatalf("Failed to read configuration file. %s", err)
+			fatalf("Failed to read configuration file. %s", err)
 			return
 		}
 		// Build body of the request
@@ -394,24 +432,27 @@ var updateSafeCmd = &cobra.Command{
 		// Update the safe
 		response, err := client.UpdateSafe(TargetSafeName, body)
 		if err != nil {
-			log.Fatalf("Failed to update the safe named %s. %s", TargetSafeName, err)
+			fatalf("Failed to update the safe named %s. %s", TargetSafeName, err)
 			return
 		}
 		// Pretty print returned object as JSON blob
-		prettyprint.PrintJSON(response)
+		printOutput(response, prettyprint.SafesTable)
 	},
 }
 
 func init() {
 	listSafesCmd.Flags().StringVarP(&User, "user", "u", "", "Username to filter request on")
 	listSafesCmd.Flags().StringVarP(&Group, "group", "g", "", "Group to filter request on")
+	listSafesCmd.Flags().BoolVar(&All, "all", false, "Retrieve all pages of safes")
 
 	listMembersCmd.Flags().StringVarP(&Safe, "safe", "s", "", "Safe name to filter request on")
 	listMembersCmd.Flags().StringVarP(&User, "user", "u", "", "Username to filter request on")
 	listMembersCmd.Flags().StringVarP(&Group, "group", "g", "", "Group to filter request on")
+	listMembersCmd.Flags().BoolVarP(&ExpandMembers, "members", "m", false, "Replace group members with the users of the groups. Every page of safe members is read")
 	listMembersCmd.Flags().StringVarP(&Sort, "sort", "r", "", "Property or properties by which to sort returned safes, followed by asc (default) or desc to control sort direction. Separate multiple properties with commas, up to a maximum of three properties")
 	listMembersCmd.Flags().IntVarP(&Offset, "offset", "o", 0, "Offset of the first safe that is returned in the collection of results")
 	listMembersCmd.Flags().IntVarP(&Limit, "limit", "l", 0, "Maximum number of returned safes. If not specified, the default value is 50. The maximum number that can be specified is 1000")
+	listMembersCmd.Flags().BoolVar(&All, "all", false, "Retrieve all pages of safe members starting at offset")
 	listMembersCmd.MarkFlagRequired("safe")
 
 	addSafeCmd.Flags().StringVarP(&SafeName, "safe", "s", "", "Safe name to create")
@@ -0,0 +1,418 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/queries"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/prettyprint"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// SessionID is the ID of a live PSM session
+	SessionID string
+
+	// RecordingID is the ID of a recorded PSM session
+	RecordingID string
+
+	// FromTime is the start of the time range, as a date, RFC3339 time, unix time or duration before now
+	FromTime string
+
+	// ToTime is the end of the time range, as a date, RFC3339 time, unix time or duration before now
+	ToTime string
+
+	// Activities filters sessions by the activities performed during them
+	Activities string
+
+	// DownloadFile is the file a download is written to
+	DownloadFile string
+)
+
+var sessionsCmd = &cobra.Command{
+	Use:   "sessions",
+	Short: "Privileged Session Manager (PSM) actions for PAS REST API",
+	Long: `All live session and recording actions that can be taken via PAS REST API.
+
+	Example Usage:
+	List live sessions: $ cybr sessions live list
+	Terminate a live session: $ cybr sessions live terminate -i 9e6b4ce4-8a7c-4d9a-b4e0-8b44e3a5e4c1
+	List recordings of the last day: $ cybr sessions recordings list --from 24h
+	Download a recording: $ cybr sessions recordings download -i 9e6b4ce4-8a7c-4d9a-b4e0-8b44e3a5e4c1`,
+	Aliases: []string{"session", "psm"},
+}
+
+var liveSessionsCmd = &cobra.Command{
+	Use:   "live",
+	Short: "Live PSM session actions",
+	Long: `List, terminate, suspend and resume PSM sessions in progress.
+
+	Example Usage:
+	$ cybr sessions live list
+	$ cybr sessions live terminate -i 9e6b4ce4-8a7c-4d9a-b4e0-8b44e3a5e4c1`,
+}
+
+var listLiveSessionsCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List live PSM sessions",
+	Long: `List the PSM sessions in progress.
+
+	Example Usage:
+	$ cybr sessions live list
+	$ cybr sessions live list --safe LinuxSafe --search root
+	$ cybr sessions live list --from 2h --output table`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		fromTime, toTime, err := parseTimeRange()
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		query := &queries.ListLiveSessions{
+			Search:     Search,
+			Safe:       Safe,
+			FromTime:   fromTime,
+			ToTime:     toTime,
+			Activities: Activities,
+			Sort:       Sort,
+			Offset:     Offset,
+			Limit:      Limit,
+		}
+
+		if All {
+			sessions := client.IterateLiveSessions(query)
+			err = printAll(sessions, func() interface{} { return sessions.Session() }, "LiveSessions", "Total", prettyprint.LiveSessionsTable)
+			if err != nil {
+				fatalf("Failed to list live sessions. %s", err)
+			}
+			return
+		}
+
+		sessions, err := client.ListLiveSessions(query)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		printOutput(sessions, prettyprint.LiveSessionsTable)
+	},
+}
+
+var terminateLiveSessionCmd = &cobra.Command{
+	Use:   "terminate",
+	Short: "Terminate a live PSM session",
+	Long: `Terminate a PSM session in progress.
+
+	Example Usage:
+	$ cybr sessions live terminate -i 9e6b4ce4-8a7c-4d9a-b4e0-8b44e3a5e4c1`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		err = client.TerminateLiveSession(SessionID)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		fmt.Printf("Successfully terminated live session '%s'.\n", SessionID)
+	},
+}
+
+var suspendLiveSessionCmd = &cobra.Command{
+	Use:   "suspend",
+	Short: "Suspend a live PSM session",
+	Long: `Suspend a PSM session in progress. The keyboard and mouse of the user are locked
+	until the session is resumed.
+
+	Example Usage:
+	$ cybr sessions live suspend -i 9e6b4ce4-8a7c-4d9a-b4e0-8b44e3a5e4c1`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		err = client.SuspendLiveSession(SessionID)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		fmt.Printf("Successfully suspended live session '%s'.\n", SessionID)
+	},
+}
+
+var resumeLiveSessionCmd = &cobra.Command{
+	Use:   "resume",
+	Short: "Resume a suspended PSM session",
+	Long: `Resume a suspended PSM session.
+
+	Example Usage:
+	$ cybr sessions live resume -i 9e6b4ce4-8a7c-4d9a-b4e0-8b44e3a5e4c1`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		err = client.ResumeLiveSession(SessionID)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		fmt.Printf("Successfully resumed live session '%s'.\n", SessionID)
+	},
+}
+
+var recordingsCmd = &cobra.Command{
+	Use:   "recordings",
+	Short: "PSM recording actions",
+	Long: `List, get and download recorded PSM sessions and their activities.
+
+	Example Usage:
+	$ cybr sessions recordings list --from 7d
+	$ cybr sessions recordings download -i 9e6b4ce4-8a7c-4d9a-b4e0-8b44e3a5e4c1`,
+	Aliases: []string{"recording"},
+}
+
+var listRecordingsCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List PSM recordings",
+	Long: `List the recorded PSM sessions.
+
+	The --from and --to flags accept a date (2006-01-02), a RFC3339 time, a unix time
+	or a duration before now such as 12h or 7d.
+
+	Example Usage:
+	$ cybr sessions recordings list --from 7d
+	$ cybr sessions recordings list --from 2021-01-01 --to 2021-02-01 --safe LinuxSafe --all
+	$ cybr sessions recordings list --activities "rm -rf" --output table`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		fromTime, toTime, err := parseTimeRange()
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		query := &queries.ListRecordings{
+			Search:     Search,
+			Safe:       Safe,
+			FromTime:   fromTime,
+			ToTime:     toTime,
+			Activities: Activities,
+			Sort:       Sort,
+			Offset:     Offset,
+			Limit:      Limit,
+		}
+
+		if All {
+			recordings := client.IterateRecordings(query)
+			err = printAll(recordings, func() interface{} { return recordings.Recording() }, "Recordings", "Total", prettyprint.RecordingsTable)
+			if err != nil {
+				fatalf("Failed to list recordings. %s", err)
+			}
+			return
+		}
+
+		recordings, err := client.ListRecordings(query)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		printOutput(recordings, prettyprint.RecordingsTable)
+	},
+}
+
+var getRecordingCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Get a specific PSM recording",
+	Long: `Get the details of a recorded PSM session.
+
+	Example Usage:
+	$ cybr sessions recordings get -i 9e6b4ce4-8a7c-4d9a-b4e0-8b44e3a5e4c1`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		recording, err := client.GetRecording(RecordingID)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		printOutput(recording, prettyprint.RecordingsTable)
+	},
+}
+
+var listRecordingActivitiesCmd = &cobra.Command{
+	Use:   "activities",
+	Short: "List the activities of a PSM recording",
+	Long: `List the commands, window titles and keystrokes recorded during a PSM session.
+
+	Example Usage:
+	$ cybr sessions recordings activities -i 9e6b4ce4-8a7c-4d9a-b4e0-8b44e3a5e4c1 --output table`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		activities, err := client.ListRecordingActivities(RecordingID)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		printOutput(activities, prettyprint.RecordingActivitiesTable)
+	},
+}
+
+var downloadRecordingCmd = &cobra.Command{
+	Use:   "download",
+	Short: "Download a PSM recording",
+	Long: `Download the file of a recorded PSM session. Without --file the recording is
+	written to the current directory using the file name returned by PAS. Use '--file -'
+	to write the recording to stdout.
+
+	Example Usage:
+	$ cybr sessions recordings download -i 9e6b4ce4-8a7c-4d9a-b4e0-8b44e3a5e4c1
+	$ cybr sessions recordings download -i 9e6b4ce4-8a7c-4d9a-b4e0-8b44e3a5e4c1 -f evidence.avi`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		if DownloadFile == "-" {
+			_, err = client.DownloadRecording(RecordingID, os.Stdout)
+			if err != nil {
+				fatalf("%s", err)
+			}
+			return
+		}
+
+		path, err := downloadToFile(DownloadFile, RecordingID+".recording", func(w io.Writer) (string, error) {
+			return client.DownloadRecording(RecordingID, w)
+		})
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		fmt.Fprintf(os.Stderr, "Successfully downloaded recording '%s' to '%s'.\n", RecordingID, path)
+	},
+}
+
+// downloadToFile writes a download to path. Without a path the download is written to the current
+// directory using the file name returned by download, or defaultName if none was returned
+func downloadToFile(path string, defaultName string, download func(w io.Writer) (string, error)) (string, error) {
+	dir := "."
+	if path != "" {
+		dir = filepath.Dir(path)
+	}
+	file, err := ioutil.TempFile(dir, ".cybr-download-")
+	if err != nil {
+		return "", fmt.Errorf("Failed to create file. %s", err)
+	}
+	defer os.Remove(file.Name())
+
+	fileName, err := download(file)
+	file.Close()
+	if err != nil {
+		return "", err
+	}
+
+	if path == "" {
+		path = filepath.Base(fileName)
+		if fileName == "" || path == "." || path == string(filepath.Separator) {
+			path = defaultName
+		}
+	}
+
+	err = os.Rename(file.Name(), path)
+	if err != nil {
+		return "", fmt.Errorf("Failed to write file '%s'. %s", path, err)
+	}
+	return path, nil
+}
+
+// parseTimeRange returns the unix times of --from and --to
+func parseTimeRange() (int, int, error) {
+	fromTime, err := parseTime(FromTime)
+	if err != nil {
+		return 0, 0, err
+	}
+	toTime, err := parseTime(ToTime)
+	if err != nil {
+		return 0, 0, err
+	}
+	return fromTime, toTime, nil
+}
+
+func init() {
+	for _, command := range []*cobra.Command{listLiveSessionsCmd, listRecordingsCmd} {
+		command.Flags().StringVarP(&Search, "search", "s", "", "List of keywords to search for in sessions, separated by a space")
+		command.Flags().StringVar(&Safe, "safe", "", "Safe of the accounts the sessions connected with")
+		command.Flags().StringVar(&FromTime, "from", "", "Start of the time range, e.g. 2021-01-01 or 24h")
+		command.Flags().StringVar(&ToTime, "to", "", "End of the time range, e.g. 2021-02-01 or 1h")
+		command.Flags().StringVarP(&Activities, "activities", "a", "", "Filter by the activities performed during the sessions")
+		command.Flags().StringVarP(&Sort, "sort", "r", "", "Property by which to sort returned sessions, followed by asc (default) or desc, e.g. 'Start desc'")
+		command.Flags().IntVarP(&Offset, "offset", "o", 0, "Offset of the first session that is returned in the collection of results")
+		command.Flags().IntVarP(&Limit, "limit", "l", 0, "Maximum number of returned sessions")
+		command.Flags().BoolVar(&All, "all", false, "Retrieve all pages of sessions starting at offset instead of a single page")
+	}
+
+	for _, command := range []*cobra.Command{terminateLiveSessionCmd, suspendLiveSessionCmd, resumeLiveSessionCmd} {
+		command.Flags().StringVarP(&SessionID, "session-id", "i", "", "ID of the live session")
+		command.MarkFlagRequired("session-id")
+	}
+
+	for _, command := range []*cobra.Command{getRecordingCmd, listRecordingActivitiesCmd, downloadRecordingCmd} {
+		command.Flags().StringVarP(&RecordingID, "recording-id", "i", "", "ID of the recording")
+		command.MarkFlagRequired("recording-id")
+	}
+	downloadRecordingCmd.Flags().StringVarP(&DownloadFile, "file", "f", "", "File the recording is written to, or '-' for stdout. Defaults to the file name returned by PAS")
+
+	liveSessionsCmd.AddCommand(listLiveSessionsCmd)
+	liveSessionsCmd.AddCommand(terminateLiveSessionCmd)
+	liveSessionsCmd.AddCommand(suspendLiveSessionCmd)
+	liveSessionsCmd.AddCommand(resumeLiveSessionCmd)
+
+	recordingsCmd.AddCommand(listRecordingsCmd)
+	recordingsCmd.AddCommand(getRecordingCmd)
+	recordingsCmd.AddCommand(listRecordingActivitiesCmd)
+	recordingsCmd.AddCommand(downloadRecordingCmd)
+
+	sessionsCmd.AddCommand(liveSessionsCmd)
+	sessionsCmd.AddCommand(recordingsCmd)
+
+	rootCmd.AddCommand(sessionsCmd)
+}
@@ -2,11 +2,13 @@ package cmd
 
 import (
 	"fmt"
-	"log"
+	"strings"
 
 	pasapi "github.com/infamousjoeg/cybr-cli/pkg/cybr/api"
+
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/queries"
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/requests"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/responses"
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/prettyprint"
 	"github.com/spf13/cobra"
 )
@@ -56,6 +58,15 @@ var (
 
 	// PersonalDetails of user
 	PersonalDetails string
+
+	// AuthnMethod is the authentication method enabled or disabled for a user
+	AuthnMethod string
+
+	// AddAuthorizations adds vault authorizations to those of a user instead of replacing them
+	AddAuthorizations bool
+
+	// RemoveAuthorizations removes vault authorizations from those of a user
+	RemoveAuthorizations bool
 )
 
 var usersCmd = &cobra.Command{
@@ -64,7 +75,9 @@ var usersCmd = &cobra.Command{
 	Long: `All users actions that can be taken via PAS REST API.
 	
 	Example Usage:
-	Unsuspend a User: cybr users unsuspend -u userName`,
+	Unsuspend a User: cybr users unsuspend -u userName
+	Reset the password of a User: cybr users reset-password -u userName
+	Grant authorizations to a User: cybr users set-authorizations -u userName -v AuditUsers --add`,
 	Aliases: []string{"user"},
 }
 
@@ -76,15 +89,15 @@ var unsuspendUserCmd = &cobra.Command{
 	Example Usage:
 	$ cybr users unsuspend --id 9`,
 	Run: func(cmd *cobra.Command, args []string) {
-		client, err := pasapi.GetConfig()
+		client, err := getClient()
 		if err != nil {
-			log.Fatalf("Failed to read configuration file. %s", err)
+			fatalf("Failed to read configuration file. %s", err)
 			return
 		}
 
 		err = client.UnsuspendUser(UserID)
 		if err != nil {
-			log.Fatalf("Failed to unsuspend user with id '%d'. %s", UserID, err)
+			fatalf("Failed to unsuspend user with id '%d'. %s", UserID, err)
 			return
 		}
 
@@ -98,11 +111,12 @@ var listUsersCmd = &cobra.Command{
 	Long: `Lists cyberark PAS users.
 	
 	Example Usage:
-	$ cybr users list --search userName --filter userType`,
+	$ cybr users list --search userName --filter userType
+	$ cybr users list --all`,
 	Run: func(cmd *cobra.Command, args []string) {
-		client, err := pasapi.GetConfig()
+		client, err := getClient()
 		if err != nil {
-			log.Fatalf("Failed to read configuration file. %s", err)
+			fatalf("Failed to read configuration file. %s", err)
 			return
 		}
 
@@ -111,13 +125,22 @@ var listUsersCmd = &cobra.Command{
 			Filter: Filter,
 		}
 
+		if All {
+			users := client.IterateUsers(query)
+			err = printAll(users, func() interface{} { return users.User() }, "Users", "Total", prettyprint.UsersTable)
+			if err != nil {
+				fatalf("Failed to list users. %s", err)
+			}
+			return
+		}
+
 		users, err := client.ListUsers(query)
 		if err != nil {
-			log.Fatalf("Failed to list users. %s", err)
+			fatalf("Failed to list users. %s", err)
 			return
 		}
 
-		prettyprint.PrintJSON(users)
+		printOutput(users, prettyprint.UsersTable)
 	},
 }
 
@@ -129,15 +152,15 @@ var deleteUserCmd = &cobra.Command{
 	Example Usage:
 	$ cybr users delete --id 9`,
 	Run: func(cmd *cobra.Command, args []string) {
-		client, err := pasapi.GetConfig()
+		client, err := getClient()
 		if err != nil {
-			log.Fatalf("Failed to read configuration file. %s", err)
+			fatalf("Failed to read configuration file. %s", err)
 			return
 		}
 
 		err = client.DeleteUser(UserID)
 		if err != nil {
-			log.Fatalf("Failed to delete user with id '%d'. %s", UserID, err)
+			fatalf("Failed to delete user with id '%d'. %s", UserID, err)
 			return
 		}
 
@@ -153,7 +176,7 @@ var addUserCmd = &cobra.Command{
 	Example Usage:
 	$ cybr users add --username userName \
 	  --user-type EPVUser \
-	  --initial-password initialPassword \
+	  --initial-password env:INITIAL_PASSWORD \
 	  --authentication-method AuthTypePass \
 	  --location "\\" \
 	  --unauthorized-interface PSM,PSMP \
@@ -166,36 +189,45 @@ var addUserCmd = &cobra.Command{
 	  --internet "homeEmail=userName@Cyberark.com"
 	  --personal-details "firtName=me,lastName=lastme"`,
 	Run: func(cmd *cobra.Command, args []string) {
-		client, err := pasapi.GetConfig()
+		client, err := getClient()
 		if err != nil {
-			log.Fatalf("Failed to read configuration file. %s", err)
+			fatalf("Failed to read configuration file. %s", err)
 			return
 		}
 
 		businessAddress, err := keyValueStringToMap(BusinessAddress)
 		if err != nil {
-			log.Fatalf("Failed to parse 'business-address'. %s", err)
+			fatalf("Failed to parse 'business-address'. %s", err)
 		}
 
 		phones, err := keyValueStringToMap(Phones)
 		if err != nil {
-			log.Fatalf("Failed to parse 'phones'. %s", err)
+			fatalf("Failed to parse 'phones'. %s", err)
 		}
 
 		personalDetails, err := keyValueStringToMap(PersonalDetails)
 		if err != nil {
-			log.Fatalf("Failed to parse 'personal-details'. %s", err)
+			fatalf("Failed to parse 'personal-details'. %s", err)
 		}
 
 		internet, err := keyValueStringToMap(Internet)
 		if err != nil {
-			log.Fatalf("Failed to parse 'internet'. %s", err)
+			fatalf("Failed to parse 'internet'. %s", err)
+		}
+
+		initialPassword := InitialPassword
+		if cmd.Flags().Changed("initial-password") {
+			initialPassword, err = readSecret("initial password", InitialPassword)
+			if err != nil {
+				fatalf("%s", err)
+				return
+			}
 		}
 
 		user := requests.AddUser{
 			Username:               Username,
 			UserType:               UserType,
-			InitialPassword:        InitialPassword,
+			InitialPassword:        initialPassword,
 			AuthenticationMethod:   AuthenticationMethod,
 			Location:               Location,
 			UnAuthorizedInterfaces: UnauthorizedInterfaces,
@@ -214,14 +246,387 @@ var addUserCmd = &cobra.Command{
 
 		response, err := client.AddUser(user)
 		if err != nil {
-			log.Fatalf("Failed to unsuspend user '%s'. %s", Username, err)
+			fatalf("Failed to unsuspend user '%s'. %s", Username, err)
+			return
+		}
+
+		printOutput(response, prettyprint.UsersTable)
+	},
+}
+
+var getUserCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Get a specific user",
+	Long: `Get the details of a user.
+
+	Example Usage:
+	$ cybr users get --id 9
+	$ cybr users get -u userName --output table`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		userID, err := getUserID(client)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		user, err := client.GetUser(userID)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		printOutput(user, prettyprint.UsersTable)
+	},
+}
+
+var loggedOnUserCmd = &cobra.Command{
+	Use:   "logged-on",
+	Short: "Get the logged on user",
+	Long: `Get the details of the user cybr is logged on as.
+
+	Example Usage:
+	$ cybr users logged-on`,
+	Aliases: []string{"whoami"},
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
 			return
 		}
 
-		prettyprint.PrintJSON(response)
+		user, err := client.GetLoggedOnUser()
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		printOutput(user, prettyprint.UsersTable)
 	},
 }
 
+var updateUserCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Update a user",
+	Long: `Update the properties of a user. Only the properties given are changed. The address,
+	internet, phones and personal details given are merged with those of the user.
+
+	Example Usage:
+	$ cybr users update -u userName --description "Service user of App1" --password-never-expires
+	$ cybr users update --id 9 --expiry-date 1735689600 --unauthorized-interfaces PSM,PSMP
+	$ cybr users update -u userName --personal-details "department=Finance"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		details := map[string]map[string]string{}
+		for flag, value := range map[string]string{"business-address": BusinessAddress, "internet": Internet, "phones": Phones, "personal-details": PersonalDetails} {
+			details[flag], err = keyValueStringToMap(value)
+			if err != nil {
+				fatalf("Failed to parse '%s'. %s", flag, err)
+				return
+			}
+		}
+
+		userID, err := getUserID(client)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		flags := cmd.Flags()
+		user, err := client.ModifyUser(userID, func(user *requests.UpdateUser) error {
+			if flags.Changed("description") {
+				user.Description = Description
+			}
+			if flags.Changed("user-type") {
+				user.UserType = UserType
+			}
+			if flags.Changed("location") {
+				user.Location = Location
+			}
+			if flags.Changed("expiry-date") {
+				user.ExpiryDate = &ExpiryDate
+			}
+			if flags.Changed("unauthorized-interfaces") {
+				user.UnAuthorizedInterfaces = UnauthorizedInterfaces
+			}
+			if flags.Changed("enable-user") {
+				user.EnableUser = EnableUser
+			}
+			if flags.Changed("change-password-on-logon") {
+				user.ChangePassOnNextLogon = ChangePasswordOnLogon
+			}
+			if flags.Changed("password-never-expires") {
+				user.PasswordNeverExpires = PasswordNeverExpires
+			}
+			if flags.Changed("distinguished-name") {
+				user.DistinguishedName = DistinguishedName
+			}
+			user.BusinessAddress = mergeUserDetails(user.BusinessAddress, details["business-address"])
+			user.Internet = mergeUserDetails(user.Internet, details["internet"])
+			user.Phones = mergeUserDetails(user.Phones, details["phones"])
+			user.PersonalDetails = mergeUserDetails(user.PersonalDetails, details["personal-details"])
+			return nil
+		})
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		printOutput(user, prettyprint.UsersTable)
+	},
+}
+
+var activateUserCmd = &cobra.Command{
+	Use:   "activate",
+	Short: "Activate a disabled user",
+	Long: `Enables a disabled user. Use unsuspend to activate a suspended user.
+
+	Example Usage:
+	$ cybr users activate --id 9
+	$ cybr users activate -u userName`,
+	Aliases: []string{"enable"},
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		userID, err := getUserID(client)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		err = client.ActivateUser(userID)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		fmt.Printf("Successfully activated user with id '%d'\n", userID)
+	},
+}
+
+var resetUserPasswordCmd = &cobra.Command{
+	Use:   "reset-password",
+	Short: "Reset the vault password of a user",
+	Long: `Sets a new vault password for a user. The password is read from a file with --new-password @path,
+	from stdin with --new-password - or from an environment variable with --new-password env:VAR.
+	It is prompted for when --new-password is not given.
+
+	Example Usage:
+	$ cybr users reset-password -u svc_app1
+	$ cybr users reset-password --id 9 --new-password env:NEW_PASSWORD`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		userID, err := getUserID(client)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		newPassword, err := readSecret("new password", NewPassword)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		err = client.ResetUserPassword(userID, newPassword)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		fmt.Printf("Successfully reset password of user with id '%d'\n", userID)
+	},
+}
+
+var enableUserAuthnMethodCmd = &cobra.Command{
+	Use:   "enable-authn-method",
+	Short: "Allow a user to authenticate with an authentication method",
+	Long: `Adds an authentication method to those a user can authenticate with.
+	Valid methods are AuthTypePass, AuthTypeLDAP and AuthTypeRADIUS, or pass, ldap and radius.
+
+	Example Usage:
+	$ cybr users enable-authn-method -u userName -a radius`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		userID, err := getUserID(client)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		user, err := client.EnableUserAuthenticationMethod(userID, AuthnMethod)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		printOutput(user, prettyprint.UsersTable)
+	},
+}
+
+var disableUserAuthnMethodCmd = &cobra.Command{
+	Use:   "disable-authn-method",
+	Short: "Stop a user from authenticating with an authentication method",
+	Long: `Removes an authentication method from those a user can authenticate with.
+	The last authentication method of a user cannot be removed.
+
+	Example Usage:
+	$ cybr users disable-authn-method -u userName -a ldap`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		userID, err := getUserID(client)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		user, err := client.DisableUserAuthenticationMethod(userID, AuthnMethod)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		printOutput(user, prettyprint.UsersTable)
+	},
+}
+
+var setUserAuthorizationsCmd = &cobra.Command{
+	Use:   "set-authorizations",
+	Short: "Set the vault authorizations of a user",
+	Long: `Replaces the vault authorizations of a user, or adds or removes authorizations with --add or --remove.
+	The user running the command must have the authorizations given.
+
+	Valid authorizations: AddUpdateUsers, AddSafes, AddNetworkAreas, ManageDirectoryMapping,
+	ManageServerFileCategories, AuditUsers, BackupAllSafes, RestoreAllSafes, ResetUsersPasswords, ActivateUsers
+
+	Example Usage:
+	$ cybr users set-authorizations -u userName -v AddSafes,AuditUsers
+	$ cybr users set-authorizations -u userName -v ManageServerFileCategories --add
+	$ cybr users set-authorizations --id 9 -v AuditUsers --remove`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if AddAuthorizations && RemoveAuthorizations {
+			fatalf("Only one of --add or --remove can be given")
+			return
+		}
+
+		client, err := getClient()
+		if err != nil {
+			fatalf("Failed to read configuration file. %s", err)
+			return
+		}
+
+		userID, err := getUserID(client)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		authorizations := VaultAuthorization
+		if AddAuthorizations || RemoveAuthorizations {
+			user, err := client.GetUser(userID)
+			if err != nil {
+				fatalf("%s", err)
+				return
+			}
+			authorizations = changeAuthorizations(user, VaultAuthorization, AddAuthorizations)
+		}
+
+		user, err := client.SetUserAuthorizations(userID, authorizations)
+		if err != nil {
+			fatalf("%s", err)
+			return
+		}
+
+		printOutput(user, prettyprint.UsersTable)
+	},
+}
+
+// getUserID returns the --id flag, or the id of the user named by --username
+func getUserID(client pasapi.Client) (int, error) {
+	if (UserID == 0) == (Username == "") {
+		return 0, fmt.Errorf("Either --id or --username is required, but not both")
+	}
+	if UserID != 0 {
+		return UserID, nil
+	}
+
+	user, err := client.FindUser(Username)
+	if err != nil {
+		return 0, err
+	}
+	return user.ID, nil
+}
+
+// mergeUserDetails returns the details of a user with the values changed by update
+func mergeUserDetails(details map[string]string, update map[string]string) map[string]string {
+	if len(update) == 0 {
+		return details
+	}
+	if details == nil {
+		details = map[string]string{}
+	}
+	for key, value := range update {
+		details[key] = value
+	}
+	return details
+}
+
+// changeAuthorizations returns the vault authorizations of user with authorizations added, or removed
+func changeAuthorizations(user responses.GetUser, authorizations []string, add bool) []string {
+	changed := []string{}
+	for _, current := range user.VaultAuthorization {
+		if add || !containsFold(authorizations, current) {
+			changed = append(changed, current)
+		}
+	}
+	if add {
+		for _, authorization := range authorizations {
+			if !containsFold(changed, authorization) {
+				changed = append(changed, authorization)
+			}
+		}
+	}
+	return changed
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
 func init() {
 	// unsuspend
 	unsuspendUserCmd.Flags().IntVarP(&UserID, "id", "i", 0, "The ID of the user you wish to unsuspend")
@@ -230,6 +635,7 @@ func init() {
 	// list
 	listUsersCmd.Flags().StringVarP(&Search, "search", "s", "", "Search for the username, first name or last name of a user")
 	listUsersCmd.Flags().StringVarP(&Filter, "filter", "f", "", "Filter on userType or componentUser")
+	listUsersCmd.Flags().BoolVar(&All, "all", false, "Retrieve all pages of users")
 
 	// delete
 	deleteUserCmd.Flags().IntVarP(&UserID, "id", "i", 0, "The ID of the user you wish to delete")
@@ -240,7 +646,7 @@ func init() {
 	addUserCmd.MarkFlagRequired("username")
 	addUserCmd.Flags().StringVarP(&Description, "description", "d", "", "The user's notes and comments")
 	addUserCmd.Flags().StringVarP(&UserType, "user-type", "t", "EPVUser", "The PAS user type")
-	addUserCmd.Flags().StringVarP(&InitialPassword, "initial-password", "p", "", "Initial user password")
+	addUserCmd.Flags().StringVarP(&InitialPassword, "initial-password", "p", "", "Initial user password, @file, - for stdin or env:VAR. Prompted for if given empty")
 	addUserCmd.Flags().StringSliceVarP(&AuthenticationMethod, "authentication-method", "a", []string{"AuthTypePass"}, "User authentication method. Support values: Cyberark, LDAP, Radius")
 	addUserCmd.Flags().StringVarP(&Location, "location", "l", "\\", "The location in the Vault where the user will be created")
 	addUserCmd.Flags().StringSliceVarP(&UnauthorizedInterfaces, "unauthorized-interfaces", "i", []string{}, "The CyberArk interfaces that this user is not authorized to use")
@@ -255,9 +661,53 @@ func init() {
 	addUserCmd.Flags().StringVar(&Phones, "phones", "", "The user's phone numbers. e.g homeNumber=555123456,businessNumber=555456789")
 	addUserCmd.Flags().StringVar(&PersonalDetails, "personal-details", "", "The user's personal details. e.g. street=Dizzengof 56,city=Tel Aviv")
 
+	// get, update, activate, reset-password, enable-authn-method, disable-authn-method and set-authorizations
+	for _, command := range []*cobra.Command{getUserCmd, updateUserCmd, activateUserCmd, resetUserPasswordCmd, enableUserAuthnMethodCmd, disableUserAuthnMethodCmd, setUserAuthorizationsCmd} {
+		command.Flags().IntVarP(&UserID, "id", "i", 0, "The ID of the user")
+		command.Flags().StringVarP(&Username, "username", "u", "", "The username of the user, instead of --id")
+	}
+
+	// update
+	updateUserCmd.Flags().StringVarP(&Description, "description", "d", "", "The user's notes and comments")
+	updateUserCmd.Flags().StringVarP(&UserType, "user-type", "t", "", "The PAS user type")
+	updateUserCmd.Flags().StringVarP(&Location, "location", "l", "", "The location in the Vault of the user")
+	updateUserCmd.Flags().IntVarP(&ExpiryDate, "expiry-date", "e", 0, "The EPOCH time in which this user expires. 0 removes the expiry date")
+	updateUserCmd.Flags().StringSliceVar(&UnauthorizedInterfaces, "unauthorized-interfaces", []string{}, "The CyberArk interfaces that this user is not authorized to use")
+	updateUserCmd.Flags().BoolVar(&EnableUser, "enable-user", false, "Whether the user is enabled")
+	updateUserCmd.Flags().BoolVar(&ChangePasswordOnLogon, "change-password-on-logon", false, "Whether or not the user must change their password on next log on")
+	updateUserCmd.Flags().BoolVar(&PasswordNeverExpires, "password-never-expires", false, "Whether the user’s password will not expire unless they decide to change it")
+	updateUserCmd.Flags().StringVar(&DistinguishedName, "distinguished-name", "", "The user’s distinguished name")
+	updateUserCmd.Flags().StringVar(&BusinessAddress, "business-address", "", "The user’s postal address. e.g workCity=Newton,workState=MA")
+	updateUserCmd.Flags().StringVar(&Internet, "internet", "", "The user's email addresses. e.g homePage=Cyberark.com,homeEmail=user@gmail.com")
+	updateUserCmd.Flags().StringVar(&Phones, "phones", "", "The user's phone numbers. e.g homeNumber=555123456,businessNumber=555456789")
+	updateUserCmd.Flags().StringVar(&PersonalDetails, "personal-details", "", "The user's personal details. e.g. street=Dizzengof 56,city=Tel Aviv")
+
+	// reset-password
+	resetUserPasswordCmd.Flags().StringVarP(&NewPassword, "new-password", "p", "", "The new vault password of the user, @file, - for stdin or env:VAR. Prompted for if not given")
+
+	// enable-authn-method and disable-authn-method
+	for _, command := range []*cobra.Command{enableUserAuthnMethodCmd, disableUserAuthnMethodCmd} {
+		command.Flags().StringVarP(&AuthnMethod, "authentication-method", "a", "", "The authentication method. Valid values: pass, ldap or radius")
+		command.MarkFlagRequired("authentication-method")
+	}
+
+	// set-authorizations
+	setUserAuthorizationsCmd.Flags().StringSliceVarP(&VaultAuthorization, "vault-authorization", "v", []string{}, "The vault authorizations of the user")
+	setUserAuthorizationsCmd.MarkFlagRequired("vault-authorization")
+	setUserAuthorizationsCmd.Flags().BoolVar(&AddAuthorizations, "add", false, "Add the authorizations to those of the user")
+	setUserAuthorizationsCmd.Flags().BoolVar(&RemoveAuthorizations, "remove", false, "Remove the authorizations from those of the user")
+
 	usersCmd.AddCommand(unsuspendUserCmd)
 	usersCmd.AddCommand(listUsersCmd)
 	usersCmd.AddCommand(deleteUserCmd)
 	usersCmd.AddCommand(addUserCmd)
+	usersCmd.AddCommand(getUserCmd)
+	usersCmd.AddCommand(loggedOnUserCmd)
+	usersCmd.AddCommand(updateUserCmd)
+	usersCmd.AddCommand(activateUserCmd)
+	usersCmd.AddCommand(resetUserPasswordCmd)
+	usersCmd.AddCommand(enableUserAuthnMethodCmd)
+	usersCmd.AddCommand(disableUserAuthnMethodCmd)
+	usersCmd.AddCommand(setUserAuthorizationsCmd)
 	rootCmd.AddCommand(usersCmd)
 }
@@ -1,8 +1,17 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
 	"strings"
+	"syscall"
+	"time"
+
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/prettyprint"
+	terminal "golang.org/x/term"
 )
 
 // The content will look like
@@ -36,3 +45,164 @@ func keyValueStringToMap(content string) (map[string]string, error) {
 
 	return m, nil
 }
+
+// listIterator is implemented by the list iterators of the PAS REST API client
+type listIterator interface {
+	Next() bool
+	Err() error
+}
+
+// printAll prints every item of the iterator under key and the number of items under countKey,
+// matching the output of a single page. JSON output is printed as soon as each item is retrieved
+func printAll(it listIterator, item func() interface{}, key string, countKey string, table *prettyprint.Table) error {
+	printer := getPrinter()
+	if printer.Format != prettyprint.FormatJSON {
+		items := []interface{}{}
+		for it.Next() {
+			items = append(items, item())
+		}
+		if it.Err() != nil {
+			return it.Err()
+		}
+		return printer.Print(os.Stdout, map[string]interface{}{key: items, countKey: len(items)}, table)
+	}
+
+	writer := prettyprint.NewJSONListWriter(os.Stdout, key)
+	for it.Next() {
+		err := writer.Write(item())
+		if err != nil {
+			return err
+		}
+	}
+	if it.Err() != nil {
+		return it.Err()
+	}
+	return writer.Close(countKey)
+}
+
+// getPrinter returns the printer of the output format selected with --output
+func getPrinter() *prettyprint.Printer {
+	printer, err := prettyprint.NewPrinter(Output)
+	if err != nil {
+		fatalf("%s", err)
+	}
+	return printer
+}
+
+// printOutput prints obj in the output format selected with --output. The table
+// contains the columns of the table, wide and csv formats
+func printOutput(obj interface{}, table *prettyprint.Table) {
+	err := getPrinter().Print(os.Stdout, obj, table)
+	if err != nil {
+		fatalf("Failed to print output. %s", err)
+	}
+}
+
+// parseTime returns the unix time of a date (2006-01-02), a RFC3339 time, a unix time or a
+// duration before now such as 12h or 7d. An empty value is 0
+func parseTime(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if unix, err := strconv.Atoi(value); err == nil {
+		return unix, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return int(t.Unix()), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return int(t.Unix()), nil
+	}
+
+	duration := value
+	if strings.HasSuffix(value, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err == nil {
+			duration = fmt.Sprintf("%dh", days*24)
+		}
+	}
+	if d, err := time.ParseDuration(duration); err == nil {
+		return int(time.Now().Add(-d).Unix()), nil
+	}
+
+	return 0, fmt.Errorf("Invalid time '%s'. Use a date (2006-01-02), a RFC3339 time, a unix time or a duration such as 12h or 7d", value)
+}
+
+// readSecret returns the secret of a secret-bearing flag. The value is one of:
+//
+//	@path    the content of the file at path
+//	env:VAR  the value of the environment variable VAR
+//	-        the content of stdin, or prompted for without echo if stdin is a terminal
+//	empty    prompted for without echo if stdin is a terminal
+//	@@value  the secret @value, as an escape for secrets starting with @
+//
+// Any other value is the secret itself. Multi-line secrets, such as SSH private keys, are
+// returned unchanged, while the trailing newline of a single line is removed. name describes
+// the secret in the prompt and in errors
+func readSecret(name string, value string) (string, error) {
+	var secret string
+	switch {
+	case strings.HasPrefix(value, "@@"):
+		secret = value[1:]
+	case strings.HasPrefix(value, "@"):
+		content, err := ioutil.ReadFile(value[1:])
+		if err != nil {
+			return "", fmt.Errorf("Failed to read %s from file '%s'. Use @%s if the %s starts with @. %s", name, value[1:], value, name, err)
+		}
+		secret = trimSingleLine(string(content))
+	case strings.HasPrefix(value, "env:"):
+		env, ok := os.LookupEnv(value[4:])
+		if !ok {
+			return "", fmt.Errorf("Failed to read %s. Environment variable '%s' is not set", name, value[4:])
+		}
+		secret = env
+	case value == "-" || value == "":
+		if !terminal.IsTerminal(int(syscall.Stdin)) {
+			if value == "" {
+				return "", fmt.Errorf("The %s is required", name)
+			}
+			content, err := ioutil.ReadAll(os.Stdin)
+			if err != nil {
+				return "", fmt.Errorf("Failed to read %s from stdin. %s", name, err)
+			}
+			secret = trimSingleLine(string(content))
+			break
+		}
+		fmt.Fprintf(os.Stderr, "Enter %s: ", name)
+		content, err := terminal.ReadPassword(int(syscall.Stdin))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("Failed to read %s from stdin. %s", name, err)
+		}
+		secret = string(content)
+	default:
+		secret = value
+	}
+
+	if secret == "" {
+		return "", fmt.Errorf("The %s cannot be empty", name)
+	}
+	return secret, nil
+}
+
+// trimSingleLine removes the trailing newline of content if it is a single line
+func trimSingleLine(content string) string {
+	trimmed := strings.TrimSuffix(strings.TrimSuffix(content, "\n"), "\r")
+	if strings.Contains(trimmed, "\n") {
+		return content
+	}
+	return trimmed
+}
+
+// confirm asks a yes or no question on stderr and reads the answer from stdin. Anything but
+// y or yes is no
+func confirm(question string) (bool, error) {
+	fmt.Fprintf(os.Stderr, "%s [y/N]: ", question)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && answer == "" {
+		return false, fmt.Errorf("Failed to read answer from stdin. %s", err)
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadSecret(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secret")
+	err := ioutil.WriteFile(path, []byte("from-file\n"), 0600)
+	if err != nil {
+		t.Fatalf("Failed to write secret file. %s", err)
+	}
+	os.Setenv("CYBR_TEST_SECRET", "from-env")
+	defer os.Unsetenv("CYBR_TEST_SECRET")
+
+	tests := map[string]string{
+		"literal":              "literal",
+		"@" + path:             "from-file",
+		"env:CYBR_TEST_SECRET": "from-env",
+		"@@" + path:            "@" + path,
+		"@@secret":             "@secret",
+		"@@-":                  "@-",
+		"@@@":                  "@@",
+	}
+	for value, expected := range tests {
+		secret, err := readSecret("password", value)
+		if err != nil || secret != expected {
+			t.Errorf("Expected '%s' for '%s' but got '%s'. %v", expected, value, secret, err)
+		}
+	}
+
+	_, err = readSecret("password", "@"+filepath.Join(t.TempDir(), "missing"))
+	if err == nil {
+		t.Errorf("Expected a missing file to fail")
+	}
+	_, err = readSecret("password", "env:CYBR_TEST_MISSING")
+	if err == nil {
+		t.Errorf("Expected a missing environment variable to fail")
+	}
+}
@@ -3,6 +3,7 @@ module github.com/infamousjoeg/cybr-cli
 go 1.15
 
 require (
+	filippo.io/age v1.0.0
 	github.com/aws/aws-sdk-go-v2/config v1.18.10
 	github.com/aws/aws-sdk-go-v2/service/sts v1.18.2
 	github.com/cyberark/conjur-api-go v0.6.1
@@ -10,5 +11,6 @@ require (
 	github.com/spf13/cobra v1.1.1
 	golang.org/x/crypto v0.17.0
 	golang.org/x/term v0.15.0
-
+	gopkg.in/yaml.v2 v2.2.8
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -0,0 +1,228 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/queries"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/requests"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/responses"
+	httpJson "github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/httpjson"
+)
+
+// DefaultAccessRequestInterval is the default time between checks of an access request
+const DefaultAccessRequestInterval = 10 * time.Second
+
+// Status of an access request. The StatusTitle describing it is localized
+const (
+	AccessRequestStatusWaiting   = 1
+	AccessRequestStatusConfirmed = 2
+	AccessRequestStatusRejected  = 3
+	AccessRequestStatusDeleted   = 4
+	AccessRequestStatusInvalid   = 5
+	AccessRequestStatusExpired   = 6
+)
+
+// WaitForAccessOptions are the options of WaitForAccountAccess
+type WaitForAccessOptions struct {
+	// Interval is the time between checks of the access request. Defaults to DefaultAccessRequestInterval
+	Interval time.Duration
+	// Timeout is the maximum time to wait for the access request to be confirmed. Zero waits until
+	// the request is confirmed, rejected or expires
+	Timeout time.Duration
+	// Progress is called with the access request every time it is checked
+	Progress func(request *responses.AccessRequest)
+}
+
+// CreateAccessRequest asks for access to an account protected by dual control
+func (c Client) CreateAccessRequest(request requests.CreateAccessRequest) (*responses.AccessRequest, error) {
+	url := fmt.Sprintf("%s/passwordvault/api/MyRequests", c.BaseURL)
+	response, err := c.GetTransport().Post(c.GetContext(), false, url, c.SessionToken, request, c.Logger)
+	if err != nil {
+		return &responses.AccessRequest{}, fmt.Errorf("Failed to create access request for account '%s'. %w", request.AccountID, err)
+	}
+
+	jsonString, _ := json.Marshal(response)
+	CreateAccessRequestResponse := &responses.AccessRequest{}
+	err = json.Unmarshal(jsonString, CreateAccessRequestResponse)
+	return CreateAccessRequestResponse, err
+}
+
+// ListMyAccessRequests returns the access requests of the logged on user
+func (c Client) ListMyAccessRequests(query *queries.ListAccessRequests) (*responses.ListMyAccessRequests, error) {
+	if query == nil {
+		query = &queries.ListAccessRequests{}
+	}
+	url := fmt.Sprintf("%s/passwordvault/api/MyRequests%s", c.BaseURL, httpJson.GetURLQuery(query))
+	response, err := c.GetTransport().Get(c.GetContext(), false, url, c.SessionToken, c.Logger)
+	if err != nil {
+		return &responses.ListMyAccessRequests{}, fmt.Errorf("Failed to list my access requests. %w", err)
+	}
+
+	jsonString, _ := json.Marshal(response)
+	ListMyAccessRequestsResponse := &responses.ListMyAccessRequests{}
+	err = json.Unmarshal(jsonString, ListMyAccessRequestsResponse)
+	return ListMyAccessRequestsResponse, err
+}
+
+// ListIncomingAccessRequests returns the access requests the logged on user can confirm or reject
+func (c Client) ListIncomingAccessRequests(query *queries.ListAccessRequests) (*responses.ListIncomingAccessRequests, error) {
+	if query == nil {
+		query = &queries.ListAccessRequests{}
+	}
+	url := fmt.Sprintf("%s/passwordvault/api/IncomingRequests%s", c.BaseURL, httpJson.GetURLQuery(query))
+	response, err := c.GetTransport().Get(c.GetContext(), false, url, c.SessionToken, c.Logger)
+	if err != nil {
+		return &responses.ListIncomingAccessRequests{}, fmt.Errorf("Failed to list incoming access requests. %w", err)
+	}
+
+	jsonString, _ := json.Marshal(response)
+	ListIncomingAccessRequestsResponse := &responses.ListIncomingAccessRequests{}
+	err = json.Unmarshal(jsonString, ListIncomingAccessRequestsResponse)
+	return ListIncomingAccessRequestsResponse, err
+}
+
+// GetMyAccessRequest details for a specific access request of the logged on user
+func (c Client) GetMyAccessRequest(requestID string) (*responses.AccessRequest, error) {
+	url := fmt.Sprintf("%s/passwordvault/api/MyRequests/%s", c.BaseURL, url.QueryEscape(requestID))
+	response, err := c.GetTransport().Get(c.GetContext(), false, url, c.SessionToken, c.Logger)
+	if err != nil {
+		return &responses.AccessRequest{}, fmt.Errorf("Failed to get access request '%s'. %w", requestID, err)
+	}
+
+	jsonString, _ := json.Marshal(response)
+	GetMyAccessRequestResponse := &responses.AccessRequest{}
+	err = json.Unmarshal(jsonString, GetMyAccessRequestResponse)
+	return GetMyAccessRequestResponse, err
+}
+
+// ConfirmAccessRequest confirms an incoming access request
+func (c Client) ConfirmAccessRequest(requestID string, request requests.ReviewAccessRequest) error {
+	url := fmt.Sprintf("%s/passwordvault/api/IncomingRequests/%s/Confirm", c.BaseURL, url.QueryEscape(requestID))
+	_, err := c.GetTransport().Post(c.GetContext(), false, url, c.SessionToken, request, c.Logger)
+	if err != nil {
+		return fmt.Errorf("Failed to confirm access request '%s'. %w", requestID, err)
+	}
+
+	return nil
+}
+
+// RejectAccessRequest rejects an incoming access request
+func (c Client) RejectAccessRequest(requestID string, request requests.ReviewAccessRequest) error {
+	url := fmt.Sprintf("%s/passwordvault/api/IncomingRequests/%s/Reject", c.BaseURL, url.QueryEscape(requestID))
+	_, err := c.GetTransport().Post(c.GetContext(), false, url, c.SessionToken, request, c.Logger)
+	if err != nil {
+		return fmt.Errorf("Failed to reject access request '%s'. %w", requestID, err)
+	}
+
+	return nil
+}
+
+// DeleteAccessRequest deletes an access request of the logged on user
+func (c Client) DeleteAccessRequest(requestID string) error {
+	url := fmt.Sprintf("%s/passwordvault/api/MyRequests/%s", c.BaseURL, url.QueryEscape(requestID))
+	_, err := c.GetTransport().Delete(c.GetContext(), false, url, c.SessionToken, c.Logger)
+	if err != nil {
+		return fmt.Errorf("Failed to delete access request '%s'. %w", requestID, err)
+	}
+
+	return nil
+}
+
+// WaitForAccountAccess waits until an access request for the account is confirmed. An open
+// access request of the logged on user for the account is reused, otherwise request is submitted.
+// An error is returned if the access request is rejected, expires, the timeout is reached or the
+// context of the client is done
+func (c Client) WaitForAccountAccess(request requests.CreateAccessRequest, options WaitForAccessOptions) (*responses.AccessRequest, error) {
+	if options.Interval <= 0 {
+		options.Interval = DefaultAccessRequestInterval
+	}
+
+	accessRequest, err := c.findAccessRequest(request.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	if accessRequest == nil {
+		accessRequest, err = c.CreateAccessRequest(request)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var deadline time.Time
+	if options.Timeout > 0 {
+		deadline = time.Now().Add(options.Timeout)
+	}
+	for {
+		if options.Progress != nil {
+			options.Progress(accessRequest)
+		}
+
+		switch accessRequestState(accessRequest) {
+		case accessRequestConfirmed:
+			return accessRequest, nil
+		case accessRequestClosed:
+			return accessRequest, fmt.Errorf("Access request '%s' for account '%s' was not confirmed. Status is '%s'", accessRequest.RequestID, request.AccountID, accessRequest.StatusTitle)
+		}
+
+		if !deadline.IsZero() && time.Now().Add(options.Interval).After(deadline) {
+			return accessRequest, fmt.Errorf("Timed out waiting for access request '%s' for account '%s' to be confirmed", accessRequest.RequestID, request.AccountID)
+		}
+		select {
+		case <-c.GetContext().Done():
+			return accessRequest, fmt.Errorf("Stopped waiting for access request '%s' for account '%s' to be confirmed. %w", accessRequest.RequestID, request.AccountID, c.GetContext().Err())
+		case <-time.After(options.Interval):
+		}
+
+		accessRequest, err = c.GetMyAccessRequest(accessRequest.RequestID)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// findAccessRequest returns an access request of the logged on user for the account that is
+// waiting or confirmed, or nil if there is none
+func (c Client) findAccessRequest(accountID string) (*responses.AccessRequest, error) {
+	accessRequests, err := c.ListMyAccessRequests(&queries.ListAccessRequests{})
+	if err != nil {
+		return nil, err
+	}
+
+	var waiting *responses.AccessRequest
+	for i, accessRequest := range accessRequests.MyRequests {
+		if accessRequest.AccountDetails.AccountID != accountID {
+			continue
+		}
+		switch accessRequestState(&accessRequest) {
+		case accessRequestConfirmed:
+			return &accessRequests.MyRequests[i], nil
+		case accessRequestWaiting:
+			waiting = &accessRequests.MyRequests[i]
+		}
+	}
+	return waiting, nil
+}
+
+const (
+	accessRequestWaiting = iota
+	accessRequestConfirmed
+	accessRequestClosed
+)
+
+// accessRequestState returns whether the access request is waiting for confirmation, confirmed,
+// or closed because it was rejected, deleted, expired or became invalid
+func accessRequestState(request *responses.AccessRequest) int {
+	switch request.Status {
+	case 0, AccessRequestStatusWaiting:
+		return accessRequestWaiting
+	case AccessRequestStatusConfirmed:
+		if request.ConfirmationsLeft > 0 {
+			return accessRequestWaiting
+		}
+		return accessRequestConfirmed
+	}
+	return accessRequestClosed
+}
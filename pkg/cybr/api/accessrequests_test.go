@@ -0,0 +1,169 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	pasapi "github.com/infamousjoeg/cybr-cli/pkg/cybr/api"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/queries"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/requests"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/responses"
+)
+
+// accessRequestStandIn is a PAS stand-in for access requests. A created request is confirmed
+// after confirmAfter checks, or has status finalStatus if set. Status titles are localized, so
+// they are in German
+type accessRequestStandIn struct {
+	requests     []responses.AccessRequest
+	created      []requests.CreateAccessRequest
+	reviewed     []string
+	checks       int
+	confirmAfter int
+	finalStatus  int
+}
+
+func (s *accessRequestStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/passwordvault/api/")
+	parts := strings.Split(path, "/")
+
+	switch {
+	case path == "MyRequests" && r.Method == http.MethodGet:
+		json.NewEncoder(w).Encode(responses.ListMyAccessRequests{MyRequests: s.requests})
+	case path == "MyRequests" && r.Method == http.MethodPost:
+		request := requests.CreateAccessRequest{}
+		json.NewDecoder(r.Body).Decode(&request)
+		s.created = append(s.created, request)
+		created := responses.AccessRequest{
+			RequestID: "Safe1_" + request.AccountID, Status: pasapi.AccessRequestStatusWaiting, StatusTitle: "Wartet", ConfirmationsLeft: 1,
+			AccountDetails: responses.AccessRequestAccount{AccountID: request.AccountID},
+		}
+		s.requests = append(s.requests, created)
+		json.NewEncoder(w).Encode(created)
+	case len(parts) == 2 && parts[0] == "MyRequests" && r.Method == http.MethodGet:
+		s.checks++
+		request := s.requests[len(s.requests)-1]
+		if s.checks >= s.confirmAfter {
+			request.Status, request.StatusTitle, request.ConfirmationsLeft = pasapi.AccessRequestStatusConfirmed, "Bestätigt", 0
+			if s.finalStatus != 0 {
+				request.Status, request.StatusTitle = s.finalStatus, "Abgelehnt"
+			}
+		}
+		json.NewEncoder(w).Encode(request)
+	case len(parts) == 2 && parts[0] == "MyRequests" && r.Method == http.MethodDelete:
+		s.reviewed = append(s.reviewed, "Delete "+parts[1])
+	case path == "IncomingRequests" && r.Method == http.MethodGet:
+		if r.URL.Query().Get("onlywaiting") != "true" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"ErrorCode":"PASWS000E","ErrorMessage":"Invalid query"}`))
+			return
+		}
+		w.Write([]byte(`{"IncomingRequests":[{"RequestID":"Safe1_12_3","RequestorUserName":"user1","StatusTitle":"Waiting","AccountDetails":{"AccountID":"12_3"}}]}`))
+	case len(parts) == 3 && parts[0] == "IncomingRequests" && r.Method == http.MethodPost:
+		review := requests.ReviewAccessRequest{}
+		json.NewDecoder(r.Body).Decode(&review)
+		s.reviewed = append(s.reviewed, parts[2]+" "+parts[1]+" "+review.Reason)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"ErrorCode":"PASWS000E","ErrorMessage":"Not found"}`))
+	}
+}
+
+func TestWaitForAccountAccess(t *testing.T) {
+	standIn := &accessRequestStandIn{confirmAfter: 2}
+	server := httptest.NewServer(standIn)
+	defer server.Close()
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	checked := 0
+	request, err := client.WaitForAccountAccess(requests.CreateAccessRequest{AccountID: "12_1", Reason: "Maintenance"}, pasapi.WaitForAccessOptions{
+		Interval: time.Millisecond,
+		Progress: func(request *responses.AccessRequest) { checked++ },
+	})
+	if err != nil {
+		t.Fatalf("Failed to wait for access. %s", err)
+	}
+	if request.RequestID != "Safe1_12_1" || request.Status != pasapi.AccessRequestStatusConfirmed || checked != 3 {
+		t.Errorf("Expected the access request to be confirmed after 3 checks. %+v %d", request, checked)
+	}
+	if len(standIn.created) != 1 || standIn.created[0].Reason != "Maintenance" {
+		t.Errorf("Invalid access request created. %+v", standIn.created)
+	}
+
+	// The confirmed request is reused
+	standIn.requests[0].Status = pasapi.AccessRequestStatusConfirmed
+	_, err = client.WaitForAccountAccess(requests.CreateAccessRequest{AccountID: "12_1"}, pasapi.WaitForAccessOptions{Interval: time.Millisecond})
+	if err != nil || len(standIn.created) != 1 {
+		t.Errorf("Expected the confirmed access request to be reused. %v %+v", err, standIn.created)
+	}
+}
+
+func TestWaitForAccountAccessRejected(t *testing.T) {
+	standIn := &accessRequestStandIn{confirmAfter: 1, finalStatus: pasapi.AccessRequestStatusRejected}
+	server := httptest.NewServer(standIn)
+	defer server.Close()
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	_, err := client.WaitForAccountAccess(requests.CreateAccessRequest{AccountID: "12_1"}, pasapi.WaitForAccessOptions{Interval: time.Millisecond})
+	if err == nil || !strings.Contains(err.Error(), "Abgelehnt") {
+		t.Errorf("Expected the rejected access request to fail but got %v", err)
+	}
+
+	standIn = &accessRequestStandIn{confirmAfter: 1000}
+	server = httptest.NewServer(standIn)
+	defer server.Close()
+
+	client = pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	_, err = client.WaitForAccountAccess(requests.CreateAccessRequest{AccountID: "12_1"}, pasapi.WaitForAccessOptions{Interval: time.Millisecond, Timeout: 20 * time.Millisecond})
+	if err == nil || !strings.Contains(err.Error(), "Timed out") {
+		t.Errorf("Expected waiting for the access request to time out but got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client = client.WithContext(ctx)
+	_, err = client.WaitForAccountAccess(requests.CreateAccessRequest{AccountID: "12_1"}, pasapi.WaitForAccessOptions{
+		Interval: time.Hour,
+		Progress: func(request *responses.AccessRequest) { cancel() },
+	})
+	if !errors.Is(err, context.Canceled) || !strings.Contains(err.Error(), "Stopped waiting") {
+		t.Errorf("Expected waiting for the access request to stop when the context is canceled but got %v", err)
+	}
+}
+
+func TestReviewAccessRequests(t *testing.T) {
+	standIn := &accessRequestStandIn{}
+	server := httptest.NewServer(standIn)
+	defer server.Close()
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	incoming, err := client.ListIncomingAccessRequests(&queries.ListAccessRequests{OnlyWaiting: true})
+	if err != nil {
+		t.Fatalf("Failed to list incoming access requests. %s", err)
+	}
+	if len(incoming.IncomingRequests) != 1 || incoming.IncomingRequests[0].AccountDetails.AccountID != "12_3" {
+		t.Errorf("Invalid incoming access requests. %+v", incoming)
+	}
+
+	err = client.ConfirmAccessRequest("Safe1_12_3", requests.ReviewAccessRequest{Reason: "Approved"})
+	if err != nil {
+		t.Errorf("Failed to confirm access request. %s", err)
+	}
+	err = client.RejectAccessRequest("Safe1_12_4", requests.ReviewAccessRequest{Reason: "No ticket"})
+	if err != nil {
+		t.Errorf("Failed to reject access request. %s", err)
+	}
+	err = client.DeleteAccessRequest("Safe1_12_5")
+	if err != nil {
+		t.Errorf("Failed to delete access request. %s", err)
+	}
+
+	expected := []string{"Confirm Safe1_12_3 Approved", "Reject Safe1_12_4 No ticket", "Delete Safe1_12_5"}
+	if strings.Join(standIn.reviewed, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v but got %v", expected, standIn.reviewed)
+	}
+}
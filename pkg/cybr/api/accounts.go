@@ -17,9 +17,9 @@ var emptyBody = struct{}{}
 // ListAccounts CyberArk user has access to
 func (c Client) ListAccounts(query *queries.ListAccounts) (*responses.ListAccount, error) {
 	url := fmt.Sprintf("%s/passwordvault/api/Accounts%s", c.BaseURL, httpJson.GetURLQuery(query))
-	response, err := httpJson.Get(false, url, c.SessionToken, c.InsecureTLS, c.Logger)
+	response, err := c.GetTransport().Get(c.GetContext(), false, url, c.SessionToken, c.Logger)
 	if err != nil {
-		return &responses.ListAccount{}, fmt.Errorf("Failed to list accounts. %s", err)
+		return &responses.ListAccount{}, fmt.Errorf("Failed to list accounts. %w", err)
 	}
 
 	jsonString, _ := json.Marshal(response)
@@ -28,12 +28,27 @@ func (c Client) ListAccounts(query *queries.ListAccounts) (*responses.ListAccoun
 	return &ListAccountsResponse, err
 }
 
+// IterateAccounts returns an iterator over all accounts matching query. Every page is read
+// instead of only the page selected by the offset and limit of query
+func (c Client) IterateAccounts(query *queries.ListAccounts) *AccountIterator {
+	q := queries.ListAccounts{}
+	if query != nil {
+		q = *query
+	}
+	return &AccountIterator{
+		pager: newPager(c, "accounts", "value", "count", q.Offset, q.Limit, func(offset int) string {
+			q.Offset = offset
+			return fmt.Sprintf("%s/passwordvault/api/Accounts%s", c.BaseURL, httpJson.GetURLQuery(&q))
+		}),
+	}
+}
+
 // GetAccount details for specific account
 func (c Client) GetAccount(accountID string) (*responses.GetAccount, error) {
 	url := fmt.Sprintf("%s/passwordvault/api/Accounts/%s", c.BaseURL, accountID)
-	response, err := httpJson.Get(false, url, c.SessionToken, c.InsecureTLS, c.Logger)
+	response, err := c.GetTransport().Get(c.GetContext(), false, url, c.SessionToken, c.Logger)
 	if err != nil {
-		return &responses.GetAccount{}, fmt.Errorf("Failed to get account. %s", err)
+		return &responses.GetAccount{}, fmt.Errorf("Failed to get account. %w", err)
 	}
 
 	jsonString, _ := json.Marshal(response)
@@ -46,12 +61,11 @@ func (c Client) GetAccount(accountID string) (*responses.GetAccount, error) {
 func (c Client) AddAccount(account requests.AddAccount) (*responses.GetAccount, error) {
 	url := fmt.Sprintf("%s/passwordvault/api/Accounts", c.BaseURL)
 	logger := c.GetLogger().AddSecret(account.Secret)
-	response, err := httpJson.Post(false, url, c.SessionToken, account, c.InsecureTLS, logger)
+	response, err := c.GetTransport().Post(c.GetContext(), false, url, c.SessionToken, account, logger)
 	logger = logger.ClearSecrets()
 
 	if err != nil {
-		returnedError, _ := json.Marshal(response)
-		return &responses.GetAccount{}, fmt.Errorf("Failed to add account. %s. %s", string(returnedError), err)
+		return &responses.GetAccount{}, fmt.Errorf("Failed to add account. %w", err)
 	}
 
 	jsonString, _ := json.Marshal(response)
@@ -63,10 +77,9 @@ func (c Client) AddAccount(account requests.AddAccount) (*responses.GetAccount,
 // DeleteAccount from cyberark
 func (c Client) DeleteAccount(accountID string) error {
 	url := fmt.Sprintf("%s/passwordvault/api/Accounts/%s", c.BaseURL, accountID)
-	response, err := httpJson.Delete(false, url, c.SessionToken, c.InsecureTLS, c.Logger)
+	_, err := c.GetTransport().Delete(c.GetContext(), false, url, c.SessionToken, c.Logger)
 	if err != nil {
-		returnedError, _ := json.Marshal(response)
-		return fmt.Errorf("Failed to delete account '%s'. %s. %s", accountID, string(returnedError), err)
+		return fmt.Errorf("Failed to delete account '%s'. %w", accountID, err)
 	}
 
 	return nil
@@ -75,10 +88,9 @@ func (c Client) DeleteAccount(accountID string) error {
 // GetJITAccess from a specific account
 func (c Client) GetJITAccess(accountID string) error {
 	url := fmt.Sprintf("%s/passwordvault/api/Accounts/%s/grantAdministrativeAccess", c.BaseURL, accountID)
-	response, err := httpJson.Post(false, url, c.SessionToken, emptyBody, c.InsecureTLS, c.Logger)
+	_, err := c.GetTransport().Post(c.GetContext(), false, url, c.SessionToken, emptyBody, c.Logger)
 	if err != nil {
-		returnedError, _ := json.Marshal(response)
-		return fmt.Errorf("Failed to get JIT access for account '%s'. %s. %s", accountID, string(returnedError), err)
+		return fmt.Errorf("Failed to get JIT access for account '%s'. %w", accountID, err)
 	}
 
 	return nil
@@ -87,10 +99,9 @@ func (c Client) GetJITAccess(accountID string) error {
 // RevokeJITAccess from a specific account
 func (c Client) RevokeJITAccess(accountID string) error {
 	url := fmt.Sprintf("%s/passwordvault/api/Accounts/%s/RevokeAdministrativeAccess", c.BaseURL, accountID)
-	response, err := httpJson.Post(false, url, c.SessionToken, emptyBody, c.InsecureTLS, c.Logger)
+	_, err := c.GetTransport().Post(c.GetContext(), false, url, c.SessionToken, emptyBody, c.Logger)
 	if err != nil {
-		returnedError, _ := json.Marshal(response)
-		return fmt.Errorf("Failed to revoke JIT access for account '%s'. %s. %s", accountID, string(returnedError), err)
+		return fmt.Errorf("Failed to revoke JIT access for account '%s'. %w", accountID, err)
 	}
 
 	return nil
@@ -100,35 +111,32 @@ func (c Client) RevokeJITAccess(accountID string) error {
 func (c Client) GetAccountPassword(accountID string, request requests.GetAccountPassword) (string, error) {
 	url := fmt.Sprintf("%s/passwordvault/api/Accounts/%s/Password/Retrieve", c.BaseURL, accountID)
 
-	response, err := httpJson.SendRequestRaw(false, url, "POST", c.SessionToken, request, c.InsecureTLS, c.Logger)
+	response, err := c.GetTransport().SendRequestRaw(c.GetContext(), false, url, "POST", c.SessionToken, request, c.Logger)
 	if err != nil {
-		returnedError, _ := json.Marshal(response)
-		return "", fmt.Errorf("Failed to retrieve the account password '%s'. %s. %s", accountID, string(returnedError), err)
+		return "", fmt.Errorf("Failed to retrieve the account password '%s'. %w", accountID, err)
 	}
 
-	return strings.Trim(string(response), "\""), nil
+	return decodeSecret(response), nil
 }
 
 // GetAccountSSHKey This method enables users to retrieve the password or SSH key of an existing account that is identified by its Account ID. It enables users to specify a reason and ticket ID, if required
 func (c Client) GetAccountSSHKey(accountID string, request requests.GetAccountPassword) (string, error) {
 	url := fmt.Sprintf("%s/passwordvault/api/Accounts/%s/Secret/Retrieve", c.BaseURL, accountID)
 
-	response, err := httpJson.SendRequestRaw(false, url, "POST", c.SessionToken, request, c.InsecureTLS, c.Logger)
+	response, err := c.GetTransport().SendRequestRaw(c.GetContext(), false, url, "POST", c.SessionToken, request, c.Logger)
 	if err != nil {
-		returnedError, _ := json.Marshal(response)
-		return "", fmt.Errorf("Failed to retrieve the account SSH Key '%s'. %s. %s", accountID, string(returnedError), err)
+		return "", fmt.Errorf("Failed to retrieve the account SSH Key '%s'. %w", accountID, err)
 	}
 
-	return strings.Trim(string(response), "\""), nil
+	return decodeSecret(response), nil
 }
 
 // VerifyAccountCredentials marks an account for verification
 func (c Client) VerifyAccountCredentials(accountID string) error {
 	url := fmt.Sprintf("%s/passwordvault/API/Accounts/%s/Verify", c.BaseURL, accountID)
-	response, err := httpJson.Post(false, url, c.SessionToken, emptyBody, c.InsecureTLS, c.Logger)
+	_, err := c.GetTransport().Post(c.GetContext(), false, url, c.SessionToken, emptyBody, c.Logger)
 	if err != nil {
-		returnedError, _ := json.Marshal(response)
-		return fmt.Errorf("Failed to verify account '%s'. %s. %s", accountID, string(returnedError), err)
+		return fmt.Errorf("Failed to verify account '%s'. %w", accountID, err)
 	}
 
 	return nil
@@ -153,10 +161,9 @@ func (c Client) ChangeAccountCredentials(accountID string, changeEntireGroup boo
 		NewCredentials:    newPassword,
 	}
 
-	response, err := httpJson.Post(false, url, c.SessionToken, body, c.InsecureTLS, c.Logger)
+	_, err := c.GetTransport().Post(c.GetContext(), false, url, c.SessionToken, body, c.Logger)
 	if err != nil {
-		returnedError, _ := json.Marshal(response)
-		return fmt.Errorf("Failed to mark change on account '%s'. %s. %s", accountID, string(returnedError), err)
+		return fmt.Errorf("Failed to mark change on account '%s'. %w", accountID, err)
 	}
 
 	return nil
@@ -165,10 +172,9 @@ func (c Client) ChangeAccountCredentials(accountID string, changeEntireGroup boo
 // ReconileAccountCredentials marks an account for reconciliation
 func (c Client) ReconileAccountCredentials(accountID string) error {
 	url := fmt.Sprintf("%s/passwordvault/API/Accounts/%s/Reconcile", c.BaseURL, accountID)
-	response, err := httpJson.Post(false, url, c.SessionToken, emptyBody, c.InsecureTLS, c.Logger)
+	_, err := c.GetTransport().Post(c.GetContext(), false, url, c.SessionToken, emptyBody, c.Logger)
 	if err != nil {
-		returnedError, _ := json.Marshal(response)
-		return fmt.Errorf("Failed to mark reconcile on account '%s'. %s. %s", accountID, string(returnedError), err)
+		return fmt.Errorf("Failed to mark reconcile on account '%s'. %w", accountID, err)
 	}
 
 	return nil
@@ -177,10 +183,9 @@ func (c Client) ReconileAccountCredentials(accountID string) error {
 // Unlock removes a lock from an account
 func (c Client) Unlock(accountID string) error {
 	url := fmt.Sprintf("%s/passwordvault/API/Accounts/%s/Unlock", c.BaseURL, accountID)
-	response, err := httpJson.Post(false, url, c.SessionToken, emptyBody, c.InsecureTLS, c.Logger)
+	_, err := c.GetTransport().Post(c.GetContext(), false, url, c.SessionToken, emptyBody, c.Logger)
 	if err != nil {
-		returnedError, _ := json.Marshal(response)
-		return fmt.Errorf("Failed to unlock account '%s'. %s. %s", accountID, string(returnedError), err)
+		return fmt.Errorf("Failed to unlock account '%s'. %w", accountID, err)
 	}
 
 	return nil
@@ -189,11 +194,117 @@ func (c Client) Unlock(accountID string) error {
 // CheckIn checks in an account that is checked out by the user
 func (c Client) CheckIn(accountID string) error {
 	url := fmt.Sprintf("%s/passwordvault/API/Accounts/%s/CheckIn", c.BaseURL, accountID)
-	response, err := httpJson.Post(false, url, c.SessionToken, emptyBody, c.InsecureTLS, c.Logger)
+	_, err := c.GetTransport().Post(c.GetContext(), false, url, c.SessionToken, emptyBody, c.Logger)
 	if err != nil {
-		returnedError, _ := json.Marshal(response)
-		return fmt.Errorf("Failed to check-in account '%s'. %s. %s", accountID, string(returnedError), err)
+		return fmt.Errorf("Failed to check-in account '%s'. %w", accountID, err)
 	}
 
 	return nil
 }
+
+// ListLinkedAccounts returns the logon, enable, reconcile and other accounts linked to an account
+func (c Client) ListLinkedAccounts(accountID string) (*responses.ListLinkedAccounts, error) {
+	url := fmt.Sprintf("%s/passwordvault/api/ExtendedAccounts/%s/LinkedAccounts", c.BaseURL, accountID)
+	response, err := c.GetTransport().Get(c.GetContext(), false, url, c.SessionToken, c.Logger)
+	if err != nil {
+		return &responses.ListLinkedAccounts{}, fmt.Errorf("Failed to list linked accounts of account '%s'. %w", accountID, err)
+	}
+
+	jsonString, _ := json.Marshal(response)
+	ListLinkedAccountsResponse := &responses.ListLinkedAccounts{}
+	err = json.Unmarshal(jsonString, ListLinkedAccountsResponse)
+	return ListLinkedAccountsResponse, err
+}
+
+// LinkAccount links an account to the account at the index of the link, e.g. 1 for the logon account
+func (c Client) LinkAccount(accountID string, link requests.LinkAccount) error {
+	url := fmt.Sprintf("%s/passwordvault/api/Accounts/%s/LinkAccount", c.BaseURL, accountID)
+	_, err := c.GetTransport().Post(c.GetContext(), false, url, c.SessionToken, link, c.Logger)
+	if err != nil {
+		return fmt.Errorf("Failed to link account '%s' to account '%s'. %w", link.Name, accountID, err)
+	}
+
+	return nil
+}
+
+// UnlinkAccount removes the account linked to an account at the index of the link
+func (c Client) UnlinkAccount(accountID string, extraPasswordIndex int) error {
+	url := fmt.Sprintf("%s/passwordvault/api/Accounts/%s/LinkAccount/%d", c.BaseURL, accountID, extraPasswordIndex)
+	_, err := c.GetTransport().Delete(c.GetContext(), false, url, c.SessionToken, c.Logger)
+	if err != nil {
+		return fmt.Errorf("Failed to unlink account at index '%d' from account '%s'. %w", extraPasswordIndex, accountID, err)
+	}
+
+	return nil
+}
+
+const (
+	// LinkIndexLogon is the index of the logon account linked to an account
+	LinkIndexLogon = 1
+	// LinkIndexEnable is the index of the enable account linked to an account
+	LinkIndexEnable = 2
+	// LinkIndexReconcile is the index of the reconcile account linked to an account
+	LinkIndexReconcile = 3
+)
+
+// linkTypes are the names of the indexes of linked accounts. The extra names are those of the
+// extra passwords of the platform
+var linkTypes = map[string]int{
+	"logon":     LinkIndexLogon,
+	"enable":    LinkIndexEnable,
+	"reconcile": LinkIndexReconcile,
+	"extra1":    LinkIndexLogon,
+	"extra2":    LinkIndexEnable,
+	"extra3":    LinkIndexReconcile,
+}
+
+// GetLinkIndex returns the index of a linked account type: logon, enable, reconcile or extra1 to extra3
+func GetLinkIndex(linkType string) (int, error) {
+	index, ok := linkTypes[strings.ToLower(linkType)]
+	if !ok {
+		return 0, fmt.Errorf("Invalid link type '%s'. Valid values: logon, enable, reconcile, extra1, extra2 or extra3", linkType)
+	}
+	return index, nil
+}
+
+// ListAccountActivities returns the actions performed on an account
+func (c Client) ListAccountActivities(accountID string) (*responses.ListAccountActivities, error) {
+	url := fmt.Sprintf("%s/passwordvault/api/Accounts/%s/Activities", c.BaseURL, accountID)
+	response, err := c.GetTransport().Get(c.GetContext(), false, url, c.SessionToken, c.Logger)
+	if err != nil {
+		return &responses.ListAccountActivities{}, fmt.Errorf("Failed to list activities of account '%s'. %w", accountID, err)
+	}
+
+	jsonString, _ := json.Marshal(response)
+	ListAccountActivitiesResponse := &responses.ListAccountActivities{}
+	err = json.Unmarshal(jsonString, ListAccountActivitiesResponse)
+	return ListAccountActivitiesResponse, err
+}
+
+// ListSecretVersions returns the versions of the secret of an account, which can be retrieved
+// with the Version of GetAccountPassword
+func (c Client) ListSecretVersions(accountID string, query *queries.ListSecretVersions) (*responses.ListSecretVersions, error) {
+	if query == nil {
+		query = &queries.ListSecretVersions{}
+	}
+	url := fmt.Sprintf("%s/passwordvault/api/Accounts/%s/Secret/Versions%s", c.BaseURL, accountID, httpJson.GetURLQuery(query))
+	response, err := c.GetTransport().Get(c.GetContext(), false, url, c.SessionToken, c.Logger)
+	if err != nil {
+		return &responses.ListSecretVersions{}, fmt.Errorf("Failed to list secret versions of account '%s'. %w", accountID, err)
+	}
+
+	jsonString, _ := json.Marshal(response)
+	ListSecretVersionsResponse := &responses.ListSecretVersions{}
+	err = json.Unmarshal(jsonString, ListSecretVersionsResponse)
+	return ListSecretVersionsResponse, err
+}
+
+// decodeSecret returns the secret of a retrieve response. The secret is usually a JSON string,
+// whose escaped characters such as the newlines of an SSH key are decoded
+func decodeSecret(response []byte) string {
+	var secret string
+	if err := json.Unmarshal(response, &secret); err == nil {
+		return secret
+	}
+	return strings.Trim(string(response), "\"")
+}
@@ -1,8 +1,11 @@
 package api_test
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	pasapi "github.com/infamousjoeg/cybr-cli/pkg/cybr/api"
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/queries"
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/requests"
 )
@@ -251,3 +254,75 @@ func TestCheckInFailure(t *testing.T) {
 		t.Errorf("Set account for check in but it should not exist")
 	}
 }
+
+func TestGetLinkIndex(t *testing.T) {
+	for linkType, expected := range map[string]int{"logon": 1, "Enable": 2, "reconcile": 3, "extra1": 1, "EXTRA3": 3} {
+		index, err := pasapi.GetLinkIndex(linkType)
+		if err != nil || index != expected {
+			t.Errorf("Expected link type '%s' to have index %d but got %d. %v", linkType, expected, index, err)
+		}
+	}
+
+	_, err := pasapi.GetLinkIndex("extra4")
+	if err == nil {
+		t.Errorf("Expected an invalid link type to fail")
+	}
+}
+
+func TestUnlinkAccount(t *testing.T) {
+	requested := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.Method + " " + r.URL.Path
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	err := client.UnlinkAccount("24_1", pasapi.LinkIndexReconcile)
+	if err != nil {
+		t.Errorf("Failed to unlink account. %s", err)
+	}
+	if requested != "DELETE /passwordvault/api/Accounts/24_1/LinkAccount/3" {
+		t.Errorf("Invalid request '%s'", requested)
+	}
+}
+
+func TestListAccountActivitiesAndSecretVersions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/passwordvault/api/Accounts/24_1/Activities":
+			w.Write([]byte(`{"Activities":[{"Date":1600000000,"User":"Administrator","Action":"Retrieve password","ActionID":295,"ClientID":"PVWA","Reason":"Maintenance"}]}`))
+		case "/passwordvault/api/Accounts/24_1/Secret/Versions":
+			if r.URL.Query().Get("showTemporary") != "true" {
+				t.Errorf("Expected temporary versions to be requested")
+			}
+			w.Write([]byte(`{"Versions":[{"versionID":2,"modifiedBy":"PasswordManager","modificationDate":1600000100,"isTemporary":false},{"versionID":1,"modifiedBy":"Administrator","modificationDate":1600000000,"isTemporary":true}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"ErrorCode":"PASWS000E","ErrorMessage":"Not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	activities, err := client.ListAccountActivities("24_1")
+	if err != nil {
+		t.Fatalf("Failed to list account activities. %s", err)
+	}
+	if len(activities.Activities) != 1 || activities.Activities[0].Action != "Retrieve password" || activities.Activities[0].Date != 1600000000 {
+		t.Errorf("Invalid activities. %+v", activities)
+	}
+
+	versions, err := client.ListSecretVersions("24_1", &queries.ListSecretVersions{ShowTemporary: true})
+	if err != nil {
+		t.Fatalf("Failed to list secret versions. %s", err)
+	}
+	if len(versions.Versions) != 2 || versions.Versions[0].VersionID != 2 || !versions.Versions[1].IsTemporary {
+		t.Errorf("Invalid versions. %+v", versions)
+	}
+
+	_, err = client.ListAccountActivities("99_9")
+	if err == nil {
+		t.Errorf("Expected an unknown account to fail")
+	}
+}
@@ -7,15 +7,14 @@ import (
 
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/requests"
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/responses"
-	httpJson "github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/httpjson"
 )
 
 // ListApplications returns all Application Identities setup in PAS
 func (c Client) ListApplications(location string) (*responses.ListApplications, error) {
 	url := fmt.Sprintf("%s/passwordvault/WebServices/PIMServices.svc/Applications?Location=%s", c.BaseURL, location)
-	response, err := httpJson.Get(false, url, c.SessionToken, c.InsecureTLS, c.Logger)
+	response, err := c.GetTransport().Get(c.GetContext(), false, url, c.SessionToken, c.Logger)
 	if err != nil {
-		return &responses.ListApplications{}, fmt.Errorf("Error listing applications in location '%s'. %s", location, err)
+		return &responses.ListApplications{}, fmt.Errorf("Error listing applications in location '%s'. %w", location, err)
 	}
 	jsonString, _ := json.Marshal(response)
 	ListApplicationsResponse := responses.ListApplications{}
@@ -26,9 +25,9 @@ func (c Client) ListApplications(location string) (*responses.ListApplications,
 // ListApplicationAuthenticationMethods returns all auth methods for a specific Application Identity
 func (c Client) ListApplicationAuthenticationMethods(appID string) (*responses.ListApplicationAuthenticationMethods, error) {
 	url := fmt.Sprintf("%s/passwordvault/WebServices/PIMServices.svc/Applications/%s/Authentications", c.BaseURL, appID)
-	response, err := httpJson.Get(false, url, c.SessionToken, c.InsecureTLS, c.Logger)
+	response, err := c.GetTransport().Get(c.GetContext(), false, url, c.SessionToken, c.Logger)
 	if err != nil {
-		return &responses.ListApplicationAuthenticationMethods{}, fmt.Errorf("Error listing application's '%s' authentication methods. %s", appID, err)
+		return &responses.ListApplicationAuthenticationMethods{}, fmt.Errorf("Error listing application's '%s' authentication methods. %w", appID, err)
 	}
 	jsonString, _ := json.Marshal(response)
 	ListApplicationAuthenticationMethodsResponse := responses.ListApplicationAuthenticationMethods{}
@@ -39,10 +38,9 @@ func (c Client) ListApplicationAuthenticationMethods(appID string) (*responses.L
 // AddApplication add an applications to PAS
 func (c Client) AddApplication(application requests.AddApplication) error {
 	url := fmt.Sprintf("%s/passwordvault/WebServices/PIMServices.svc/Applications", c.BaseURL)
-	response, err := httpJson.Post(false, url, c.SessionToken, application, c.InsecureTLS, c.Logger)
+	_, err := c.GetTransport().Post(c.GetContext(), false, url, c.SessionToken, application, c.Logger)
 	if err != nil {
-		returnedError, _ := json.Marshal(response)
-		return fmt.Errorf("Error adding application '%s' authentication methods. %s. %s", application.Application.AppID, string(returnedError), err)
+		return fmt.Errorf("Error adding application '%s' authentication methods. %w", application.Application.AppID, err)
 	}
 	return nil
 }
@@ -50,10 +48,9 @@ func (c Client) AddApplication(application requests.AddApplication) error {
 // DeleteApplication delete an applications to PAS
 func (c Client) DeleteApplication(appID string) error {
 	url := fmt.Sprintf("%s/passwordvault/WebServices/PIMServices.svc/Applications/%s", c.BaseURL, url.QueryEscape(appID))
-	response, err := httpJson.Delete(false, url, c.SessionToken, c.InsecureTLS, c.Logger)
+	_, err := c.GetTransport().Delete(c.GetContext(), false, url, c.SessionToken, c.Logger)
 	if err != nil {
-		returnedError, _ := json.Marshal(response)
-		return fmt.Errorf("Error deleting application '%s' authentication methods. %s. %s", appID, string(returnedError), err)
+		return fmt.Errorf("Error deleting application '%s' authentication methods. %w", appID, err)
 	}
 	return nil
 }
@@ -61,10 +58,9 @@ func (c Client) DeleteApplication(appID string) error {
 // AddApplicationAuthenticationMethod add authentication method to an application
 func (c Client) AddApplicationAuthenticationMethod(appID string, authenticationMethod requests.AddApplicationAuthentication) error {
 	url := fmt.Sprintf("%s/passwordvault/WebServices/PIMServices.svc/Applications/%s/Authentications/", c.BaseURL, url.QueryEscape(appID))
-	response, err := httpJson.Post(false, url, c.SessionToken, authenticationMethod, c.InsecureTLS, c.Logger)
+	_, err := c.GetTransport().Post(c.GetContext(), false, url, c.SessionToken, authenticationMethod, c.Logger)
 	if err != nil {
-		returnedError, _ := json.Marshal(response)
-		return fmt.Errorf("Error adding application authentication method to '%s'. %s. %s", appID, string(returnedError), err)
+		return fmt.Errorf("Error adding application authentication method to '%s'. %w", appID, err)
 	}
 	return nil
 }
@@ -72,10 +68,9 @@ func (c Client) AddApplicationAuthenticationMethod(appID string, authenticationM
 // DeleteApplicationAuthenticationMethod delete an applications authentication method
 func (c Client) DeleteApplicationAuthenticationMethod(appID string, authnMethodID string) error {
 	url := fmt.Sprintf("%s/passwordvault/WebServices/PIMServices.svc/Applications/%s/Authentications/%s", c.BaseURL, url.QueryEscape(appID), url.QueryEscape(authnMethodID))
-	response, err := httpJson.Delete(false, url, c.SessionToken, c.InsecureTLS, c.Logger)
+	_, err := c.GetTransport().Delete(c.GetContext(), false, url, c.SessionToken, c.Logger)
 	if err != nil {
-		returnedError, _ := json.Marshal(response)
-		return fmt.Errorf("Error deleting application '%s' authentication methods. %s. %s", appID, string(returnedError), err)
+		return fmt.Errorf("Error deleting application '%s' authentication methods. %w", appID, err)
 	}
 	return nil
 }
@@ -1,11 +1,13 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/requests"
-	httpJson "github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/httpjson"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/httpjson"
 )
 
 // Logon to PAS REST API Web Service
@@ -19,22 +21,83 @@ func (c *Client) Logon(req requests.Logon) error {
 
 	// Handle cyberark, ldap, and radius push, append & challenge/response authentication methods
 	url := fmt.Sprintf("%s/passwordvault/api/auth/%s/logon", c.BaseURL, c.AuthType)
-	token, err := httpJson.SendRequestRaw(false, url, "POST", "", req, c.InsecureTLS, c.Logger)
+	token, err := c.GetTransport().SendRequestRaw(c.GetContext(), false, url, "POST", "", req, c.Logger)
 	if err != nil {
-		return fmt.Errorf("Failed to authenticate to the PAS REST API. %s", err)
+		return fmt.Errorf("Failed to authenticate to the PAS REST API. %w", err)
 	}
 
 	c.SessionToken = strings.Trim(string(token), "\"")
 	return nil
 }
 
+// reauthenticationErrors remembers the users who failed to log on again, by PVWA and username,
+// so a wrong or expired password is not sent again for every following request and does not
+// lock out the user
+var (
+	reauthenticationErrors     = map[string]error{}
+	reauthenticationErrorsLock sync.Mutex
+)
+
+// EnableReauthentication logs on again as the client's user with password when a request is
+// rejected because the session token expired, and the request is sent once more. The new
+// session token is saved to the client's profile so following commands use it too. If logging
+// on again fails, it is not attempted again for the rest of the process
+func (c *Client) EnableReauthentication(password string) error {
+	err := c.IsValid()
+	if err != nil {
+		return err
+	}
+	if c.Username == "" {
+		return fmt.Errorf("Failed to enable reauthentication. The username of profile '%s' is unknown, logon again first", c.Profile)
+	}
+	if password == "" {
+		return fmt.Errorf("Failed to enable reauthentication. The password is empty")
+	}
+
+	// The shared transport is used by other clients so it must not reauthenticate as this user
+	if c.transport == nil {
+		c.transport = httpjson.NewTransport(c.InsecureTLS)
+	}
+
+	client := *c
+	key := c.BaseURL + "|" + c.Username
+	c.transport.Reauthenticate = func(ctx context.Context) (string, error) {
+		reauthenticationErrorsLock.Lock()
+		defer reauthenticationErrorsLock.Unlock()
+		if err, ok := reauthenticationErrors[key]; ok {
+			return "", err
+		}
+
+		client = client.WithContext(ctx)
+		client.SessionToken = ""
+		client.Logger = c.GetLogger().AddSecret(password)
+		err := client.Logon(requests.Logon{
+			Username:          client.Username,
+			Password:          password,
+			ConcurrentSession: true,
+		})
+		if err != nil {
+			reauthenticationErrors[key] = err
+			return "", err
+		}
+
+		client.Logger = client.Logger.ClearSecrets()
+		err = client.SetConfig()
+		if err != nil {
+			client.GetLogger().Writef("Failed to save the new session token. %s\n", err)
+		}
+		return client.SessionToken, nil
+	}
+	return nil
+}
+
 // Logoff the PAS REST API Web Service
 func (c Client) Logoff() error {
 	// Set URL for request
 	url := fmt.Sprintf("%s/passwordvault/api/auth/logoff", c.BaseURL)
-	_, err := httpJson.Post(false, url, c.SessionToken, emptyBody, c.InsecureTLS, c.Logger)
+	_, err := c.GetTransport().Post(c.GetContext(), false, url, c.SessionToken, emptyBody, c.Logger)
 	if err != nil {
-		return fmt.Errorf("Unable to logoff PAS REST API Web Service. %s", err)
+		return fmt.Errorf("Unable to logoff PAS REST API Web Service. %w", err)
 	}
 	return nil
 }
@@ -1,6 +1,9 @@
 package api_test
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
@@ -113,3 +116,89 @@ func TestCyberarkLogoffFailNotLoggedIn(t *testing.T) {
 		t.Errorf("Expected to recieve 401 statuc code. %s", err)
 	}
 }
+
+func TestEnableReauthentication(t *testing.T) {
+	setTempHome(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/passwordvault/api/auth/cyberark/logon":
+			creds := requests.Logon{}
+			json.NewDecoder(r.Body).Decode(&creds)
+			if creds.Username != "alice" || creds.Password != "secret" || !creds.ConcurrentSession {
+				t.Errorf("Invalid logon request. %+v", creds)
+			}
+			w.Write([]byte(`"new-token"`))
+		case "/passwordvault/api/LoggedOnUser":
+			if r.Header.Get("Authorization") != "new-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"ErrorCode":"PASWS006E","ErrorMessage":"Your session expired"}`))
+				return
+			}
+			w.Write([]byte(`{"id":2,"username":"alice"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark", Profile: pasapi.DefaultProfile, SessionToken: "expired-token"}
+	err := client.EnableReauthentication("secret")
+	if err == nil {
+		t.Errorf("Expected reauthentication without a username to fail")
+	}
+
+	client.Username = "alice"
+	err = client.EnableReauthentication("secret")
+	if err != nil {
+		t.Fatalf("Failed to enable reauthentication. %s", err)
+	}
+	user, err := client.GetLoggedOnUser()
+	if err != nil {
+		t.Fatalf("Expected the request to be sent again after logging on. %s", err)
+	}
+	if user.Username != "alice" {
+		t.Errorf("Invalid user. %+v", user)
+	}
+
+	saved, err := pasapi.GetConfig()
+	if err != nil || saved.SessionToken != "new-token" || saved.Username != "alice" {
+		t.Errorf("Expected the new session token to be saved. %+v %v", saved, err)
+	}
+}
+
+func TestEnableReauthenticationRemembersFailure(t *testing.T) {
+	setTempHome(t)
+
+	logons := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		if r.URL.Path == "/passwordvault/api/auth/cyberark/logon" {
+			logons++
+			w.Write([]byte(`{"ErrorCode":"ITATS004E","ErrorMessage":"Authentication failure"}`))
+			return
+		}
+		w.Write([]byte(`{"ErrorCode":"PASWS006E","ErrorMessage":"Your session expired"}`))
+	}))
+	defer server.Close()
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark", Profile: pasapi.DefaultProfile, Username: "alice", SessionToken: "expired-token"}
+	err := client.EnableReauthentication("wrong")
+	if err != nil {
+		t.Fatalf("Failed to enable reauthentication. %s", err)
+	}
+	for i := 0; i < 2; i++ {
+		_, err = client.GetLoggedOnUser()
+		if err == nil || !strings.Contains(err.Error(), "Failed to reauthenticate") {
+			t.Errorf("Expected the reauthentication to fail but got %v", err)
+		}
+	}
+
+	// Another client of the same user must not send the wrong password again either
+	other := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark", Profile: pasapi.DefaultProfile, Username: "alice", SessionToken: "expired-token"}
+	other.EnableReauthentication("wrong")
+	other.GetLoggedOnUser()
+	if logons != 1 {
+		t.Errorf("Expected a single logon attempt but got %d", logons)
+	}
+}
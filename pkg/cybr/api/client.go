@@ -1,11 +1,13 @@
 package api
 
 import (
+	"context"
 	"encoding/gob"
 	"fmt"
 	"os"
 
-	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/util"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/credstore"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/httpjson"
 	"github.com/infamousjoeg/cybr-cli/pkg/logger"
 )
 
@@ -16,7 +18,12 @@ type Client struct {
 	TenantID     string
 	InsecureTLS  bool
 	SessionToken string
+	Profile      string
+	Username     string
 	Logger       logger.Logger
+
+	transport *httpjson.Transport
+	ctx       context.Context
 }
 
 // IsValid checks to make sure that the authentication method chosen is valid
@@ -27,91 +34,148 @@ func (c *Client) IsValid() error {
 	return fmt.Errorf("Invalid auth type '%s'", c.AuthType)
 }
 
-// SetConfig file on the local filesystem for use
+// SetConfig file on the local filesystem for use. The file written belongs
+// to the client's profile, or the active profile if none is set. The session
+// token is kept in the credential store instead of the configuration file
 func (c *Client) SetConfig() error {
-	// Get user home directory
-	userHome, err := util.GetUserHomeDir()
+	c.Profile = ResolveProfile(c.Profile)
+	configPath, err := GetProfileConfigPath(c.Profile)
 	if err != nil {
-		return fmt.Errorf("ACL error. %s", err)
+		return err
 	}
 
-	// Check if .cybr directory already exists, create if not
-	if _, err = os.Stat(userHome + "/.cybr"); os.IsNotExist(err) {
-		// Create .cybr folder in user home directory
-		err = os.Mkdir(userHome+"/.cybr", 0766)
-		if err != nil {
-			return fmt.Errorf("Could not create folder %s/.cybr on local file system. %s", userHome, err)
-		}
+	// Check if .cybr directory and profile directory already exist, create if not
+	err = createConfigDir(c.Profile)
+	if err != nil {
+		return err
+	}
+
+	store, err := credstore.GetStore()
+	if err != nil {
+		return err
+	}
+	if c.SessionToken != "" {
+		err = store.Set(sessionTokenKey(c.Profile), c.SessionToken)
+	} else {
+		err = store.Delete(sessionTokenKey(c.Profile))
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to save session token in the %s credential store. %s", store.Name(), err)
 	}
 
 	// Check for config file and remove if existing
-	if _, err = os.Stat(userHome + "/.cybr/config"); !os.IsNotExist(err) {
-		err = os.Remove(userHome + "/.cybr/config")
+	if _, err = os.Stat(configPath); !os.IsNotExist(err) {
+		err = os.Remove(configPath)
 		if err != nil {
-			return fmt.Errorf("Could not remove existing %s/.cybr/config file. %s", userHome, err)
+			return fmt.Errorf("Could not remove existing %s file. %s", configPath, err)
 		}
 	}
 	// Create config file in user home directory
-	dataFile, err := os.Create(userHome + "/.cybr/config")
+	dataFile, err := os.OpenFile(configPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
 	if err != nil {
-		return fmt.Errorf("Could not create configuration file at %s/.cybr/config. %s", userHome, err)
+		return fmt.Errorf("Could not create configuration file at %s. %s", configPath, err)
 	}
+	defer dataFile.Close()
 
-	// serialize the data
+	// serialize the data without the session token
+	config := *c
+	config.SessionToken = ""
+	config.Logger = nil
 	dataEncoder := gob.NewEncoder(dataFile)
-	dataEncoder.Encode(&c)
-
-	dataFile.Close()
+	err = dataEncoder.Encode(&config)
+	if err != nil {
+		return fmt.Errorf("Could not write configuration file at %s. %s", configPath, err)
+	}
 
 	return nil
 }
 
-// GetConfig file from local filesystem and read
+// GetConfig file from local filesystem and read. The active profile is used
 func GetConfig() (Client, error) {
+	return GetProfileConfig("")
+}
+
+// GetProfileConfig reads the configuration file of the given profile from the local filesystem.
+// If profile is empty the active profile is used
+func GetProfileConfig(profile string) (Client, error) {
 	var client Client
 
-	// Get user home directory
-	userHome, err := util.GetUserHomeDir()
+	profile = ResolveProfile(profile)
+	configPath, err := GetProfileConfigPath(profile)
 	if err != nil {
-		return Client{}, fmt.Errorf("ACL error. %s", err)
+		return Client{}, err
 	}
 
 	// open data file
-	dataFile, err := os.Open(userHome + "/.cybr/config")
+	dataFile, err := os.Open(configPath)
 	if err != nil {
-		return Client{}, fmt.Errorf("Failed to retrieve configuration file at .cybr/config. %s", err)
+		return Client{}, fmt.Errorf("Failed to retrieve configuration file for profile '%s' at %s. %s", profile, configPath, err)
 	}
 
 	dataDecoder := gob.NewDecoder(dataFile)
 	err = dataDecoder.Decode(&client)
+	dataFile.Close()
 	if err != nil {
-		return Client{}, fmt.Errorf("Failed to decode configuration file at .cybr/config. %s", err)
+		return Client{}, fmt.Errorf("Failed to decode configuration file for profile '%s' at %s. %s", profile, configPath, err)
 	}
 
-	dataFile.Close()
+	// Configuration files written before profiles existed do not contain a profile name
+	client.Profile = profile
+
+	store, err := credstore.GetStore()
+	if err != nil {
+		return Client{}, err
+	}
+
+	// Configuration files written by older versions contain the session token in plaintext.
+	// Move it into the credential store and rewrite the file without it
+	if client.SessionToken != "" {
+		err = client.SetConfig()
+		if err != nil {
+			return Client{}, fmt.Errorf("Failed to migrate session token of profile '%s' to the %s credential store. %s", profile, store.Name(), err)
+		}
+		return client, nil
+	}
+
+	client.SessionToken, err = store.Get(sessionTokenKey(profile))
+	if err != nil && err != credstore.ErrNotFound {
+		return Client{}, fmt.Errorf("Failed to retrieve session token of profile '%s' from the %s credential store. %s", profile, store.Name(), err)
+	}
 
 	return client, nil
 }
 
 // GetConfigWithLogger is the same as GetConfig except it also sets the logger
 func GetConfigWithLogger(logger logger.Logger) (Client, error) {
-	client, err := GetConfig()
+	return GetProfileConfigWithLogger("", logger)
+}
+
+// GetProfileConfigWithLogger is the same as GetProfileConfig except it also sets the logger
+func GetProfileConfigWithLogger(profile string, logger logger.Logger) (Client, error) {
+	client, err := GetProfileConfig(profile)
 	client.Logger = logger
 	return client, err
 }
 
 // RemoveConfig file on the local filesystem
 func (c *Client) RemoveConfig() error {
-	// Get user home directory
-	userHome, err := util.GetUserHomeDir()
+	configPath, err := GetProfileConfigPath(ResolveProfile(c.Profile))
 	if err != nil {
-		return fmt.Errorf("ACL error. %s", err)
+		return err
 	}
 
-	fullPath := userHome + "/.cybr/config"
-	err = os.Remove(fullPath)
+	err = os.Remove(configPath)
 	if err != nil {
-		return fmt.Errorf("Failed to remove configuration file at %s/.cybr/config. %s", userHome, err)
+		return fmt.Errorf("Failed to remove configuration file at %s. %s", configPath, err)
+	}
+
+	store, err := credstore.GetStore()
+	if err != nil {
+		return err
+	}
+	err = store.Delete(sessionTokenKey(ResolveProfile(c.Profile)))
+	if err != nil {
+		return fmt.Errorf("Failed to remove session token from the %s credential store. %s", store.Name(), err)
 	}
 
 	return nil
@@ -127,3 +191,33 @@ func (c *Client) GetLogger() logger.Logger {
 		LoggerEnabled: false,
 	}
 }
+
+// GetTransport retrieve Client HTTP transport. The shared transport for the
+// client's TLS verification mode is used if none was set
+func (c *Client) GetTransport() *httpjson.Transport {
+	if c.transport != nil {
+		return c.transport
+	}
+
+	return httpjson.SharedTransport(c.InsecureTLS)
+}
+
+// SetTransport set the HTTP transport used for all requests sent by the Client
+func (c *Client) SetTransport(transport *httpjson.Transport) {
+	c.transport = transport
+}
+
+// GetContext retrieve Client context used to cancel requests
+func (c *Client) GetContext() context.Context {
+	if c.ctx != nil {
+		return c.ctx
+	}
+
+	return context.Background()
+}
+
+// WithContext returns a copy of the Client whose requests are bound to ctx
+func (c Client) WithContext(ctx context.Context) Client {
+	c.ctx = ctx
+	return c
+}
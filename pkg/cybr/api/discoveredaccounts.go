@@ -0,0 +1,143 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/queries"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/requests"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/responses"
+	httpJson "github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/httpjson"
+)
+
+const (
+	// OnboardStatusOnboarded is the status of a discovered account that was onboarded
+	OnboardStatusOnboarded = "onboarded"
+	// OnboardStatusFailed is the status of a discovered account that could not be onboarded.
+	// The account remains in the discovered accounts
+	OnboardStatusFailed = "failed"
+)
+
+// OnboardDiscoveredAccountResult is the result of onboarding a discovered account
+type OnboardDiscoveredAccountResult struct {
+	DiscoveredID string `json:"discoveredId"`
+	ID           string `json:"id,omitempty"`
+	UserName     string `json:"userName"`
+	Address      string `json:"address"`
+	SafeName     string `json:"safeName"`
+	Status       string `json:"status"`
+	Error        string `json:"error,omitempty"`
+}
+
+// ListDiscoveredAccounts returns the accounts found by Accounts Discovery that have not been onboarded
+func (c Client) ListDiscoveredAccounts(query *queries.ListDiscoveredAccounts) (*responses.ListDiscoveredAccounts, error) {
+	url := fmt.Sprintf("%s/passwordvault/api/DiscoveredAccounts%s", c.BaseURL, httpJson.GetURLQuery(query))
+	response, err := c.GetTransport().Get(c.GetContext(), false, url, c.SessionToken, c.Logger)
+	if err != nil {
+		return &responses.ListDiscoveredAccounts{}, fmt.Errorf("Failed to list discovered accounts. %w", err)
+	}
+
+	jsonString, _ := json.Marshal(response)
+	ListDiscoveredAccountsResponse := &responses.ListDiscoveredAccounts{}
+	err = json.Unmarshal(jsonString, ListDiscoveredAccountsResponse)
+	return ListDiscoveredAccountsResponse, err
+}
+
+// IterateDiscoveredAccounts returns an iterator over all discovered accounts matching query, reading every page
+func (c Client) IterateDiscoveredAccounts(query *queries.ListDiscoveredAccounts) *DiscoveredAccountIterator {
+	q := queries.ListDiscoveredAccounts{}
+	if query != nil {
+		q = *query
+	}
+	return &DiscoveredAccountIterator{
+		pager: newPager(c, "discovered accounts", "value", "count", q.Offset, q.Limit, func(offset int) string {
+			q.Offset = offset
+			return fmt.Sprintf("%s/passwordvault/api/DiscoveredAccounts%s", c.BaseURL, httpJson.GetURLQuery(&q))
+		}),
+	}
+}
+
+// GetDiscoveredAccount details for a specific discovered account
+func (c Client) GetDiscoveredAccount(discoveredID string) (*responses.DiscoveredAccount, error) {
+	url := fmt.Sprintf("%s/passwordvault/api/DiscoveredAccounts/%s", c.BaseURL, url.QueryEscape(discoveredID))
+	response, err := c.GetTransport().Get(c.GetContext(), false, url, c.SessionToken, c.Logger)
+	if err != nil {
+		return &responses.DiscoveredAccount{}, fmt.Errorf("Failed to get discovered account '%s'. %w", discoveredID, err)
+	}
+
+	jsonString, _ := json.Marshal(response)
+	GetDiscoveredAccountResponse := &responses.DiscoveredAccount{}
+	err = json.Unmarshal(jsonString, GetDiscoveredAccountResponse)
+	return GetDiscoveredAccountResponse, err
+}
+
+// DeleteDiscoveredAccount removes an account from the discovered accounts
+func (c Client) DeleteDiscoveredAccount(discoveredID string) error {
+	url := fmt.Sprintf("%s/passwordvault/api/DiscoveredAccounts/%s", c.BaseURL, url.QueryEscape(discoveredID))
+	_, err := c.GetTransport().Delete(c.GetContext(), false, url, c.SessionToken, c.Logger)
+	if err != nil {
+		return fmt.Errorf("Failed to delete discovered account '%s'. %w", discoveredID, err)
+	}
+
+	return nil
+}
+
+// OnboardDiscoveredAccount adds a discovered account to a safe and removes it from the
+// discovered accounts. The user name and address of the discovered account are used, every
+// other property of the account added is taken from template, which must have a safe and platform
+func (c Client) OnboardDiscoveredAccount(discovered responses.DiscoveredAccount, template requests.AddAccount) (*responses.GetAccount, error) {
+	if template.SafeName == "" || template.PlatformID == "" {
+		return nil, fmt.Errorf("A safe and platform are required to onboard discovered account '%s'", discovered.ID)
+	}
+
+	account := template
+	account.UserName = discovered.UserName
+	account.Address = discovered.Address
+	if account.SecretType == "" {
+		account.SecretType = "password"
+	}
+
+	created, err := c.AddAccount(account)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to onboard discovered account '%s'. %w", discovered.ID, err)
+	}
+
+	err = c.DeleteDiscoveredAccount(discovered.ID)
+	if err != nil {
+		return created, fmt.Errorf("Onboarded discovered account '%s' as account '%s' but it was not removed from the discovered accounts. %w", discovered.ID, created.ID, err)
+	}
+	return created, nil
+}
+
+// OnboardDiscoveredAccounts onboards every discovered account using template. A discovered account
+// that fails to onboard is reported and does not stop the other accounts from being onboarded
+func (c Client) OnboardDiscoveredAccounts(discovered []responses.DiscoveredAccount, template requests.AddAccount, progress func(result OnboardDiscoveredAccountResult)) []OnboardDiscoveredAccountResult {
+	results := []OnboardDiscoveredAccountResult{}
+	for _, account := range discovered {
+		result := OnboardDiscoveredAccountResult{
+			DiscoveredID: account.ID,
+			UserName:     account.UserName,
+			Address:      account.Address,
+			SafeName:     template.SafeName,
+			Status:       OnboardStatusOnboarded,
+		}
+
+		created, err := c.OnboardDiscoveredAccount(account, template)
+		if created != nil {
+			result.ID = created.ID
+		}
+		if err != nil {
+			result.Error = err.Error()
+			if created == nil {
+				result.Status = OnboardStatusFailed
+			}
+		}
+
+		if progress != nil {
+			progress(result)
+		}
+		results = append(results, result)
+	}
+	return results
+}
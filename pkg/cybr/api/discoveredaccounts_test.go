@@ -0,0 +1,94 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pasapi "github.com/infamousjoeg/cybr-cli/pkg/cybr/api"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/queries"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/requests"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/responses"
+)
+
+// newDiscoveredServer returns a PAS stand-in with two discovered accounts. Adding an account
+// with user name 'locked' fails
+func newDiscoveredServer(t *testing.T, added *[]requests.AddAccount, deleted *[]string) *httptest.Server {
+	return newPASServer(nil,
+		route{http.MethodGet, "/passwordvault/api/DiscoveredAccounts", func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("filter") != "privileged eq true" {
+				t.Errorf("Invalid filter '%s'", r.URL.Query().Get("filter"))
+			}
+			w.Write([]byte(`{"value":[
+				{"id":"d1","userName":"admin","address":"host1","platformType":"Windows Server Local","privileged":true,"discoveryDateTime":1600000000},
+				{"id":"d2","userName":"locked","address":"host2","platformType":"Windows Server Local","privileged":true,"discoveryDateTime":1600000100}
+			],"count":2}`))
+		}},
+		route{http.MethodGet, "/passwordvault/api/DiscoveredAccounts/d1", respond(`{"id":"d1","userName":"admin","address":"host1","numberOfDependencies":1,"dependencies":[{"name":"Backup","address":"host1","type":"Windows Service"}]}`)},
+		route{http.MethodDelete, "/passwordvault/api/DiscoveredAccounts/*", func(w http.ResponseWriter, r *http.Request) {
+			*deleted = append(*deleted, strings.TrimPrefix(r.URL.Path, "/passwordvault/api/DiscoveredAccounts/"))
+			w.WriteHeader(http.StatusNoContent)
+		}},
+		route{http.MethodPost, "/passwordvault/api/Accounts", func(w http.ResponseWriter, r *http.Request) {
+			account := requests.AddAccount{}
+			json.NewDecoder(r.Body).Decode(&account)
+			if account.UserName == "locked" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"ErrorCode":"PASWS167E","ErrorMessage":"Account already exists"}`))
+				return
+			}
+			*added = append(*added, account)
+			json.NewEncoder(w).Encode(responses.GetAccount{ID: "12_1", UserName: account.UserName, Address: account.Address, SafeName: account.SafeName})
+		}},
+	)
+}
+
+func TestGetDiscoveredAccount(t *testing.T) {
+	server := newDiscoveredServer(t, &[]requests.AddAccount{}, &[]string{})
+	defer server.Close()
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	account, err := client.GetDiscoveredAccount("d1")
+	if err != nil {
+		t.Fatalf("Failed to get discovered account. %s", err)
+	}
+	if account.UserName != "admin" || len(account.Dependencies) != 1 || account.Dependencies[0].Type != "Windows Service" {
+		t.Errorf("Invalid discovered account. %+v", account)
+	}
+}
+
+func TestOnboardDiscoveredAccounts(t *testing.T) {
+	added := []requests.AddAccount{}
+	deleted := []string{}
+	server := newDiscoveredServer(t, &added, &deleted)
+	defer server.Close()
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	discovered := []responses.DiscoveredAccount{}
+	accounts := client.IterateDiscoveredAccounts(&queries.ListDiscoveredAccounts{Filter: "privileged eq true"})
+	for accounts.Next() {
+		discovered = append(discovered, accounts.Account())
+	}
+	if accounts.Err() != nil || len(discovered) != 2 {
+		t.Fatalf("Failed to list discovered accounts. %v %+v", accounts.Err(), discovered)
+	}
+
+	template := requests.AddAccount{SafeName: "Safe1", PlatformID: "WinServerLocal", PlatformAccountProperties: map[string]string{"LogonDomain": "host1"}}
+	results := client.OnboardDiscoveredAccounts(discovered, template, nil)
+	if len(results) != 2 || results[0].Status != pasapi.OnboardStatusOnboarded || results[0].ID != "12_1" || results[1].Status != pasapi.OnboardStatusFailed {
+		t.Errorf("Invalid results. %+v", results)
+	}
+	if len(added) != 1 || added[0].UserName != "admin" || added[0].Address != "host1" || added[0].SecretType != "password" || added[0].PlatformAccountProperties["LogonDomain"] != "host1" {
+		t.Errorf("Invalid account added. %+v", added)
+	}
+	if len(deleted) != 1 || deleted[0] != "d1" {
+		t.Errorf("Expected only the onboarded account to be removed from the discovered accounts. %v", deleted)
+	}
+
+	_, err := client.OnboardDiscoveredAccount(discovered[0], requests.AddAccount{SafeName: "Safe1"})
+	if err == nil {
+		t.Errorf("Expected onboarding without a platform to fail")
+	}
+}
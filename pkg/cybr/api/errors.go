@@ -0,0 +1,20 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/httpjson"
+)
+
+// APIError is returned by Client methods when PAS responds with a non-2xx status code.
+// It contains the PAS error code (e.g. PASWS167E) and message from the response body
+type APIError = httpjson.APIError
+
+// AsAPIError returns the APIError wrapped in err, if any
+func AsAPIError(err error) (*APIError, bool) {
+	var apiError *APIError
+	if errors.As(err, &apiError) {
+		return apiError, true
+	}
+	return nil, false
+}
@@ -0,0 +1,31 @@
+package api_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	pasapi "github.com/infamousjoeg/cybr-cli/pkg/cybr/api"
+)
+
+func TestAPIErrorPropagated(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"ErrorCode":"PASWS013E","ErrorMessage":"Account 12_3 was not found."}`))
+	}))
+	defer server.Close()
+
+	client := pasapi.Client{
+		BaseURL:  server.URL,
+		AuthType: "cyberark",
+	}
+
+	err := client.DeleteAccount("12_3")
+	apiError, ok := pasapi.AsAPIError(err)
+	if !ok {
+		t.Fatalf("Expected an APIError but got '%v'", err)
+	}
+	if apiError.StatusCode != http.StatusNotFound || apiError.ErrorCode != "PASWS013E" {
+		t.Errorf("Invalid APIError returned. %+v", apiError)
+	}
+}
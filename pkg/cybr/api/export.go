@@ -0,0 +1,422 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/queries"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/requests"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/responses"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/shared"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/prettyprint"
+	"gopkg.in/yaml.v2"
+)
+
+// SnapshotVersion is the version of the snapshot format written by Export
+const SnapshotVersion = 1
+
+// Snapshot is the inventory of a vault. Safes, members, accounts, applications and users are
+// stored as the requests used to add them, so a snapshot can be used to recreate them.
+// Secrets and initial passwords are never exported
+type Snapshot struct {
+	Version      int                      `json:"version"`
+	ExportedAt   string                   `json:"exportedAt,omitempty"`
+	Safes        []SnapshotSafe           `json:"safes"`
+	Applications []SnapshotApplication    `json:"applications"`
+	Users        []requests.AddUser       `json:"users"`
+	Platforms    []responses.ListPlatform `json:"platforms"`
+}
+
+// SnapshotSafe is a safe with its members and the metadata of its accounts
+type SnapshotSafe struct {
+	requests.AddSafe
+	Members  []requests.AddSafeMember `json:"Members,omitempty"`
+	Accounts []requests.AddAccount    `json:"Accounts,omitempty"`
+}
+
+// SnapshotApplication is an application with its authentication methods
+type SnapshotApplication struct {
+	requests.Application
+	Authentications []requests.ApplicationAuthenticationMethod `json:"Authentications,omitempty"`
+}
+
+// ExportOptions selects the applications exported by Export
+type ExportOptions struct {
+	// ApplicationLocation is the location of the exported applications. Defaults to '\'
+	ApplicationLocation string
+}
+
+// Export reads the safes, safe members, accounts, applications, users and platforms
+// of the vault. Predefined safe members are not exported since they cannot be added
+func (c Client) Export(options ExportOptions) (*Snapshot, error) {
+	snapshot := &Snapshot{
+		Version:      SnapshotVersion,
+		ExportedAt:   time.Now().UTC().Format(time.RFC3339),
+		Safes:        []SnapshotSafe{},
+		Applications: []SnapshotApplication{},
+		Users:        []requests.AddUser{},
+		Platforms:    []responses.ListPlatform{},
+	}
+
+	err := c.exportSafes(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	location := options.ApplicationLocation
+	if location == "" {
+		location = "\\"
+	}
+	err = c.exportApplications(snapshot, location)
+	if err != nil {
+		return nil, err
+	}
+
+	users := c.IterateUsers(nil)
+	for users.Next() {
+		user := users.User()
+		snapshot.Users = append(snapshot.Users, requests.AddUser{
+			Username:           user.Username,
+			UserType:           user.UserType,
+			Location:           user.Location,
+			VaultAuthorization: user.VaultAuthorization,
+			PersonalDetails: map[string]string{
+				"firstName":  user.PersonalDetails.FirstName,
+				"middleName": user.PersonalDetails.MiddleName,
+				"lastName":   user.PersonalDetails.LastName,
+			},
+		})
+	}
+	if users.Err() != nil {
+		return nil, fmt.Errorf("Failed to export users. %w", users.Err())
+	}
+	sort.Slice(snapshot.Users, func(i, j int) bool {
+		return strings.ToLower(snapshot.Users[i].Username) < strings.ToLower(snapshot.Users[j].Username)
+	})
+
+	// The active filter is always sent, so active and inactive platforms are listed separately
+	for _, active := range []bool{true, false} {
+		platforms := c.IteratePlatforms(&queries.ListPlatforms{Active: active})
+		for platforms.Next() {
+			snapshot.Platforms = append(snapshot.Platforms, platforms.Platform())
+		}
+		if platforms.Err() != nil {
+			return nil, fmt.Errorf("Failed to export platforms. %w", platforms.Err())
+		}
+	}
+	sort.Slice(snapshot.Platforms, func(i, j int) bool {
+		return strings.ToLower(snapshot.Platforms[i].General.ID) < strings.ToLower(snapshot.Platforms[j].General.ID)
+	})
+
+	return snapshot, nil
+}
+
+// memberDirectories maps the type and lowercase name of users and groups to where they are
+// searched for when they are added as safe members
+type memberDirectories map[string]string
+
+// searchIn returns the vault, or the LDAP directory of a directory group. It is empty for
+// members whose directory is unknown, such as users of an LDAP directory
+func (d memberDirectories) searchIn(memberType string, memberName string) string {
+	name := strings.ToLower(memberName)
+	if memberType != "" {
+		return d[strings.ToLower(memberType)+"/"+name]
+	}
+	if searchIn, ok := d["user/"+name]; ok {
+		return searchIn
+	}
+	return d["group/"+name]
+}
+
+// memberDirectories returns where the users and groups of the vault are searched for when they are
+// added as safe members. PAS does not return the directory of LDAP users, so they are left out
+func (c Client) memberDirectories() (memberDirectories, error) {
+	directories := memberDirectories{}
+
+	users := c.IterateUsers(nil)
+	for users.Next() {
+		user := users.User()
+		if user.Source == "" || strings.EqualFold(user.Source, "CyberArk") {
+			directories["user/"+strings.ToLower(user.Username)] = "Vault"
+		}
+	}
+	if users.Err() != nil {
+		return nil, fmt.Errorf("Failed to export safe members. %w", users.Err())
+	}
+
+	groups, err := c.ListGroups(nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to export safe members. %w", err)
+	}
+	for _, group := range groups.Groups {
+		searchIn := "Vault"
+		if group.GroupType == GroupTypeDirectory {
+			searchIn = group.Directory
+		}
+		directories["group/"+strings.ToLower(group.GroupName)] = searchIn
+	}
+	return directories, nil
+}
+
+func (c Client) exportSafes(snapshot *Snapshot) error {
+	safeIndex := map[string]int{}
+	safes := c.IterateSafes(&queries.ListSafes{Limit: 1000})
+	for safes.Next() {
+		safe := safes.Safe()
+		safeIndex[safe.SafeName] = len(snapshot.Safes)
+		addSafe := requests.AddSafe{
+			SafeName:                  safe.SafeName,
+			Description:               safe.Description,
+			OLACEnabled:               safe.OLACEnabled,
+			ManagingCPM:               safe.ManagingCPM,
+			NumberOfVersionsRetention: safe.NumberOfVersionsRetention,
+			AutoPurgeEnabled:          safe.AutoPurgeEnabled,
+			SafeLocation:              safe.Location,
+		}
+		// A safe retains either a number of versions or a number of days, which may be 0
+		if safe.NumberOfVersionsRetention == 0 {
+			days := safe.NumberOfDaysRetention
+			addSafe.NumberOfDaysRetention = &days
+		}
+		snapshot.Safes = append(snapshot.Safes, SnapshotSafe{AddSafe: addSafe})
+	}
+	if safes.Err() != nil {
+		return fmt.Errorf("Failed to export safes. %w", safes.Err())
+	}
+
+	directories, err := c.memberDirectories()
+	if err != nil {
+		return err
+	}
+
+	for i := range snapshot.Safes {
+		safe := &snapshot.Safes[i]
+		members := c.IterateSafeMembers(safe.SafeName, &queries.ListSafeMembers{Limit: 1000})
+		for members.Next() {
+			member := members.Member()
+			if member.IsPredefinedUser {
+				continue
+			}
+			safe.Members = append(safe.Members, requests.AddSafeMember{
+				MemberName:  member.MemberName,
+				SearchIn:    directories.searchIn(member.MemberType, member.MemberName),
+				Permissions: grantedPermissions(member.Permissions),
+				MemberType:  member.MemberType,
+			})
+		}
+		if members.Err() != nil {
+			return fmt.Errorf("Failed to export members of safe '%s'. %w", safe.SafeName, members.Err())
+		}
+	}
+
+	accounts := c.IterateAccounts(&queries.ListAccounts{Limit: 1000})
+	for accounts.Next() {
+		account := accounts.Account()
+		i, ok := safeIndex[account.SafeName]
+		if !ok {
+			i = len(snapshot.Safes)
+			safeIndex[account.SafeName] = i
+			snapshot.Safes = append(snapshot.Safes, SnapshotSafe{AddSafe: requests.AddSafe{SafeName: account.SafeName}})
+		}
+		snapshot.Safes[i].Accounts = append(snapshot.Safes[i].Accounts, requests.AddAccount{
+			Name:                      account.Name,
+			Address:                   account.Address,
+			UserName:                  account.UserName,
+			PlatformID:                account.PlatformID,
+			SafeName:                  account.SafeName,
+			SecretType:                account.SecretType,
+			PlatformAccountProperties: account.PlatformAccountProperties,
+			SecretManagement: shared.SecretManagement{
+				AutomaticManagementEnabled: account.SecretManagement.AutomaticManagementEnabled,
+				ManualManagementReason:     account.SecretManagement.ManualManagementReason,
+			},
+		})
+	}
+	if accounts.Err() != nil {
+		return fmt.Errorf("Failed to export accounts. %w", accounts.Err())
+	}
+
+	sort.Slice(snapshot.Safes, func(i, j int) bool {
+		return strings.ToLower(snapshot.Safes[i].SafeName) < strings.ToLower(snapshot.Safes[j].SafeName)
+	})
+	for _, safe := range snapshot.Safes {
+		members := safe.Members
+		sort.Slice(members, func(i, j int) bool {
+			return strings.ToLower(members[i].MemberName) < strings.ToLower(members[j].MemberName)
+		})
+		accounts := safe.Accounts
+		sort.Slice(accounts, func(i, j int) bool {
+			if accounts[i].Name != accounts[j].Name {
+				return accounts[i].Name < accounts[j].Name
+			}
+			return accounts[i].UserName+"@"+accounts[i].Address < accounts[j].UserName+"@"+accounts[j].Address
+		})
+	}
+	return nil
+}
+
+func (c Client) exportApplications(snapshot *Snapshot, location string) error {
+	applications, err := c.ListApplications(location)
+	if err != nil {
+		return fmt.Errorf("Failed to export applications. %w", err)
+	}
+
+	for _, application := range applications.Application {
+		exported := SnapshotApplication{
+			Application: requests.Application{
+				AppID:               application.AppID,
+				Description:         application.Description,
+				Location:            application.Location,
+				AccessPermittedFrom: application.AccessPermittedFrom,
+				AccessPermittedTo:   application.AccessPermittedTo,
+				BusinessOwnerFName:  application.BusinessOwnerFName,
+				BusinessOwnerLName:  application.BusinessOwnerLName,
+				BusinessOwnerEmail:  application.BusinessOwnerEmail,
+				BusinessOwnerPhone:  application.BusinessOwnerPhone,
+			},
+		}
+		if application.Disabled {
+			exported.Disabled = "yes"
+		}
+		if application.ExpirationDate.Year() > 1 {
+			exported.ExpirationDate = application.ExpirationDate.Format("01-02-2006")
+		}
+
+		methods, err := c.ListApplicationAuthenticationMethods(application.AppID)
+		if err != nil {
+			return fmt.Errorf("Failed to export authentication methods of application '%s'. %w", application.AppID, err)
+		}
+		for _, method := range methods.Authentication {
+			exported.Authentications = append(exported.Authentications, requests.ApplicationAuthenticationMethod{
+				AuthType:             method.AuthType,
+				AuthValue:            method.AuthValue,
+				IsFolder:             method.IsFolder,
+				AllowInternalScripts: method.AllowInternalScripts,
+			})
+		}
+		snapshot.Applications = append(snapshot.Applications, exported)
+	}
+
+	sort.Slice(snapshot.Applications, func(i, j int) bool {
+		return strings.ToLower(snapshot.Applications[i].AppID) < strings.ToLower(snapshot.Applications[j].AppID)
+	})
+	return nil
+}
+
+// grantedPermissions returns the permissions of a safe member that are granted
+func grantedPermissions(permissions responses.Permissions) map[string]string {
+	content, _ := json.Marshal(permissions)
+	values := map[string]bool{}
+	json.Unmarshal(content, &values)
+
+	granted := map[string]string{}
+	for name, value := range values {
+		if value {
+			granted[name] = "true"
+		}
+	}
+	return granted
+}
+
+// WriteSnapshot writes the snapshot to w in the json or yaml format
+func WriteSnapshot(w io.Writer, format string, snapshot *Snapshot) error {
+	printer, err := prettyprint.NewPrinter(format)
+	if err != nil {
+		return err
+	}
+	if printer.Format != prettyprint.FormatJSON && printer.Format != prettyprint.FormatYAML {
+		return fmt.Errorf("Unsupported snapshot format '%s'. The format must be json or yaml", format)
+	}
+	return printer.Print(w, snapshot, nil)
+}
+
+// WriteSnapshotFile writes the snapshot to path. The format is selected by the file extension
+func WriteSnapshotFile(path string, snapshot *Snapshot) error {
+	format, err := getImportFormat(path)
+	if err == nil && format == "csv" {
+		err = fmt.Errorf("Unsupported file '%s'. The file extension must be .json, .yaml or .yml", path)
+	}
+	if err != nil {
+		return err
+	}
+
+	buffer := &bytes.Buffer{}
+	err = WriteSnapshot(buffer, format, snapshot)
+	if err != nil {
+		return err
+	}
+
+	err = ioutil.WriteFile(path, buffer.Bytes(), 0644)
+	if err != nil {
+		return fmt.Errorf("Failed to write snapshot '%s'. %s", path, err)
+	}
+	return nil
+}
+
+// ReadSnapshot reads a JSON or YAML snapshot written by WriteSnapshot
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// JSON is valid YAML, so both formats are converted to JSON and decoded using the json tags
+	var value interface{}
+	err = yaml.Unmarshal(content, &value)
+	if err != nil {
+		return nil, err
+	}
+	content, err = json.Marshal(yamlToJSON(value))
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &Snapshot{}
+	err = json.Unmarshal(content, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot.Version != SnapshotVersion {
+		return nil, fmt.Errorf("Unsupported snapshot version %d", snapshot.Version)
+	}
+	return snapshot, nil
+}
+
+// ReadSnapshotFile reads a JSON or YAML snapshot file
+func ReadSnapshotFile(path string) (*Snapshot, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read snapshot '%s'. %s", path, err)
+	}
+	defer file.Close()
+
+	snapshot, err := ReadSnapshot(file)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse snapshot '%s'. %s", path, err)
+	}
+	return snapshot, nil
+}
+
+// yamlToJSON converts the maps decoded by yaml.v2 to maps that can be marshalled to JSON
+func yamlToJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := map[string]interface{}{}
+		for key, item := range v {
+			m[fmt.Sprintf("%v", key)] = yamlToJSON(item)
+		}
+		return m
+	case []interface{}:
+		for i, item := range v {
+			v[i] = yamlToJSON(item)
+		}
+	}
+	return value
+}
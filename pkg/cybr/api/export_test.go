@@ -0,0 +1,115 @@
+package api_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	pasapi "github.com/infamousjoeg/cybr-cli/pkg/cybr/api"
+)
+
+// newExportServer returns a PAS stand-in with one safe, member, account, application,
+// user and an active and inactive platform
+func newExportServer(t *testing.T) *httptest.Server {
+	return newPASServer(nil,
+		route{http.MethodGet, "/passwordvault/api/safes", respond(`{"value":[{"SafeName":"Safe1","Description":"First","ManagingCPM":"PasswordManager","NumberOfDaysRetention":7}],"count":1}`)},
+		route{http.MethodGet, "/passwordvault/api/Safes/Safe1/Members", respond(`{"value":[
+			{"memberName":"user1","memberType":"User","Permissions":{"UseAccounts":true,"ListAccounts":true}},
+			{"memberName":"Admins","memberType":"Group","Permissions":{"ManageSafe":true}},
+			{"memberName":"ldapuser","memberType":"User","Permissions":{"ListAccounts":true}},
+			{"memberName":"Administrator","isPredefinedUser":true,"Permissions":{"ManageSafe":true}}
+		],"count":4}`)},
+		route{http.MethodGet, "/passwordvault/api/UserGroups", respond(`{"value":[{"id":1,"groupName":"Admins","groupType":"Directory","directory":"example.com"}],"count":1}`)},
+		route{http.MethodGet, "/passwordvault/api/Accounts", respond(`{"value":[{"id":"12_3","name":"root-account","address":"10.0.0.1","userName":"root","platformId":"UnixSSH","safeName":"Safe1","secretType":"key","platformAccountProperties":{"Port":"22"},"secretManagement":{"automaticManagementEnabled":true,"status":"success","lastModifiedTime":1600000000}}],"count":1}`)},
+		route{http.MethodGet, "/passwordvault/WebServices/PIMServices.svc/Applications", respond(`{"application":[{"AppID":"App1","Location":"\\","Disabled":true,"ExpirationDate":"0001-01-01T00:00:00Z"}]}`)},
+		route{http.MethodGet, "/passwordvault/WebServices/PIMServices.svc/Applications/App1/Authentications", respond(`{"authentication":[{"AppID":"App1","AuthType":"machineAddress","AuthValue":"10.0.0.2","authID":"1"}]}`)},
+		route{http.MethodGet, "/passwordvault/api/Users", respond(`{"Users":[{"id":2,"username":"user1","source":"CyberArk","userType":"EPVUser","location":"\\","vaultAuthorization":["AuditUsers"]},{"id":3,"username":"ldapuser","source":"LDAP"}],"Total":2}`)},
+		route{http.MethodGet, "/passwordvault/api/platforms", func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("active") == "true" {
+				w.Write([]byte(`{"Platforms":[{"general":{"id":"UnixSSH","active":true}}],"Total":1}`))
+				return
+			}
+			w.Write([]byte(`{"Platforms":[{"general":{"id":"Inactive","active":false}}],"Total":1}`))
+		}},
+	)
+}
+
+func TestExport(t *testing.T) {
+	server := newExportServer(t)
+	defer server.Close()
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	snapshot, err := client.Export(pasapi.ExportOptions{})
+	if err != nil {
+		t.Fatalf("Failed to export. %s", err)
+	}
+
+	if snapshot.Version != pasapi.SnapshotVersion || len(snapshot.Safes) != 1 {
+		t.Fatalf("Invalid snapshot. %+v", snapshot)
+	}
+	safe := snapshot.Safes[0]
+	if safe.SafeName != "Safe1" || safe.ManagingCPM != "PasswordManager" || safe.NumberOfDaysRetention == nil || *safe.NumberOfDaysRetention != 7 {
+		t.Errorf("Invalid safe exported. %+v", safe.AddSafe)
+	}
+	if len(safe.Members) != 3 || safe.Members[2].MemberName != "user1" || len(safe.Members[2].Permissions) != 2 {
+		t.Fatalf("Expected only the granted permissions of user1. %+v", safe.Members)
+	}
+	searchIn := []string{safe.Members[0].SearchIn, safe.Members[1].SearchIn, safe.Members[2].SearchIn}
+	if !reflect.DeepEqual(searchIn, []string{"example.com", "", "Vault"}) {
+		t.Errorf("Expected the directory of the group, no directory for the LDAP user and the vault for user1. %v", searchIn)
+	}
+	if len(safe.Accounts) != 1 || safe.Accounts[0].PlatformAccountProperties["Port"] != "22" || safe.Accounts[0].SecretManagement.Status != "" {
+		t.Errorf("Invalid account exported. %+v", safe.Accounts)
+	}
+
+	if len(snapshot.Applications) != 1 || snapshot.Applications[0].Disabled != "yes" || snapshot.Applications[0].ExpirationDate != "" {
+		t.Errorf("Invalid application exported. %+v", snapshot.Applications)
+	}
+	if len(snapshot.Applications[0].Authentications) != 1 || snapshot.Applications[0].Authentications[0].AuthValue != "10.0.0.2" {
+		t.Errorf("Invalid authentication methods exported. %+v", snapshot.Applications[0].Authentications)
+	}
+	if len(snapshot.Users) != 2 || snapshot.Users[1].Username != "user1" {
+		t.Errorf("Invalid users exported. %+v", snapshot.Users)
+	}
+	if len(snapshot.Platforms) != 2 || snapshot.Platforms[0].General.ID != "Inactive" {
+		t.Errorf("Expected active and inactive platforms. %+v", snapshot.Platforms)
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	server := newExportServer(t)
+	defer server.Close()
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	snapshot, err := client.Export(pasapi.ExportOptions{})
+	if err != nil {
+		t.Fatalf("Failed to export. %s", err)
+	}
+
+	for _, format := range []string{"json", "yaml"} {
+		buffer := &bytes.Buffer{}
+		err = pasapi.WriteSnapshot(buffer, format, snapshot)
+		if err != nil {
+			t.Fatalf("Failed to write %s snapshot. %s", format, err)
+		}
+		if strings.Contains(strings.ToLower(buffer.String()), "secret\"") || strings.Contains(buffer.String(), "secret:") || strings.Contains(buffer.String(), "initialPassword") {
+			t.Errorf("Expected no secrets in the %s snapshot.\n%s", format, buffer)
+		}
+
+		read, err := pasapi.ReadSnapshot(buffer)
+		if err != nil {
+			t.Fatalf("Failed to read %s snapshot. %s", format, err)
+		}
+		if !reflect.DeepEqual(read, snapshot) {
+			t.Errorf("Expected the %s snapshot to round trip.\n%+v\n%+v", format, snapshot, read)
+		}
+	}
+
+	err = pasapi.WriteSnapshot(&bytes.Buffer{}, "csv", snapshot)
+	if err == nil {
+		t.Errorf("Expected csv to be an unsupported snapshot format")
+	}
+}
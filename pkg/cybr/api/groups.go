@@ -0,0 +1,173 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/queries"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/requests"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/responses"
+	httpJson "github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/httpjson"
+)
+
+const (
+	// GroupTypeVault is the type of groups created in the vault
+	GroupTypeVault = "Vault"
+	// GroupTypeDirectory is the type of groups mapped from an LDAP directory
+	GroupTypeDirectory = "Directory"
+)
+
+// EffectiveSafeMembers response from ListEffectiveSafeMembers
+type EffectiveSafeMembers struct {
+	Members []EffectiveSafeMember `json:"value"`
+	Count   int                   `json:"count"`
+}
+
+// EffectiveSafeMember is a safe member, or a user that is a member of a safe through a group.
+// ViaGroup is the name of the group the user is a member through, and Permissions are those
+// of the group
+type EffectiveSafeMember struct {
+	responses.Members
+	ViaGroup string `json:"viaGroup,omitempty"`
+}
+
+// ListGroups returns the vault and directory groups
+func (c Client) ListGroups(query *queries.ListGroups) (*responses.ListGroups, error) {
+	if query == nil {
+		query = &queries.ListGroups{}
+	}
+	url := fmt.Sprintf("%s/passwordvault/api/UserGroups%s", c.BaseURL, httpJson.GetURLQuery(query))
+	response, err := c.GetTransport().Get(c.GetContext(), false, url, c.SessionToken, c.Logger)
+	if err != nil {
+		return &responses.ListGroups{}, fmt.Errorf("Failed to list groups. %w", err)
+	}
+
+	jsonString, _ := json.Marshal(response)
+	ListGroupsResponse := &responses.ListGroups{}
+	err = json.Unmarshal(jsonString, ListGroupsResponse)
+	return ListGroupsResponse, err
+}
+
+// GetGroup details for a specific group, including its members
+func (c Client) GetGroup(groupID int) (*responses.Group, error) {
+	url := fmt.Sprintf("%s/passwordvault/api/UserGroups/%d?includeMembers=true", c.BaseURL, groupID)
+	response, err := c.GetTransport().Get(c.GetContext(), false, url, c.SessionToken, c.Logger)
+	if err != nil {
+		return &responses.Group{}, fmt.Errorf("Failed to get group with id '%d'. %w", groupID, err)
+	}
+
+	jsonString, _ := json.Marshal(response)
+	GetGroupResponse := &responses.Group{}
+	err = json.Unmarshal(jsonString, GetGroupResponse)
+	return GetGroupResponse, err
+}
+
+// AddGroup creates a vault group
+func (c Client) AddGroup(group requests.AddGroup) (*responses.Group, error) {
+	url := fmt.Sprintf("%s/passwordvault/api/UserGroups", c.BaseURL)
+	response, err := c.GetTransport().Post(c.GetContext(), false, url, c.SessionToken, group, c.Logger)
+	if err != nil {
+		return &responses.Group{}, fmt.Errorf("Failed to add group '%s'. %w", group.GroupName, err)
+	}
+
+	jsonString, _ := json.Marshal(response)
+	AddGroupResponse := &responses.Group{}
+	err = json.Unmarshal(jsonString, AddGroupResponse)
+	return AddGroupResponse, err
+}
+
+// DeleteGroup from PAS
+func (c Client) DeleteGroup(groupID int) error {
+	url := fmt.Sprintf("%s/passwordvault/api/UserGroups/%d", c.BaseURL, groupID)
+	_, err := c.GetTransport().Delete(c.GetContext(), false, url, c.SessionToken, c.Logger)
+	if err != nil {
+		return fmt.Errorf("Failed to delete group with id '%d'. %w", groupID, err)
+	}
+
+	return nil
+}
+
+// AddGroupMember adds a vault or directory user to a group
+func (c Client) AddGroupMember(groupID int, member requests.AddGroupMember) error {
+	url := fmt.Sprintf("%s/passwordvault/api/UserGroups/%d/Members", c.BaseURL, groupID)
+	_, err := c.GetTransport().Post(c.GetContext(), false, url, c.SessionToken, member, c.Logger)
+	if err != nil {
+		return fmt.Errorf("Failed to add member '%s' to group with id '%d'. %w", member.MemberID, groupID, err)
+	}
+
+	return nil
+}
+
+// RemoveGroupMember removes a user from a group
+func (c Client) RemoveGroupMember(groupID int, memberName string) error {
+	url := fmt.Sprintf("%s/passwordvault/api/UserGroups/%d/Members/%s", c.BaseURL, groupID, url.PathEscape(memberName))
+	_, err := c.GetTransport().Delete(c.GetContext(), false, url, c.SessionToken, c.Logger)
+	if err != nil {
+		return fmt.Errorf("Failed to remove member '%s' from group with id '%d'. %w", memberName, groupID, err)
+	}
+
+	return nil
+}
+
+// FindGroup returns the group with the name given, including its members
+func (c Client) FindGroup(groupName string) (*responses.Group, error) {
+	groups, err := c.ListGroups(&queries.ListGroups{Search: groupName, IncludeMembers: true})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, group := range groups.Groups {
+		if strings.EqualFold(group.GroupName, groupName) {
+			return &group, nil
+		}
+	}
+	return nil, fmt.Errorf("Group '%s' does not exist", groupName)
+}
+
+// ListEffectiveSafeMembers returns the members of a safe matching query, reading every page,
+// with every group member replaced by the users of the group. Groups whose users are not
+// known to the vault, such as directory groups no user has logged on through, are kept
+func (c Client) ListEffectiveSafeMembers(safeName string, query *queries.ListSafeMembers) (*EffectiveSafeMembers, error) {
+	effective := &EffectiveSafeMembers{Members: []EffectiveSafeMember{}}
+	groups := map[string]*responses.Group{}
+
+	members := c.IterateSafeMembers(safeName, query)
+	for members.Next() {
+		member := members.Member()
+		if !strings.EqualFold(member.MemberType, "group") {
+			effective.Members = append(effective.Members, EffectiveSafeMember{Members: member})
+			continue
+		}
+
+		group, ok := groups[strings.ToLower(member.MemberName)]
+		if !ok {
+			var err error
+			group, err = c.FindGroup(member.MemberName)
+			if err != nil {
+				return nil, err
+			}
+			groups[strings.ToLower(member.MemberName)] = group
+		}
+		if len(group.Members) == 0 {
+			effective.Members = append(effective.Members, EffectiveSafeMember{Members: member})
+			continue
+		}
+
+		for _, user := range group.Members {
+			userMember := member
+			userMember.MemberID = user.ID
+			userMember.MemberName = user.Username
+			userMember.MemberType = "User"
+			userMember.IsPredefinedUser = false
+			effective.Members = append(effective.Members, EffectiveSafeMember{Members: userMember, ViaGroup: group.GroupName})
+		}
+	}
+	if members.Err() != nil {
+		return nil, members.Err()
+	}
+
+	effective.Count = len(effective.Members)
+	return effective, nil
+}
@@ -0,0 +1,114 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pasapi "github.com/infamousjoeg/cybr-cli/pkg/cybr/api"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/queries"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/requests"
+)
+
+// newGroupsServer returns a PAS stand-in with a vault group of two users, a directory group
+// without known users and a safe with the groups and a user as members
+func newGroupsServer(t *testing.T, requested *[]string) *httptest.Server {
+	changeMember := func(w http.ResponseWriter, r *http.Request) {
+		member := requests.AddGroupMember{}
+		json.NewDecoder(r.Body).Decode(&member)
+		*requested = append(*requested, strings.TrimSpace(r.Method+" "+r.URL.EscapedPath()+" "+member.MemberType+" "+member.DomainName))
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{}`))
+	}
+
+	return newPASServer(nil,
+		route{http.MethodGet, "/passwordvault/api/UserGroups", func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("includeMembers") != "true" {
+				t.Errorf("Expected the members of the groups to be included")
+			}
+			switch strings.ToLower(r.URL.Query().Get("search")) {
+			case "appowners":
+				w.Write([]byte(`{"value":[
+					{"id":5,"groupName":"AppOwners2","groupType":"Vault"},
+					{"id":6,"groupName":"AppOwners","groupType":"Vault","members":[{"id":10,"username":"alice"},{"id":11,"username":"bob"}]}
+				],"count":2}`))
+			case "domain admins":
+				w.Write([]byte(`{"value":[{"id":7,"groupName":"Domain Admins","groupType":"Directory","directory":"cyberark.local","dn":"CN=Domain Admins,DC=cyberark,DC=local"}],"count":1}`))
+			default:
+				w.Write([]byte(`{"value":[],"count":0}`))
+			}
+		}},
+		route{http.MethodGet, "/passwordvault/api/Safes/Safe1/Members", respond(`{"value":[
+			{"safeName":"Safe1","memberName":"carol","memberType":"User","Permissions":{"ListAccounts":true}},
+			{"safeName":"Safe1","memberName":"AppOwners","memberType":"Group","Permissions":{"UseAccounts":true}},
+			{"safeName":"Safe1","memberName":"Domain Admins","memberType":"Group","Permissions":{"ManageSafe":true}}
+		],"count":3}`)},
+		route{http.MethodPost, "/passwordvault/api/UserGroups/6/Members", changeMember},
+		route{http.MethodDelete, "/passwordvault/api/UserGroups/6/Members/*", changeMember},
+	)
+}
+
+func TestFindGroup(t *testing.T) {
+	server := newGroupsServer(t, &[]string{})
+	defer server.Close()
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	group, err := client.FindGroup("appowners")
+	if err != nil {
+		t.Fatalf("Failed to find group. %s", err)
+	}
+	if group.ID != 6 || len(group.Members) != 2 {
+		t.Errorf("Expected the group with the exact name. %+v", group)
+	}
+
+	_, err = client.FindGroup("Unknown")
+	if err == nil {
+		t.Errorf("Expected an unknown group to fail")
+	}
+}
+
+func TestListEffectiveSafeMembers(t *testing.T) {
+	server := newGroupsServer(t, &[]string{})
+	defer server.Close()
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	members, err := client.ListEffectiveSafeMembers("Safe1", &queries.ListSafeMembers{})
+	if err != nil {
+		t.Fatalf("Failed to list effective safe members. %s", err)
+	}
+
+	if members.Count != 4 || len(members.Members) != 4 {
+		t.Fatalf("Expected carol, alice, bob and Domain Admins. %+v", members.Members)
+	}
+	alice := members.Members[1]
+	if alice.MemberName != "alice" || alice.MemberType != "User" || alice.ViaGroup != "AppOwners" || !alice.Permissions.UseAccounts {
+		t.Errorf("Invalid group user. %+v", alice)
+	}
+	if members.Members[0].ViaGroup != "" || members.Members[3].MemberName != "Domain Admins" || members.Members[3].MemberType != "Group" {
+		t.Errorf("Expected users and directory groups without known users to be kept. %+v", members.Members)
+	}
+}
+
+func TestGroupMembers(t *testing.T) {
+	requested := []string{}
+	server := newGroupsServer(t, &requested)
+	defer server.Close()
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	err := client.AddGroupMember(6, requests.AddGroupMember{MemberID: "john", MemberType: "domain", DomainName: "cyberark.local"})
+	if err != nil {
+		t.Errorf("Failed to add group member. %s", err)
+	}
+
+	err = client.RemoveGroupMember(6, "john doe")
+	if err != nil {
+		t.Errorf("Failed to remove group member. %s", err)
+	}
+
+	expected := []string{"POST /passwordvault/api/UserGroups/6/Members domain cyberark.local", "DELETE /passwordvault/api/UserGroups/6/Members/john%20doe"}
+	if strings.Join(requested, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v but got %v", expected, requested)
+	}
+}
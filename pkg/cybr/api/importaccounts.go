@@ -0,0 +1,634 @@
+package api
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/queries"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/requests"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/shared"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// ImportStatusCreated is the status of a row whose account was created
+	ImportStatusCreated = "created"
+	// ImportStatusFailed is the status of a row whose account could not be created
+	ImportStatusFailed = "failed"
+	// ImportStatusValid is the status of a valid row when running with DryRun
+	ImportStatusValid = "valid"
+	// ImportStatusInvalid is the status of a row that failed validation
+	ImportStatusInvalid = "invalid"
+)
+
+// AccountImportRow is an account read from an import file and the result of importing it
+type AccountImportRow struct {
+	// Row is the number of the row in the import file, starting at 1
+	Row     int
+	Account requests.AddAccount
+	Status  string
+	ID      string
+	Error   string
+}
+
+// AccountImportOptions configures ImportAccounts
+type AccountImportOptions struct {
+	// Concurrency is the number of accounts created at the same time. Defaults to 1
+	Concurrency int
+	// DryRun only validates the rows
+	DryRun bool
+	// Progress is called once each row has been processed
+	Progress func(row AccountImportRow)
+}
+
+// accountImportRecord is the representation of a row in JSON and YAML files. The secret
+// management settings may also be nested like in requests.AddAccount
+type accountImportRecord struct {
+	Row                        int                      `json:"row,omitempty" yaml:"row,omitempty"`
+	Name                       string                   `json:"name,omitempty" yaml:"name,omitempty"`
+	Address                    string                   `json:"address" yaml:"address"`
+	UserName                   string                   `json:"userName" yaml:"userName"`
+	PlatformID                 string                   `json:"platformId" yaml:"platformId"`
+	SafeName                   string                   `json:"safeName" yaml:"safeName"`
+	SecretType                 string                   `json:"secretType" yaml:"secretType"`
+	Secret                     string                   `json:"secret,omitempty" yaml:"secret,omitempty"`
+	AutomaticManagementEnabled bool                     `json:"automaticManagementEnabled" yaml:"automaticManagementEnabled"`
+	ManualManagementReason     string                   `json:"manualManagementReason,omitempty" yaml:"manualManagementReason,omitempty"`
+	SecretManagement           *shared.SecretManagement `json:"secretManagement,omitempty" yaml:"secretManagement,omitempty"`
+	PlatformAccountProperties  map[string]interface{}   `json:"platformAccountProperties,omitempty" yaml:"platformAccountProperties,omitempty"`
+	Status                     string                   `json:"status,omitempty" yaml:"status,omitempty"`
+	ID                         string                   `json:"id,omitempty" yaml:"id,omitempty"`
+	Error                      string                   `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// accountImportColumns maps the normalized CSV column names to the fields of a row.
+// Any other column is a platform account property
+var accountImportColumns = map[string]string{
+	"row":                        "row",
+	"name":                       "name",
+	"address":                    "address",
+	"username":                   "userName",
+	"platformid":                 "platformId",
+	"platform":                   "platformId",
+	"safename":                   "safeName",
+	"safe":                       "safeName",
+	"secrettype":                 "secretType",
+	"secret":                     "secret",
+	"automaticmanagementenabled": "automaticManagementEnabled",
+	"automaticmanagement":        "automaticManagementEnabled",
+	"manualmanagementreason":     "manualManagementReason",
+	"status":                     "status",
+	"id":                         "id",
+	"error":                      "error",
+}
+
+var csvColumns = []string{"row", "name", "address", "userName", "platformId", "safeName", "secretType", "secret", "automaticManagementEnabled", "manualManagementReason"}
+
+// MarshalJSON writes the row without the secret of the account
+func (r AccountImportRow) MarshalJSON() ([]byte, error) {
+	record := newAccountImportRecord(r)
+	record.Secret = ""
+	return json.Marshal(record)
+}
+
+func newAccountImportRecord(r AccountImportRow) accountImportRecord {
+	record := accountImportRecord{
+		Row:                        r.Row,
+		Name:                       r.Account.Name,
+		Address:                    r.Account.Address,
+		UserName:                   r.Account.UserName,
+		PlatformID:                 r.Account.PlatformID,
+		SafeName:                   r.Account.SafeName,
+		SecretType:                 r.Account.SecretType,
+		Secret:                     r.Account.Secret,
+		AutomaticManagementEnabled: r.Account.SecretManagement.AutomaticManagementEnabled,
+		ManualManagementReason:     r.Account.SecretManagement.ManualManagementReason,
+		Status:                     r.Status,
+		ID:                         r.ID,
+		Error:                      r.Error,
+	}
+	if len(r.Account.PlatformAccountProperties) > 0 {
+		record.PlatformAccountProperties = map[string]interface{}{}
+		for key, value := range r.Account.PlatformAccountProperties {
+			record.PlatformAccountProperties[key] = value
+		}
+	}
+	return record
+}
+
+func (record accountImportRecord) row() AccountImportRow {
+	r := AccountImportRow{
+		Row:    record.Row,
+		Status: record.Status,
+		ID:     record.ID,
+		Error:  record.Error,
+		Account: requests.AddAccount{
+			Name:       record.Name,
+			Address:    record.Address,
+			UserName:   record.UserName,
+			PlatformID: record.PlatformID,
+			SafeName:   record.SafeName,
+			SecretType: record.SecretType,
+			Secret:     record.Secret,
+			SecretManagement: shared.SecretManagement{
+				AutomaticManagementEnabled: record.AutomaticManagementEnabled,
+				ManualManagementReason:     record.ManualManagementReason,
+			},
+		},
+	}
+	if record.SecretManagement != nil {
+		r.Account.SecretManagement.AutomaticManagementEnabled = record.SecretManagement.AutomaticManagementEnabled
+		r.Account.SecretManagement.ManualManagementReason = record.SecretManagement.ManualManagementReason
+	}
+	if len(record.PlatformAccountProperties) > 0 {
+		r.Account.PlatformAccountProperties = map[string]string{}
+		for key, value := range record.PlatformAccountProperties {
+			r.Account.PlatformAccountProperties[key] = fmt.Sprint(value)
+		}
+	}
+	if r.Account.SecretType == "" {
+		r.Account.SecretType = "password"
+	}
+	return r
+}
+
+// getImportFormat returns csv, json or yaml depending on the extension of path
+func getImportFormat(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return "csv", nil
+	case ".json":
+		return "json", nil
+	case ".yaml", ".yml":
+		return "yaml", nil
+	}
+	return "", fmt.Errorf("Unsupported file '%s'. The file extension must be .csv, .json, .yaml or .yml", path)
+}
+
+// ReadAccountImportFile reads the accounts of a CSV, JSON or YAML file. The format is selected
+// by the file extension. A results report written by WriteAccountImportReport can be read again
+func ReadAccountImportFile(path string) ([]AccountImportRow, error) {
+	format, err := getImportFormat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read import file '%s'. %s", path, err)
+	}
+
+	rows, err := ParseAccountImport(bytes.NewReader(content), format)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse import file '%s'. %s", path, err)
+	}
+	return rows, nil
+}
+
+// ParseAccountImport reads accounts in the csv, json or yaml format.
+// CSV columns are matched to the fields of requests.AddAccount ignoring case, spaces, '-' and '_'.
+// Columns that do not match a field are platform account properties
+func ParseAccountImport(r io.Reader, format string) ([]AccountImportRow, error) {
+	rows := []AccountImportRow{}
+	switch format {
+	case "csv":
+		return parseAccountImportCSV(r)
+	case "json":
+		content, err := ioutil.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		records := []accountImportRecord{}
+		err = json.Unmarshal(content, &records)
+		if err != nil {
+			// Also accept an object containing the list, e.g. {"accounts": [...]}
+			wrapped := struct {
+				Accounts []accountImportRecord `json:"accounts"`
+			}{}
+			if json.Unmarshal(content, &wrapped) != nil {
+				return nil, err
+			}
+			records = wrapped.Accounts
+		}
+		for i, record := range records {
+			rows = append(rows, withRowNumber(record.row(), i))
+		}
+	case "yaml":
+		content, err := ioutil.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		records := []accountImportRecord{}
+		err = yaml.Unmarshal(content, &records)
+		if err != nil {
+			wrapped := struct {
+				Accounts []accountImportRecord `yaml:"accounts"`
+			}{}
+			if yaml.Unmarshal(content, &wrapped) != nil {
+				return nil, err
+			}
+			records = wrapped.Accounts
+		}
+		for i, record := range records {
+			rows = append(rows, withRowNumber(record.row(), i))
+		}
+	default:
+		return nil, fmt.Errorf("Unsupported import format '%s'", format)
+	}
+	return rows, nil
+}
+
+func withRowNumber(row AccountImportRow, index int) AccountImportRow {
+	if row.Row == 0 {
+		row.Row = index + 1
+	}
+	return row
+}
+
+func normalizeColumn(column string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "_", "", "-", "").Replace(strings.TrimSpace(column)))
+}
+
+func parseAccountImportCSV(r io.Reader) ([]AccountImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("The header row is missing")
+	}
+
+	header := records[0]
+	rows := []AccountImportRow{}
+	for i, values := range records[1:] {
+		if strings.TrimSpace(strings.Join(values, "")) == "" {
+			continue
+		}
+		if len(values) > len(header) {
+			return nil, fmt.Errorf("Row %d has %d columns but the header has %d", i+1, len(values), len(header))
+		}
+
+		record := accountImportRecord{}
+		for j, value := range values {
+			column := strings.TrimSpace(header[j])
+			err = record.set(column, value)
+			if err != nil {
+				return nil, fmt.Errorf("Row %d is invalid. %s", i+1, err)
+			}
+		}
+		rows = append(rows, withRowNumber(record.row(), i))
+	}
+	return rows, nil
+}
+
+// set assigns the value of a CSV column
+func (record *accountImportRecord) set(column string, value string) error {
+	lowerColumn := strings.ToLower(column)
+	for _, prefix := range []string{"platformaccountproperties.", "properties."} {
+		if strings.HasPrefix(lowerColumn, prefix) {
+			record.setProperty(column[len(prefix):], value)
+			return nil
+		}
+	}
+
+	var err error
+	switch accountImportColumns[normalizeColumn(column)] {
+	case "row":
+		if strings.TrimSpace(value) != "" {
+			record.Row, err = strconv.Atoi(strings.TrimSpace(value))
+		}
+	case "name":
+		record.Name = value
+	case "address":
+		record.Address = value
+	case "userName":
+		record.UserName = value
+	case "platformId":
+		record.PlatformID = value
+	case "safeName":
+		record.SafeName = value
+	case "secretType":
+		record.SecretType = value
+	case "secret":
+		record.Secret = value
+	case "automaticManagementEnabled":
+		if strings.TrimSpace(value) != "" {
+			record.AutomaticManagementEnabled, err = strconv.ParseBool(strings.TrimSpace(value))
+		}
+	case "manualManagementReason":
+		record.ManualManagementReason = value
+	case "status":
+		record.Status = value
+	case "id":
+		record.ID = value
+	case "error":
+		record.Error = value
+	default:
+		record.setProperty(column, value)
+	}
+	if err != nil {
+		return fmt.Errorf("Invalid value '%s' for column '%s'", value, column)
+	}
+	return nil
+}
+
+func (record *accountImportRecord) setProperty(key string, value string) {
+	if value == "" {
+		return
+	}
+	if record.PlatformAccountProperties == nil {
+		record.PlatformAccountProperties = map[string]interface{}{}
+	}
+	record.PlatformAccountProperties[key] = value
+}
+
+// WriteAccountImportReport writes the rows and their result to path in the format of its
+// extension. The report can be imported again to retry the rows that were not created. The
+// secrets of created accounts are removed from the report
+func WriteAccountImportReport(path string, rows []AccountImportRow) error {
+	format, err := getImportFormat(path)
+	if err != nil {
+		return err
+	}
+
+	buffer := &bytes.Buffer{}
+	err = WriteAccountImport(buffer, format, rows)
+	if err != nil {
+		return err
+	}
+
+	err = ioutil.WriteFile(path, buffer.Bytes(), 0600)
+	if err != nil {
+		return fmt.Errorf("Failed to write results report '%s'. %s", path, err)
+	}
+	return nil
+}
+
+// WriteAccountImport writes the rows and their result in the csv, json or yaml format
+func WriteAccountImport(w io.Writer, format string, rows []AccountImportRow) error {
+	records := []accountImportRecord{}
+	for _, row := range rows {
+		record := newAccountImportRecord(row)
+		if row.Status == ImportStatusCreated {
+			record.Secret = ""
+		}
+		records = append(records, record)
+	}
+
+	switch format {
+	case "csv":
+		return writeAccountImportCSV(w, records)
+	case "json":
+		content, err := json.MarshalIndent(records, "", "    ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(content))
+		return err
+	case "yaml":
+		content, err := yaml.Marshal(records)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(content)
+		return err
+	}
+	return fmt.Errorf("Unsupported import format '%s'", format)
+}
+
+func writeAccountImportCSV(w io.Writer, records []accountImportRecord) error {
+	properties := map[string]bool{}
+	for _, record := range records {
+		for key := range record.PlatformAccountProperties {
+			properties[key] = true
+		}
+	}
+	propertyColumns := []string{}
+	for key := range properties {
+		propertyColumns = append(propertyColumns, key)
+	}
+	sort.Strings(propertyColumns)
+
+	writer := csv.NewWriter(w)
+	header := append(append(append([]string{}, csvColumns...), propertyColumns...), "status", "id", "error")
+	writer.Write(header)
+	for _, record := range records {
+		values := []string{
+			strconv.Itoa(record.Row),
+			record.Name,
+			record.Address,
+			record.UserName,
+			record.PlatformID,
+			record.SafeName,
+			record.SecretType,
+			record.Secret,
+			strconv.FormatBool(record.AutomaticManagementEnabled),
+			record.ManualManagementReason,
+		}
+		for _, key := range propertyColumns {
+			value, ok := record.PlatformAccountProperties[key]
+			if !ok {
+				value = ""
+			}
+			values = append(values, fmt.Sprint(value))
+		}
+		values = append(values, record.Status, record.ID, record.Error)
+		writer.Write(values)
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// platformValidator validates accounts against their platform. Platforms are retrieved once
+type platformValidator struct {
+	client    Client
+	mutex     sync.Mutex
+	platforms map[string]error
+	required  map[string][]string
+	listErr   error
+	listed    bool
+}
+
+func newPlatformValidator(c Client) *platformValidator {
+	return &platformValidator{
+		client:    c,
+		platforms: map[string]error{},
+		required:  map[string][]string{},
+	}
+}
+
+// checkPlatform returns an error if the platform does not exist or is not active
+func (v *platformValidator) checkPlatform(platformID string) error {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	key := strings.ToLower(platformID)
+	if err, ok := v.platforms[key]; ok {
+		return err
+	}
+
+	platform, err := v.client.GetPlatform(platformID)
+	if apiError, ok := AsAPIError(err); ok && apiError.StatusCode == http.StatusNotFound {
+		err = fmt.Errorf("Platform '%s' does not exist", platformID)
+	} else if err != nil {
+		err = fmt.Errorf("Failed to validate platform '%s'. %w", platformID, err)
+	} else if !platform.Active {
+		err = fmt.Errorf("Platform '%s' is not active", platformID)
+	}
+	v.platforms[key] = err
+	return err
+}
+
+// requiredProperties returns the names of the required properties of a platform
+func (v *platformValidator) requiredProperties(platformID string) ([]string, error) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	if !v.listed {
+		v.listed = true
+		platforms, err := v.client.ListPlatforms(&queries.ListPlatforms{Active: true})
+		if err != nil {
+			v.listErr = fmt.Errorf("Failed to retrieve the required properties of the platforms. %w", err)
+		} else {
+			for _, platform := range platforms.Platforms {
+				names := []string{}
+				for _, property := range platform.Properties.Required {
+					names = append(names, property.Name)
+				}
+				v.required[strings.ToLower(platform.General.ID)] = names
+			}
+		}
+	}
+	return v.required[strings.ToLower(platformID)], v.listErr
+}
+
+// validate returns an error if the account cannot be created
+func (v *platformValidator) validate(account requests.AddAccount) error {
+	if account.SafeName == "" {
+		return fmt.Errorf("The safe name is required")
+	}
+	if account.PlatformID == "" {
+		return fmt.Errorf("The platform ID is required")
+	}
+
+	err := v.checkPlatform(account.PlatformID)
+	if err != nil {
+		return err
+	}
+
+	required, err := v.requiredProperties(account.PlatformID)
+	if err != nil {
+		return err
+	}
+
+	missing := []string{}
+	for _, name := range required {
+		if getAccountProperty(account, name) == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("Missing required properties of platform '%s': %s", account.PlatformID, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// getAccountProperty returns the value of a platform property of an account. The address and
+// username are properties of every platform
+func getAccountProperty(account requests.AddAccount, name string) string {
+	switch strings.ToLower(name) {
+	case "address":
+		return account.Address
+	case "username":
+		return account.UserName
+	}
+	for key, value := range account.PlatformAccountProperties {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return ""
+}
+
+// ImportAccounts validates and creates the accounts of the rows. Rows already created,
+// e.g. when importing a results report again, are skipped. The rows are returned in
+// the same order with their status, the ID of the created account or an error
+func (c Client) ImportAccounts(rows []AccountImportRow, options AccountImportOptions) []AccountImportRow {
+	results := make([]AccountImportRow, len(rows))
+	copy(results, rows)
+
+	concurrency := options.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	validator := newPlatformValidator(c)
+	indexes := make(chan int)
+	progressMutex := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				results[index] = c.importAccount(results[index], validator, options.DryRun)
+				if options.Progress != nil {
+					progressMutex.Lock()
+					options.Progress(results[index])
+					progressMutex.Unlock()
+				}
+			}
+		}()
+	}
+
+	for i := range results {
+		if results[i].Status == ImportStatusCreated {
+			continue
+		}
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
+
+func (c Client) importAccount(row AccountImportRow, validator *platformValidator, dryRun bool) AccountImportRow {
+	row.ID = ""
+	row.Error = ""
+
+	err := validator.validate(row.Account)
+	if err != nil {
+		row.Status = ImportStatusInvalid
+		row.Error = err.Error()
+		return row
+	}
+
+	if dryRun {
+		row.Status = ImportStatusValid
+		return row
+	}
+
+	account, err := c.AddAccount(row.Account)
+	if err != nil {
+		row.Status = ImportStatusFailed
+		row.Error = err.Error()
+		return row
+	}
+
+	row.Status = ImportStatusCreated
+	row.ID = account.ID
+	return row
+}
@@ -0,0 +1,143 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	pasapi "github.com/infamousjoeg/cybr-cli/pkg/cybr/api"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/requests"
+)
+
+const importCSV = `safeName,platformId,userName,address,secret,Port
+Safe1,UnixSSH,root,10.0.0.1,Secret1,22
+Safe1,UnixSSH,admin,,Secret2,22
+Safe1,Missing,user,10.0.0.3,Secret3,
+Safe1,Inactive,user,10.0.0.4,Secret4,
+`
+
+// newImportServer returns a PAS stand-in with an active UnixSSH platform requiring
+// an address and an inactive platform. The accounts added are returned
+func newImportServer(t *testing.T) (*httptest.Server, *[]requests.AddAccount) {
+	mutex := sync.Mutex{}
+	added := []requests.AddAccount{}
+	server := newPASServer(nil,
+		route{http.MethodGet, "/passwordvault/api/platforms/UnixSSH", respond(`{"PlatformID":"UnixSSH","Active":true}`)},
+		route{http.MethodGet, "/passwordvault/api/platforms/Inactive", respond(`{"PlatformID":"Inactive","Active":false}`)},
+		route{http.MethodGet, "/passwordvault/api/platforms", respond(`{"Platforms":[{"general":{"id":"UnixSSH"},"properties":{"required":[{"name":"Address"},{"name":"Username"}]}}]}`)},
+		route{http.MethodPost, "/passwordvault/api/Accounts", func(w http.ResponseWriter, r *http.Request) {
+			account := requests.AddAccount{}
+			json.NewDecoder(r.Body).Decode(&account)
+			mutex.Lock()
+			added = append(added, account)
+			mutex.Unlock()
+			w.Write([]byte(`{"id":"12_` + account.Address[len(account.Address)-1:] + `"}`))
+		}},
+	)
+	return server, &added
+}
+
+func TestParseAccountImportCSV(t *testing.T) {
+	rows, err := pasapi.ParseAccountImport(strings.NewReader(importCSV), "csv")
+	if err != nil {
+		t.Fatalf("Failed to parse CSV. %s", err)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("Expected 4 rows but got %d", len(rows))
+	}
+
+	account := rows[0].Account
+	if rows[0].Row != 1 || account.SafeName != "Safe1" || account.UserName != "root" || account.SecretType != "password" {
+		t.Errorf("Invalid row parsed. %+v", rows[0])
+	}
+	if account.PlatformAccountProperties["Port"] != "22" {
+		t.Errorf("Expected the Port column to be a platform account property. %v", account.PlatformAccountProperties)
+	}
+}
+
+func TestImportAccounts(t *testing.T) {
+	server, added := newImportServer(t)
+	defer server.Close()
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	rows, _ := pasapi.ParseAccountImport(strings.NewReader(importCSV), "csv")
+
+	results := client.ImportAccounts(rows, pasapi.AccountImportOptions{Concurrency: 3})
+	expected := []string{pasapi.ImportStatusCreated, pasapi.ImportStatusInvalid, pasapi.ImportStatusInvalid, pasapi.ImportStatusInvalid}
+	for i, result := range results {
+		if result.Status != expected[i] {
+			t.Errorf("Expected row %d to be %s but got %s. %s", result.Row, expected[i], result.Status, result.Error)
+		}
+	}
+	if results[0].ID != "12_1" || len(*added) != 1 {
+		t.Errorf("Expected a single account 12_1 to be created. %+v", results[0])
+	}
+	if !strings.Contains(results[1].Error, "Address") || !strings.Contains(results[2].Error, "does not exist") || !strings.Contains(results[3].Error, "not active") {
+		t.Errorf("Invalid validation errors. %+v", results)
+	}
+}
+
+func TestImportAccountsDryRun(t *testing.T) {
+	server, added := newImportServer(t)
+	defer server.Close()
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	rows, _ := pasapi.ParseAccountImport(strings.NewReader(importCSV), "csv")
+
+	results := client.ImportAccounts(rows, pasapi.AccountImportOptions{DryRun: true})
+	if results[0].Status != pasapi.ImportStatusValid || len(*added) != 0 {
+		t.Errorf("Expected the first row to be valid without creating accounts. %+v", results[0])
+	}
+}
+
+func TestImportAccountsReportRetriesFailures(t *testing.T) {
+	server, added := newImportServer(t)
+	defer server.Close()
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	rows, _ := pasapi.ParseAccountImport(strings.NewReader(importCSV), "csv")
+	results := client.ImportAccounts(rows, pasapi.AccountImportOptions{})
+
+	for _, format := range []string{"csv", "json", "yaml"} {
+		report := filepath.Join(t.TempDir(), "accounts.results."+format)
+		err := pasapi.WriteAccountImportReport(report, results)
+		if err != nil {
+			t.Fatalf("Failed to write %s report. %s", format, err)
+		}
+
+		reportRows, err := pasapi.ReadAccountImportFile(report)
+		if err != nil {
+			t.Fatalf("Failed to read %s report. %s", format, err)
+		}
+		if len(reportRows) != 4 || reportRows[0].Status != pasapi.ImportStatusCreated || reportRows[0].Account.Secret != "" {
+			t.Fatalf("Expected the created row without its secret in the %s report. %+v", format, reportRows[0])
+		}
+		if reportRows[1].Account.Secret != "Secret2" || reportRows[3].Row != 4 {
+			t.Errorf("Expected the failed rows to keep their secret and row number in the %s report. %+v", format, reportRows)
+		}
+
+		// Fix the missing address and import the report again
+		reportRows[1].Account.Address = "10.0.0.2"
+		retried := client.ImportAccounts(reportRows, pasapi.AccountImportOptions{})
+		if retried[0].ID != "12_1" || retried[1].Status != pasapi.ImportStatusCreated || retried[1].ID != "12_2" {
+			t.Errorf("Expected only the failed rows to be retried. %+v", retried)
+		}
+	}
+
+	if len(*added) != 4 {
+		t.Errorf("Expected the first row to be created once and the second once per format but got %d accounts", len(*added))
+	}
+}
+
+func TestAccountImportRowHidesSecret(t *testing.T) {
+	row := pasapi.AccountImportRow{Row: 1, Account: requests.AddAccount{Secret: "SuperSecret"}}
+	content, _ := json.Marshal(row)
+	if bytes.Contains(content, []byte("SuperSecret")) {
+		t.Errorf("Expected the secret to be removed. %s", content)
+	}
+}
@@ -0,0 +1,246 @@
+package api
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/queries"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/requests"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/responses"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/shared"
+)
+
+const (
+	// MoveStatusMoved is the status of an account that was moved
+	MoveStatusMoved = "moved"
+	// MoveStatusFailed is the status of an account that could not be moved. The account
+	// remains in its safe
+	MoveStatusFailed = "failed"
+)
+
+// MoveAccountOptions are the options of MoveAccount
+type MoveAccountOptions struct {
+	// Reason is the reason given when retrieving the secret of the account
+	Reason string
+	// TicketID is the ticket given when retrieving the secret of the account
+	TicketID string
+}
+
+// MoveAccountResult is the result of moving an account
+type MoveAccountResult struct {
+	SourceID string `json:"sourceId"`
+	ID       string `json:"id,omitempty"`
+	Name     string `json:"name"`
+	SafeName string `json:"safeName"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+}
+
+// MoveAccount moves an account to another safe. PAS cannot move accounts, so the account is
+// copied with its secret, platform properties, remote machine access and linked accounts.
+// The copy is verified before the account is deleted. If any step fails the copy is deleted,
+// leaving the account unchanged in its safe.
+//
+// A copy left in the target safe by an interrupted move is reused, so running the move
+// again completes it. The versions and activities of the account are not copied
+func (c Client) MoveAccount(accountID string, targetSafe string, options MoveAccountOptions) (*responses.GetAccount, error) {
+	account, err := c.GetAccount(accountID)
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(account.SafeName, targetSafe) {
+		return nil, fmt.Errorf("Account '%s' is already in safe '%s'", accountID, targetSafe)
+	}
+
+	retrieve := requests.GetAccountPassword{Reason: options.Reason, TicketID: options.TicketID}
+	secret, err := c.getSecret(accountID, account.SecretType, retrieve)
+	if err != nil {
+		return nil, err
+	}
+
+	linkedAccounts, err := c.ListLinkedAccounts(accountID)
+	if apiError, ok := AsAPIError(err); ok && apiError.StatusCode == 404 {
+		// The PAS version does not support listing linked accounts
+		linkedAccounts, err = &responses.ListLinkedAccounts{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	newAccount := requests.AddAccount{
+		Name:                      account.Name,
+		Address:                   account.Address,
+		UserName:                  account.UserName,
+		PlatformID:                account.PlatformID,
+		SafeName:                  targetSafe,
+		SecretType:                account.SecretType,
+		Secret:                    secret,
+		PlatformAccountProperties: account.PlatformAccountProperties,
+		SecretManagement: shared.SecretManagement{
+			AutomaticManagementEnabled: account.SecretManagement.AutomaticManagementEnabled,
+			ManualManagementReason:     account.SecretManagement.ManualManagementReason,
+		},
+		RemoteMachinesAccess: account.RemoteMachinesAccess,
+	}
+
+	created, err := c.findMovedAccount(newAccount)
+	if err != nil {
+		return nil, err
+	}
+	rollback := func(err error) error { return err }
+	linked := map[int]bool{}
+	if created != nil {
+		existing, err := c.ListLinkedAccounts(created.ID)
+		if err == nil {
+			for _, link := range existing.LinkedAccounts {
+				linked[link.ExtraPasswordIndex] = true
+			}
+		}
+	} else {
+		created, err = c.AddAccount(newAccount)
+		if err != nil {
+			return nil, err
+		}
+		rollback = func(err error) error {
+			deleteErr := c.DeleteAccount(created.ID)
+			if deleteErr != nil {
+				return fmt.Errorf("%w. Rollback failed, account '%s' in safe '%s' must be deleted manually. %s", err, created.ID, targetSafe, deleteErr)
+			}
+			return fmt.Errorf("%w. Account '%s' was not moved", err, accountID)
+		}
+	}
+
+	for _, link := range linkedAccounts.LinkedAccounts {
+		if linked[link.ExtraPasswordIndex] {
+			continue
+		}
+		err = c.LinkAccount(created.ID, requests.LinkAccount{
+			Safe:               link.SafeName,
+			ExtraPasswordIndex: link.ExtraPasswordIndex,
+			Name:               link.Name,
+			Folder:             link.Folder,
+		})
+		if err != nil {
+			return nil, rollback(err)
+		}
+	}
+
+	err = c.verifyMovedAccount(created.ID, newAccount, retrieve)
+	if err != nil {
+		return nil, rollback(err)
+	}
+
+	err = c.DeleteAccount(accountID)
+	if err != nil {
+		// The delete may have succeeded although it failed, e.g. when the connection was reset
+		// and the retry found no account. The verified copy is only deleted if the account
+		// certainly still exists
+		_, getErr := c.GetAccount(accountID)
+		if apiError, ok := AsAPIError(getErr); ok && apiError.StatusCode == 404 {
+			return c.GetAccount(created.ID)
+		}
+		if getErr != nil {
+			return nil, fmt.Errorf("%w. Account '%s' may have been deleted, so the copy '%s' in safe '%s' was kept. %s", err, accountID, created.ID, targetSafe, getErr)
+		}
+		return nil, rollback(err)
+	}
+
+	return c.GetAccount(created.ID)
+}
+
+// MoveAccounts moves every account matching query to another safe. An account that fails
+// to move is reported and does not stop the other accounts from being moved
+func (c Client) MoveAccounts(query *queries.ListAccounts, targetSafe string, options MoveAccountOptions, progress func(result MoveAccountResult)) ([]MoveAccountResult, error) {
+	// All accounts are listed before moving any, since moving changes the pages of the query
+	accounts := []responses.GetAccount{}
+	it := c.IterateAccounts(query)
+	for it.Next() {
+		accounts = append(accounts, it.Account())
+	}
+	if it.Err() != nil {
+		return nil, it.Err()
+	}
+
+	results := []MoveAccountResult{}
+	for _, account := range accounts {
+		result := MoveAccountResult{SourceID: account.ID, Name: account.Name, SafeName: account.SafeName, Status: MoveStatusMoved}
+		moved, err := c.MoveAccount(account.ID, targetSafe, options)
+		if err != nil {
+			result.Status = MoveStatusFailed
+			result.Error = err.Error()
+		} else {
+			result.ID = moved.ID
+			result.SafeName = moved.SafeName
+		}
+
+		if progress != nil {
+			progress(result)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// getSecret retrieves the SSH key of key accounts and the password of other accounts
+func (c Client) getSecret(accountID string, secretType string, request requests.GetAccountPassword) (string, error) {
+	if secretType == "key" {
+		return c.GetAccountSSHKey(accountID, request)
+	}
+	return c.GetAccountPassword(accountID, request)
+}
+
+// findMovedAccount returns the account with the name of account in its safe, or nil if there
+// is none. An account with the same name but a different user, address or platform is an error
+func (c Client) findMovedAccount(account requests.AddAccount) (*responses.GetAccount, error) {
+	if account.Name == "" {
+		return nil, nil
+	}
+
+	accounts := c.IterateAccounts(&queries.ListAccounts{
+		Search: account.UserName,
+		Filter: fmt.Sprintf("safeName eq %s", account.SafeName),
+	})
+	for accounts.Next() {
+		existing := accounts.Account()
+		if !strings.EqualFold(existing.Name, account.Name) {
+			continue
+		}
+		if existing.UserName != account.UserName || existing.Address != account.Address || existing.PlatformID != account.PlatformID {
+			return nil, fmt.Errorf("Account '%s' already exists in safe '%s'", account.Name, account.SafeName)
+		}
+		return &existing, nil
+	}
+	return nil, accounts.Err()
+}
+
+// verifyMovedAccount returns an error if the account does not have the properties and secret
+// of the account it was copied from
+func (c Client) verifyMovedAccount(accountID string, expected requests.AddAccount, request requests.GetAccountPassword) error {
+	account, err := c.GetAccount(accountID)
+	if err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(account.SafeName, expected.SafeName) || account.UserName != expected.UserName ||
+		account.Address != expected.Address || account.PlatformID != expected.PlatformID {
+		return fmt.Errorf("Verification of account '%s' failed. The account does not match the account moved", accountID)
+	}
+	for key, value := range expected.PlatformAccountProperties {
+		if account.PlatformAccountProperties[key] != value {
+			return fmt.Errorf("Verification of account '%s' failed. Platform property '%s' was not copied", accountID, key)
+		}
+	}
+	if expected.RemoteMachinesAccess != nil && !reflect.DeepEqual(account.RemoteMachinesAccess, expected.RemoteMachinesAccess) {
+		return fmt.Errorf("Verification of account '%s' failed. Remote machine access was not copied", accountID)
+	}
+
+	secret, err := c.getSecret(accountID, account.SecretType, request)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(secret) != strings.TrimSpace(expected.Secret) {
+		return fmt.Errorf("Verification of account '%s' failed. The secret does not match the secret of the account moved", accountID)
+	}
+	return nil
+}
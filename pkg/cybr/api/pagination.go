@@ -0,0 +1,287 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/responses"
+)
+
+// pager reads every page of a PAS list endpoint. The nextLink returned by PAS is
+// followed when present. Otherwise the offset is increased by the number of items
+// read until the total is reached or a page is not full
+type pager struct {
+	client      Client
+	description string
+	itemsKey    string
+	totalKey    string
+	limit       int
+	pageURL     func(offset int) string
+
+	nextURL   string
+	firstItem json.RawMessage
+	offset    int
+	read      int
+	total     int
+	items     []json.RawMessage
+	index     int
+	current   json.RawMessage
+	err       error
+}
+
+func newPager(c Client, description string, itemsKey string, totalKey string, offset int, limit int, pageURL func(offset int) string) pager {
+	return pager{
+		client:      c,
+		description: description,
+		itemsKey:    itemsKey,
+		totalKey:    totalKey,
+		limit:       limit,
+		pageURL:     pageURL,
+		nextURL:     pageURL(offset),
+		offset:      offset,
+	}
+}
+
+// resolveNextLink converts the relative nextLink returned by PAS (e.g. 'api/Accounts?offset=50')
+// to an absolute url
+func (p *pager) resolveNextLink(nextLink string) string {
+	if strings.HasPrefix(nextLink, "http://") || strings.HasPrefix(nextLink, "https://") {
+		return nextLink
+	}
+	if strings.HasPrefix(nextLink, "/") {
+		return p.client.BaseURL + nextLink
+	}
+	return fmt.Sprintf("%s/passwordvault/%s", p.client.BaseURL, nextLink)
+}
+
+func (p *pager) fetch() error {
+	url := p.nextURL
+	c := p.client
+	response, err := c.GetTransport().Get(c.GetContext(), false, url, c.SessionToken, c.Logger)
+	if err != nil {
+		return fmt.Errorf("Failed to list %s. %w", p.description, err)
+	}
+
+	page := struct {
+		Items    []json.RawMessage
+		Total    int
+		NextLink string
+	}{}
+	content, _ := json.Marshal(map[string]interface{}{
+		"Items":    response[p.itemsKey],
+		"Total":    response[p.totalKey],
+		"NextLink": response["nextLink"],
+	})
+	err = json.Unmarshal(content, &page)
+	if err != nil {
+		return fmt.Errorf("Failed to parse list of %s. %w", p.description, err)
+	}
+
+	// Stop if the server ignores the offset and returns the same page again
+	if len(page.Items) > 0 && p.firstItem != nil && string(page.Items[0]) == string(p.firstItem) {
+		page.Items = nil
+	}
+	if len(page.Items) > 0 {
+		p.firstItem = page.Items[0]
+	}
+
+	p.items = page.Items
+	p.index = 0
+	p.read += len(page.Items)
+	p.total = page.Total
+	p.offset += len(page.Items)
+
+	nextURL := ""
+	switch {
+	case page.NextLink != "":
+		nextURL = p.resolveNextLink(page.NextLink)
+	case len(page.Items) == 0:
+	case p.total > 0 && p.read < p.total:
+		nextURL = p.pageURL(p.offset)
+	case p.total == 0 && p.limit > 0 && len(page.Items) == p.limit:
+		nextURL = p.pageURL(p.offset)
+	}
+	// Stop if the next page url does not change
+	if nextURL == url {
+		nextURL = ""
+	}
+	p.nextURL = nextURL
+
+	return nil
+}
+
+// next advances to the next item, fetching the next page when required
+func (p *pager) next() bool {
+	if p.err != nil {
+		return false
+	}
+	for p.index >= len(p.items) {
+		if p.nextURL == "" {
+			return false
+		}
+		p.err = p.fetch()
+		if p.err != nil {
+			return false
+		}
+	}
+
+	p.current = p.items[p.index]
+	p.index++
+	return true
+}
+
+func (p *pager) decode(v interface{}) bool {
+	err := json.Unmarshal(p.current, v)
+	if err != nil {
+		p.err = fmt.Errorf("Failed to parse %s. %w", p.description, err)
+		return false
+	}
+	return true
+}
+
+// Err returns the error that stopped the iteration, if any
+func (p *pager) Err() error {
+	return p.err
+}
+
+// Total returns the total number of items reported by PAS, 0 if unknown
+func (p *pager) Total() int {
+	return p.total
+}
+
+// AccountIterator iterates over all accounts returned by IterateAccounts
+type AccountIterator struct {
+	pager
+	account responses.GetAccount
+}
+
+// Next advances to the next account. It returns false when all accounts have been read or an error occurred
+func (it *AccountIterator) Next() bool {
+	it.account = responses.GetAccount{}
+	return it.next() && it.decode(&it.account)
+}
+
+// Account returns the current account
+func (it *AccountIterator) Account() responses.GetAccount {
+	return it.account
+}
+
+// SafeIterator iterates over all safes returned by IterateSafes
+type SafeIterator struct {
+	pager
+	safe responses.ListSafe
+}
+
+// Next advances to the next safe. It returns false when all safes have been read or an error occurred
+func (it *SafeIterator) Next() bool {
+	it.safe = responses.ListSafe{}
+	return it.next() && it.decode(&it.safe)
+}
+
+// Safe returns the current safe
+func (it *SafeIterator) Safe() responses.ListSafe {
+	return it.safe
+}
+
+// SafeMemberIterator iterates over all safe members returned by IterateSafeMembers
+type SafeMemberIterator struct {
+	pager
+	member responses.Members
+}
+
+// Next advances to the next safe member. It returns false when all members have been read or an error occurred
+func (it *SafeMemberIterator) Next() bool {
+	it.member = responses.Members{}
+	return it.next() && it.decode(&it.member)
+}
+
+// Member returns the current safe member
+func (it *SafeMemberIterator) Member() responses.Members {
+	return it.member
+}
+
+// UserIterator iterates over all users returned by IterateUsers
+type UserIterator struct {
+	pager
+	user responses.UserResponse
+}
+
+// Next advances to the next user. It returns false when all users have been read or an error occurred
+func (it *UserIterator) Next() bool {
+	it.user = responses.UserResponse{}
+	return it.next() && it.decode(&it.user)
+}
+
+// User returns the current user
+func (it *UserIterator) User() responses.UserResponse {
+	return it.user
+}
+
+// PlatformIterator iterates over all platforms returned by IteratePlatforms
+type PlatformIterator struct {
+	pager
+	platform responses.ListPlatform
+}
+
+// Next advances to the next platform. It returns false when all platforms have been read or an error occurred
+func (it *PlatformIterator) Next() bool {
+	it.platform = responses.ListPlatform{}
+	return it.next() && it.decode(&it.platform)
+}
+
+// Platform returns the current platform
+func (it *PlatformIterator) Platform() responses.ListPlatform {
+	return it.platform
+}
+
+// LiveSessionIterator iterates over all live sessions returned by IterateLiveSessions
+type LiveSessionIterator struct {
+	pager
+	session responses.LiveSession
+}
+
+// Next advances to the next live session. It returns false when all sessions have been read or an error occurred
+func (it *LiveSessionIterator) Next() bool {
+	it.session = responses.LiveSession{}
+	return it.next() && it.decode(&it.session)
+}
+
+// Session returns the current live session
+func (it *LiveSessionIterator) Session() responses.LiveSession {
+	return it.session
+}
+
+// RecordingIterator iterates over all recordings returned by IterateRecordings
+type RecordingIterator struct {
+	pager
+	recording responses.Recording
+}
+
+// Next advances to the next recording. It returns false when all recordings have been read or an error occurred
+func (it *RecordingIterator) Next() bool {
+	it.recording = responses.Recording{}
+	return it.next() && it.decode(&it.recording)
+}
+
+// Recording returns the current recording
+func (it *RecordingIterator) Recording() responses.Recording {
+	return it.recording
+}
+
+// DiscoveredAccountIterator iterates over all discovered accounts returned by IterateDiscoveredAccounts
+type DiscoveredAccountIterator struct {
+	pager
+	account responses.DiscoveredAccount
+}
+
+// Next advances to the next discovered account. It returns false when all accounts have been read or an error occurred
+func (it *DiscoveredAccountIterator) Next() bool {
+	it.account = responses.DiscoveredAccount{}
+	return it.next() && it.decode(&it.account)
+}
+
+// Account returns the current discovered account
+func (it *DiscoveredAccountIterator) Account() responses.DiscoveredAccount {
+	return it.account
+}
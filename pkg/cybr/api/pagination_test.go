@@ -0,0 +1,104 @@
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	pasapi "github.com/infamousjoeg/cybr-cli/pkg/cybr/api"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/queries"
+)
+
+func TestIterateAccountsFollowsNextLink(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Query().Get("offset") {
+		case "":
+			w.Write([]byte(`{"value":[{"id":"1_1"},{"id":"1_2"}],"count":3,"nextLink":"api/Accounts?offset=2&limit=2"}`))
+		case "2":
+			w.Write([]byte(`{"value":[{"id":"1_3"}],"count":3}`))
+		default:
+			t.Errorf("Unexpected request '%s'", r.URL)
+		}
+	}))
+	defer server.Close()
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	accounts := client.IterateAccounts(&queries.ListAccounts{Limit: 2})
+
+	ids := []string{}
+	for accounts.Next() {
+		ids = append(ids, accounts.Account().ID)
+	}
+	if accounts.Err() != nil {
+		t.Fatalf("Failed to iterate accounts. %s", accounts.Err())
+	}
+	if fmt.Sprint(ids) != "[1_1 1_2 1_3]" {
+		t.Errorf("Invalid accounts returned. %v", ids)
+	}
+	if accounts.Total() != 3 || requests != 2 {
+		t.Errorf("Expected total 3 in 2 requests but got total %d in %d requests", accounts.Total(), requests)
+	}
+}
+
+func TestIterateUsersUsesOffset(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		users := ""
+		for i := offset; i < offset+2 && i < 5; i++ {
+			if users != "" {
+				users += ","
+			}
+			users += fmt.Sprintf(`{"id":%d}`, i)
+		}
+		w.Write([]byte(fmt.Sprintf(`{"Users":[%s],"Total":5}`, users)))
+	}))
+	defer server.Close()
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	users := client.IterateUsers(&queries.ListUsers{Limit: 2})
+
+	count := 0
+	for users.Next() {
+		if users.User().ID != count {
+			t.Errorf("Expected user %d but got %d", count, users.User().ID)
+		}
+		count++
+	}
+	if users.Err() != nil {
+		t.Fatalf("Failed to iterate users. %s", users.Err())
+	}
+	if count != 5 {
+		t.Errorf("Expected 5 users but got %d", count)
+	}
+}
+
+func TestIterateSafesStopsOnError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("offset") == "1" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"ErrorCode":"PASWS041E","ErrorMessage":"Forbidden"}`))
+			return
+		}
+		w.Write([]byte(`{"value":[{"safeName":"Safe1"}],"count":2}`))
+	}))
+	defer server.Close()
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	safes := client.IterateSafes(&queries.ListSafes{Limit: 1})
+
+	count := 0
+	for safes.Next() {
+		count++
+	}
+	apiError, ok := pasapi.AsAPIError(safes.Err())
+	if !ok || apiError.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected a forbidden APIError but got '%v'", safes.Err())
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 safe before the error but got %d", count)
+	}
+}
@@ -3,8 +3,15 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/queries"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/requests"
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/responses"
 	httpJson "github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/httpjson"
 )
@@ -12,9 +19,9 @@ import (
 // ListPlatforms available in CyberArk
 func (c Client) ListPlatforms(query *queries.ListPlatforms) (*responses.ListPlatforms, error) {
 	url := fmt.Sprintf("%s/passwordvault/api/platforms%s", c.BaseURL, httpJson.GetURLQuery(query))
-	response, err := httpJson.Get(false, url, c.SessionToken, c.InsecureTLS, c.Logger)
+	response, err := c.GetTransport().Get(c.GetContext(), false, url, c.SessionToken, c.Logger)
 	if err != nil {
-		return &responses.ListPlatforms{}, fmt.Errorf("Failed to list platforms. %s", err)
+		return &responses.ListPlatforms{}, fmt.Errorf("Failed to list platforms. %w", err)
 	}
 	jsonString, _ := json.Marshal(response)
 	ListPlatformsResponse := responses.ListPlatforms{}
@@ -22,12 +29,25 @@ func (c Client) ListPlatforms(query *queries.ListPlatforms) (*responses.ListPlat
 	return &ListPlatformsResponse, err
 }
 
+// IteratePlatforms returns an iterator over all platforms matching query, reading every page
+func (c Client) IteratePlatforms(query *queries.ListPlatforms) *PlatformIterator {
+	q := queries.ListPlatforms{}
+	if query != nil {
+		q = *query
+	}
+	return &PlatformIterator{
+		pager: newPager(c, "platforms", "Platforms", "Total", 0, 0, func(offset int) string {
+			return fmt.Sprintf("%s/passwordvault/api/platforms%s", c.BaseURL, httpJson.GetURLQuery(&q))
+		}),
+	}
+}
+
 // GetPlatform details for specific platform
 func (c Client) GetPlatform(platformID string) (*responses.GetPlatform, error) {
 	url := fmt.Sprintf("%s/passwordvault/api/platforms/%s", c.BaseURL, platformID)
-	response, err := httpJson.Get(false, url, c.SessionToken, c.InsecureTLS, c.Logger)
+	response, err := c.GetTransport().Get(c.GetContext(), false, url, c.SessionToken, c.Logger)
 	if err != nil {
-		return &responses.GetPlatform{}, fmt.Errorf("Failed to get platform. %s", err)
+		return &responses.GetPlatform{}, fmt.Errorf("Failed to get platform. %w", err)
 	}
 
 	jsonString, _ := json.Marshal(response)
@@ -35,3 +55,157 @@ func (c Client) GetPlatform(platformID string) (*responses.GetPlatform, error) {
 	err = json.Unmarshal(jsonString, GetPlatformResponse)
 	return GetPlatformResponse, err
 }
+
+const (
+	// PlatformTypeTarget is the type of the platforms of accounts
+	PlatformTypeTarget = "target"
+	// PlatformTypeDependent is the type of the platforms of the dependencies of accounts, such as services
+	PlatformTypeDependent = "dependent"
+	// PlatformTypeGroup is the type of the platforms of account groups
+	PlatformTypeGroup = "group"
+	// PlatformTypeRotationalGroup is the type of the platforms of rotational groups
+	PlatformTypeRotationalGroup = "rotational-group"
+)
+
+// platformTypePaths are the paths of the API of each platform type
+var platformTypePaths = map[string]string{
+	PlatformTypeTarget:          "Targets",
+	PlatformTypeDependent:       "Dependents",
+	PlatformTypeGroup:           "Groups",
+	PlatformTypeRotationalGroup: "RotationalGroups",
+}
+
+func getPlatformTypePath(platformType string) (string, error) {
+	path, ok := platformTypePaths[strings.ToLower(platformType)]
+	if !ok {
+		return "", fmt.Errorf("Invalid platform type '%s'. Valid values: target, dependent, group or rotational-group", platformType)
+	}
+	return path, nil
+}
+
+// ListPlatformsOfType returns the target, dependent, group or rotational group platforms
+func (c Client) ListPlatformsOfType(platformType string) (*responses.ListPlatformSummaries, error) {
+	path, err := getPlatformTypePath(platformType)
+	if err != nil {
+		return &responses.ListPlatformSummaries{}, err
+	}
+
+	url := fmt.Sprintf("%s/passwordvault/api/Platforms/%s", c.BaseURL, path)
+	response, err := c.GetTransport().Get(c.GetContext(), false, url, c.SessionToken, c.Logger)
+	if err != nil {
+		return &responses.ListPlatformSummaries{}, fmt.Errorf("Failed to list %s platforms. %w", platformType, err)
+	}
+
+	jsonString, _ := json.Marshal(response)
+	ListPlatformSummariesResponse := &responses.ListPlatformSummaries{}
+	err = json.Unmarshal(jsonString, ListPlatformSummariesResponse)
+	return ListPlatformSummariesResponse, err
+}
+
+// FindPlatform returns the platform of a type with the platform ID given, e.g. WinDomain, or with
+// the numeric ID given
+func (c Client) FindPlatform(platformType string, platformID string) (*responses.PlatformSummary, error) {
+	platforms, err := c.ListPlatformsOfType(platformType)
+	if err != nil {
+		return nil, err
+	}
+
+	id, _ := strconv.Atoi(platformID)
+	for _, platform := range platforms.Platforms {
+		if strings.EqualFold(platform.PlatformID, platformID) || (id != 0 && platform.ID == id) {
+			return &platform, nil
+		}
+	}
+	return nil, fmt.Errorf("%s platform '%s' does not exist", strings.Title(platformType), platformID)
+}
+
+// ExportPlatform writes the platform package, a zip file, to w. The file name returned by PAS is
+// returned, or an empty string if PAS did not return one
+func (c Client) ExportPlatform(platformID string, w io.Writer) (string, error) {
+	url := fmt.Sprintf("%s/passwordvault/api/Platforms/%s/Export", c.BaseURL, url.PathEscape(platformID))
+	fileName, err := c.GetTransport().Download(c.GetContext(), false, url, http.MethodPost, c.SessionToken, emptyBody, w, c.Logger)
+	if err != nil {
+		return "", fmt.Errorf("Failed to export platform '%s'. %w", platformID, err)
+	}
+
+	return fileName, nil
+}
+
+// ImportPlatform imports a platform package, a zip file read from r, and returns the ID of the
+// platform imported. fileName is only used in errors
+func (c Client) ImportPlatform(fileName string, r io.Reader) (*responses.ImportPlatform, error) {
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return &responses.ImportPlatform{}, fmt.Errorf("Failed to read platform package '%s'. %s", fileName, err)
+	}
+
+	// The package is sent as the bytes of ImportFile, which are encoded as base64 in JSON
+	url := fmt.Sprintf("%s/passwordvault/api/Platforms/Import", c.BaseURL)
+	response, err := c.GetTransport().Post(c.GetContext(), false, url, c.SessionToken, map[string]interface{}{"ImportFile": content}, c.Logger)
+	if err != nil {
+		return &responses.ImportPlatform{}, fmt.Errorf("Failed to import platform '%s'. %w", fileName, err)
+	}
+
+	jsonString, _ := json.Marshal(response)
+	ImportPlatformResponse := &responses.ImportPlatform{}
+	err = json.Unmarshal(jsonString, ImportPlatformResponse)
+	return ImportPlatformResponse, err
+}
+
+// DuplicatePlatform creates a platform of a type from the platform with the numeric ID given
+func (c Client) DuplicatePlatform(platformType string, id int, platform requests.DuplicatePlatform) (*responses.PlatformSummary, error) {
+	path, err := getPlatformTypePath(platformType)
+	if err != nil {
+		return &responses.PlatformSummary{}, err
+	}
+
+	url := fmt.Sprintf("%s/passwordvault/api/Platforms/%s/%d/Duplicate", c.BaseURL, path, id)
+	response, err := c.GetTransport().Post(c.GetContext(), false, url, c.SessionToken, platform, c.Logger)
+	if err != nil {
+		return &responses.PlatformSummary{}, fmt.Errorf("Failed to duplicate %s platform '%d'. %w", platformType, id, err)
+	}
+
+	jsonString, _ := json.Marshal(response)
+	DuplicatePlatformResponse := &responses.PlatformSummary{}
+	err = json.Unmarshal(jsonString, DuplicatePlatformResponse)
+	return DuplicatePlatformResponse, err
+}
+
+// ActivatePlatform activates the target platform with the numeric ID given
+func (c Client) ActivatePlatform(id int) error {
+	url := fmt.Sprintf("%s/passwordvault/api/Platforms/Targets/%d/activate", c.BaseURL, id)
+	_, err := c.GetTransport().Post(c.GetContext(), false, url, c.SessionToken, emptyBody, c.Logger)
+	if err != nil {
+		return fmt.Errorf("Failed to activate target platform '%d'. %w", id, err)
+	}
+
+	return nil
+}
+
+// DeactivatePlatform deactivates the target platform with the numeric ID given. Accounts of an
+// inactive platform are not managed
+func (c Client) DeactivatePlatform(id int) error {
+	url := fmt.Sprintf("%s/passwordvault/api/Platforms/Targets/%d/deactivate", c.BaseURL, id)
+	_, err := c.GetTransport().Post(c.GetContext(), false, url, c.SessionToken, emptyBody, c.Logger)
+	if err != nil {
+		return fmt.Errorf("Failed to deactivate target platform '%d'. %w", id, err)
+	}
+
+	return nil
+}
+
+// DeletePlatform deletes the platform of a type with the numeric ID given
+func (c Client) DeletePlatform(platformType string, id int) error {
+	path, err := getPlatformTypePath(platformType)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/passwordvault/api/Platforms/%s/%d", c.BaseURL, path, id)
+	_, err = c.GetTransport().Delete(c.GetContext(), false, url, c.SessionToken, c.Logger)
+	if err != nil {
+		return fmt.Errorf("Failed to delete %s platform '%d'. %w", platformType, id, err)
+	}
+
+	return nil
+}
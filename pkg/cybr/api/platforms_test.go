@@ -0,0 +1,123 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pasapi "github.com/infamousjoeg/cybr-cli/pkg/cybr/api"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/requests"
+)
+
+// newPlatformsServer returns a PAS stand-in with the target platform WinDomain and the group
+// platform SampleGroup. The requests changing platforms are added to requested
+func newPlatformsServer(t *testing.T, requested *[]string) *httptest.Server {
+	return newPASServer(requested,
+		route{http.MethodGet, "/passwordvault/api/Platforms/Targets", respond(`{"Platforms":[{"ID":12,"PlatformID":"WinDomain","Name":"Windows Domain","Active":true,"SystemType":"Windows"}]}`)},
+		route{http.MethodGet, "/passwordvault/api/Platforms/Groups", respond(`{"Platforms":[{"ID":40,"PlatformID":"SampleGroup","Name":"Sample Group"}]}`)},
+		route{http.MethodPost, "/passwordvault/api/Platforms/Groups/40/Duplicate", respond(`{"ID":41,"PlatformID":"App1Group","Name":"App1 Group"}`)},
+		route{"", "/passwordvault/api/Platforms/WinDomain/Export", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Disposition", `attachment; filename="WinDomain.zip"`)
+			w.Write([]byte("PK\x03\x04zip"))
+		}},
+		route{http.MethodPost, "/passwordvault/api/Platforms/Import", func(w http.ResponseWriter, r *http.Request) {
+			body := struct {
+				ImportFile []byte
+			}{}
+			err := json.NewDecoder(r.Body).Decode(&body)
+			if err != nil || r.Header.Get("Content-Type") != "application/json" {
+				t.Fatalf("Expected the platform package in a JSON body. %s", err)
+			}
+			if string(body.ImportFile) != "PK\x03\x04zip" {
+				t.Errorf("Invalid platform package '%s'", body.ImportFile)
+			}
+			w.Write([]byte(`{"PlatformID":"WinDomain"}`))
+		}},
+		route{"", "/passwordvault/api/Platforms/Targets/12/deactivate", respondStatus(http.StatusNoContent, "")},
+		route{"", "/passwordvault/api/Platforms/Targets/12", respondStatus(http.StatusNoContent, "")},
+	)
+}
+
+func TestFindPlatform(t *testing.T) {
+	server := newPlatformsServer(t, &[]string{})
+	defer server.Close()
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	for _, platformID := range []string{"windomain", "12"} {
+		platform, err := client.FindPlatform(pasapi.PlatformTypeTarget, platformID)
+		if err != nil {
+			t.Fatalf("Failed to find platform '%s'. %s", platformID, err)
+		}
+		if platform.ID != 12 || platform.PlatformID != "WinDomain" {
+			t.Errorf("Invalid platform. %+v", platform)
+		}
+	}
+
+	_, err := client.FindPlatform(pasapi.PlatformTypeGroup, "WinDomain")
+	if err == nil {
+		t.Errorf("Expected a platform of another type not to be found")
+	}
+	_, err = client.FindPlatform("policy", "WinDomain")
+	if err == nil {
+		t.Errorf("Expected an invalid platform type to fail")
+	}
+}
+
+func TestDuplicateAndDeletePlatforms(t *testing.T) {
+	requested := []string{}
+	server := newPlatformsServer(t, &requested)
+	defer server.Close()
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	platform, err := client.DuplicatePlatform(pasapi.PlatformTypeGroup, 40, requests.DuplicatePlatform{Name: "App1 Group"})
+	if err != nil {
+		t.Fatalf("Failed to duplicate platform. %s", err)
+	}
+	if platform.ID != 41 || platform.PlatformID != "App1Group" {
+		t.Errorf("Invalid platform. %+v", platform)
+	}
+
+	err = client.DeactivatePlatform(12)
+	if err != nil {
+		t.Errorf("Failed to deactivate platform. %s", err)
+	}
+	err = client.DeletePlatform(pasapi.PlatformTypeTarget, 12)
+	if err != nil {
+		t.Errorf("Failed to delete platform. %s", err)
+	}
+
+	expected := []string{
+		"POST /passwordvault/api/Platforms/Groups/40/Duplicate",
+		"POST /passwordvault/api/Platforms/Targets/12/deactivate",
+		"DELETE /passwordvault/api/Platforms/Targets/12",
+	}
+	if strings.Join(requested, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v but got %v", expected, requested)
+	}
+}
+
+func TestExportAndImportPlatform(t *testing.T) {
+	server := newPlatformsServer(t, &[]string{})
+	defer server.Close()
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	content := new(bytes.Buffer)
+	fileName, err := client.ExportPlatform("WinDomain", content)
+	if err != nil {
+		t.Fatalf("Failed to export platform. %s", err)
+	}
+	if fileName != "WinDomain.zip" || content.String() != "PK\x03\x04zip" {
+		t.Errorf("Invalid platform package '%s' '%s'", fileName, content)
+	}
+
+	platform, err := client.ImportPlatform(fileName, content)
+	if err != nil {
+		t.Fatalf("Failed to import platform. %s", err)
+	}
+	if platform.PlatformID != "WinDomain" {
+		t.Errorf("Invalid platform. %+v", platform)
+	}
+}
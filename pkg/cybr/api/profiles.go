@@ -0,0 +1,227 @@
+package api
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/util"
+)
+
+const (
+	// DefaultProfile is used when no profile has been selected. It is stored in
+	// ~/.cybr/config so configurations created before profiles existed keep working
+	DefaultProfile = "default"
+	// ProfileEnvKey is the environment variable used to select a profile
+	ProfileEnvKey = "CYBR_PROFILE"
+)
+
+var validProfileName = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// ProfileSummary contains the non-sensitive details of a stored profile
+type ProfileSummary struct {
+	Name        string `json:"name"`
+	Active      bool   `json:"active"`
+	BaseURL     string `json:"baseURL"`
+	AuthType    string `json:"authType"`
+	TenantID    string `json:"tenantID,omitempty"`
+	InsecureTLS bool   `json:"insecureTLS"`
+	LoggedOn    bool   `json:"loggedOn"`
+}
+
+func getConfigDir() (string, error) {
+	// Get user home directory
+	userHome, err := util.GetUserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("ACL error. %s", err)
+	}
+	return filepath.Join(userHome, ".cybr"), nil
+}
+
+func createConfigDir(profile string) error {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return err
+	}
+
+	dir := configDir
+	if profile != DefaultProfile {
+		dir = filepath.Join(configDir, "profiles")
+	}
+
+	// Create .cybr folder in user home directory
+	err = util.CreatePrivateDir(configDir)
+	if err != nil {
+		return err
+	}
+	return util.CreatePrivateDir(dir)
+}
+
+// sessionTokenKey is the credential store key holding the session token of a profile
+func sessionTokenKey(profile string) string {
+	return "pas/" + profile
+}
+
+func getActiveProfilePath() (string, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "profile"), nil
+}
+
+// ValidateProfileName makes sure a profile name can safely be used as a file name
+func ValidateProfileName(profile string) error {
+	if !validProfileName.MatchString(profile) {
+		return fmt.Errorf("Invalid profile name '%s'. Only letters, numbers, '.', '_' and '-' are allowed", profile)
+	}
+	return nil
+}
+
+// GetProfileConfigPath returns the path of the configuration file for the given profile
+func GetProfileConfigPath(profile string) (string, error) {
+	err := ValidateProfileName(profile)
+	if err != nil {
+		return "", err
+	}
+
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	if profile == DefaultProfile {
+		return filepath.Join(configDir, "config"), nil
+	}
+	return filepath.Join(configDir, "profiles", profile), nil
+}
+
+// GetActiveProfile returns the profile selected with 'cybr profile use' or the default profile
+func GetActiveProfile() string {
+	activeProfilePath, err := getActiveProfilePath()
+	if err != nil {
+		return DefaultProfile
+	}
+
+	content, err := ioutil.ReadFile(activeProfilePath)
+	if err != nil {
+		return DefaultProfile
+	}
+
+	profile := strings.TrimSpace(string(content))
+	if profile == "" {
+		return DefaultProfile
+	}
+	return profile
+}
+
+// SetActiveProfile persists the profile used when neither --profile nor CYBR_PROFILE are provided
+func SetActiveProfile(profile string) error {
+	err := ValidateProfileName(profile)
+	if err != nil {
+		return err
+	}
+
+	err = createConfigDir(DefaultProfile)
+	if err != nil {
+		return err
+	}
+
+	activeProfilePath, err := getActiveProfilePath()
+	if err != nil {
+		return err
+	}
+
+	err = ioutil.WriteFile(activeProfilePath, []byte(profile+"\n"), 0600)
+	if err != nil {
+		return fmt.Errorf("Failed to write active profile file '%s'. %s", activeProfilePath, err)
+	}
+	return nil
+}
+
+// ResolveProfile returns the profile to use. The provided profile takes precedence,
+// followed by the CYBR_PROFILE environment variable and lastly the active profile
+func ResolveProfile(profile string) string {
+	if profile != "" {
+		return profile
+	}
+	if envProfile := os.Getenv(ProfileEnvKey); envProfile != "" {
+		return envProfile
+	}
+	return GetActiveProfile()
+}
+
+// ProfileExists returns true if a configuration file exists for the given profile
+func ProfileExists(profile string) bool {
+	configPath, err := GetProfileConfigPath(profile)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(configPath)
+	return err == nil
+}
+
+// ListProfiles returns the names of all stored profiles sorted alphabetically
+func ListProfiles() ([]string, error) {
+	profiles := []string{}
+	if ProfileExists(DefaultProfile) {
+		profiles = append(profiles, DefaultProfile)
+	}
+
+	configDir, err := getConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := ioutil.ReadDir(filepath.Join(configDir, "profiles"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("Failed to read profiles directory. %s", err)
+	}
+
+	for _, file := range files {
+		if file.IsDir() || ValidateProfileName(file.Name()) != nil || file.Name() == DefaultProfile {
+			continue
+		}
+		profiles = append(profiles, file.Name())
+	}
+
+	sort.Strings(profiles)
+	return profiles, nil
+}
+
+// GetProfileSummary returns the non-sensitive details of a stored profile
+func GetProfileSummary(profile string) (ProfileSummary, error) {
+	client, err := GetProfileConfig(profile)
+	if err != nil {
+		return ProfileSummary{}, err
+	}
+
+	return ProfileSummary{
+		Name:        client.Profile,
+		Active:      client.Profile == ResolveProfile(""),
+		BaseURL:     client.BaseURL,
+		AuthType:    client.AuthType,
+		TenantID:    client.TenantID,
+		InsecureTLS: client.InsecureTLS,
+		LoggedOn:    client.SessionToken != "",
+	}, nil
+}
+
+// RemoveProfile deletes the configuration file of a profile. If the profile
+// was the active profile, the default profile becomes active again
+func RemoveProfile(profile string) error {
+	client := Client{Profile: profile}
+	err := client.RemoveConfig()
+	if err != nil {
+		return err
+	}
+
+	if GetActiveProfile() == profile && profile != DefaultProfile {
+		return SetActiveProfile(DefaultProfile)
+	}
+	return nil
+}
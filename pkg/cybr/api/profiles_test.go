@@ -0,0 +1,163 @@
+package api_test
+
+import (
+	"encoding/gob"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	pasapi "github.com/infamousjoeg/cybr-cli/pkg/cybr/api"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/credstore"
+)
+
+func setTempHome(t *testing.T) {
+	oldEnv := map[string]string{}
+	for _, key := range []string{"HOME", pasapi.ProfileEnvKey, credstore.StoreEnvKey, credstore.PassphraseEnvKey} {
+		oldEnv[key] = os.Getenv(key)
+	}
+	os.Setenv("HOME", t.TempDir())
+	os.Unsetenv(pasapi.ProfileEnvKey)
+	os.Setenv(credstore.StoreEnvKey, credstore.FileBackend)
+	os.Setenv(credstore.PassphraseEnvKey, "passphrase")
+	t.Cleanup(func() {
+		for key, value := range oldEnv {
+			os.Setenv(key, value)
+		}
+	})
+}
+
+func TestSetGetRemoveProfileConfigSuccess(t *testing.T) {
+	setTempHome(t)
+
+	client := pasapi.Client{
+		BaseURL:      "https://prod.example.com",
+		AuthType:     "ldap",
+		Profile:      "prod",
+		SessionToken: "prod-token",
+	}
+
+	err := client.SetConfig()
+	if err != nil {
+		t.Errorf("Failed to set the pasapi configuration for profile 'prod'. %s", err)
+	}
+
+	client, err = pasapi.GetProfileConfig("prod")
+	if err != nil {
+		t.Errorf("Failed to retrieve pasapi config for profile 'prod'. %s", err)
+	}
+	if client.BaseURL != "https://prod.example.com" || client.Profile != "prod" || client.SessionToken != "prod-token" {
+		t.Errorf("Invalid config retrieved for profile 'prod'. %v", client)
+	}
+
+	configPath, _ := pasapi.GetProfileConfigPath("prod")
+	content, _ := ioutil.ReadFile(configPath)
+	if strings.Contains(string(content), "prod-token") {
+		t.Errorf("Configuration file at %s contains the session token in plaintext", configPath)
+	}
+
+	_, err = pasapi.GetConfig()
+	if err == nil {
+		t.Errorf("Successfully retrieved default pasapi config but only profile 'prod' should exist")
+	}
+
+	err = client.RemoveConfig()
+	if err != nil {
+		t.Errorf("Failed to remove pasapi config for profile 'prod'. %s", err)
+	}
+}
+
+func TestResolveProfile(t *testing.T) {
+	setTempHome(t)
+
+	if profile := pasapi.ResolveProfile(""); profile != pasapi.DefaultProfile {
+		t.Errorf("Expected profile '%s' but got '%s'", pasapi.DefaultProfile, profile)
+	}
+
+	err := pasapi.SetActiveProfile("dev")
+	if err != nil {
+		t.Errorf("Failed to set active profile. %s", err)
+	}
+	if profile := pasapi.ResolveProfile(""); profile != "dev" {
+		t.Errorf("Expected active profile 'dev' but got '%s'", profile)
+	}
+
+	os.Setenv(pasapi.ProfileEnvKey, "staging")
+	if profile := pasapi.ResolveProfile(""); profile != "staging" {
+		t.Errorf("Expected profile 'staging' from %s but got '%s'", pasapi.ProfileEnvKey, profile)
+	}
+
+	if profile := pasapi.ResolveProfile("prod"); profile != "prod" {
+		t.Errorf("Expected explicit profile 'prod' but got '%s'", profile)
+	}
+}
+
+func TestListAndRemoveProfiles(t *testing.T) {
+	setTempHome(t)
+
+	for _, profile := range []string{"prod", pasapi.DefaultProfile, "dev"} {
+		client := pasapi.Client{BaseURL: "https://" + profile, AuthType: "cyberark", Profile: profile}
+		err := client.SetConfig()
+		if err != nil {
+			t.Errorf("Failed to set the pasapi configuration for profile '%s'. %s", profile, err)
+		}
+	}
+
+	profiles, err := pasapi.ListProfiles()
+	if err != nil {
+		t.Errorf("Failed to list profiles. %s", err)
+	}
+	if len(profiles) != 3 || profiles[0] != "default" || profiles[1] != "dev" || profiles[2] != "prod" {
+		t.Errorf("Invalid profiles listed. %v", profiles)
+	}
+
+	pasapi.SetActiveProfile("prod")
+	err = pasapi.RemoveProfile("prod")
+	if err != nil {
+		t.Errorf("Failed to remove profile 'prod'. %s", err)
+	}
+	if profile := pasapi.GetActiveProfile(); profile != pasapi.DefaultProfile {
+		t.Errorf("Active profile should be reset to '%s' but is '%s'", pasapi.DefaultProfile, profile)
+	}
+}
+
+func TestInvalidProfileName(t *testing.T) {
+	for _, profile := range []string{"", "../config", "a/b", "with space"} {
+		if err := pasapi.ValidateProfileName(profile); err == nil {
+			t.Errorf("Profile name '%s' is not valid however it was accepted", profile)
+		}
+	}
+}
+
+func TestMigratePlaintextSessionToken(t *testing.T) {
+	setTempHome(t)
+
+	// Write a configuration file the way older versions did
+	configPath, _ := pasapi.GetProfileConfigPath(pasapi.DefaultProfile)
+	os.MkdirAll(strings.TrimSuffix(configPath, "config"), 0700)
+	dataFile, err := os.Create(configPath)
+	if err != nil {
+		t.Fatalf("Failed to create legacy configuration file. %s", err)
+	}
+	gob.NewEncoder(dataFile).Encode(pasapi.Client{
+		BaseURL:      "https://pvwa.example.com",
+		AuthType:     "cyberark",
+		SessionToken: "legacy-token",
+	})
+	dataFile.Close()
+
+	client, err := pasapi.GetConfig()
+	if err != nil || client.SessionToken != "legacy-token" {
+		t.Errorf("Failed to read legacy configuration file. %v %v", client, err)
+	}
+
+	content, _ := ioutil.ReadFile(configPath)
+	if strings.Contains(string(content), "legacy-token") {
+		t.Errorf("Session token was not removed from configuration file at %s", configPath)
+	}
+
+	client, err = pasapi.GetConfig()
+	if err != nil || client.SessionToken != "legacy-token" {
+		t.Errorf("Failed to read migrated session token from the credential store. %v %v", client, err)
+	}
+}
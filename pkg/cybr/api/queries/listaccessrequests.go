@@ -0,0 +1,7 @@
+package queries
+
+// ListAccessRequests represents valid query parameters when listing access requests
+type ListAccessRequests struct {
+	OnlyWaiting bool `query_key:"onlywaiting"`
+	Expired     bool `query_key:"expired"`
+}
@@ -0,0 +1,11 @@
+package queries
+
+// ListDiscoveredAccounts represents valid query parameters when listing discovered accounts.
+// Filter supports platformType, privileged and accountEnabled, e.g. 'privileged eq true'
+type ListDiscoveredAccounts struct {
+	Search     string `query_key:"search"`
+	SearchType string `query_key:"searchType"`
+	Filter     string `query_key:"filter"`
+	Offset     int    `query_key:"offset"`
+	Limit      int    `query_key:"limit"`
+}
@@ -0,0 +1,10 @@
+package queries
+
+// ListGroups represents valid query parameters when listing groups.
+// Filter supports groupType, e.g. 'groupType eq Directory'
+type ListGroups struct {
+	Search         string `query_key:"search"`
+	Filter         string `query_key:"filter"`
+	Sort           string `query_key:"sort"`
+	IncludeMembers bool   `query_key:"includeMembers"`
+}
@@ -0,0 +1,13 @@
+package queries
+
+// ListRecordings represents valid query parameters when listing PSM recordings
+type ListRecordings struct {
+	Search     string `query_key:"Search"`
+	Safe       string `query_key:"Safe"`
+	FromTime   int    `query_key:"FromTime"`
+	ToTime     int    `query_key:"ToTime"`
+	Activities string `query_key:"Activities"`
+	Sort       string `query_key:"Sort"`
+	Offset     int    `query_key:"Offset"`
+	Limit      int    `query_key:"Limit"`
+}
@@ -0,0 +1,9 @@
+package queries
+
+// ListSafes represents valid query parameters when listing safes
+type ListSafes struct {
+	Search string `query_key:"search"`
+	Sort   string `query_key:"sort"`
+	Offset int    `query_key:"offset"`
+	Limit  int    `query_key:"limit"`
+}
@@ -0,0 +1,6 @@
+package queries
+
+// ListSecretVersions represents valid query parameters when listing the versions of a secret
+type ListSecretVersions struct {
+	ShowTemporary bool `query_key:"showTemporary"`
+}
@@ -4,4 +4,6 @@ package queries
 type ListUsers struct {
 	Search string `query_key:"search"`
 	Filter string `query_key:"filter"`
+	Offset int    `query_key:"offset"`
+	Limit  int    `query_key:"limit"`
 }
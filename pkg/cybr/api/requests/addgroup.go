@@ -0,0 +1,8 @@
+package requests
+
+// AddGroup request used to create a vault group
+type AddGroup struct {
+	GroupName   string `json:"groupName"`
+	Description string `json:"description,omitempty"`
+	Location    string `json:"location,omitempty"`
+}
@@ -0,0 +1,9 @@
+package requests
+
+// AddGroupMember request used to add a user to a group. MemberType is 'vault' for vault
+// users and 'domain' for directory users, which also require the DomainName
+type AddGroupMember struct {
+	MemberID   string `json:"memberId"`
+	MemberType string `json:"memberType"`
+	DomainName string `json:"domainName,omitempty"`
+}
@@ -1,12 +1,14 @@
 package requests
 
-// AddSafe contains the body of the Add Safe function's request
+// AddSafe contains the body of the Add Safe function's request. NumberOfDaysRetention is a
+// pointer so 0 days can be sent, while it is omitted when versions are retained instead
 type AddSafe struct {
-	SafeName              string `json:"SafeName"`
-	Description           string `json:"Description"`
-	OLACEnabled           bool   `json:"OLACEnabled,omitempty"`
-	ManagingCPM           string `json:"ManagingCPM"`
-	NumberOfDaysRetention int    `json:"NumberOfDaysRetention"`
-	AutoPurgeEnabled      bool   `json:"AutoPurgeEnabled,omitempty"`
-	SafeLocation          string `json:"Location,omitempty"`
+	SafeName                  string `json:"SafeName"`
+	Description               string `json:"Description"`
+	OLACEnabled               bool   `json:"OLACEnabled,omitempty"`
+	ManagingCPM               string `json:"ManagingCPM"`
+	NumberOfDaysRetention     *int   `json:"NumberOfDaysRetention,omitempty"`
+	NumberOfVersionsRetention int    `json:"NumberOfVersionsRetention,omitempty"`
+	AutoPurgeEnabled          bool   `json:"AutoPurgeEnabled,omitempty"`
+	SafeLocation              string `json:"Location,omitempty"`
 }
@@ -4,7 +4,7 @@ package requests
 type AddUser struct {
 	Username               string            `json:"username"`
 	UserType               string            `json:"userType"`
-	InitialPassword        string            `json:"initialPassword"`
+	InitialPassword        string            `json:"initialPassword,omitempty"`
 	AuthenticationMethod   []string          `json:"authenticationMethod"`
 	Location               string            `json:"location"`
 	UnAuthorizedInterfaces []string          `json:"unAuthorizedInterfaces"`
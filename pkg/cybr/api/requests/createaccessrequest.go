@@ -0,0 +1,16 @@
+package requests
+
+// CreateAccessRequest request used to ask for access to an account protected by dual control.
+// FromDate and ToDate are unix times limiting the access, and are only used with MultipleAccessRequired
+type CreateAccessRequest struct {
+	AccountID              string            `json:"AccountId"`
+	Reason                 string            `json:"Reason,omitempty"`
+	TicketingSystemName    string            `json:"TicketingSystemName,omitempty"`
+	TicketID               string            `json:"TicketId,omitempty"`
+	MultipleAccessRequired bool              `json:"MultipleAccessRequired"`
+	FromDate               int               `json:"FromDate,omitempty"`
+	ToDate                 int               `json:"ToDate,omitempty"`
+	AdditionalInfo         map[string]string `json:"AdditionalInfo,omitempty"`
+	UseConnect             bool              `json:"UseConnect,omitempty"`
+	ConnectionComponent    string            `json:"ConnectionComponent,omitempty"`
+}
@@ -0,0 +1,7 @@
+package requests
+
+// DuplicatePlatform request used to create a platform from an existing platform
+type DuplicatePlatform struct {
+	Name        string `json:"Name"`
+	Description string `json:"Description,omitempty"`
+}
@@ -0,0 +1,9 @@
+package requests
+
+// LinkAccount request used to associate a logon, reconcile or other linked account to an account
+type LinkAccount struct {
+	Safe               string `json:"safe"`
+	ExtraPasswordIndex int    `json:"extraPasswordIndex"`
+	Name               string `json:"name"`
+	Folder             string `json:"folder"`
+}
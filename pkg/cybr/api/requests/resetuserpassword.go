@@ -0,0 +1,7 @@
+package requests
+
+// ResetUserPassword request used to set a new vault password for a user
+type ResetUserPassword struct {
+	ID          int    `json:"id"`
+	NewPassword string `json:"newPassword"`
+}
@@ -0,0 +1,6 @@
+package requests
+
+// ReviewAccessRequest request used to confirm or reject an incoming access request
+type ReviewAccessRequest struct {
+	Reason string `json:"Reason"`
+}
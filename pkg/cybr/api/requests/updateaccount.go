@@ -0,0 +1,10 @@
+package requests
+
+// PatchOp is a JSON Patch (RFC 6902) operation on the properties of an account. Path is a JSON
+// Pointer (RFC 6901), e.g. /platformAccountProperties/Port
+type PatchOp struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+	From  string      `json:"from,omitempty"`
+}
@@ -0,0 +1,7 @@
+package requests
+
+// UpdateSafeMember used to update the permissions of a safe member
+type UpdateSafeMember struct {
+	MembershipExpirationDate string            `json:"MembershipExpirationDate,omitempty"`
+	Permissions              map[string]string `json:"Permissions"`
+}
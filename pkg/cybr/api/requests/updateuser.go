@@ -0,0 +1,27 @@
+package requests
+
+// UpdateUser request used to update a PAS user. Every property of the user is replaced, so the
+// request is usually built from the current details of the user. ExpiryDate is a pointer so the
+// expiry date of a user can be removed by setting it to 0
+type UpdateUser struct {
+	ID                     int               `json:"id"`
+	Username               string            `json:"username"`
+	UserType               string            `json:"userType"`
+	Source                 string            `json:"source,omitempty"`
+	ComponentUser          bool              `json:"componentUser"`
+	Location               string            `json:"location"`
+	EnableUser             bool              `json:"enableUser"`
+	Suspended              bool              `json:"suspended"`
+	ChangePassOnNextLogon  bool              `json:"changePassOnNextLogon"`
+	ExpiryDate             *int              `json:"expiryDate,omitempty"`
+	AuthenticationMethod   []string          `json:"authenticationMethod"`
+	UnAuthorizedInterfaces []string          `json:"unAuthorizedInterfaces"`
+	VaultAuthorization     []string          `json:"vaultAuthorization"`
+	PasswordNeverExpires   bool              `json:"passwordNeverExpires"`
+	DistinguishedName      string            `json:"distinguishedName"`
+	Description            string            `json:"description"`
+	BusinessAddress        map[string]string `json:"businessAddress,omitempty"`
+	Internet               map[string]string `json:"internet,omitempty"`
+	Phones                 map[string]string `json:"phones,omitempty"`
+	PersonalDetails        map[string]string `json:"personalDetails,omitempty"`
+}
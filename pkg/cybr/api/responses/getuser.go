@@ -0,0 +1,4 @@
+package responses
+
+// GetUser response contains the details of a user. It has the same properties as the response of adding a user
+type GetUser = AddUser
@@ -0,0 +1,57 @@
+package responses
+
+// ListMyAccessRequests response from listing the access requests of the logged on user
+type ListMyAccessRequests struct {
+	MyRequests []AccessRequest `json:"MyRequests"`
+}
+
+// ListIncomingAccessRequests response from listing the access requests the logged on user can confirm
+type ListIncomingAccessRequests struct {
+	IncomingRequests []AccessRequest `json:"IncomingRequests"`
+}
+
+// AccessRequest is a request for access to an account protected by dual control
+type AccessRequest struct {
+	RequestID                string                  `json:"RequestID"`
+	SafeName                 string                  `json:"SafeName"`
+	RequestorUserName        string                  `json:"RequestorUserName"`
+	RequestorReason          string                  `json:"RequestorReason"`
+	UserReason               string                  `json:"UserReason,omitempty"`
+	CreationDate             int                     `json:"CreationDate"`
+	ExpirationDate           int                     `json:"ExpirationDate"`
+	Operation                string                  `json:"Operation,omitempty"`
+	OperationType            int                     `json:"OperationType,omitempty"`
+	AccessType               string                  `json:"AccessType"`
+	AccessFrom               int                     `json:"AccessFrom,omitempty"`
+	AccessTo                 int                     `json:"AccessTo,omitempty"`
+	ConfirmationsLeft        int                     `json:"ConfirmationsLeft"`
+	CurrentConfirmationLevel int                     `json:"CurrentConfirmationLevel,omitempty"`
+	RequiredConfirmersCount  int                     `json:"RequiredConfirmersCount,omitempty"`
+	Status                   int                     `json:"Status"`
+	StatusTitle              string                  `json:"StatusTitle"`
+	InvalidRequestReason     int                     `json:"InvalidRequestReason,omitempty"`
+	TicketingSystemName      string                  `json:"TicketingSystemName,omitempty"`
+	TicketID                 string                  `json:"TicketId,omitempty"`
+	AdditionalInfo           map[string]interface{}  `json:"AdditionalInfo,omitempty"`
+	Confirmers               []AccessRequestReviewer `json:"Confirmers,omitempty"`
+	AccountDetails           AccessRequestAccount    `json:"AccountDetails"`
+}
+
+// AccessRequestReviewer is a user or group that can confirm an access request
+type AccessRequestReviewer struct {
+	ID      int    `json:"ID"`
+	Name    string `json:"Name"`
+	Type    string `json:"Type"`
+	Members []struct {
+		ID     int    `json:"ID"`
+		Name   string `json:"Name"`
+		Reason string `json:"Reason,omitempty"`
+		Action string `json:"Action,omitempty"`
+	} `json:"Members,omitempty"`
+}
+
+// AccessRequestAccount is the account an access request is for
+type AccessRequestAccount struct {
+	AccountID  string                 `json:"AccountID"`
+	Properties map[string]interface{} `json:"Properties,omitempty"`
+}
@@ -0,0 +1,17 @@
+package responses
+
+// ListAccountActivities response from listing the activities of an account
+type ListAccountActivities struct {
+	Activities []AccountActivity `json:"Activities"`
+}
+
+// AccountActivity is an action performed on an account, such as retrieving or changing its secret
+type AccountActivity struct {
+	Date     int    `json:"Date"`
+	User     string `json:"User"`
+	Action   string `json:"Action"`
+	ActionID int    `json:"ActionID"`
+	ClientID string `json:"ClientID"`
+	Alert    bool   `json:"Alert"`
+	Reason   string `json:"Reason"`
+}
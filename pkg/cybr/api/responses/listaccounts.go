@@ -2,6 +2,7 @@ package responses
 
 // ListAccount response from listing accounts
 type ListAccount struct {
-	Value []GetAccount `json:"value"`
-	Count int          `json:"count"`
+	Value    []GetAccount `json:"value"`
+	Count    int          `json:"count"`
+	NextLink string       `json:"nextLink,omitempty"`
 }
@@ -0,0 +1,42 @@
+package responses
+
+// ListDiscoveredAccounts response from listing discovered accounts
+type ListDiscoveredAccounts struct {
+	Value    []DiscoveredAccount `json:"value"`
+	Count    int                 `json:"count"`
+	NextLink string              `json:"nextLink,omitempty"`
+}
+
+// DiscoveredAccount is an account found by Accounts Discovery that has not been onboarded
+type DiscoveredAccount struct {
+	ID                         string                 `json:"id"`
+	UserName                   string                 `json:"userName"`
+	Address                    string                 `json:"address"`
+	DiscoveryDateTime          int                    `json:"discoveryDateTime"`
+	AccountEnabled             bool                   `json:"accountEnabled"`
+	OSGroups                   string                 `json:"osGroups,omitempty"`
+	PlatformType               string                 `json:"platformType"`
+	Domain                     string                 `json:"domain,omitempty"`
+	LastLogonDateTime          int                    `json:"lastLogonDateTime,omitempty"`
+	LastPasswordSetDateTime    int                    `json:"lastPasswordSetDateTime,omitempty"`
+	PasswordNeverExpires       bool                   `json:"passwordNeverExpires"`
+	PasswordExpirationDateTime int                    `json:"passwordExpirationDateTime,omitempty"`
+	OSVersion                  string                 `json:"osVersion,omitempty"`
+	OSFamily                   string                 `json:"osFamily,omitempty"`
+	Privileged                 bool                   `json:"privileged"`
+	PrivilegedCriteria         string                 `json:"privilegedCriteria,omitempty"`
+	UserDisplayName            string                 `json:"userDisplayName,omitempty"`
+	Description                string                 `json:"description,omitempty"`
+	OrganizationalUnit         string                 `json:"organizationalUnit,omitempty"`
+	AdditionalProperties       map[string]interface{} `json:"additionalProperties,omitempty"`
+	NumberOfDependencies       int                    `json:"numberOfDependencies"`
+	Dependencies               []DiscoveredDependency `json:"dependencies,omitempty"`
+}
+
+// DiscoveredDependency is a service, scheduled task or application pool using a discovered account
+type DiscoveredDependency struct {
+	Name       string `json:"name"`
+	Address    string `json:"address"`
+	Type       string `json:"type"`
+	TaskFolder string `json:"taskFolder,omitempty"`
+}
@@ -0,0 +1,26 @@
+package responses
+
+// ListGroups response from listing groups
+type ListGroups struct {
+	Groups []Group `json:"value"`
+	Count  int     `json:"count"`
+}
+
+// Group is a vault group or a group mapped from an LDAP directory. Directory and DN are only
+// set for groups of type Directory
+type Group struct {
+	ID          int           `json:"id"`
+	GroupName   string        `json:"groupName"`
+	GroupType   string        `json:"groupType"`
+	Description string        `json:"description,omitempty"`
+	Location    string        `json:"location,omitempty"`
+	Directory   string        `json:"directory,omitempty"`
+	DN          string        `json:"dn,omitempty"`
+	Members     []GroupMember `json:"members,omitempty"`
+}
+
+// GroupMember is a user of a group
+type GroupMember struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+}
@@ -0,0 +1,16 @@
+package responses
+
+// ListLinkedAccounts response from listing the linked accounts of an account
+type ListLinkedAccounts struct {
+	LinkedAccounts []LinkedAccount `json:"LinkedAccounts"`
+}
+
+// LinkedAccount is an account linked to another account. ExtraPasswordIndex is 1 for the
+// logon account, 2 for the enable account and 3 for the reconcile account
+type LinkedAccount struct {
+	AccountID          string `json:"AccountID,omitempty"`
+	Name               string `json:"Name"`
+	SafeName           string `json:"SafeName"`
+	Folder             string `json:"Folder"`
+	ExtraPasswordIndex int    `json:"ExtraPasswordIndex"`
+}
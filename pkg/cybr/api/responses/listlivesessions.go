@@ -0,0 +1,37 @@
+package responses
+
+// ListLiveSessions response from listing live PSM sessions
+type ListLiveSessions struct {
+	LiveSessions []LiveSession `json:"LiveSessions"`
+	Total        int           `json:"Total"`
+}
+
+// LiveSession is a PSM session in progress
+type LiveSession struct {
+	SessionID               string                 `json:"SessionID"`
+	SessionGUID             string                 `json:"SessionGuid"`
+	SafeName                string                 `json:"SafeName"`
+	FolderName              string                 `json:"FolderName,omitempty"`
+	Start                   int                    `json:"Start"`
+	End                     int                    `json:"End,omitempty"`
+	Duration                int                    `json:"Duration"`
+	User                    string                 `json:"User"`
+	RemoteMachine           string                 `json:"RemoteMachine"`
+	ProtocolName            string                 `json:"ProtocolName"`
+	ClientApp               string                 `json:"ClientApp,omitempty"`
+	FromIP                  string                 `json:"FromIP"`
+	AccountUsername         string                 `json:"AccountUsername"`
+	AccountPlatformID       string                 `json:"AccountPlatformID"`
+	AccountAddress          string                 `json:"AccountAddress"`
+	AccountID               string                 `json:"AccountID,omitempty"`
+	ConnectionComponentID   string                 `json:"ConnectionComponentID"`
+	ConnectionComponentName string                 `json:"ConnectionComponentName,omitempty"`
+	PSMServerID             string                 `json:"PSMServerID,omitempty"`
+	RiskScore               float64                `json:"RiskScore,omitempty"`
+	Severity                string                 `json:"Severity,omitempty"`
+	IsLive                  bool                   `json:"IsLive"`
+	CanTerminate            bool                   `json:"CanTerminate"`
+	CanMonitor              bool                   `json:"CanMonitor"`
+	CanSuspend              bool                   `json:"CanSuspend"`
+	RawProperties           map[string]interface{} `json:"RawProperties,omitempty"`
+}
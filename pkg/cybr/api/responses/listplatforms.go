@@ -3,6 +3,7 @@ package responses
 // ListPlatforms contains an array of all platforms
 type ListPlatforms struct {
 	Platforms []ListPlatform `json:"Platforms"`
+	Total     int            `json:"Total,omitempty"`
 }
 
 // ListPlatform contains the platform details of every platform
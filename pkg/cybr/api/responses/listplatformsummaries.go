@@ -0,0 +1,22 @@
+package responses
+
+// ListPlatformSummaries response from listing the target, dependent, group or rotational group platforms
+type ListPlatformSummaries struct {
+	Platforms []PlatformSummary `json:"Platforms"`
+}
+
+// PlatformSummary is a target, dependent, group or rotational group platform. ID is the numeric ID
+// used to duplicate, activate, deactivate or delete the platform
+type PlatformSummary struct {
+	ID          int    `json:"ID"`
+	PlatformID  string `json:"PlatformID"`
+	Name        string `json:"Name"`
+	Description string `json:"Description,omitempty"`
+	Active      bool   `json:"Active"`
+	SystemType  string `json:"SystemType,omitempty"`
+}
+
+// ImportPlatform response from importing a platform package
+type ImportPlatform struct {
+	PlatformID string `json:"PlatformID"`
+}
@@ -0,0 +1,54 @@
+package responses
+
+// ListRecordings response from listing PSM recordings
+type ListRecordings struct {
+	Recordings []Recording `json:"Recordings"`
+	Total      int         `json:"Total"`
+}
+
+// Recording is a recorded PSM session
+type Recording struct {
+	SessionID               string                 `json:"SessionID"`
+	SessionGUID             string                 `json:"SessionGuid"`
+	SafeName                string                 `json:"SafeName"`
+	FolderName              string                 `json:"FolderName,omitempty"`
+	FileName                string                 `json:"FileName,omitempty"`
+	Start                   int                    `json:"Start"`
+	End                     int                    `json:"End"`
+	Duration                int                    `json:"Duration"`
+	User                    string                 `json:"User"`
+	RemoteMachine           string                 `json:"RemoteMachine"`
+	ProtocolName            string                 `json:"ProtocolName"`
+	ClientApp               string                 `json:"ClientApp,omitempty"`
+	FromIP                  string                 `json:"FromIP"`
+	AccountUsername         string                 `json:"AccountUsername"`
+	AccountPlatformID       string                 `json:"AccountPlatformID"`
+	AccountAddress          string                 `json:"AccountAddress"`
+	ConnectionComponentID   string                 `json:"ConnectionComponentID"`
+	ConnectionComponentName string                 `json:"ConnectionComponentName,omitempty"`
+	RiskScore               float64                `json:"RiskScore,omitempty"`
+	Severity                string                 `json:"Severity,omitempty"`
+	RecordingFiles          []RecordingFile        `json:"RecordingFiles,omitempty"`
+	RawProperties           map[string]interface{} `json:"RawProperties,omitempty"`
+}
+
+// RecordingFile is a file of a PSM recording
+type RecordingFile struct {
+	FileName      string `json:"FileName"`
+	FileSize      int    `json:"FileSize"`
+	RecordingType string `json:"RecordingType"`
+}
+
+// ListRecordingActivities response from listing the activities of a PSM recording
+type ListRecordingActivities struct {
+	Activities []RecordingActivity `json:"Activities"`
+}
+
+// RecordingActivity is a command, window title or keystroke recorded during a PSM session
+type RecordingActivity struct {
+	Time         int    `json:"Time"`
+	ActivityType string `json:"ActivityType,omitempty"`
+	Activity     string `json:"Activity"`
+	User         string `json:"User,omitempty"`
+	RiskScore    int    `json:"RiskScore,omitempty"`
+}
@@ -2,8 +2,9 @@ package responses
 
 // ListSafeMembers contains data of all members of a specific safe
 type ListSafeMembers struct {
-	Members []Members `json:"value"`
-	Count   int       `json:"count"`
+	Members  []Members `json:"value"`
+	Count    int       `json:"count"`
+	NextLink string    `json:"nextLink,omitempty"`
 }
 
 // Members contains all safe member username/group name and their permissions
@@ -15,6 +16,7 @@ type Members struct {
 	MemberType                string      `json:"memberType"`
 	IsExpiredMembershipEnable bool        `json:"isExpiredMembershipEnable"`
 	IsPredefinedUser          bool        `json:"isPredefinedUser"`
+	MembershipExpirationDate  *int64      `json:"membershipExpirationDate"`
 	Permissions               Permissions `json:"Permissions"`
 }
 
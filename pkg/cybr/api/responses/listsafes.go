@@ -2,14 +2,21 @@ package responses
 
 // ListSafes contains an array of all safes the current user can read
 type ListSafes struct {
-	Safes []ListSafe `json:"value"`
+	Safes    []ListSafe `json:"value"`
+	Count    int        `json:"count,omitempty"`
+	NextLink string     `json:"nextLink,omitempty"`
 }
 
 // ListSafe contains the safe details of every safe the current user can read
 // for ListSafesResponse struct
 type ListSafe struct {
-	SafeURLId   string `json:"SafeUrlId"`
-	SafeName    string `json:"SafeName"`
-	Description string `json:"Description,omitempty"`
-	Location    string `json:"Location"`
+	SafeURLId                 string `json:"SafeUrlId"`
+	SafeName                  string `json:"SafeName"`
+	Description               string `json:"Description,omitempty"`
+	Location                  string `json:"Location"`
+	ManagingCPM               string `json:"ManagingCPM,omitempty"`
+	NumberOfDaysRetention     int    `json:"NumberOfDaysRetention,omitempty"`
+	NumberOfVersionsRetention int    `json:"NumberOfVersionsRetention,omitempty"`
+	OLACEnabled               bool   `json:"OLACEnabled,omitempty"`
+	AutoPurgeEnabled          bool   `json:"AutoPurgeEnabled,omitempty"`
 }
@@ -0,0 +1,15 @@
+package responses
+
+// ListSecretVersions response from listing the versions of the secret of an account
+type ListSecretVersions struct {
+	Versions []SecretVersion `json:"Versions"`
+}
+
+// SecretVersion is a version of the secret of an account. Temporary versions are the passwords
+// set during a change that did not complete
+type SecretVersion struct {
+	VersionID        int    `json:"versionID"`
+	ModifiedBy       string `json:"modifiedBy"`
+	ModificationDate int    `json:"modificationDate"`
+	IsTemporary      bool   `json:"isTemporary"`
+}
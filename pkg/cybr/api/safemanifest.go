@@ -0,0 +1,540 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/queries"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/requests"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/responses"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// ChangeCreate creates a safe or adds a safe member
+	ChangeCreate = "create"
+	// ChangeUpdate updates a safe or the permissions and expiration date of a safe member
+	ChangeUpdate = "update"
+	// ChangeDelete removes a safe member
+	ChangeDelete = "delete"
+
+	// KindSafe is a change of a safe
+	KindSafe = "safe"
+	// KindMember is a change of a safe member
+	KindMember = "member"
+)
+
+// SafeManifest describes the desired state of safes and their members
+type SafeManifest struct {
+	Version int            `json:"version,omitempty" yaml:"version,omitempty"`
+	Safes   []ManifestSafe `json:"safes" yaml:"safes"`
+}
+
+// ManifestSafe is the desired state of a safe. Properties that are not set are not changed
+type ManifestSafe struct {
+	Name                      string           `json:"name" yaml:"name"`
+	Description               *string          `json:"description,omitempty" yaml:"description,omitempty"`
+	Location                  string           `json:"location,omitempty" yaml:"location,omitempty"`
+	ManagingCPM               *string          `json:"managingCPM,omitempty" yaml:"managingCPM,omitempty"`
+	NumberOfDaysRetention     *int             `json:"numberOfDaysRetention,omitempty" yaml:"numberOfDaysRetention,omitempty"`
+	NumberOfVersionsRetention *int             `json:"numberOfVersionsRetention,omitempty" yaml:"numberOfVersionsRetention,omitempty"`
+	OLACEnabled               *bool            `json:"olacEnabled,omitempty" yaml:"olacEnabled,omitempty"`
+	AutoPurgeEnabled          bool             `json:"autoPurgeEnabled,omitempty" yaml:"autoPurgeEnabled,omitempty"`
+	Members                   []ManifestMember `json:"members,omitempty" yaml:"members,omitempty"`
+}
+
+// ManifestMember is a safe member with the permissions of a role. Permissions override the
+// permissions of the role. The membership expiration date is a date as MM/DD/YYYY or a Unix time
+type ManifestMember struct {
+	Name                     string          `json:"name" yaml:"name"`
+	MemberType               string          `json:"memberType,omitempty" yaml:"memberType,omitempty"`
+	SearchIn                 string          `json:"searchIn,omitempty" yaml:"searchIn,omitempty"`
+	Role                     string          `json:"role,omitempty" yaml:"role,omitempty"`
+	Permissions              map[string]bool `json:"permissions,omitempty" yaml:"permissions,omitempty"`
+	MembershipExpirationDate string          `json:"membershipExpirationDate,omitempty" yaml:"membershipExpirationDate,omitempty"`
+}
+
+// FieldChange is the change of a safe property or safe member permission
+type FieldChange struct {
+	Name string      `json:"name"`
+	From interface{} `json:"from,omitempty"`
+	To   interface{} `json:"to"`
+}
+
+// SafeChange is a change required to converge a safe or safe member to the manifest
+type SafeChange struct {
+	Action     string        `json:"action"`
+	Kind       string        `json:"kind"`
+	SafeName   string        `json:"safeName"`
+	MemberName string        `json:"memberName,omitempty"`
+	Fields     []FieldChange `json:"fields,omitempty"`
+
+	addSafe      *requests.AddSafe
+	updateSafe   *requests.UpdateSafe
+	addMember    *requests.AddSafeMember
+	updateMember *requests.UpdateSafeMember
+}
+
+// SafePlan contains the changes required to converge the safes to a manifest
+type SafePlan struct {
+	Changes []SafeChange `json:"changes"`
+}
+
+// ReadSafeManifest reads a YAML or JSON safe manifest and validates it
+func ReadSafeManifest(path string) (*SafeManifest, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read manifest '%s'. %s", path, err)
+	}
+
+	manifest := &SafeManifest{}
+	err = yaml.UnmarshalStrict(content, manifest)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse manifest '%s'. %s", path, err)
+	}
+
+	err = manifest.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("Invalid manifest '%s'. %s", path, err)
+	}
+	return manifest, nil
+}
+
+// getPermissionNames returns the names of all safe member permissions
+func getPermissionNames() []string {
+	content, _ := json.Marshal(responses.Permissions{})
+	permissions := map[string]bool{}
+	json.Unmarshal(content, &permissions)
+
+	names := []string{}
+	for name := range permissions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// findPermissionName returns the name of a permission ignoring case
+func findPermissionName(name string) (string, bool) {
+	for _, permission := range getPermissionNames() {
+		if strings.EqualFold(permission, name) {
+			return permission, true
+		}
+	}
+	return "", false
+}
+
+// Validate returns an error if the manifest contains duplicate safes or members,
+// unknown roles or permissions, or members without a role or permissions
+func (m SafeManifest) Validate() error {
+	if m.Version > 1 {
+		return fmt.Errorf("Unsupported manifest version %d", m.Version)
+	}
+
+	safes := map[string]bool{}
+	for i, safe := range m.Safes {
+		if safe.Name == "" {
+			return fmt.Errorf("Safe %d has no name", i+1)
+		}
+		if safes[strings.ToLower(safe.Name)] {
+			return fmt.Errorf("Safe '%s' is defined more than once", safe.Name)
+		}
+		safes[strings.ToLower(safe.Name)] = true
+
+		if safe.NumberOfDaysRetention != nil && safe.NumberOfVersionsRetention != nil {
+			return fmt.Errorf("Safe '%s' can only have one of numberOfDaysRetention and numberOfVersionsRetention", safe.Name)
+		}
+
+		members := map[string]bool{}
+		for j, member := range safe.Members {
+			if member.Name == "" {
+				return fmt.Errorf("Member %d of safe '%s' has no name", j+1, safe.Name)
+			}
+			if members[strings.ToLower(member.Name)] {
+				return fmt.Errorf("Member '%s' of safe '%s' is defined more than once", member.Name, safe.Name)
+			}
+			members[strings.ToLower(member.Name)] = true
+
+			_, err := member.getPermissions()
+			if err == nil {
+				_, err = member.getExpirationDate()
+			}
+			if err != nil {
+				return fmt.Errorf("Member '%s' of safe '%s' is invalid. %s", member.Name, safe.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// getPermissions returns the value of every permission of the member
+func (m ManifestMember) getPermissions() (map[string]bool, error) {
+	if m.Role == "" && len(m.Permissions) == 0 {
+		return nil, fmt.Errorf("A role or permissions are required")
+	}
+
+	permissions := map[string]bool{}
+	for _, name := range getPermissionNames() {
+		permissions[name] = false
+	}
+
+	if m.Role != "" {
+		rolePermissions, err := GetRolePermissions(m.Role)
+		if err != nil {
+			return nil, err
+		}
+		for name, value := range rolePermissions {
+			permissions[name] = value == "true"
+		}
+	}
+
+	for name, value := range m.Permissions {
+		permission, ok := findPermissionName(name)
+		if !ok {
+			return nil, fmt.Errorf("Unknown permission '%s'", name)
+		}
+		permissions[permission] = value
+	}
+	return permissions, nil
+}
+
+// getExpirationDate returns the membership expiration date of the member as a Unix time, or
+// nil if the manifest does not set it
+func (m ManifestMember) getExpirationDate() (*int64, error) {
+	if m.MembershipExpirationDate == "" {
+		return nil, nil
+	}
+	unix, err := strconv.ParseInt(m.MembershipExpirationDate, 10, 64)
+	if err == nil {
+		return &unix, nil
+	}
+	date, err := time.Parse("01/02/2006", m.MembershipExpirationDate)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid membership expiration date '%s'. Use MM/DD/YYYY or a Unix time", m.MembershipExpirationDate)
+	}
+	unix = date.Unix()
+	return &unix, nil
+}
+
+// sameExpirationDate returns true if the membership expires on the desired date. Dates given
+// as MM/DD/YYYY match every time of that day
+func sameExpirationDate(member ManifestMember, desired int64, current *int64) bool {
+	if current == nil {
+		return false
+	}
+	if _, err := strconv.ParseInt(member.MembershipExpirationDate, 10, 64); err == nil {
+		return desired == *current
+	}
+	return time.Unix(*current, 0).UTC().Format("01/02/2006") == member.MembershipExpirationDate
+}
+
+func permissionsToRequest(permissions map[string]bool) map[string]string {
+	request := map[string]string{}
+	for name, value := range permissions {
+		request[name] = fmt.Sprintf("%v", value)
+	}
+	return request
+}
+
+// findSafe returns the safe with the given name, or nil if the safe does not exist
+func (c Client) findSafe(safeName string) (*responses.ListSafe, error) {
+	safes := c.IterateSafes(&queries.ListSafes{Search: safeName})
+	for safes.Next() {
+		safe := safes.Safe()
+		if strings.EqualFold(safe.SafeName, safeName) {
+			return &safe, nil
+		}
+	}
+	return nil, safes.Err()
+}
+
+// PlanSafes compares the safes and safe members of the manifest to PAS and returns the changes
+// required to converge them. When prune is true, members that are not in the manifest are
+// removed. Predefined users and the managing CPM are never removed, and safes are never deleted
+func (c Client) PlanSafes(manifest SafeManifest, prune bool) (*SafePlan, error) {
+	plan := &SafePlan{Changes: []SafeChange{}}
+	for _, safe := range manifest.Safes {
+		current, err := c.findSafe(safe.Name)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to retrieve safe '%s'. %w", safe.Name, err)
+		}
+
+		if current == nil {
+			plan.Changes = append(plan.Changes, planCreateSafe(safe))
+		} else if change, ok := planUpdateSafe(safe, *current); ok {
+			plan.Changes = append(plan.Changes, change)
+		}
+
+		memberChanges, err := c.planMembers(safe, current, prune)
+		if err != nil {
+			return nil, err
+		}
+		plan.Changes = append(plan.Changes, memberChanges...)
+	}
+	return plan, nil
+}
+
+func planCreateSafe(safe ManifestSafe) SafeChange {
+	addSafe := &requests.AddSafe{
+		SafeName:         safe.Name,
+		ManagingCPM:      "PasswordManager",
+		AutoPurgeEnabled: safe.AutoPurgeEnabled,
+		SafeLocation:     safe.Location,
+	}
+	if safe.Description != nil {
+		addSafe.Description = *safe.Description
+	}
+	if safe.ManagingCPM != nil {
+		addSafe.ManagingCPM = *safe.ManagingCPM
+	}
+	if safe.OLACEnabled != nil {
+		addSafe.OLACEnabled = *safe.OLACEnabled
+	}
+	if safe.NumberOfVersionsRetention != nil {
+		addSafe.NumberOfVersionsRetention = *safe.NumberOfVersionsRetention
+	} else {
+		days := 7
+		if safe.NumberOfDaysRetention != nil {
+			days = *safe.NumberOfDaysRetention
+		}
+		addSafe.NumberOfDaysRetention = &days
+	}
+
+	change := SafeChange{Action: ChangeCreate, Kind: KindSafe, SafeName: safe.Name, addSafe: addSafe}
+	change.addField("description", nil, addSafe.Description)
+	change.addField("location", nil, addSafe.SafeLocation)
+	change.addField("managingCPM", nil, addSafe.ManagingCPM)
+	if addSafe.NumberOfDaysRetention != nil {
+		change.addField("numberOfDaysRetention", nil, *addSafe.NumberOfDaysRetention)
+	}
+	change.addField("numberOfVersionsRetention", nil, addSafe.NumberOfVersionsRetention)
+	change.addField("olacEnabled", nil, addSafe.OLACEnabled)
+	change.addField("autoPurgeEnabled", nil, addSafe.AutoPurgeEnabled)
+	return change
+}
+
+func planUpdateSafe(safe ManifestSafe, current responses.ListSafe) (SafeChange, bool) {
+	updateSafe := &requests.UpdateSafe{}
+	change := SafeChange{Action: ChangeUpdate, Kind: KindSafe, SafeName: current.SafeName, updateSafe: updateSafe}
+
+	if safe.Description != nil && *safe.Description != current.Description {
+		updateSafe.Description = *safe.Description
+		change.Fields = append(change.Fields, FieldChange{Name: "description", From: current.Description, To: *safe.Description})
+	}
+	if safe.ManagingCPM != nil && *safe.ManagingCPM != current.ManagingCPM {
+		updateSafe.ManagingCPM = *safe.ManagingCPM
+		change.Fields = append(change.Fields, FieldChange{Name: "managingCPM", From: current.ManagingCPM, To: *safe.ManagingCPM})
+	}
+	if safe.NumberOfDaysRetention != nil && *safe.NumberOfDaysRetention != current.NumberOfDaysRetention {
+		updateSafe.NumberOfDaysRetention = *safe.NumberOfDaysRetention
+		change.Fields = append(change.Fields, FieldChange{Name: "numberOfDaysRetention", From: current.NumberOfDaysRetention, To: *safe.NumberOfDaysRetention})
+	}
+	if safe.NumberOfVersionsRetention != nil && *safe.NumberOfVersionsRetention != current.NumberOfVersionsRetention {
+		updateSafe.NumberOfVersionsRetention = *safe.NumberOfVersionsRetention
+		change.Fields = append(change.Fields, FieldChange{Name: "numberOfVersionsRetention", From: current.NumberOfVersionsRetention, To: *safe.NumberOfVersionsRetention})
+	}
+	// OLAC cannot be disabled once enabled
+	if safe.OLACEnabled != nil && *safe.OLACEnabled && !current.OLACEnabled {
+		updateSafe.OLACEnabled = true
+		change.Fields = append(change.Fields, FieldChange{Name: "olacEnabled", From: false, To: true})
+	}
+
+	return change, len(change.Fields) > 0
+}
+
+func (c Client) planMembers(safe ManifestSafe, current *responses.ListSafe, prune bool) ([]SafeChange, error) {
+	currentMembers := map[string]responses.Members{}
+	if current != nil {
+		members := c.IterateSafeMembers(current.SafeName, nil)
+		for members.Next() {
+			member := members.Member()
+			currentMembers[strings.ToLower(member.MemberName)] = member
+		}
+		if members.Err() != nil {
+			return nil, fmt.Errorf("Failed to retrieve members of safe '%s'. %w", safe.Name, members.Err())
+		}
+	}
+
+	changes := []SafeChange{}
+	desiredMembers := map[string]bool{}
+	for _, member := range safe.Members {
+		desiredMembers[strings.ToLower(member.Name)] = true
+		permissions, err := member.getPermissions()
+		if err != nil {
+			return nil, fmt.Errorf("Member '%s' of safe '%s' is invalid. %s", member.Name, safe.Name, err)
+		}
+		expirationDate, err := member.getExpirationDate()
+		if err != nil {
+			return nil, fmt.Errorf("Member '%s' of safe '%s' is invalid. %s", member.Name, safe.Name, err)
+		}
+
+		existing, ok := currentMembers[strings.ToLower(member.Name)]
+		if !ok {
+			changes = append(changes, planAddMember(safe.Name, member, permissions))
+			continue
+		}
+
+		currentPermissions := map[string]bool{}
+		content, _ := json.Marshal(existing.Permissions)
+		json.Unmarshal(content, &currentPermissions)
+
+		change := SafeChange{
+			Action:     ChangeUpdate,
+			Kind:       KindMember,
+			SafeName:   safe.Name,
+			MemberName: existing.MemberName,
+			updateMember: &requests.UpdateSafeMember{
+				MembershipExpirationDate: member.MembershipExpirationDate,
+				Permissions:              permissionsToRequest(permissions),
+			},
+		}
+		for _, name := range getPermissionNames() {
+			if permissions[name] != currentPermissions[name] {
+				change.Fields = append(change.Fields, FieldChange{Name: name, From: currentPermissions[name], To: permissions[name]})
+			}
+		}
+		if expirationDate != nil && !sameExpirationDate(member, *expirationDate, existing.MembershipExpirationDate) {
+			var from interface{}
+			if existing.MembershipExpirationDate != nil {
+				from = time.Unix(*existing.MembershipExpirationDate, 0).UTC().Format("01/02/2006")
+			}
+			change.Fields = append(change.Fields, FieldChange{Name: "membershipExpirationDate", From: from, To: member.MembershipExpirationDate})
+		}
+		if len(change.Fields) > 0 {
+			changes = append(changes, change)
+		}
+	}
+
+	if !prune || current == nil {
+		return changes, nil
+	}
+
+	names := []string{}
+	for name := range currentMembers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		member := currentMembers[name]
+		if desiredMembers[name] || member.IsPredefinedUser || strings.EqualFold(member.MemberName, current.ManagingCPM) {
+			continue
+		}
+		changes = append(changes, SafeChange{Action: ChangeDelete, Kind: KindMember, SafeName: safe.Name, MemberName: member.MemberName})
+	}
+	return changes, nil
+}
+
+func planAddMember(safeName string, member ManifestMember, permissions map[string]bool) SafeChange {
+	searchIn := member.SearchIn
+	if searchIn == "" {
+		searchIn = "Vault"
+	}
+	memberType := member.MemberType
+	if memberType == "" {
+		memberType = "User"
+	}
+
+	change := SafeChange{
+		Action:     ChangeCreate,
+		Kind:       KindMember,
+		SafeName:   safeName,
+		MemberName: member.Name,
+		addMember: &requests.AddSafeMember{
+			MemberName:               member.Name,
+			SearchIn:                 searchIn,
+			MembershipExpirationDate: member.MembershipExpirationDate,
+			Permissions:              permissionsToRequest(permissions),
+			MemberType:               memberType,
+		},
+	}
+	change.addField("memberType", nil, memberType)
+	change.addField("role", nil, member.Role)
+	for _, name := range getPermissionNames() {
+		if permissions[name] {
+			change.Fields = append(change.Fields, FieldChange{Name: name, To: true})
+		}
+	}
+	return change
+}
+
+// addField adds a field to the change unless its value is empty
+func (change *SafeChange) addField(name string, from interface{}, to interface{}) {
+	if to == "" || to == 0 || to == false {
+		return
+	}
+	change.Fields = append(change.Fields, FieldChange{Name: name, From: from, To: to})
+}
+
+// HasChanges returns true if the plan contains any change
+func (p SafePlan) HasChanges() bool {
+	return len(p.Changes) > 0
+}
+
+// String returns the plan as a diff. Created safes and members are prefixed with '+',
+// updated with '~' and removed with '-'
+func (p SafePlan) String() string {
+	if !p.HasChanges() {
+		return "No changes. The safes match the manifest.\n"
+	}
+
+	buffer := &bytes.Buffer{}
+	for _, change := range p.Changes {
+		fmt.Fprintln(buffer, change.String())
+		for _, field := range change.Fields {
+			if change.Action == ChangeCreate {
+				fmt.Fprintf(buffer, "    %s: %v\n", field.Name, field.To)
+			} else {
+				fmt.Fprintf(buffer, "    %s: %v -> %v\n", field.Name, field.From, field.To)
+			}
+		}
+	}
+
+	counts := map[string]int{}
+	for _, change := range p.Changes {
+		counts[change.Action]++
+	}
+	fmt.Fprintf(buffer, "\nPlan: %d to create, %d to update, %d to delete.\n", counts[ChangeCreate], counts[ChangeUpdate], counts[ChangeDelete])
+	return buffer.String()
+}
+
+// String returns a one line description of the change
+func (change SafeChange) String() string {
+	prefix := map[string]string{ChangeCreate: "+", ChangeUpdate: "~", ChangeDelete: "-"}[change.Action]
+	if change.Kind == KindMember {
+		return fmt.Sprintf("%s member '%s' of safe '%s'", prefix, change.MemberName, change.SafeName)
+	}
+	return fmt.Sprintf("%s safe '%s'", prefix, change.SafeName)
+}
+
+// ApplySafePlan applies the changes of a plan returned by PlanSafes in order. It stops at
+// the first change that fails. Progress is called after each change has been applied
+func (c Client) ApplySafePlan(plan *SafePlan, progress func(change SafeChange)) error {
+	for _, change := range plan.Changes {
+		var err error
+		switch {
+		case change.addSafe != nil:
+			err = c.AddSafe(*change.addSafe)
+		case change.updateSafe != nil:
+			_, err = c.UpdateSafe(change.SafeName, *change.updateSafe)
+		case change.addMember != nil:
+			err = c.AddSafeMember(change.SafeName, *change.addMember)
+		case change.updateMember != nil:
+			err = c.UpdateSafeMember(change.SafeName, change.MemberName, *change.updateMember)
+		case change.Action == ChangeDelete:
+			err = c.RemoveSafeMember(change.SafeName, change.MemberName)
+		}
+		if err != nil {
+			return err
+		}
+		if progress != nil {
+			progress(change)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+package api_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	pasapi "github.com/infamousjoeg/cybr-cli/pkg/cybr/api"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/requests"
+)
+
+const safeManifest = `version: 1
+safes:
+- name: Safe1
+  description: Updated description
+  managingCPM: PasswordManager
+  members:
+  - name: user1
+    role: EndUser
+  - name: user2
+    role: Auditor
+    permissions:
+      useaccounts: true
+- name: Safe2
+  numberOfVersionsRetention: 5
+  members:
+  - name: Admins
+    memberType: Group
+    role: SafeManager
+`
+
+// newSafeServer returns a PAS stand-in with Safe1 containing user1 with the EndUser role and a
+// membership expiring on 12/31/2030, user3, the Administrator predefined user and the PasswordManager CPM user. The requests
+// changing safes and members are returned as 'METHOD path'
+func newSafeServer(t *testing.T) (*httptest.Server, *[]string) {
+	received := []string{}
+	server := newPASServer(&received,
+		route{http.MethodGet, "/passwordvault/api/safes", func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("search") == "Safe1" {
+				w.Write([]byte(`{"value":[{"SafeName":"Safe10"},{"SafeName":"Safe1","Description":"Old","ManagingCPM":"PasswordManager","NumberOfDaysRetention":7}],"count":2}`))
+				return
+			}
+			w.Write([]byte(`{"value":[],"count":0}`))
+		}},
+		route{http.MethodGet, "/passwordvault/api/Safes/Safe1/Members", respond(`{"value":[
+			{"memberName":"user1","membershipExpirationDate":1924905600,"Permissions":{"UseAccounts":true,"RetrieveAccounts":true,"ListAccounts":true,"ViewAuditLog":true,"ViewSafeMembers":true}},
+			{"memberName":"user3","Permissions":{"ListAccounts":true}},
+			{"memberName":"Administrator","isPredefinedUser":true},
+			{"memberName":"PasswordManager"}
+		],"count":4}`)},
+		route{http.MethodPost, "*", respond(`{}`)},
+		route{http.MethodPut, "*", respond(`{}`)},
+		route{http.MethodDelete, "*", respond(`{}`)},
+	)
+	return server, &received
+}
+
+func writeManifest(t *testing.T, content string) string {
+	dir, err := ioutil.TempDir("", "manifest")
+	if err != nil {
+		t.Fatalf("Failed to create temporary directory. %s", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "safes.yaml")
+	err = ioutil.WriteFile(path, []byte(content), 0600)
+	if err != nil {
+		t.Fatalf("Failed to write manifest. %s", err)
+	}
+	return path
+}
+
+func TestPlanSafes(t *testing.T) {
+	server, _ := newSafeServer(t)
+	defer server.Close()
+
+	manifest, err := pasapi.ReadSafeManifest(writeManifest(t, safeManifest))
+	if err != nil {
+		t.Fatalf("Failed to read manifest. %s", err)
+	}
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	plan, err := client.PlanSafes(*manifest, true)
+	if err != nil {
+		t.Fatalf("Failed to plan safes. %s", err)
+	}
+
+	expected := []string{
+		"~ safe 'Safe1'",
+		"+ member 'user2' of safe 'Safe1'",
+		"- member 'user3' of safe 'Safe1'",
+		"+ safe 'Safe2'",
+		"+ member 'Admins' of safe 'Safe2'",
+	}
+	if len(plan.Changes) != len(expected) {
+		t.Fatalf("Expected %d changes but got:\n%s", len(expected), plan)
+	}
+	for i, change := range plan.Changes {
+		if change.String() != expected[i] {
+			t.Errorf("Expected change '%s' but got '%s'", expected[i], change)
+		}
+	}
+
+	fields := plan.Changes[0].Fields
+	if len(fields) != 1 || fields[0].Name != "description" || fields[0].From != "Old" {
+		t.Errorf("Expected only the description of Safe1 to change. %+v", fields)
+	}
+	if !strings.Contains(plan.String(), "    UseAccounts: true\n") {
+		t.Errorf("Expected the permissions of user2 to override its role.\n%s", plan)
+	}
+	if !strings.HasSuffix(plan.String(), "Plan: 3 to create, 1 to update, 1 to delete.\n") {
+		t.Errorf("Invalid plan summary.\n%s", plan)
+	}
+
+	plan, _ = client.PlanSafes(*manifest, false)
+	for _, change := range plan.Changes {
+		if change.Action == pasapi.ChangeDelete {
+			t.Errorf("Expected no members to be removed without prune. %s", change)
+		}
+	}
+}
+
+func TestPlanSafesMembershipExpirationDate(t *testing.T) {
+	server, _ := newSafeServer(t)
+	defer server.Close()
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	for date, from := range map[string]string{"12/31/2030": "", "1924905600": "", "01/31/2031": "12/31/2030"} {
+		manifest := pasapi.SafeManifest{Safes: []pasapi.ManifestSafe{{
+			Name:    "Safe1",
+			Members: []pasapi.ManifestMember{{Name: "user1", Role: "EndUser", MembershipExpirationDate: date}},
+		}}}
+		plan, err := client.PlanSafes(manifest, false)
+		if err != nil {
+			t.Fatalf("Failed to plan safes. %s", err)
+		}
+
+		if from == "" {
+			if len(plan.Changes) != 0 {
+				t.Errorf("Expected no changes for %s but got:\n%s", date, plan)
+			}
+			continue
+		}
+		if len(plan.Changes) != 1 || len(plan.Changes[0].Fields) != 1 {
+			t.Fatalf("Expected the expiration date of user1 to change but got:\n%s", plan)
+		}
+		field := plan.Changes[0].Fields[0]
+		if field.Name != "membershipExpirationDate" || field.From != from || field.To != date {
+			t.Errorf("Invalid change of the expiration date. %+v", field)
+		}
+	}
+}
+
+func TestApplySafePlan(t *testing.T) {
+	server, received := newSafeServer(t)
+	defer server.Close()
+
+	manifest, _ := pasapi.ReadSafeManifest(writeManifest(t, safeManifest))
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	plan, err := client.PlanSafes(*manifest, true)
+	if err != nil {
+		t.Fatalf("Failed to plan safes. %s", err)
+	}
+
+	applied := 0
+	err = client.ApplySafePlan(plan, func(change pasapi.SafeChange) { applied++ })
+	if err != nil {
+		t.Fatalf("Failed to apply plan. %s", err)
+	}
+
+	expected := []string{
+		"PUT /passwordvault/WebServices/PIMServices.svc/Safes/Safe1",
+		"POST /passwordvault/api/safes/Safe1/members",
+		"DELETE /passwordvault/api/Safes/Safe1/Members/user3",
+		"POST /passwordvault/api/safes",
+		"POST /passwordvault/api/safes/Safe2/members",
+	}
+	if strings.Join(*received, "\n") != strings.Join(expected, "\n") || applied != len(expected) {
+		t.Errorf("Expected requests:\n%s\nbut got:\n%s", strings.Join(expected, "\n"), strings.Join(*received, "\n"))
+	}
+}
+
+func TestAddSafeSendsZeroDays(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body = map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	days := 0
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	err := client.AddSafe(requests.AddSafe{SafeName: "Safe3", NumberOfDaysRetention: &days})
+	if err != nil {
+		t.Fatalf("Failed to add safe. %s", err)
+	}
+	if value, ok := body["NumberOfDaysRetention"]; !ok || value != float64(0) {
+		t.Errorf("Expected 0 days to be sent. %v", body)
+	}
+
+	err = client.AddSafe(requests.AddSafe{SafeName: "Safe4", NumberOfVersionsRetention: 5})
+	if _, ok := body["NumberOfDaysRetention"]; err != nil || ok {
+		t.Errorf("Expected the days to be omitted when versions are retained. %v %v", body, err)
+	}
+}
+
+func TestApplySafePlanAddsMemberWithAllPermissions(t *testing.T) {
+	var member requests.AddSafeMember
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/members") {
+			json.NewDecoder(r.Body).Decode(&member)
+		}
+		w.Write([]byte(`{"value":[],"count":0}`))
+	}))
+	defer server.Close()
+
+	manifest, _ := pasapi.ReadSafeManifest(writeManifest(t, safeManifest))
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	plan, _ := client.PlanSafes(pasapi.SafeManifest{Safes: manifest.Safes[1:]}, false)
+	err := client.ApplySafePlan(plan, nil)
+	if err != nil {
+		t.Fatalf("Failed to apply plan. %s", err)
+	}
+
+	if member.MemberType != "Group" || member.SearchIn != "Vault" || len(member.Permissions) != 22 {
+		t.Errorf("Invalid member added. %+v", member)
+	}
+	if member.Permissions["ManageSafe"] != "true" || member.Permissions["UseAccounts"] != "false" {
+		t.Errorf("Expected the permissions of the SafeManager role. %v", member.Permissions)
+	}
+}
+
+func TestSafeManifestValidate(t *testing.T) {
+	invalid := map[string]string{
+		"duplicate safe":       "safes:\n- name: Safe1\n- name: safe1\n",
+		"unknown role":         "safes:\n- name: Safe1\n  members:\n  - name: user1\n    role: Owner\n",
+		"unknown permission":   "safes:\n- name: Safe1\n  members:\n  - name: user1\n    permissions:\n      Fly: true\n",
+		"no role":              "safes:\n- name: Safe1\n  members:\n  - name: user1\n",
+		"both retentions":      "safes:\n- name: Safe1\n  numberOfDaysRetention: 1\n  numberOfVersionsRetention: 1\n",
+		"unknown field":        "safes:\n- name: Safe1\n  owner: me\n",
+		"unsupported version":  "version: 2\nsafes: []\n",
+		"duplicate member":     "safes:\n- name: Safe1\n  members:\n  - name: a\n    role: EndUser\n  - name: A\n    role: EndUser\n",
+		"safe without a name":  "safes:\n- description: none\n",
+		"member without name":  "safes:\n- name: Safe1\n  members:\n  - role: EndUser\n",
+		"invalid retention":    "safes:\n- name: Safe1\n  numberOfDaysRetention: seven\n",
+		"members is not array": "safes:\n- name: Safe1\n  members: user1\n",
+		"invalid expiration":   "safes:\n- name: Safe1\n  members:\n  - name: user1\n    role: EndUser\n    membershipExpirationDate: 2030-12-31\n",
+	}
+
+	for name, content := range invalid {
+		_, err := pasapi.ReadSafeManifest(writeManifest(t, content))
+		if err == nil {
+			t.Errorf("Expected an error for %s", name)
+		}
+	}
+}
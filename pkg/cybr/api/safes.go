@@ -14,9 +14,9 @@ import (
 // ListSafes CyberArk user has access to
 func (c Client) ListSafes() (*responses.ListSafes, error) {
 	url := fmt.Sprintf("%s/passwordvault/api/safes", c.BaseURL)
-	response, err := httpJson.Get(false, url, c.SessionToken, c.InsecureTLS, c.Logger)
+	response, err := c.GetTransport().Get(c.GetContext(), false, url, c.SessionToken, c.Logger)
 	if err != nil {
-		return &responses.ListSafes{}, fmt.Errorf("Failed to list safes. %s", err)
+		return &responses.ListSafes{}, fmt.Errorf("Failed to list safes. %w", err)
 	}
 	jsonString, _ := json.Marshal(response)
 	ListSafesResponse := responses.ListSafes{}
@@ -24,12 +24,27 @@ func (c Client) ListSafes() (*responses.ListSafes, error) {
 	return &ListSafesResponse, err
 }
 
+// IterateSafes returns an iterator over all safes matching query. Every page is read
+// instead of only the first page returned by ListSafes
+func (c Client) IterateSafes(query *queries.ListSafes) *SafeIterator {
+	q := queries.ListSafes{}
+	if query != nil {
+		q = *query
+	}
+	return &SafeIterator{
+		pager: newPager(c, "safes", "value", "count", q.Offset, q.Limit, func(offset int) string {
+			q.Offset = offset
+			return fmt.Sprintf("%s/passwordvault/api/safes%s", c.BaseURL, httpJson.GetURLQuery(&q))
+		}),
+	}
+}
+
 // ListSafeMembers List all members of a safe
 func (c Client) ListSafeMembers(safeName string, query *queries.ListSafeMembers) (*responses.ListSafeMembers, error) {
 	url := fmt.Sprintf("%s/passwordvault/api/Safes/%s/Members%s", c.BaseURL, url.QueryEscape(safeName), httpJson.GetURLQuery(query))
-	response, err := httpJson.Get(false, url, c.SessionToken, c.InsecureTLS, c.Logger)
+	response, err := c.GetTransport().Get(c.GetContext(), false, url, c.SessionToken, c.Logger)
 	if err != nil {
-		return &responses.ListSafeMembers{}, fmt.Errorf("Failed to list members of safe '%s'. %s", safeName, err)
+		return &responses.ListSafeMembers{}, fmt.Errorf("Failed to list members of safe '%s'. %w", safeName, err)
 	}
 	jsonString, _ := json.Marshal(response)
 	ListSafeMembersResponse := responses.ListSafeMembers{}
@@ -37,13 +52,37 @@ func (c Client) ListSafeMembers(safeName string, query *queries.ListSafeMembers)
 	return &ListSafeMembersResponse, err
 }
 
+// IterateSafeMembers returns an iterator over all members of a safe matching query. Every page
+// is read instead of only the page selected by the offset and limit of query
+func (c Client) IterateSafeMembers(safeName string, query *queries.ListSafeMembers) *SafeMemberIterator {
+	q := queries.ListSafeMembers{}
+	if query != nil {
+		q = *query
+	}
+	return &SafeMemberIterator{
+		pager: newPager(c, fmt.Sprintf("members of safe '%s'", safeName), "value", "count", q.Offset, q.Limit, func(offset int) string {
+			q.Offset = offset
+			return fmt.Sprintf("%s/passwordvault/api/Safes/%s/Members%s", c.BaseURL, url.QueryEscape(safeName), httpJson.GetURLQuery(&q))
+		}),
+	}
+}
+
 // AddSafeMember Add a user or application as a member to a safe with specific permissions
 func (c Client) AddSafeMember(safeName string, addMember requests.AddSafeMember) error {
 	url := fmt.Sprintf("%s/passwordvault/api/safes/%s/members", c.BaseURL, url.QueryEscape(safeName))
-	response, err := httpJson.Post(false, url, c.SessionToken, addMember, c.InsecureTLS, c.Logger)
+	_, err := c.GetTransport().Post(c.GetContext(), false, url, c.SessionToken, addMember, c.Logger)
 	if err != nil {
-		returnedError, _ := json.Marshal(response)
-		return fmt.Errorf("Failed to add member '%s' to safe '%s'. %s. %s", addMember.MemberName, safeName, string(returnedError), err)
+		return fmt.Errorf("Failed to add member '%s' to safe '%s'. %w", addMember.MemberName, safeName, err)
+	}
+	return nil
+}
+
+// UpdateSafeMember Update the permissions of a member of a specific safe
+func (c Client) UpdateSafeMember(safeName string, memberName string, updateMember requests.UpdateSafeMember) error {
+	url := fmt.Sprintf("%s/passwordvault/api/Safes/%s/Members/%s", c.BaseURL, url.QueryEscape(safeName), url.QueryEscape(memberName))
+	_, err := c.GetTransport().Put(c.GetContext(), false, url, c.SessionToken, updateMember, c.Logger)
+	if err != nil {
+		return fmt.Errorf("Failed to update member '%s' of safe '%s'. %w", memberName, safeName, err)
 	}
 	return nil
 }
@@ -51,10 +90,9 @@ func (c Client) AddSafeMember(safeName string, addMember requests.AddSafeMember)
 // RemoveSafeMember Remove a member from a specific safe
 func (c Client) RemoveSafeMember(safeName string, member string) error {
 	url := fmt.Sprintf("%s/passwordvault/api/Safes/%s/Members/%s", c.BaseURL, url.QueryEscape(safeName), url.QueryEscape(member))
-	response, err := httpJson.Delete(false, url, c.SessionToken, c.InsecureTLS, c.Logger)
+	_, err := c.GetTransport().Delete(c.GetContext(), false, url, c.SessionToken, c.Logger)
 	if err != nil {
-		returnedError, _ := json.Marshal(response)
-		return fmt.Errorf("Failed to remove member '%s' from safe '%s'. %s.  %s", member, safeName, string(returnedError), err)
+		return fmt.Errorf("Failed to remove member '%s' from safe '%s'. %w", member, safeName, err)
 	}
 	return nil
 }
@@ -63,9 +101,9 @@ func (c Client) RemoveSafeMember(safeName string, member string) error {
 func (c Client) AddSafe(body requests.AddSafe) error {
 	// Set URL for request
 	url := fmt.Sprintf("%s/passwordvault/api/safes", c.BaseURL)
-	_, err := httpJson.Post(false, url, c.SessionToken, body, c.InsecureTLS, c.Logger)
+	_, err := c.GetTransport().Post(c.GetContext(), false, url, c.SessionToken, body, c.Logger)
 	if err != nil {
-		return fmt.Errorf("Unable to add the safe named %s. %s", body.SafeName, err)
+		return fmt.Errorf("Unable to add the safe named %s. %w", body.SafeName, err)
 	}
 	return nil
 }
@@ -74,9 +112,9 @@ func (c Client) AddSafe(body requests.AddSafe) error {
 func (c Client) DeleteSafe(safeName string) error {
 	// Set URL for request
 	url := fmt.Sprintf("%s/passwordvault/api/safes/%s", c.BaseURL, url.QueryEscape(safeName))
-	_, err := httpJson.Delete(false, url, c.SessionToken, c.InsecureTLS, c.Logger)
+	_, err := c.GetTransport().Delete(c.GetContext(), false, url, c.SessionToken, c.Logger)
 	if err != nil {
-		return fmt.Errorf("Unable to delete the safe named %s. %s", safeName, err)
+		return fmt.Errorf("Unable to delete the safe named %s. %w", safeName, err)
 	}
 	return nil
 }
@@ -85,9 +123,9 @@ func (c Client) DeleteSafe(safeName string) error {
 func (c Client) UpdateSafe(targetSafeName string, body requests.UpdateSafe) (*responses.UpdateSafe, error) {
 	// Set URL for request
 	url := fmt.Sprintf("%s/passwordvault/WebServices/PIMServices.svc/Safes/%s", c.BaseURL, targetSafeName)
-	response, err := httpJson.Put(false, url, c.SessionToken, body, c.InsecureTLS, c.Logger)
+	response, err := c.GetTransport().Put(c.GetContext(), false, url, c.SessionToken, body, c.Logger)
 	if err != nil {
-		return nil, fmt.Errorf("Unable to update the safe named %s. %s", targetSafeName, err)
+		return nil, fmt.Errorf("Unable to update the safe named %s. %w", targetSafeName, err)
 	}
 	jsonString, _ := json.Marshal(response)
 	UpdateSafeResponse := responses.UpdateSafe{}
@@ -97,12 +135,6 @@ func (c Client) UpdateSafe(targetSafeName string, body requests.UpdateSafe) (*re
 
 // FilterSafes will return a list of safes that match the given filter, commonly used to filter by safe member
 func (c Client) FilterSafes(filter string, search string) ([]string, error) {
-	// List All Safes
-	safes, err := c.ListSafes()
-	if err != nil {
-		return nil, fmt.Errorf("Failed to retrieve a list of all safes. %s", err)
-	}
-
 	// For each safe, extract the safe name and ListSafeMembers for that safe
 	query := &queries.ListSafeMembers{
 		Filter: filter,
@@ -110,27 +142,25 @@ func (c Client) FilterSafes(filter string, search string) ([]string, error) {
 	}
 
 	var filteredSafes []string
-	for i := 0; i < len(safes.Safes); i++ {
-		safeName := safes.Safes[i].SafeName
+	// Iterate over all safes
+	safes := c.IterateSafes(nil)
+	for safes.Next() {
+		safeName := safes.Safe().SafeName
 		if safeName == "Notification Engine" {
 			continue
 		}
 		// List Safe Members
 		listMemberResult, err := c.ListSafeMembers(safeName, query)
 		if err != nil {
-			return nil, fmt.Errorf("Failed to retrieve a list of members for safe '%s'. %s", safeName, err)
-		}
-		// Unmarshal the response
-		jsonListMemberResult, _ := json.Marshal(listMemberResult)
-		parsedListMemberResult := responses.ListSafeMembers{}
-		err = json.Unmarshal(jsonListMemberResult, &parsedListMemberResult)
-		if err != nil {
-			return nil, fmt.Errorf("Failed to unmarshal the safe member from %s. %s", safeName, err)
+			return nil, fmt.Errorf("Failed to retrieve a list of members for safe '%s'. %w", safeName, err)
 		}
-		if parsedListMemberResult.Count > 0 {
-			filteredSafes = append(filteredSafes, safes.Safes[i].SafeName)
+		if listMemberResult.Count > 0 {
+			filteredSafes = append(filteredSafes, safeName)
 		}
 	}
+	if safes.Err() != nil {
+		return nil, fmt.Errorf("Failed to retrieve a list of all safes. %w", safes.Err())
+	}
 
 	return filteredSafes, nil
 }
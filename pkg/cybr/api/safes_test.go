@@ -95,12 +95,13 @@ func TestListSafeMembersInvalidSafeName(t *testing.T) {
 func TestAddSafeSuccess(t *testing.T) {
 	client, err := defaultPASAPIClient(t)
 
+	days := 0
 	newSafe := requests.AddSafe{
 		SafeName:              "TestCreateDelete",
 		Description:           "Testing creating and deleteing a safe",
 		OLACEnabled:           false,
 		ManagingCPM:           "PasswordManager",
-		NumberOfDaysRetention: 0,
+		NumberOfDaysRetention: &days,
 	}
 	err = client.AddSafe(newSafe)
 	if err != nil {
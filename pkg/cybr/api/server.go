@@ -5,15 +5,14 @@ import (
 	"fmt"
 
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/responses"
-	httpJson "github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/httpjson"
 )
 
 // ServerVerify is an unauthenticated endpoint for testing Web Service availability
 func (c Client) ServerVerify() (*responses.ServerVerify, error) {
 	url := fmt.Sprintf("%s/passwordvault/WebServices/PIMServices.svc/Verify", c.BaseURL)
-	response, err := httpJson.SendRequest(false, url, "GET", "", nil, c.InsecureTLS, c.Logger)
+	response, err := c.GetTransport().SendRequest(c.GetContext(), false, url, "GET", "", nil, c.Logger)
 	if err != nil {
-		return &responses.ServerVerify{}, fmt.Errorf("Error verifying PAS REST API Web Service. %s", err)
+		return &responses.ServerVerify{}, fmt.Errorf("Error verifying PAS REST API Web Service. %w", err)
 	}
 	jsonString, _ := json.Marshal(response)
 	VerifyResponse := responses.ServerVerify{}
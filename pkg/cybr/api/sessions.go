@@ -0,0 +1,142 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/queries"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/responses"
+	httpJson "github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/httpjson"
+)
+
+// ListLiveSessions returns the PSM sessions in progress
+func (c Client) ListLiveSessions(query *queries.ListLiveSessions) (*responses.ListLiveSessions, error) {
+	url := fmt.Sprintf("%s/passwordvault/api/LiveSessions%s", c.BaseURL, httpJson.GetURLQuery(query))
+	response, err := c.GetTransport().Get(c.GetContext(), false, url, c.SessionToken, c.Logger)
+	if err != nil {
+		return &responses.ListLiveSessions{}, fmt.Errorf("Failed to list live sessions. %w", err)
+	}
+
+	jsonString, _ := json.Marshal(response)
+	ListLiveSessionsResponse := &responses.ListLiveSessions{}
+	err = json.Unmarshal(jsonString, ListLiveSessionsResponse)
+	return ListLiveSessionsResponse, err
+}
+
+// IterateLiveSessions returns an iterator over all live sessions matching query, reading every page
+func (c Client) IterateLiveSessions(query *queries.ListLiveSessions) *LiveSessionIterator {
+	q := queries.ListLiveSessions{}
+	if query != nil {
+		q = *query
+	}
+	return &LiveSessionIterator{
+		pager: newPager(c, "live sessions", "LiveSessions", "Total", q.Offset, q.Limit, func(offset int) string {
+			q.Offset = offset
+			return fmt.Sprintf("%s/passwordvault/api/LiveSessions%s", c.BaseURL, httpJson.GetURLQuery(&q))
+		}),
+	}
+}
+
+// TerminateLiveSession terminates a PSM session in progress
+func (c Client) TerminateLiveSession(sessionID string) error {
+	url := fmt.Sprintf("%s/passwordvault/api/LiveSessions/%s/Terminate", c.BaseURL, url.QueryEscape(sessionID))
+	_, err := c.GetTransport().Post(c.GetContext(), false, url, c.SessionToken, emptyBody, c.Logger)
+	if err != nil {
+		return fmt.Errorf("Failed to terminate live session '%s'. %w", sessionID, err)
+	}
+
+	return nil
+}
+
+// SuspendLiveSession suspends a PSM session in progress, locking the keyboard and mouse of the user
+func (c Client) SuspendLiveSession(sessionID string) error {
+	url := fmt.Sprintf("%s/passwordvault/api/LiveSessions/%s/Suspend", c.BaseURL, url.QueryEscape(sessionID))
+	_, err := c.GetTransport().Post(c.GetContext(), false, url, c.SessionToken, emptyBody, c.Logger)
+	if err != nil {
+		return fmt.Errorf("Failed to suspend live session '%s'. %w", sessionID, err)
+	}
+
+	return nil
+}
+
+// ResumeLiveSession resumes a suspended PSM session
+func (c Client) ResumeLiveSession(sessionID string) error {
+	url := fmt.Sprintf("%s/passwordvault/api/LiveSessions/%s/Resume", c.BaseURL, url.QueryEscape(sessionID))
+	_, err := c.GetTransport().Post(c.GetContext(), false, url, c.SessionToken, emptyBody, c.Logger)
+	if err != nil {
+		return fmt.Errorf("Failed to resume live session '%s'. %w", sessionID, err)
+	}
+
+	return nil
+}
+
+// ListRecordings returns the recorded PSM sessions
+func (c Client) ListRecordings(query *queries.ListRecordings) (*responses.ListRecordings, error) {
+	url := fmt.Sprintf("%s/passwordvault/api/Recordings%s", c.BaseURL, httpJson.GetURLQuery(query))
+	response, err := c.GetTransport().Get(c.GetContext(), false, url, c.SessionToken, c.Logger)
+	if err != nil {
+		return &responses.ListRecordings{}, fmt.Errorf("Failed to list recordings. %w", err)
+	}
+
+	jsonString, _ := json.Marshal(response)
+	ListRecordingsResponse := &responses.ListRecordings{}
+	err = json.Unmarshal(jsonString, ListRecordingsResponse)
+	return ListRecordingsResponse, err
+}
+
+// IterateRecordings returns an iterator over all recordings matching query, reading every page
+func (c Client) IterateRecordings(query *queries.ListRecordings) *RecordingIterator {
+	q := queries.ListRecordings{}
+	if query != nil {
+		q = *query
+	}
+	return &RecordingIterator{
+		pager: newPager(c, "recordings", "Recordings", "Total", q.Offset, q.Limit, func(offset int) string {
+			q.Offset = offset
+			return fmt.Sprintf("%s/passwordvault/api/Recordings%s", c.BaseURL, httpJson.GetURLQuery(&q))
+		}),
+	}
+}
+
+// GetRecording details for a specific recording
+func (c Client) GetRecording(recordingID string) (*responses.Recording, error) {
+	url := fmt.Sprintf("%s/passwordvault/api/Recordings/%s", c.BaseURL, url.QueryEscape(recordingID))
+	response, err := c.GetTransport().Get(c.GetContext(), false, url, c.SessionToken, c.Logger)
+	if err != nil {
+		return &responses.Recording{}, fmt.Errorf("Failed to get recording '%s'. %w", recordingID, err)
+	}
+
+	jsonString, _ := json.Marshal(response)
+	GetRecordingResponse := &responses.Recording{}
+	err = json.Unmarshal(jsonString, GetRecordingResponse)
+	return GetRecordingResponse, err
+}
+
+// ListRecordingActivities returns the commands, window titles and keystrokes recorded during a session
+func (c Client) ListRecordingActivities(recordingID string) (*responses.ListRecordingActivities, error) {
+	url := fmt.Sprintf("%s/passwordvault/api/Recordings/%s/activities", c.BaseURL, url.QueryEscape(recordingID))
+	response, err := c.GetTransport().Get(c.GetContext(), false, url, c.SessionToken, c.Logger)
+	if err != nil {
+		return &responses.ListRecordingActivities{}, fmt.Errorf("Failed to list activities of recording '%s'. %w", recordingID, err)
+	}
+
+	jsonString, _ := json.Marshal(response)
+	ListRecordingActivitiesResponse := &responses.ListRecordingActivities{}
+	err = json.Unmarshal(jsonString, ListRecordingActivitiesResponse)
+	return ListRecordingActivitiesResponse, err
+}
+
+// DownloadRecording writes the recording file to w. The file name returned by PAS is returned,
+// or an empty string if PAS did not return one
+func (c Client) DownloadRecording(recordingID string, w io.Writer) (string, error) {
+	url := fmt.Sprintf("%s/passwordvault/api/Recordings/%s/Play", c.BaseURL, url.QueryEscape(recordingID))
+	fileName, err := c.GetTransport().Download(c.GetContext(), false, url, http.MethodPost, c.SessionToken, emptyBody, w, c.Logger)
+	if err != nil {
+		return "", fmt.Errorf("Failed to download recording '%s'. %w", recordingID, err)
+	}
+
+	return fileName, nil
+}
@@ -0,0 +1,122 @@
+package api_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	pasapi "github.com/infamousjoeg/cybr-cli/pkg/cybr/api"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/queries"
+)
+
+// newSessionsServer returns a PSM stand-in with three live sessions, returned two per page,
+// and one recording. The requests other than GET are added to requested
+func newSessionsServer(t *testing.T, requested *[]string) *httptest.Server {
+	return newPASServer(requested,
+		route{http.MethodGet, "/passwordvault/api/LiveSessions", func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("Safe") != "LinuxSafe" || r.URL.Query().Get("FromTime") != "1600000000" {
+				t.Errorf("Invalid query '%s'", r.URL.RawQuery)
+			}
+			if r.URL.Query().Get("Offset") == "2" {
+				w.Write([]byte(`{"LiveSessions":[{"SessionID":"s3","User":"user3"}],"Total":3}`))
+				return
+			}
+			w.Write([]byte(`{"LiveSessions":[{"SessionID":"s1","User":"user1"},{"SessionID":"s2","User":"user2"}],"Total":3}`))
+		}},
+		route{http.MethodPost, "/passwordvault/api/LiveSessions/s1/Terminate", respond("")},
+		route{http.MethodPost, "/passwordvault/api/LiveSessions/s1/Suspend", respond("")},
+		route{http.MethodPost, "/passwordvault/api/LiveSessions/s1/Resume", respond("")},
+		route{http.MethodGet, "/passwordvault/api/Recordings", respond(`{"Recordings":[{"SessionID":"r1","AccountUsername":"root","RecordingFiles":[{"FileName":"r1.avi","FileSize":5,"RecordingType":"video"}]}],"Total":1}`)},
+		route{http.MethodGet, "/passwordvault/api/Recordings/r1", respond(`{"SessionID":"r1","AccountUsername":"root","Duration":60}`)},
+		route{http.MethodGet, "/passwordvault/api/Recordings/r1/activities", respond(`{"Activities":[{"Time":1600000001,"ActivityType":"Command","Activity":"sudo su -"}]}`)},
+		route{http.MethodPost, "/passwordvault/api/Recordings/r1/Play", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Disposition", `attachment; filename="r1.avi"`)
+			w.Write([]byte("video"))
+		}},
+	)
+}
+
+func TestIterateLiveSessions(t *testing.T) {
+	server := newSessionsServer(t, &[]string{})
+	defer server.Close()
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	sessions := client.IterateLiveSessions(&queries.ListLiveSessions{Safe: "LinuxSafe", FromTime: 1600000000})
+	users := []string{}
+	for sessions.Next() {
+		users = append(users, sessions.Session().User)
+	}
+	if sessions.Err() != nil {
+		t.Fatalf("Failed to list live sessions. %s", sessions.Err())
+	}
+	if len(users) != 3 || users[2] != "user3" {
+		t.Errorf("Expected all pages of live sessions but got %v", users)
+	}
+}
+
+func TestLiveSessionActions(t *testing.T) {
+	posted := []string{}
+	server := newSessionsServer(t, &posted)
+	defer server.Close()
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	for _, action := range []func(string) error{client.TerminateLiveSession, client.SuspendLiveSession, client.ResumeLiveSession} {
+		err := action("s1")
+		if err != nil {
+			t.Errorf("Failed live session action. %s", err)
+		}
+	}
+	if len(posted) != 3 || posted[0] != "POST /passwordvault/api/LiveSessions/s1/Terminate" || posted[2] != "POST /passwordvault/api/LiveSessions/s1/Resume" {
+		t.Errorf("Invalid live session actions %v", posted)
+	}
+
+	err := client.TerminateLiveSession("unknown")
+	if apiError, ok := pasapi.AsAPIError(err); !ok || apiError.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected terminating an unknown session to fail but got %v", err)
+	}
+}
+
+func TestRecordings(t *testing.T) {
+	server := newSessionsServer(t, &[]string{})
+	defer server.Close()
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	recordings, err := client.ListRecordings(&queries.ListRecordings{})
+	if err != nil {
+		t.Fatalf("Failed to list recordings. %s", err)
+	}
+	if recordings.Total != 1 || len(recordings.Recordings[0].RecordingFiles) != 1 || recordings.Recordings[0].RecordingFiles[0].FileName != "r1.avi" {
+		t.Errorf("Invalid recordings. %+v", recordings)
+	}
+
+	recording, err := client.GetRecording("r1")
+	if err != nil || recording.Duration != 60 {
+		t.Errorf("Invalid recording. %+v %v", recording, err)
+	}
+
+	activities, err := client.ListRecordingActivities("r1")
+	if err != nil || len(activities.Activities) != 1 || activities.Activities[0].Activity != "sudo su -" {
+		t.Errorf("Invalid recording activities. %+v %v", activities, err)
+	}
+}
+
+func TestDownloadRecording(t *testing.T) {
+	server := newSessionsServer(t, &[]string{})
+	defer server.Close()
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	buffer := &bytes.Buffer{}
+	fileName, err := client.DownloadRecording("r1", buffer)
+	if err != nil {
+		t.Fatalf("Failed to download recording. %s", err)
+	}
+	if fileName != "r1.avi" || buffer.String() != "video" {
+		t.Errorf("Invalid recording downloaded. '%s' '%s'", fileName, buffer)
+	}
+
+	_, err = client.DownloadRecording("unknown", &bytes.Buffer{})
+	if apiError, ok := pasapi.AsAPIError(err); !ok || apiError.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected downloading an unknown recording to fail but got %v", err)
+	}
+}
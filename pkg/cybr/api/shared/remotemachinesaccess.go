@@ -0,0 +1,7 @@
+package shared
+
+// RemoteMachinesAccess used in getting and setting the machines an account can connect to
+type RemoteMachinesAccess struct {
+	RemoteMachines                   string `json:"remoteMachines,omitempty"`
+	AccessRestrictedToRemoteMachines bool   `json:"accessRestrictedToRemoteMachines"`
+}
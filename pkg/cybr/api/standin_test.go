@@ -0,0 +1,62 @@
+package api_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// route is a request served by a PAS stand-in. An empty method matches every method, and a
+// path ending with '*' matches every path starting with the rest of it
+type route struct {
+	method  string
+	path    string
+	handler http.HandlerFunc
+}
+
+func (rt route) matches(r *http.Request) bool {
+	if rt.method != "" && rt.method != r.Method {
+		return false
+	}
+	if strings.HasSuffix(rt.path, "*") {
+		return strings.HasPrefix(r.URL.Path, strings.TrimSuffix(rt.path, "*"))
+	}
+	return r.URL.Path == rt.path
+}
+
+// respond returns a handler responding with body
+func respond(body string) http.HandlerFunc {
+	return respondStatus(http.StatusOK, body)
+}
+
+// respondStatus returns a handler responding with status and body
+func respondStatus(status int, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}
+}
+
+// newPASServer returns a PAS stand-in serving routes. A request is handled by the first route
+// matching it, and requests without a route get the not found error of PAS. The requests other
+// than GET are added to requested as 'METHOD path', unless requested is nil
+func newPASServer(requested *[]string, routes ...route) *httptest.Server {
+	lock := sync.Mutex{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requested != nil && r.Method != http.MethodGet {
+			lock.Lock()
+			*requested = append(*requested, r.Method+" "+r.URL.EscapedPath())
+			lock.Unlock()
+		}
+
+		for _, rt := range routes {
+			if rt.matches(r) {
+				rt.handler(w, r)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"ErrorCode":"PASWS000E","ErrorMessage":"Not found"}`))
+	}))
+}
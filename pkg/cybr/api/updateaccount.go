@@ -0,0 +1,138 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/requests"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/responses"
+)
+
+// UpdateAccount changes the properties of an account with JSON Patch operations. The operations
+// are compared with the account returned by GetAccount so only the changes are sent: add and
+// replace operations setting a property to its current value and remove operations of missing
+// properties are dropped, and a replace of a missing property is sent as an add. The account is
+// returned unchanged without updating it when there are no changes
+func (c Client) UpdateAccount(accountID string, ops []requests.PatchOp) (*responses.GetAccount, error) {
+	account, err := c.GetAccount(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	patch, err := diffAccount(account, ops)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to update account '%s'. %s", accountID, err)
+	}
+	if len(patch) == 0 {
+		return account, nil
+	}
+
+	url := fmt.Sprintf("%s/passwordvault/api/Accounts/%s", c.BaseURL, accountID)
+	response, err := c.GetTransport().Patch(c.GetContext(), false, url, c.SessionToken, patch, c.Logger)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to update account '%s'. %w", accountID, err)
+	}
+
+	jsonString, _ := json.Marshal(response)
+	UpdateAccountResponse := &responses.GetAccount{}
+	err = json.Unmarshal(jsonString, UpdateAccountResponse)
+	return UpdateAccountResponse, err
+}
+
+// diffAccount returns the operations of ops that change account. Each operation is applied to a
+// copy of the account so later operations are compared with the result of earlier ones
+func diffAccount(account *responses.GetAccount, ops []requests.PatchOp) ([]requests.PatchOp, error) {
+	document := map[string]interface{}{}
+	jsonString, _ := json.Marshal(account)
+	err := json.Unmarshal(jsonString, &document)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := []requests.PatchOp{}
+	for _, op := range ops {
+		tokens, err := parsePointer(op.Path)
+		if err != nil {
+			return nil, err
+		}
+		parent, key, found := lookupPointer(document, tokens)
+
+		switch op.Op {
+		case "add", "replace":
+			if found {
+				if current, ok := parent[key]; ok && sameJSON(current, op.Value) {
+					continue
+				}
+				if _, ok := parent[key]; !ok {
+					op.Op = "add"
+				}
+				parent[key] = op.Value
+			}
+		case "remove":
+			if found {
+				if _, ok := parent[key]; !ok {
+					continue
+				}
+				delete(parent, key)
+			}
+		case "move", "copy", "test":
+		default:
+			return nil, fmt.Errorf("Invalid operation '%s' of path '%s'. Valid values: add, remove, replace, move, copy or test", op.Op, op.Path)
+		}
+		patch = append(patch, op)
+	}
+
+	return patch, nil
+}
+
+// parsePointer returns the reference tokens of a JSON Pointer
+func parsePointer(pointer string) ([]string, error) {
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("Invalid path '%s'. Paths start with '/'", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+	}
+	return tokens, nil
+}
+
+// lookupPointer returns the object holding the property referenced by tokens and the name of the
+// property. found is false when the parent of the property is not an object of document, in
+// which case the property cannot be compared
+func lookupPointer(document map[string]interface{}, tokens []string) (parent map[string]interface{}, key string, found bool) {
+	var current interface{} = document
+	for _, token := range tokens[:len(tokens)-1] {
+		switch value := current.(type) {
+		case map[string]interface{}:
+			current = value[token]
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(value) {
+				return nil, "", false
+			}
+			current = value[index]
+		default:
+			return nil, "", false
+		}
+	}
+
+	parent, found = current.(map[string]interface{})
+	return parent, tokens[len(tokens)-1], found
+}
+
+// sameJSON returns true when a and b have the same JSON encoding, e.g. the number 2222 read
+// from an account and the number 2222 of an operation
+func sameJSON(a interface{}, b interface{}) bool {
+	var decodedA, decodedB interface{}
+	jsonA, _ := json.Marshal(a)
+	jsonB, _ := json.Marshal(b)
+	if json.Unmarshal(jsonA, &decodedA) != nil || json.Unmarshal(jsonB, &decodedB) != nil {
+		return false
+	}
+	return reflect.DeepEqual(decodedA, decodedB)
+}
@@ -0,0 +1,90 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	pasapi "github.com/infamousjoeg/cybr-cli/pkg/cybr/api"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/requests"
+)
+
+// newUpdateAccountServer returns a PAS stand-in with the account 24_1. The patches sent are
+// added to patches
+func newUpdateAccountServer(t *testing.T, patches *[][]requests.PatchOp) *httptest.Server {
+	return newPASServer(nil,
+		route{http.MethodGet, "/passwordvault/api/Accounts/24_1", respond(`{"id":"24_1","name":"Operating System-UnixSSH-10.0.0.1-root","address":"10.0.0.1","userName":"root",
+			"platformId":"UnixSSH","safeName":"Safe1","secretType":"password","platformAccountProperties":{"Port":"22"},
+			"secretManagement":{"automaticManagementEnabled":true}}`)},
+		route{http.MethodPatch, "/passwordvault/api/Accounts/24_1", func(w http.ResponseWriter, r *http.Request) {
+			patch := []requests.PatchOp{}
+			err := json.NewDecoder(r.Body).Decode(&patch)
+			if err != nil {
+				t.Errorf("Invalid patch. %s", err)
+			}
+			*patches = append(*patches, patch)
+			w.Write([]byte(`{"id":"24_1","address":"10.0.0.2","platformAccountProperties":{"Port":"2222"}}`))
+		}},
+	)
+}
+
+func TestUpdateAccount(t *testing.T) {
+	patches := [][]requests.PatchOp{}
+	server := newUpdateAccountServer(t, &patches)
+	defer server.Close()
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	account, err := client.UpdateAccount("24_1", []requests.PatchOp{
+		{Op: "replace", Path: "/address", Value: "10.0.0.2"},
+		{Op: "replace", Path: "/userName", Value: "root"},
+		{Op: "replace", Path: "/platformAccountProperties/Port", Value: "2222"},
+		{Op: "replace", Path: "/platformAccountProperties/LogonDomain", Value: "CORP"},
+		{Op: "remove", Path: "/platformAccountProperties/Missing"},
+		{Op: "replace", Path: "/secretManagement/automaticManagementEnabled", Value: true},
+	})
+	if err != nil {
+		t.Fatalf("Failed to update account. %s", err)
+	}
+	if account.Address != "10.0.0.2" {
+		t.Errorf("Expected the updated account. %+v", account)
+	}
+
+	if len(patches) != 1 || len(patches[0]) != 3 {
+		t.Fatalf("Expected only the changes to be sent. %+v", patches)
+	}
+	expected := []requests.PatchOp{
+		{Op: "replace", Path: "/address", Value: "10.0.0.2"},
+		{Op: "replace", Path: "/platformAccountProperties/Port", Value: "2222"},
+		{Op: "add", Path: "/platformAccountProperties/LogonDomain", Value: "CORP"},
+	}
+	for i, op := range patches[0] {
+		if op != expected[i] {
+			t.Errorf("Expected operation %+v but got %+v", expected[i], op)
+		}
+	}
+}
+
+func TestUpdateAccountWithoutChanges(t *testing.T) {
+	patches := [][]requests.PatchOp{}
+	server := newUpdateAccountServer(t, &patches)
+	defer server.Close()
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	account, err := client.UpdateAccount("24_1", []requests.PatchOp{{Op: "add", Path: "/address", Value: "10.0.0.1"}})
+	if err != nil {
+		t.Fatalf("Failed to update account. %s", err)
+	}
+	if len(patches) != 0 || account.Address != "10.0.0.1" {
+		t.Errorf("Expected the account not to be updated. %+v", patches)
+	}
+
+	_, err = client.UpdateAccount("24_1", []requests.PatchOp{{Op: "replace", Path: "address", Value: "10.0.0.2"}})
+	if err == nil {
+		t.Errorf("Expected a path that is not a JSON Pointer to fail")
+	}
+	_, err = client.UpdateAccount("24_1", []requests.PatchOp{{Op: "merge", Path: "/address", Value: "10.0.0.2"}})
+	if err == nil || len(patches) != 0 {
+		t.Errorf("Expected an invalid operation to fail")
+	}
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/queries"
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/requests"
@@ -14,10 +15,9 @@ import (
 func (c Client) UnsuspendUser(userID int) error {
 	url := fmt.Sprintf("%s/passwordvault/api/Users/%d/activate", c.BaseURL, userID)
 
-	response, err := httpJson.Post(false, url, c.SessionToken, emptyBody, c.InsecureTLS, c.Logger)
+	_, err := c.GetTransport().Post(c.GetContext(), false, url, c.SessionToken, emptyBody, c.Logger)
 	if err != nil {
-		returnedError, _ := json.Marshal(response)
-		return fmt.Errorf("Failed to unsuspend user with id '%d'. %s. %s", userID, string(returnedError), err)
+		return fmt.Errorf("Failed to unsuspend user with id '%d'. %w", userID, err)
 	}
 	return nil
 }
@@ -26,10 +26,9 @@ func (c Client) UnsuspendUser(userID int) error {
 func (c Client) ListUsers(query *queries.ListUsers) (responses.ListUsers, error) {
 	url := fmt.Sprintf("%s/passwordvault/api/Users%s", c.BaseURL, httpJson.GetURLQuery(query))
 
-	response, err := httpJson.Get(false, url, c.SessionToken, c.InsecureTLS, c.Logger)
+	response, err := c.GetTransport().Get(c.GetContext(), false, url, c.SessionToken, c.Logger)
 	if err != nil {
-		returnedError, _ := json.Marshal(response)
-		return responses.ListUsers{}, fmt.Errorf("Failed to list users. %s. %s", string(returnedError), err)
+		return responses.ListUsers{}, fmt.Errorf("Failed to list users. %w", err)
 	}
 
 	jsonString, _ := json.Marshal(response)
@@ -38,14 +37,27 @@ func (c Client) ListUsers(query *queries.ListUsers) (responses.ListUsers, error)
 	return ListUsersResponse, err
 }
 
+// IterateUsers returns an iterator over all users matching query, reading every page
+func (c Client) IterateUsers(query *queries.ListUsers) *UserIterator {
+	q := queries.ListUsers{}
+	if query != nil {
+		q = *query
+	}
+	return &UserIterator{
+		pager: newPager(c, "users", "Users", "Total", q.Offset, q.Limit, func(offset int) string {
+			q.Offset = offset
+			return fmt.Sprintf("%s/passwordvault/api/Users%s", c.BaseURL, httpJson.GetURLQuery(&q))
+		}),
+	}
+}
+
 // DeleteUser from PAS
 func (c Client) DeleteUser(userID int) error {
 	url := fmt.Sprintf("%s/passwordvault/api/Users/%d", c.BaseURL, userID)
 
-	response, err := httpJson.Delete(false, url, c.SessionToken, c.InsecureTLS, c.Logger)
+	_, err := c.GetTransport().Delete(c.GetContext(), false, url, c.SessionToken, c.Logger)
 	if err != nil {
-		returnedError, _ := json.Marshal(response)
-		return fmt.Errorf("Failed to delete user '%d'. %s. %s", userID, string(returnedError), err)
+		return fmt.Errorf("Failed to delete user '%d'. %w", userID, err)
 	}
 
 	return nil
@@ -55,10 +67,9 @@ func (c Client) DeleteUser(userID int) error {
 func (c Client) AddUser(user requests.AddUser) (responses.AddUser, error) {
 	url := fmt.Sprintf("%s/passwordvault/api/Users", c.BaseURL)
 
-	response, err := httpJson.Post(false, url, c.SessionToken, user, c.InsecureTLS, c.Logger)
+	response, err := c.GetTransport().Post(c.GetContext(), false, url, c.SessionToken, user, c.Logger)
 	if err != nil {
-		returnedError, _ := json.Marshal(response)
-		return responses.AddUser{}, fmt.Errorf("Failed to add user '%s'. %s. %s", user.Username, string(returnedError), err)
+		return responses.AddUser{}, fmt.Errorf("Failed to add user '%s'. %w", user.Username, err)
 	}
 
 	jsonString, _ := json.Marshal(response)
@@ -66,3 +77,203 @@ func (c Client) AddUser(user requests.AddUser) (responses.AddUser, error) {
 	err = json.Unmarshal(jsonString, &addUserResponse)
 	return addUserResponse, err
 }
+
+// GetUser details for a specific user
+func (c Client) GetUser(userID int) (responses.GetUser, error) {
+	url := fmt.Sprintf("%s/passwordvault/api/Users/%d", c.BaseURL, userID)
+
+	response, err := c.GetTransport().Get(c.GetContext(), false, url, c.SessionToken, c.Logger)
+	if err != nil {
+		return responses.GetUser{}, fmt.Errorf("Failed to get user with id '%d'. %w", userID, err)
+	}
+
+	jsonString, _ := json.Marshal(response)
+	getUserResponse := responses.GetUser{}
+	err = json.Unmarshal(jsonString, &getUserResponse)
+	return getUserResponse, err
+}
+
+// GetLoggedOnUser returns the details of the user logged on
+func (c Client) GetLoggedOnUser() (responses.GetUser, error) {
+	url := fmt.Sprintf("%s/passwordvault/api/LoggedOnUser", c.BaseURL)
+
+	response, err := c.GetTransport().Get(c.GetContext(), false, url, c.SessionToken, c.Logger)
+	if err != nil {
+		return responses.GetUser{}, fmt.Errorf("Failed to get the logged on user. %w", err)
+	}
+
+	jsonString, _ := json.Marshal(response)
+	getUserResponse := responses.GetUser{}
+	err = json.Unmarshal(jsonString, &getUserResponse)
+	return getUserResponse, err
+}
+
+// FindUser returns the user with the username given
+func (c Client) FindUser(username string) (responses.UserResponse, error) {
+	users := c.IterateUsers(&queries.ListUsers{Search: username})
+	for users.Next() {
+		if strings.EqualFold(users.User().Username, username) {
+			return users.User(), nil
+		}
+	}
+	if users.Err() != nil {
+		return responses.UserResponse{}, users.Err()
+	}
+	return responses.UserResponse{}, fmt.Errorf("User '%s' does not exist", username)
+}
+
+// UpdateUser replaces the properties of a user
+func (c Client) UpdateUser(userID int, user requests.UpdateUser) (responses.GetUser, error) {
+	url := fmt.Sprintf("%s/passwordvault/api/Users/%d", c.BaseURL, userID)
+
+	response, err := c.GetTransport().Put(c.GetContext(), false, url, c.SessionToken, user, c.Logger)
+	if err != nil {
+		return responses.GetUser{}, fmt.Errorf("Failed to update user with id '%d'. %w", userID, err)
+	}
+
+	jsonString, _ := json.Marshal(response)
+	updateUserResponse := responses.GetUser{}
+	err = json.Unmarshal(jsonString, &updateUserResponse)
+	return updateUserResponse, err
+}
+
+// ModifyUser updates the properties of a user changed by modify. modify is called with the
+// current properties of the user
+func (c Client) ModifyUser(userID int, modify func(user *requests.UpdateUser) error) (responses.GetUser, error) {
+	current, err := c.GetUser(userID)
+	if err != nil {
+		return responses.GetUser{}, err
+	}
+
+	// The addresses, phones and personal details are objects of strings, read into maps of the request
+	user := requests.UpdateUser{}
+	jsonString, _ := json.Marshal(current)
+	err = json.Unmarshal(jsonString, &user)
+	if err != nil {
+		return responses.GetUser{}, fmt.Errorf("Failed to read user with id '%d'. %s", userID, err)
+	}
+
+	err = modify(&user)
+	if err != nil {
+		return responses.GetUser{}, err
+	}
+	return c.UpdateUser(userID, user)
+}
+
+// ActivateUser enables a disabled user. Use UnsuspendUser to activate a suspended user
+func (c Client) ActivateUser(userID int) error {
+	url := fmt.Sprintf("%s/passwordvault/api/Users/%d/enable", c.BaseURL, userID)
+
+	_, err := c.GetTransport().Post(c.GetContext(), false, url, c.SessionToken, emptyBody, c.Logger)
+	if err != nil {
+		return fmt.Errorf("Failed to activate user with id '%d'. %w", userID, err)
+	}
+	return nil
+}
+
+// ResetUserPassword sets a new vault password for a user
+func (c Client) ResetUserPassword(userID int, newPassword string) error {
+	url := fmt.Sprintf("%s/passwordvault/api/Users/%d/ResetPassword", c.BaseURL, userID)
+	logger := c.GetLogger().AddSecret(newPassword)
+	_, err := c.GetTransport().Post(c.GetContext(), false, url, c.SessionToken, requests.ResetUserPassword{ID: userID, NewPassword: newPassword}, logger)
+	logger = logger.ClearSecrets()
+
+	if err != nil {
+		return fmt.Errorf("Failed to reset password of user with id '%d'. %w", userID, err)
+	}
+	return nil
+}
+
+// EnableUserAuthenticationMethod allows a user to authenticate with an authentication method.
+// Valid methods are AuthTypePass, AuthTypeLDAP and AuthTypeRADIUS, or pass, ldap and radius
+func (c Client) EnableUserAuthenticationMethod(userID int, method string) (responses.GetUser, error) {
+	method, err := getAuthenticationMethod(method)
+	if err != nil {
+		return responses.GetUser{}, err
+	}
+
+	return c.ModifyUser(userID, func(user *requests.UpdateUser) error {
+		for _, enabled := range user.AuthenticationMethod {
+			if strings.EqualFold(enabled, method) {
+				return nil
+			}
+		}
+		user.AuthenticationMethod = append(user.AuthenticationMethod, method)
+		return nil
+	})
+}
+
+// DisableUserAuthenticationMethod stops a user from authenticating with an authentication method.
+// The last authentication method of a user cannot be disabled
+func (c Client) DisableUserAuthenticationMethod(userID int, method string) (responses.GetUser, error) {
+	method, err := getAuthenticationMethod(method)
+	if err != nil {
+		return responses.GetUser{}, err
+	}
+
+	return c.ModifyUser(userID, func(user *requests.UpdateUser) error {
+		methods := []string{}
+		for _, enabled := range user.AuthenticationMethod {
+			if !strings.EqualFold(enabled, method) {
+				methods = append(methods, enabled)
+			}
+		}
+		if len(methods) == 0 {
+			return fmt.Errorf("Authentication method '%s' is the only authentication method of user '%s' and cannot be disabled", method, user.Username)
+		}
+		user.AuthenticationMethod = methods
+		return nil
+	})
+}
+
+// SetUserAuthorizations replaces the vault authorizations of a user
+func (c Client) SetUserAuthorizations(userID int, authorizations []string) (responses.GetUser, error) {
+	valid := []string{}
+	for _, authorization := range authorizations {
+		found, err := getVaultAuthorization(authorization)
+		if err != nil {
+			return responses.GetUser{}, err
+		}
+		valid = append(valid, found)
+	}
+
+	return c.ModifyUser(userID, func(user *requests.UpdateUser) error {
+		user.VaultAuthorization = valid
+		return nil
+	})
+}
+
+// AuthenticationMethods are the authentication methods of vault users
+var AuthenticationMethods = []string{"AuthTypePass", "AuthTypeLDAP", "AuthTypeRADIUS"}
+
+// VaultAuthorizations are the vault authorizations that can be given to users
+var VaultAuthorizations = []string{
+	"AddUpdateUsers",
+	"AddSafes",
+	"AddNetworkAreas",
+	"ManageDirectoryMapping",
+	"ManageServerFileCategories",
+	"AuditUsers",
+	"BackupAllSafes",
+	"RestoreAllSafes",
+	"ResetUsersPasswords",
+	"ActivateUsers",
+}
+
+func getAuthenticationMethod(method string) (string, error) {
+	for _, valid := range AuthenticationMethods {
+		if strings.EqualFold(method, valid) || strings.EqualFold("AuthType"+method, valid) {
+			return valid, nil
+		}
+	}
+	return "", fmt.Errorf("Invalid authentication method '%s'. Valid values: %s", method, strings.Join(AuthenticationMethods, ", "))
+}
+
+func getVaultAuthorization(authorization string) (string, error) {
+	for _, valid := range VaultAuthorizations {
+		if strings.EqualFold(authorization, valid) {
+			return valid, nil
+		}
+	}
+	return "", fmt.Errorf("Invalid vault authorization '%s'. Valid values: %s", authorization, strings.Join(VaultAuthorizations, ", "))
+}
@@ -1,8 +1,14 @@
 package api_test
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	pasapi "github.com/infamousjoeg/cybr-cli/pkg/cybr/api"
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/queries"
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/requests"
 )
@@ -88,3 +94,141 @@ func TestAddRemoveUser(t *testing.T) {
 		t.Errorf("Failed to delete user '%d'. %s", addedUser.ID, err)
 	}
 }
+
+// newUsersServer returns a PAS stand-in with the user svc_app1 with id 9, who authenticates with
+// a password. The requests updating the user are added to updated
+func newUsersServer(t *testing.T, updated *[]requests.UpdateUser, requested *[]string) *httptest.Server {
+	userAction := func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&body)
+		*requested = append(*requested, strings.TrimSpace(r.Method+" "+r.URL.Path+" "+fmt.Sprint(body["newPassword"])))
+		w.WriteHeader(http.StatusOK)
+	}
+
+	return newPASServer(nil,
+		route{http.MethodGet, "/passwordvault/api/Users", respond(`{"Users":[{"id":8,"username":"svc_app10"},{"id":9,"username":"svc_app1"}],"Total":2}`)},
+		route{http.MethodGet, "/passwordvault/api/Users/9", respond(`{"id":9,"username":"svc_app1","userType":"EPVUser","location":"\\Applications","enableUser":true,"expiryDate":1735689600,
+			"authenticationMethod":["AuthTypePass"],"vaultAuthorization":["AddSafes"],"personalDetails":{"firstName":"App","lastName":"One"}}`)},
+		route{http.MethodPut, "/passwordvault/api/Users/9", func(w http.ResponseWriter, r *http.Request) {
+			user := requests.UpdateUser{}
+			json.NewDecoder(r.Body).Decode(&user)
+			*updated = append(*updated, user)
+			json.NewEncoder(w).Encode(user)
+		}},
+		route{"", "/passwordvault/api/Users/9/ResetPassword", userAction},
+		route{"", "/passwordvault/api/Users/9/enable", userAction},
+	)
+}
+
+func TestFindUser(t *testing.T) {
+	server := newUsersServer(t, &[]requests.UpdateUser{}, &[]string{})
+	defer server.Close()
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	user, err := client.FindUser("SVC_APP1")
+	if err != nil {
+		t.Fatalf("Failed to find user. %s", err)
+	}
+	if user.ID != 9 {
+		t.Errorf("Expected the user with the exact username. %+v", user)
+	}
+
+	_, err = client.FindUser("svc_app")
+	if err == nil {
+		t.Errorf("Expected an unknown user to fail")
+	}
+}
+
+func TestUserAuthenticationMethods(t *testing.T) {
+	updated := []requests.UpdateUser{}
+	server := newUsersServer(t, &updated, &[]string{})
+	defer server.Close()
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	user, err := client.EnableUserAuthenticationMethod(9, "radius")
+	if err != nil {
+		t.Fatalf("Failed to enable authentication method. %s", err)
+	}
+	if strings.Join(user.AuthenticationMethod, ",") != "AuthTypePass,AuthTypeRADIUS" {
+		t.Errorf("Expected RADIUS to be added. %v", user.AuthenticationMethod)
+	}
+	if len(updated) != 1 || updated[0].Username != "svc_app1" || updated[0].Location != "\\Applications" || !updated[0].EnableUser ||
+		updated[0].PersonalDetails["lastName"] != "One" || strings.Join(updated[0].VaultAuthorization, ",") != "AddSafes" {
+		t.Errorf("Expected the other properties of the user to be kept. %+v", updated)
+	}
+
+	_, err = client.DisableUserAuthenticationMethod(9, "AuthTypePass")
+	if err == nil {
+		t.Errorf("Expected disabling the only authentication method to fail")
+	}
+	_, err = client.EnableUserAuthenticationMethod(9, "kerberos")
+	if err == nil {
+		t.Errorf("Expected an invalid authentication method to fail")
+	}
+	if len(updated) != 1 {
+		t.Errorf("Expected the user not to be updated. %+v", updated)
+	}
+}
+
+func TestModifyUserRemovesExpiryDate(t *testing.T) {
+	updated := []requests.UpdateUser{}
+	server := newUsersServer(t, &updated, &[]string{})
+	defer server.Close()
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	_, err := client.ModifyUser(9, func(user *requests.UpdateUser) error {
+		if user.ExpiryDate == nil || *user.ExpiryDate != 1735689600 {
+			t.Errorf("Expected the current expiry date. %v", user.ExpiryDate)
+		}
+		never := 0
+		user.ExpiryDate = &never
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to modify user. %s", err)
+	}
+	if len(updated) != 1 || updated[0].ExpiryDate == nil || *updated[0].ExpiryDate != 0 {
+		t.Errorf("Expected the expiry date to be sent as 0. %+v", updated)
+	}
+}
+
+func TestSetUserAuthorizations(t *testing.T) {
+	updated := []requests.UpdateUser{}
+	server := newUsersServer(t, &updated, &[]string{})
+	defer server.Close()
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	user, err := client.SetUserAuthorizations(9, []string{"auditusers", "ManageServerFileCategories"})
+	if err != nil {
+		t.Fatalf("Failed to set authorizations. %s", err)
+	}
+	if strings.Join(user.VaultAuthorization, ",") != "AuditUsers,ManageServerFileCategories" {
+		t.Errorf("Expected the authorizations to be replaced. %v", user.VaultAuthorization)
+	}
+
+	_, err = client.SetUserAuthorizations(9, []string{"AuditUsers", "Superuser"})
+	if err == nil || len(updated) != 1 {
+		t.Errorf("Expected an invalid authorization to fail without updating the user")
+	}
+}
+
+func TestResetUserPassword(t *testing.T) {
+	requested := []string{}
+	server := newUsersServer(t, &[]requests.UpdateUser{}, &requested)
+	defer server.Close()
+
+	client := pasapi.Client{BaseURL: server.URL, AuthType: "cyberark"}
+	err := client.ResetUserPassword(9, "N3wP@ssword")
+	if err != nil {
+		t.Errorf("Failed to reset password. %s", err)
+	}
+	err = client.ActivateUser(9)
+	if err != nil {
+		t.Errorf("Failed to activate user. %s", err)
+	}
+
+	expected := []string{"POST /passwordvault/api/Users/9/ResetPassword N3wP@ssword", "POST /passwordvault/api/Users/9/enable <nil>"}
+	if strings.Join(requested, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v but got %v", expected, requested)
+	}
+}
@@ -2,11 +2,13 @@ package ccp
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 
 	httpJson "github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/httpjson"
 )
@@ -18,6 +20,10 @@ type RetrieveAccountRequest struct {
 	ClientCert      string
 	ClientKey       string
 	Query           *RetrieveAccountQuery
+	// Context used to cancel the request, defaults to context.Background()
+	Context context.Context
+	// Timeout of the request, defaults to httpjson.DefaultTimeout
+	Timeout time.Duration
 }
 
 // RetrieveAccountQuery represents valid query parameters when listing accounts
@@ -45,7 +51,7 @@ func RetrieveAccount(request RetrieveAccountRequest) (map[string]string, error)
 		return map[string]string{}, err
 	}
 
-	return sendHTTPRequest(url, request.IgnoreSSLVerify, useClientCert, cert)
+	return sendHTTPRequest(request.Context, request.Timeout, url, request.IgnoreSSLVerify, useClientCert, cert)
 }
 
 func ccpURL(url string, query string) string {
@@ -66,7 +72,7 @@ func clientCertificate(clientCert string, clientKey string) (tls.Certificate, bo
 	return cert, true, nil
 }
 
-func sendHTTPRequest(url string, insecureSkipVerify bool, useClientCert bool, cert tls.Certificate) (map[string]string, error) {
+func sendHTTPRequest(ctx context.Context, timeout time.Duration, url string, insecureSkipVerify bool, useClientCert bool, cert tls.Certificate) (map[string]string, error) {
 	tlsConfig := &tls.Config{
 		Renegotiation:      tls.RenegotiateOnceAsClient,
 		InsecureSkipVerify: insecureSkipVerify,
@@ -77,15 +83,21 @@ func sendHTTPRequest(url string, insecureSkipVerify bool, useClientCert bool, ce
 		tlsConfig.BuildNameToCertificate()
 	}
 
-	tr := &http.Transport{
-		TLSClientConfig: tlsConfig,
+	transport := httpJson.NewTransportWithTLSConfig(tlsConfig)
+	if timeout > 0 {
+		transport.Timeout = timeout
 	}
 
-	client := &http.Client{Transport: tr}
-	resp, err := client.Get(url)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create request to url '%s'. %s", url, err)
+	}
+
+	resp, err := transport.Do(ctx, req, nil)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to send request to url '%s'. %s", url, err)
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		// if we fail to read body when recieveing an invalid status code, ignore.
@@ -4,52 +4,57 @@ import (
 	"encoding/gob"
 	"fmt"
 	"os"
+	"path/filepath"
 
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/credstore"
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/util"
 )
 
-// SaveToken saving token as file on the local filesystem
+// tokenKey is the credential store key of the token saved at tokenPath
+func tokenKey(tokenPath string) string {
+	return "cem/" + filepath.Base(tokenPath)
+}
+
+// SaveToken saving token in the credential store
 func SaveToken(token string, tokenPath string) error {
-	// Get user home directory
-	userHome, err := util.GetUserHomeDir()
+	store, err := credstore.GetStore()
 	if err != nil {
-		return fmt.Errorf("ACL error. %s", err)
+		return err
 	}
 
-	// Check if .cybr directory already exists, create if not
-	if _, err = os.Stat(userHome + "/.cybr"); os.IsNotExist(err) {
-		// Create .cybr folder in user home directory
-		err = os.Mkdir(userHome+"/.cybr", 0766)
-		if err != nil {
-			return fmt.Errorf("could not create folder %s/.cybr on local file system. %s", userHome, err)
-		}
+	err = store.Set(tokenKey(tokenPath), token)
+	if err != nil {
+		return fmt.Errorf("could not save token in the %s credential store. %s", store.Name(), err)
 	}
 
-	// Check for config file and remove if existing
-	if _, err = os.Stat(userHome + tokenPath); !os.IsNotExist(err) {
-		err = os.Remove(userHome + tokenPath)
-		if err != nil {
-			return fmt.Errorf("could not remove existing %s%s file. %s", userHome, tokenPath, err)
-		}
-	}
-	// Create config file in user home directory
-	dataFile, err := os.Create(userHome + tokenPath)
+	return nil
+}
+
+// GetToken from the credential store. A token file written to tokenPath by
+// older versions is migrated to the credential store and removed
+func GetToken(tokenPath string) (string, error) {
+	store, err := credstore.GetStore()
 	if err != nil {
-		return fmt.Errorf("could not create configuration file at %s%s. %s", userHome, tokenPath, err)
+		return "", err
 	}
 
-	// serialize the data
-	dataEncoder := gob.NewEncoder(dataFile)
-	dataEncoder.Encode(token)
+	token, err := migrateTokenFile(store, tokenPath)
+	if err != nil || token != "" {
+		return token, err
+	}
 
-	dataFile.Close()
+	token, err = store.Get(tokenKey(tokenPath))
+	if err == credstore.ErrNotFound {
+		return "", fmt.Errorf("no token found. Run 'cybr cem logon' first")
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve token from the %s credential store. %s", store.Name(), err)
+	}
 
-	return nil
+	return token, nil
 }
 
-// GetToken file from local filesystem and read
-func GetToken(tokenPath string) (string, error) {
-
+func migrateTokenFile(store credstore.Store, tokenPath string) (string, error) {
 	// Get user home directory
 	userHome, err := util.GetUserHomeDir()
 	if err != nil {
@@ -58,6 +63,9 @@ func GetToken(tokenPath string) (string, error) {
 
 	// open data file
 	dataFile, err := os.Open(userHome + tokenPath)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
 	if err != nil {
 		return "", fmt.Errorf("failed to retrieve token file at %s. %s", tokenPath, err)
 	}
@@ -65,11 +73,20 @@ func GetToken(tokenPath string) (string, error) {
 	dataDecoder := gob.NewDecoder(dataFile)
 	result := ""
 	err = dataDecoder.Decode(&result)
+	dataFile.Close()
 	if err != nil {
-		return result, fmt.Errorf("failed to decode token file at .cybr/config. %s", err)
+		return result, fmt.Errorf("failed to decode token file at %s. %s", tokenPath, err)
 	}
 
-	dataFile.Close()
+	err = store.Set(tokenKey(tokenPath), result)
+	if err != nil {
+		return "", fmt.Errorf("could not migrate token file at %s to the %s credential store. %s", tokenPath, store.Name(), err)
+	}
+
+	err = os.Remove(userHome + tokenPath)
+	if err != nil {
+		return "", fmt.Errorf("could not remove token file at %s after migrating it. %s", tokenPath, err)
+	}
 
 	return result, nil
 }
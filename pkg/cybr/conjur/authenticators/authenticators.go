@@ -4,31 +4,70 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/conjur/authenticators/azure"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/conjur/authenticators/gcp"
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/conjur/authenticators/iam"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/conjur/authenticators/jwt"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/conjur/authenticators/k8s"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/conjur/authenticators/oidc"
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/authenticators"
 )
 
-func getAuthenticators(config authenticators.Config) ([]authenticators.Authenticator, error) {
-	authenticators := []authenticators.Authenticator{}
-	iamInterface, err := iam.New()
-	if err != nil {
-		return nil, fmt.Errorf("Failed to create IAM authenticator. %s", err)
+// getAuthenticators returns the constructors of the authenticators by name. Only the selected
+// authenticator is created, so the environment of the others does not have to be set
+func getAuthenticators() map[string]func() (authenticators.Authenticator, error) {
+	return map[string]func() (authenticators.Authenticator, error){
+		"authn-iam": func() (authenticators.Authenticator, error) {
+			iamInterface, err := iam.New()
+			if err != nil {
+				return nil, fmt.Errorf("Failed to create IAM authenticator. %s", err)
+			}
+			return iamInterface, nil
+		},
+		"authn-jwt": func() (authenticators.Authenticator, error) {
+			jwtInterface, err := jwt.New()
+			if err != nil {
+				return nil, fmt.Errorf("Failed to create JWT authenticator. %s", err)
+			}
+			return jwtInterface, nil
+		},
+		"authn-k8s": func() (authenticators.Authenticator, error) {
+			k8sInterface, err := k8s.New()
+			if err != nil {
+				return nil, fmt.Errorf("Failed to create Kubernetes authenticator. %s", err)
+			}
+			return k8sInterface, nil
+		},
+		"authn-oidc": func() (authenticators.Authenticator, error) {
+			oidcInterface, err := oidc.New()
+			if err != nil {
+				return nil, fmt.Errorf("Failed to create OIDC authenticator. %s", err)
+			}
+			return oidcInterface, nil
+		},
+		"authn-azure": func() (authenticators.Authenticator, error) {
+			azureInterface, err := azure.New()
+			if err != nil {
+				return nil, fmt.Errorf("Failed to create Azure authenticator. %s", err)
+			}
+			return azureInterface, nil
+		},
+		"authn-gcp": func() (authenticators.Authenticator, error) {
+			gcpInterface, err := gcp.New()
+			if err != nil {
+				return nil, fmt.Errorf("Failed to create GCP authenticator. %s", err)
+			}
+			return gcpInterface, nil
+		},
 	}
-	authenticators = append(authenticators, iamInterface)
-	return authenticators, nil
 }
 
 // GetAuthenticator will return the authenticator client for the given name
 func GetAuthenticator(name string, config authenticators.Config) (authenticators.Authenticator, error) {
-	authenticators, err := getAuthenticators(config)
-	if err != nil {
-		return nil, err
-	}
-	for _, r := range authenticators {
-		if strings.ToLower(name) == r.Name() {
-			return r, nil
-		}
+	newAuthenticator, ok := getAuthenticators()[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("Failed to retrieve authenticator with name '%s'", name)
 	}
 
-	return nil, fmt.Errorf("Failed to retrieve authenticator with name '%s'", name)
+	return newAuthenticator()
 }
@@ -0,0 +1,104 @@
+package azure
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/authenticators"
+)
+
+const (
+	// IMDSURLEnvKey is the environment variable overriding the token endpoint of the Azure Instance Metadata Service
+	IMDSURLEnvKey = "CONJUR_AZURE_IMDS_URL"
+	// ClientIDEnvKey is the environment variable holding the client ID of a user-assigned managed identity
+	ClientIDEnvKey = "CONJUR_AZURE_CLIENT_ID"
+
+	// DefaultIMDSURL is the token endpoint of the Azure Instance Metadata Service
+	DefaultIMDSURL = "http://169.254.169.254/metadata/identity/oauth2/token"
+
+	imdsAPIVersion = "2018-02-01"
+	imdsResource   = "https://management.azure.com/"
+)
+
+// Azure represents the config for the Azure authenticator
+type Azure struct {
+	IMDSURL string
+	// ClientID selects a user-assigned managed identity. The system-assigned identity is used if empty
+	ClientID string
+}
+
+// Name of the authenticator type
+func (r Azure) Name() string {
+	return "authn-azure"
+}
+
+// getManagedIdentityToken returns the access token of the managed identity of the Azure resource
+func (r Azure) getManagedIdentityToken() (string, error) {
+	query := url.Values{
+		"api-version": {imdsAPIVersion},
+		"resource":    {imdsResource},
+	}
+	if r.ClientID != "" {
+		query.Set("client_id", r.ClientID)
+	}
+	tokenURL := r.IMDSURL + "?" + query.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, tokenURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("Failed to create request '%s'. %s", tokenURL, err)
+	}
+	req.Header.Set("Metadata", "true")
+
+	// The metadata service is link-local and must never be reached through a proxy
+	client := &http.Client{Transport: &http.Transport{}, Timeout: time.Second * 10}
+	response, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("Failed to establish connection to Azure Instance Metadata Service at url '%s'. %s", r.IMDSURL, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != 200 {
+		return "", fmt.Errorf("Failed to retrieve managed identity token. Received status code '%v'", response.StatusCode)
+	}
+
+	token := struct {
+		AccessToken string `json:"access_token"`
+	}{}
+	err = json.NewDecoder(response.Body).Decode(&token)
+	if err != nil {
+		return "", fmt.Errorf("Failed to decode managed identity token. %s", err)
+	}
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("Azure Instance Metadata Service did not return an access token")
+	}
+	return token.AccessToken, nil
+}
+
+// Authenticate will retrieve a Conjur access token using authn-azure
+func (r Azure) Authenticate(config authenticators.Config) ([]byte, error) {
+	token, err := r.getManagedIdentityToken()
+	if err != nil {
+		return nil, err
+	}
+
+	authnURL := authenticators.GetAuthURL(config.ApplianceURL, "authn-azure", config.ServiceID)
+	authenticateURL := fmt.Sprintf("%s/%s/%s/authenticate", authnURL, url.PathEscape(config.Account), url.PathEscape(config.Login))
+	return authenticators.PostAuthenticate(config, authenticateURL, url.Values{"jwt": {token}})
+}
+
+// New returns a new Azure object
+func New() (Azure, error) {
+	imdsURL := os.Getenv(IMDSURLEnvKey)
+	if imdsURL == "" {
+		imdsURL = DefaultIMDSURL
+	}
+
+	return Azure{
+		IMDSURL:  imdsURL,
+		ClientID: os.Getenv(ClientIDEnvKey),
+	}, nil
+}
@@ -0,0 +1,66 @@
+package azure_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/conjur/authenticators/azure"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/authenticators"
+)
+
+func TestAuthenticate(t *testing.T) {
+	imds := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.Header.Get("Metadata") != "true" || query.Get("api-version") == "" || query.Get("resource") != "https://management.azure.com/" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if query.Get("client_id") != "" && query.Get("client_id") != "user-assigned" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"access_token":"managed.identity.token","token_type":"Bearer"}`))
+	}))
+	defer imds.Close()
+
+	conjur := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/authn-azure/prod/demo/host%2Fazure%2Fvm/authenticate" || r.FormValue("jwt") != "managed.identity.token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"protected":"p","payload":"p","signature":"s"}`))
+	}))
+	defer conjur.Close()
+
+	config := authenticators.Config{Account: "demo", ApplianceURL: conjur.URL, Login: "host/azure/vm", ServiceID: "prod", IgnoreSSLVerify: true}
+	for _, clientID := range []string{"", "user-assigned"} {
+		token, err := azure.Azure{IMDSURL: imds.URL, ClientID: clientID}.Authenticate(config)
+		if err != nil || string(token) != `{"protected":"p","payload":"p","signature":"s"}` {
+			t.Errorf("Failed to authenticate with client ID '%s'. '%s' %v", clientID, token, err)
+		}
+	}
+
+	_, err := azure.Azure{IMDSURL: imds.URL, ClientID: "unknown"}.Authenticate(config)
+	if err == nil {
+		t.Errorf("Expected an unknown managed identity to fail")
+	}
+}
+
+func TestNewUsesIMDSURL(t *testing.T) {
+	old := os.Getenv(azure.IMDSURLEnvKey)
+	defer os.Setenv(azure.IMDSURLEnvKey, old)
+
+	os.Unsetenv(azure.IMDSURLEnvKey)
+	authenticator, _ := azure.New()
+	if authenticator.IMDSURL != azure.DefaultIMDSURL {
+		t.Errorf("Expected the default IMDS URL. '%s'", authenticator.IMDSURL)
+	}
+
+	os.Setenv(azure.IMDSURLEnvKey, "http://127.0.0.1:8080/token")
+	authenticator, _ = azure.New()
+	if authenticator.IMDSURL != "http://127.0.0.1:8080/token" {
+		t.Errorf("Expected the IMDS URL of the environment variable. '%s'", authenticator.IMDSURL)
+	}
+}
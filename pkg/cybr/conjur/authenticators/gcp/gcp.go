@@ -0,0 +1,91 @@
+package gcp
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/authenticators"
+)
+
+const (
+	// MetadataURLEnvKey is the environment variable overriding the identity endpoint of the Google metadata server
+	MetadataURLEnvKey = "CONJUR_GCP_METADATA_URL"
+
+	// DefaultMetadataURL is the identity endpoint of the default service account on the Google metadata server
+	DefaultMetadataURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/identity"
+)
+
+// GCP represents the config for the Google Cloud authenticator
+type GCP struct {
+	MetadataURL string
+}
+
+// Name of the authenticator type
+func (r GCP) Name() string {
+	return "authn-gcp"
+}
+
+// getIdentityToken returns an identity token of the service account of the instance. Conjur
+// requires the audience to be conjur/{account}/{host ID}
+func (r GCP) getIdentityToken(account string, login string) (string, error) {
+	query := url.Values{
+		"audience": {fmt.Sprintf("conjur/%s/%s", account, login)},
+		"format":   {"full"},
+	}
+	identityURL := r.MetadataURL + "?" + query.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, identityURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("Failed to create request '%s'. %s", identityURL, err)
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	// The metadata server is only reachable from the instance and must never be reached through a proxy
+	client := &http.Client{Transport: &http.Transport{}, Timeout: time.Second * 10}
+	response, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("Failed to establish connection to Google metadata server at url '%s'. %s", r.MetadataURL, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != 200 {
+		return "", fmt.Errorf("Failed to retrieve identity token. Received status code '%v'", response.StatusCode)
+	}
+
+	token, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return "", fmt.Errorf("Failed to read identity token. %s", err)
+	}
+	if len(strings.TrimSpace(string(token))) == 0 {
+		return "", fmt.Errorf("Google metadata server did not return an identity token")
+	}
+	return strings.TrimSpace(string(token)), nil
+}
+
+// Authenticate will retrieve a Conjur access token using authn-gcp
+func (r GCP) Authenticate(config authenticators.Config) ([]byte, error) {
+	token, err := r.getIdentityToken(config.Account, config.Login)
+	if err != nil {
+		return nil, err
+	}
+
+	// authn-gcp has a single instance per Conjur, so it has no service ID
+	authnURL := authenticators.GetAuthURL(config.ApplianceURL, "authn-gcp", "")
+	authenticateURL := fmt.Sprintf("%s/%s/authenticate", authnURL, url.PathEscape(config.Account))
+	return authenticators.PostAuthenticate(config, authenticateURL, url.Values{"jwt": {token}})
+}
+
+// New returns a new GCP object
+func New() (GCP, error) {
+	metadataURL := os.Getenv(MetadataURLEnvKey)
+	if metadataURL == "" {
+		metadataURL = DefaultMetadataURL
+	}
+
+	return GCP{MetadataURL: metadataURL}, nil
+}
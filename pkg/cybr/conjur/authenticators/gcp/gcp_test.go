@@ -0,0 +1,42 @@
+package gcp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/conjur/authenticators/gcp"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/authenticators"
+)
+
+func TestAuthenticate(t *testing.T) {
+	metadata := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" || r.URL.Query().Get("format") != "full" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte("identity-for-" + r.URL.Query().Get("audience")))
+	}))
+	defer metadata.Close()
+
+	conjur := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/authn-gcp/demo/authenticate" || r.FormValue("jwt") != "identity-for-conjur/demo/host/gcp/instance" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"protected":"p","payload":"p","signature":"s"}`))
+	}))
+	defer conjur.Close()
+
+	config := authenticators.Config{Account: "demo", ApplianceURL: conjur.URL, Login: "host/gcp/instance", IgnoreSSLVerify: true}
+	token, err := gcp.GCP{MetadataURL: metadata.URL}.Authenticate(config)
+	if err != nil || string(token) != `{"protected":"p","payload":"p","signature":"s"}` {
+		t.Errorf("Failed to authenticate. '%s' %v", token, err)
+	}
+
+	config.Login = "host/gcp/other"
+	_, err = gcp.GCP{MetadataURL: metadata.URL}.Authenticate(config)
+	if err == nil {
+		t.Errorf("Expected an identity token with a different audience to fail")
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"os"
 	"strings"
 
-	"github.com/cyberark/conjur-api-go/conjurapi"
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/authenticators"
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/authenticators/aws"
 )
@@ -20,8 +19,8 @@ func (r IAM) Name() string {
 	return "authn-iam"
 }
 
-// Authenticate will create a new authn-iam Conjur Client
-func (r IAM) Authenticate(config authenticators.Config) (*conjurapi.Client, error) {
+// Authenticate will retrieve a Conjur access token using authn-iam
+func (r IAM) Authenticate(config authenticators.Config) ([]byte, error) {
 	// Get metadata URLs for the AWS Service
 	resource, err := GetAwsResource(r.AwsServiceType)
 	if err != nil {
@@ -49,19 +48,7 @@ func (r IAM) Authenticate(config authenticators.Config) (*conjurapi.Client, erro
 		return nil, fmt.Errorf("Failed to authenticate to Conjur with service type '%s'. %s", r.AwsServiceType, err)
 	}
 
-	// Create the necessary config for the Conjur Client
-	conjurConfig := conjurapi.Config{
-		Account:      config.Account,
-		ApplianceURL: config.ApplianceURL,
-	}
-
-	// Create the Conjur Client using the Conjur API session token
-	client, err := conjurapi.NewClientFromToken(conjurConfig, string(accessToken))
-	if err != nil {
-		return nil, fmt.Errorf("Failed to create Conjur Client with service type '%s'. %s", r.AwsServiceType, err)
-	}
-
-	return client, nil
+	return accessToken, nil
 }
 
 // New returns a new IAM object
@@ -0,0 +1,88 @@
+package jwt
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/authenticators"
+)
+
+const (
+	// TokenEnvKey is the environment variable holding the JWT
+	TokenEnvKey = "CONJUR_AUTHN_JWT_TOKEN"
+	// TokenPathEnvKey is the environment variable holding the path of a file containing the JWT
+	TokenPathEnvKey = "CONJUR_AUTHN_JWT_TOKEN_PATH"
+	// DefaultTokenPath is the service account token projected into Kubernetes pods
+	DefaultTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+)
+
+// JWT represents the config for the JWT authenticator
+type JWT struct {
+	// Token is the JWT sent to Conjur
+	Token string
+}
+
+// Name of the authenticator type
+func (r JWT) Name() string {
+	return "authn-jwt"
+}
+
+// Identifier of the JWT. The Conjur identity of authn-jwt is usually read from a claim of the
+// JWT, so the access token cached for one JWT must not be reused for another
+func (r JWT) Identifier() string {
+	sum := sha256.Sum256([]byte(r.Token))
+	return hex.EncodeToString(sum[:8])
+}
+
+// getAuthnURL returns the authenticate URL of authn-jwt. The host ID is only part of the URL
+// if it is not read from the JWT
+func getAuthnURL(authnURL string, account string, login string) string {
+	if login == "" {
+		return fmt.Sprintf("%s/%s/authenticate", authnURL, url.PathEscape(account))
+	}
+	return fmt.Sprintf("%s/%s/%s/authenticate", authnURL, url.PathEscape(account), url.PathEscape(login))
+}
+
+// Authenticate will retrieve a Conjur access token using authn-jwt
+func (r JWT) Authenticate(config authenticators.Config) ([]byte, error) {
+	authnURL := authenticators.GetAuthURL(config.ApplianceURL, "authn-jwt", config.ServiceID)
+	return authenticators.PostAuthenticate(config, getAuthnURL(authnURL, config.Account, config.Login), url.Values{"jwt": {r.Token}})
+}
+
+// readToken returns the JWT of the CONJUR_AUTHN_JWT_TOKEN environment variable, or read from the
+// file of CONJUR_AUTHN_JWT_TOKEN_PATH, or the Kubernetes service account token
+func readToken() (string, error) {
+	if token := strings.TrimSpace(os.Getenv(TokenEnvKey)); token != "" {
+		return token, nil
+	}
+
+	path := os.Getenv(TokenPathEnvKey)
+	if path == "" {
+		path = DefaultTokenPath
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("%s or %s environment variable is not set, and failed to read JWT from '%s'. %s", TokenEnvKey, TokenPathEnvKey, path, err)
+	}
+
+	token := strings.TrimSpace(string(content))
+	if token == "" {
+		return "", fmt.Errorf("JWT file '%s' is empty", path)
+	}
+	return token, nil
+}
+
+// New returns a new JWT object
+func New() (JWT, error) {
+	token, err := readToken()
+	if err != nil {
+		return JWT{}, err
+	}
+
+	return JWT{Token: token}, nil
+}
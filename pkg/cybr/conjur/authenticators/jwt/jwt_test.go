@@ -0,0 +1,86 @@
+package jwt_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/conjur/authenticators/jwt"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/authenticators"
+)
+
+func TestNewReadsTokenFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	ioutil.WriteFile(path, []byte("header.payload.signature\n"), 0600)
+
+	oldToken, oldPath := os.Getenv(jwt.TokenEnvKey), os.Getenv(jwt.TokenPathEnvKey)
+	defer os.Setenv(jwt.TokenEnvKey, oldToken)
+	defer os.Setenv(jwt.TokenPathEnvKey, oldPath)
+	os.Unsetenv(jwt.TokenEnvKey)
+	os.Setenv(jwt.TokenPathEnvKey, path)
+
+	authenticator, err := jwt.New()
+	if err != nil || authenticator.Token != "header.payload.signature" {
+		t.Errorf("Expected the JWT of the file. '%s' %v", authenticator.Token, err)
+	}
+
+	os.Setenv(jwt.TokenEnvKey, "other.payload.signature")
+	other, err := jwt.New()
+	if err != nil || other.Token != "other.payload.signature" {
+		t.Errorf("Expected the JWT of the environment variable. '%s' %v", other.Token, err)
+	}
+	if other.Identifier() == authenticator.Identifier() {
+		t.Errorf("Expected different JWTs to have different identifiers")
+	}
+
+	os.Unsetenv(jwt.TokenEnvKey)
+	os.Setenv(jwt.TokenPathEnvKey, filepath.Join(t.TempDir(), "missing"))
+	_, err = jwt.New()
+	if err == nil {
+		t.Errorf("Expected a missing JWT file to fail")
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	requested := []string{}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.EscapedPath())
+		if r.FormValue("jwt") != "header.payload.signature" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"protected":"p","payload":"p","signature":"s"}`))
+	}))
+	defer server.Close()
+
+	config := authenticators.Config{Account: "demo", ApplianceURL: server.URL, ServiceID: "gitlab", IgnoreSSLVerify: true}
+	token, err := jwt.JWT{Token: "header.payload.signature"}.Authenticate(config)
+	if err != nil || string(token) != `{"protected":"p","payload":"p","signature":"s"}` {
+		t.Errorf("Failed to authenticate. '%s' %v", token, err)
+	}
+
+	config.Login = "host/ci/project"
+	_, err = jwt.JWT{Token: "header.payload.signature"}.Authenticate(config)
+	if err != nil {
+		t.Errorf("Failed to authenticate with a host ID. %s", err)
+	}
+
+	_, err = jwt.JWT{Token: "invalid"}.Authenticate(config)
+	if err == nil {
+		t.Errorf("Expected an invalid JWT to fail")
+	}
+
+	expected := []string{
+		"/authn-jwt/gitlab/demo/authenticate",
+		"/authn-jwt/gitlab/demo/host%2Fci%2Fproject/authenticate",
+		"/authn-jwt/gitlab/demo/host%2Fci%2Fproject/authenticate",
+	}
+	for i := range expected {
+		if i >= len(requested) || requested[i] != expected[i] {
+			t.Fatalf("Expected %v but got %v", expected, requested)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package k8s
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/authenticators"
+)
+
+const (
+	// PodNameEnvKey is the environment variable holding the name of the pod, usually set from the downward API
+	PodNameEnvKey = "MY_POD_NAME"
+	// PodNamespaceEnvKey is the environment variable holding the namespace of the pod
+	PodNamespaceEnvKey = "MY_POD_NAMESPACE"
+	// ClientCertPathEnvKey is the environment variable holding the path Conjur injects the client certificate to
+	ClientCertPathEnvKey = "CONJUR_CLIENT_CERT_PATH"
+	// ClientCertTimeoutEnvKey is the environment variable holding how long to wait for the client certificate, e.g. 30s
+	ClientCertTimeoutEnvKey = "CONJUR_CLIENT_CERT_TIMEOUT"
+
+	// DefaultClientCertPath is the path Conjur injects the client certificate to
+	DefaultClientCertPath = "/etc/conjur/ssl/client.pem"
+	// DefaultClientCertTimeout is how long to wait for the client certificate to be injected
+	DefaultClientCertTimeout = 30 * time.Second
+
+	clientCertPollInterval = 100 * time.Millisecond
+)
+
+// K8s represents the config for the Kubernetes authenticator
+type K8s struct {
+	PodName           string
+	PodNamespace      string
+	ClientCertPath    string
+	ClientCertTimeout time.Duration
+}
+
+// Name of the authenticator type
+func (r K8s) Name() string {
+	return "authn-k8s"
+}
+
+// splitLogin returns the Host-Id-Prefix header and the common name of the CSR of a host ID, e.g.
+// host/conjur/authn-k8s/prod/apps/my-app is split into host.conjur.authn-k8s.prod.apps and my-app
+func splitLogin(login string) (string, string, error) {
+	if !strings.HasPrefix(login, "host/") || strings.HasSuffix(login, "/") {
+		return "", "", fmt.Errorf("Invalid host ID '%s'. authn-k8s requires a host ID starting with 'host/'", login)
+	}
+
+	parts := strings.Split(login, "/")
+	return strings.Join(parts[:len(parts)-1], "."), parts[len(parts)-1], nil
+}
+
+// generateCSR returns a PEM encoded certificate signing request for the host ID with the SPIFFE ID
+// of the pod as subject alternative name
+func (r K8s) generateCSR(key *rsa.PrivateKey, commonName string) ([]byte, error) {
+	spiffeID := &url.URL{
+		Scheme: "spiffe",
+		Host:   "cluster.local",
+		Path:   fmt.Sprintf("/namespace/%s/pod/%s", r.PodNamespace, r.PodName),
+	}
+
+	template := &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: commonName},
+		URIs:    []*url.URL{spiffeID},
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create certificate signing request. %s", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr}), nil
+}
+
+// injectClientCert sends the CSR to Conjur, which signs it and copies the client certificate into the pod
+func injectClientCert(client *http.Client, authnURL string, hostIDPrefix string, csr []byte) error {
+	url := fmt.Sprintf("%s/inject_client_cert", authnURL)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(csr))
+	if err != nil {
+		return fmt.Errorf("Failed to create request '%s'. %s", url, err)
+	}
+	req.Header.Set("Content-Type", "text/plain")
+	req.Header.Set("Host-Id-Prefix", hostIDPrefix)
+
+	response, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("Failed to establish connection to Conjur at url '%s'. %s", url, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("Failed to inject client certificate. Received status code '%v'", response.StatusCode)
+	}
+	return nil
+}
+
+// waitForClientCert returns the client certificate once Conjur has injected it. The file may be
+// read while it is being written, so it is read until it contains a certificate
+func (r K8s) waitForClientCert() ([]byte, error) {
+	deadline := time.Now().Add(r.ClientCertTimeout)
+	for {
+		content, err := ioutil.ReadFile(r.ClientCertPath)
+		if err == nil {
+			if block, _ := pem.Decode(content); block != nil && block.Type == "CERTIFICATE" {
+				if _, err = x509.ParseCertificate(block.Bytes); err == nil {
+					return content, nil
+				}
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("Client certificate was not injected to '%s' within %s", r.ClientCertPath, r.ClientCertTimeout)
+		}
+		time.Sleep(clientCertPollInterval)
+	}
+}
+
+// Authenticate will retrieve a Conjur access token using authn-k8s. A client certificate is
+// requested for the pod and used to authenticate over mutual TLS
+func (r K8s) Authenticate(config authenticators.Config) ([]byte, error) {
+	hostIDPrefix, commonName, err := splitLogin(config.Login)
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := authenticators.ReadCertFile(config.CertFile)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := authenticators.NewTLSConfig(config.IgnoreSSLVerify, cert)
+	if err != nil {
+		return nil, err
+	}
+	transport := &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: tlsConfig}
+	client := &http.Client{Transport: transport, Timeout: time.Second * 10}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to generate private key. %s", err)
+	}
+	csr, err := r.generateCSR(key, commonName)
+	if err != nil {
+		return nil, err
+	}
+
+	// A certificate left from a previous login must not be mistaken for the new one
+	err = os.Remove(r.ClientCertPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("Failed to remove previous client certificate '%s'. %s", r.ClientCertPath, err)
+	}
+
+	authnURL := authenticators.GetAuthURL(config.ApplianceURL, "authn-k8s", config.ServiceID)
+	err = injectClientCert(client, authnURL, hostIDPrefix, csr)
+	if err != nil {
+		return nil, err
+	}
+
+	clientCert, err := r.waitForClientCert()
+	if err != nil {
+		return nil, err
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	certificate, err := tls.X509KeyPair(clientCert, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load client certificate '%s'. %s", r.ClientCertPath, err)
+	}
+
+	// Authenticate over mutual TLS with the injected client certificate
+	mutualTLSConfig := tlsConfig.Clone()
+	mutualTLSConfig.Certificates = []tls.Certificate{certificate}
+	client = &http.Client{
+		Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: mutualTLSConfig},
+		Timeout:   time.Second * 10,
+	}
+
+	url := fmt.Sprintf("%s/%s/%s/authenticate", authnURL, url.PathEscape(config.Account), url.PathEscape(config.Login))
+	response, err := client.Post(url, "text/plain", nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to establish connection to Conjur at url '%s'. %s", url, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != 200 {
+		return nil, fmt.Errorf("Failed to authenticate to Conjur with authn-k8s. Received status code '%v'", response.StatusCode)
+	}
+
+	accessToken, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read Conjur Access Token %s", err)
+	}
+	if strings.EqualFold(response.Header.Get("Content-Encoding"), "base64") {
+		accessToken, err = base64.StdEncoding.DecodeString(string(accessToken))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to decode Conjur Access Token %s", err)
+		}
+	}
+
+	return accessToken, nil
+}
+
+// New returns a new K8s object
+func New() (K8s, error) {
+	podName := os.Getenv(PodNameEnvKey)
+	podNamespace := os.Getenv(PodNamespaceEnvKey)
+	if podName == "" || podNamespace == "" {
+		return K8s{}, fmt.Errorf("%s and %s environment variables must be set to the name and namespace of the pod", PodNameEnvKey, PodNamespaceEnvKey)
+	}
+
+	clientCertPath := os.Getenv(ClientCertPathEnvKey)
+	if clientCertPath == "" {
+		clientCertPath = DefaultClientCertPath
+	}
+
+	clientCertTimeout := DefaultClientCertTimeout
+	if timeout := os.Getenv(ClientCertTimeoutEnvKey); timeout != "" {
+		var err error
+		clientCertTimeout, err = time.ParseDuration(timeout)
+		if err != nil {
+			return K8s{}, fmt.Errorf("Invalid %s '%s'. %s", ClientCertTimeoutEnvKey, timeout, err)
+		}
+	}
+
+	return K8s{
+		PodName:           podName,
+		PodNamespace:      podNamespace,
+		ClientCertPath:    clientCertPath,
+		ClientCertTimeout: clientCertTimeout,
+	}, nil
+}
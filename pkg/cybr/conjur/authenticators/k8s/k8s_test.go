@@ -0,0 +1,165 @@
+package k8s
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/authenticators"
+)
+
+// newCA returns a self-signed certificate authority signing the client certificates
+func newCA(t *testing.T) (*x509.Certificate, *rsa.PrivateKey) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate CA key. %s", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "conjur-authn-k8s-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create CA certificate. %s", err)
+	}
+	ca, _ := x509.ParseCertificate(der)
+	return ca, key
+}
+
+// newConjurServer returns a stand-in of the authn-k8s endpoints of Conjur. inject_client_cert signs
+// the CSR and writes the client certificate to clientCertPath, as Conjur does with kubectl exec, and
+// authenticate requires that client certificate
+func newConjurServer(t *testing.T, clientCertPath string) *httptest.Server {
+	ca, caKey := newCA(t)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/authn-k8s/prod/inject_client_cert":
+			if r.Header.Get("Host-Id-Prefix") != "host.conjur.authn-k8s.prod.apps" {
+				t.Errorf("Invalid Host-Id-Prefix '%s'", r.Header.Get("Host-Id-Prefix"))
+			}
+			body, _ := ioutil.ReadAll(r.Body)
+			block, _ := pem.Decode(body)
+			if block == nil || block.Type != "CERTIFICATE REQUEST" {
+				t.Fatalf("Expected a PEM encoded CSR. '%s'", body)
+			}
+			csr, err := x509.ParseCertificateRequest(block.Bytes)
+			if err != nil || csr.CheckSignature() != nil {
+				t.Fatalf("Invalid CSR. %v", err)
+			}
+			if csr.Subject.CommonName != "my-app" || len(csr.URIs) != 1 || csr.URIs[0].String() != "spiffe://cluster.local/namespace/apps/pod/my-app-6d8f9" {
+				t.Errorf("Invalid CSR subject '%s' or SAN %v", csr.Subject.CommonName, csr.URIs)
+			}
+
+			template := &x509.Certificate{
+				SerialNumber: big.NewInt(2),
+				Subject:      csr.Subject,
+				URIs:         csr.URIs,
+				NotBefore:    time.Now().Add(-time.Hour),
+				NotAfter:     time.Now().Add(time.Hour),
+				KeyUsage:     x509.KeyUsageDigitalSignature,
+				ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+			}
+			der, err := x509.CreateCertificate(rand.Reader, template, ca, csr.PublicKey, caKey)
+			if err != nil {
+				t.Fatalf("Failed to sign CSR. %s", err)
+			}
+			ioutil.WriteFile(clientCertPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+			w.WriteHeader(http.StatusAccepted)
+		case "/authn-k8s/prod/demo/host%2Fconjur%2Fauthn-k8s%2Fprod%2Fapps%2Fmy-app/authenticate":
+			if len(r.TLS.PeerCertificates) == 0 {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, err := r.TLS.PeerCertificates[0].Verify(x509.VerifyOptions{Roots: pool, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}})
+			if err != nil || r.TLS.PeerCertificates[0].Subject.CommonName != "my-app" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"protected":"p","payload":"p","signature":"s"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequestClientCert}
+	server.StartTLS()
+	return server
+}
+
+func TestSplitLogin(t *testing.T) {
+	prefix, commonName, err := splitLogin("host/conjur/authn-k8s/prod/apps/my-app")
+	if err != nil || prefix != "host.conjur.authn-k8s.prod.apps" || commonName != "my-app" {
+		t.Errorf("Invalid host ID split '%s' '%s'. %v", prefix, commonName, err)
+	}
+
+	prefix, commonName, err = splitLogin("host/my-app")
+	if err != nil || prefix != "host" || commonName != "my-app" {
+		t.Errorf("Invalid host ID split '%s' '%s'. %v", prefix, commonName, err)
+	}
+
+	for _, login := range []string{"admin", "user/admin", "host/apps/"} {
+		_, _, err = splitLogin(login)
+		if err == nil {
+			t.Errorf("Expected '%s' to be rejected", login)
+		}
+	}
+}
+
+func TestAuthenticateWithInjectedClientCert(t *testing.T) {
+	dir := t.TempDir()
+	clientCertPath := filepath.Join(dir, "client.pem")
+	server := newConjurServer(t, clientCertPath)
+	defer server.Close()
+
+	// A certificate left from a previous login is replaced
+	ioutil.WriteFile(clientCertPath, []byte("stale"), 0600)
+
+	serverCertPath := filepath.Join(dir, "conjur.pem")
+	ioutil.WriteFile(serverCertPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600)
+
+	authenticator := K8s{
+		PodName:           "my-app-6d8f9",
+		PodNamespace:      "apps",
+		ClientCertPath:    clientCertPath,
+		ClientCertTimeout: 5 * time.Second,
+	}
+	config := authenticators.Config{
+		Account:      "demo",
+		ApplianceURL: server.URL,
+		Login:        "host/conjur/authn-k8s/prod/apps/my-app",
+		ServiceID:    "prod",
+		CertFile:     serverCertPath,
+	}
+
+	token, err := authenticator.Authenticate(config)
+	if err != nil {
+		t.Fatalf("Failed to authenticate. %s", err)
+	}
+	if string(token) != `{"protected":"p","payload":"p","signature":"s"}` {
+		t.Errorf("Invalid access token '%s'", token)
+	}
+
+	config.ServiceID = "dev"
+	authenticator.ClientCertTimeout = 200 * time.Millisecond
+	_, err = authenticator.Authenticate(config)
+	if err == nil {
+		t.Errorf("Expected an unknown authenticator to fail")
+	}
+}
@@ -0,0 +1,235 @@
+package oidc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/authenticators"
+)
+
+const (
+	// IDTokenEnvKey is the environment variable holding the ID token
+	IDTokenEnvKey = "CONJUR_AUTHN_OIDC_ID_TOKEN"
+	// IDTokenPathEnvKey is the environment variable holding the path of a file containing the ID token
+	IDTokenPathEnvKey = "CONJUR_AUTHN_OIDC_ID_TOKEN_PATH"
+	// ProviderURIEnvKey is the environment variable holding the issuer of the identity provider used for the device code flow
+	ProviderURIEnvKey = "CONJUR_AUTHN_OIDC_PROVIDER_URI"
+	// ClientIDEnvKey is the environment variable holding the client ID used for the device code flow
+	ClientIDEnvKey = "CONJUR_AUTHN_OIDC_CLIENT_ID"
+	// ScopeEnvKey is the environment variable holding the scopes requested by the device code flow
+	ScopeEnvKey = "CONJUR_AUTHN_OIDC_SCOPE"
+
+	// DefaultScope is requested by the device code flow if no scope is set
+	DefaultScope = "openid"
+
+	deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+)
+
+// pollIntervalUnit is the unit of the polling interval returned by the identity provider
+var pollIntervalUnit = time.Second
+
+// OIDC represents the config for the OIDC authenticator. The ID token is either given, or
+// requested from the identity provider with the device code flow
+type OIDC struct {
+	IDToken     string
+	ProviderURI string
+	ClientID    string
+	Scope       string
+	// Prompt receives the instructions of the device code flow
+	Prompt io.Writer
+}
+
+// providerConfiguration is the part of the OpenID configuration of the identity provider used by the device code flow
+type providerConfiguration struct {
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+	TokenEndpoint               string `json:"token_endpoint"`
+}
+
+// deviceAuthorization is the response of the device authorization endpoint
+type deviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// tokenResponse is the response of the token endpoint
+type tokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Name of the authenticator type
+func (r OIDC) Name() string {
+	return "authn-oidc"
+}
+
+// Identifier of the ID token, or of the client of the device code flow. The Conjur identity of
+// authn-oidc is read from a claim of the ID token
+func (r OIDC) Identifier() string {
+	if r.IDToken == "" {
+		return "device/" + r.ClientID
+	}
+	sum := sha256.Sum256([]byte(r.IDToken))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Authenticate will retrieve a Conjur access token using authn-oidc
+func (r OIDC) Authenticate(config authenticators.Config) ([]byte, error) {
+	idToken := r.IDToken
+	if idToken == "" {
+		var err error
+		idToken, err = r.deviceCodeFlow()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	authnURL := authenticators.GetAuthURL(config.ApplianceURL, "authn-oidc", config.ServiceID)
+	authenticateURL := fmt.Sprintf("%s/%s/authenticate", authnURL, url.PathEscape(config.Account))
+	return authenticators.PostAuthenticate(config, authenticateURL, url.Values{"id_token": {idToken}})
+}
+
+// decodeResponse decodes the JSON response of a request to the identity provider into v. Error
+// responses of the token endpoint are JSON too, so they are decoded if errorStatus is true
+func decodeResponse(response *http.Response, err error, v interface{}, errorStatus bool) error {
+	if err != nil {
+		return fmt.Errorf("Failed to send request to the identity provider. %s", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != 200 && !(errorStatus && response.StatusCode == 400) {
+		return fmt.Errorf("Identity provider returned status code '%d' for '%s'", response.StatusCode, response.Request.URL)
+	}
+	err = json.NewDecoder(response.Body).Decode(v)
+	if err != nil {
+		return fmt.Errorf("Failed to decode response of the identity provider. %s", err)
+	}
+	return nil
+}
+
+// deviceCodeFlow returns an ID token for the user completing the device authorization in a browser
+func (r OIDC) deviceCodeFlow() (string, error) {
+	client := &http.Client{Timeout: time.Second * 10}
+
+	provider := providerConfiguration{}
+	discoveryURL := strings.TrimSuffix(r.ProviderURI, "/") + "/.well-known/openid-configuration"
+	response, err := client.Get(discoveryURL)
+	err = decodeResponse(response, err, &provider, false)
+	if err != nil {
+		return "", err
+	}
+	if provider.DeviceAuthorizationEndpoint == "" || provider.TokenEndpoint == "" {
+		return "", fmt.Errorf("Identity provider '%s' does not support the device code flow", r.ProviderURI)
+	}
+
+	authorization := deviceAuthorization{}
+	response, err = client.PostForm(provider.DeviceAuthorizationEndpoint, url.Values{"client_id": {r.ClientID}, "scope": {r.Scope}})
+	err = decodeResponse(response, err, &authorization, false)
+	if err != nil {
+		return "", err
+	}
+
+	if authorization.VerificationURIComplete != "" {
+		fmt.Fprintf(r.Prompt, "To authenticate to Conjur, open %s and confirm the code %s\n", authorization.VerificationURIComplete, authorization.UserCode)
+	} else {
+		fmt.Fprintf(r.Prompt, "To authenticate to Conjur, open %s and enter the code %s\n", authorization.VerificationURI, authorization.UserCode)
+	}
+
+	interval := time.Duration(authorization.Interval) * pollIntervalUnit
+	if authorization.Interval == 0 {
+		interval = 5 * pollIntervalUnit
+	}
+	deadline := time.Now().Add(time.Duration(authorization.ExpiresIn) * pollIntervalUnit)
+
+	for {
+		time.Sleep(interval)
+
+		token := tokenResponse{}
+		form := url.Values{"grant_type": {deviceCodeGrantType}, "device_code": {authorization.DeviceCode}, "client_id": {r.ClientID}}
+		response, err = client.PostForm(provider.TokenEndpoint, form)
+		err = decodeResponse(response, err, &token, true)
+		if err != nil {
+			return "", err
+		}
+
+		switch token.Error {
+		case "":
+			if token.IDToken == "" {
+				return "", fmt.Errorf("Identity provider did not return an ID token. Make sure the scope includes 'openid'")
+			}
+			return token.IDToken, nil
+		case "authorization_pending":
+		case "slow_down":
+			interval += 5 * pollIntervalUnit
+		default:
+			return "", fmt.Errorf("Device authorization failed. %s %s", token.Error, token.ErrorDescription)
+		}
+
+		if authorization.ExpiresIn != 0 && time.Now().After(deadline) {
+			return "", fmt.Errorf("Device authorization expired before it was completed")
+		}
+	}
+}
+
+// readIDToken returns the ID token of the CONJUR_AUTHN_OIDC_ID_TOKEN environment variable, or read
+// from the file of CONJUR_AUTHN_OIDC_ID_TOKEN_PATH
+func readIDToken() (string, error) {
+	if token := strings.TrimSpace(os.Getenv(IDTokenEnvKey)); token != "" {
+		return token, nil
+	}
+
+	path := os.Getenv(IDTokenPathEnvKey)
+	if path == "" {
+		return "", nil
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("Failed to read ID token from '%s'. %s", path, err)
+	}
+
+	token := strings.TrimSpace(string(content))
+	if token == "" {
+		return "", fmt.Errorf("ID token file '%s' is empty", path)
+	}
+	return token, nil
+}
+
+// New returns a new OIDC object
+func New() (OIDC, error) {
+	idToken, err := readIDToken()
+	if err != nil {
+		return OIDC{}, err
+	}
+
+	providerURI := os.Getenv(ProviderURIEnvKey)
+	clientID := os.Getenv(ClientIDEnvKey)
+	if idToken == "" && (providerURI == "" || clientID == "") {
+		return OIDC{}, fmt.Errorf("%s or %s environment variable, or %s and %s for the device code flow, must be set", IDTokenEnvKey, IDTokenPathEnvKey, ProviderURIEnvKey, ClientIDEnvKey)
+	}
+
+	scope := os.Getenv(ScopeEnvKey)
+	if scope == "" {
+		scope = DefaultScope
+	}
+
+	return OIDC{
+		IDToken:     idToken,
+		ProviderURI: providerURI,
+		ClientID:    clientID,
+		Scope:       scope,
+		Prompt:      os.Stderr,
+	}, nil
+}
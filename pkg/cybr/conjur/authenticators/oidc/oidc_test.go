@@ -0,0 +1,124 @@
+package oidc
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/authenticators"
+)
+
+// newIdentityProvider returns a stand-in of an identity provider supporting the device code flow.
+// The device is authorized after pending polls
+func newIdentityProvider(t *testing.T, pending int) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			json.NewEncoder(w).Encode(providerConfiguration{
+				DeviceAuthorizationEndpoint: server.URL + "/device",
+				TokenEndpoint:               server.URL + "/token",
+			})
+		case "/device":
+			if r.FormValue("client_id") != "cybr" || r.FormValue("scope") != "openid email" {
+				t.Errorf("Invalid device authorization request '%s' '%s'", r.FormValue("client_id"), r.FormValue("scope"))
+			}
+			json.NewEncoder(w).Encode(deviceAuthorization{
+				DeviceCode:      "device-code",
+				UserCode:        "ABCD-EFGH",
+				VerificationURI: server.URL + "/activate",
+				ExpiresIn:       600,
+				Interval:        1,
+			})
+		case "/token":
+			if r.FormValue("grant_type") != deviceCodeGrantType || r.FormValue("device_code") != "device-code" {
+				t.Errorf("Invalid token request '%s' '%s'", r.FormValue("grant_type"), r.FormValue("device_code"))
+			}
+			if pending > 0 {
+				pending--
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(tokenResponse{Error: "authorization_pending"})
+				return
+			}
+			json.NewEncoder(w).Encode(tokenResponse{IDToken: "id.token.signature"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return server
+}
+
+func TestAuthenticateWithDeviceCodeFlow(t *testing.T) {
+	oldUnit := pollIntervalUnit
+	defer func() { pollIntervalUnit = oldUnit }()
+	pollIntervalUnit = time.Millisecond
+
+	provider := newIdentityProvider(t, 2)
+	defer provider.Close()
+
+	conjur := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/authn-oidc/okta/demo/authenticate" || r.FormValue("id_token") != "id.token.signature" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"protected":"p","payload":"p","signature":"s"}`))
+	}))
+	defer conjur.Close()
+
+	prompt := &bytes.Buffer{}
+	authenticator := OIDC{ProviderURI: provider.URL + "/", ClientID: "cybr", Scope: "openid email", Prompt: prompt}
+	config := authenticators.Config{Account: "demo", ApplianceURL: conjur.URL, ServiceID: "okta", IgnoreSSLVerify: true}
+
+	token, err := authenticator.Authenticate(config)
+	if err != nil || string(token) != `{"protected":"p","payload":"p","signature":"s"}` {
+		t.Fatalf("Failed to authenticate. '%s' %v", token, err)
+	}
+	if !strings.Contains(prompt.String(), provider.URL+"/activate") || !strings.Contains(prompt.String(), "ABCD-EFGH") {
+		t.Errorf("Expected the verification URI and user code to be shown. '%s'", prompt.String())
+	}
+
+	_, err = OIDC{IDToken: "invalid"}.Authenticate(config)
+	if err == nil {
+		t.Errorf("Expected an invalid ID token to fail")
+	}
+}
+
+func TestDeviceCodeFlowDenied(t *testing.T) {
+	oldUnit := pollIntervalUnit
+	defer func() { pollIntervalUnit = oldUnit }()
+	pollIntervalUnit = time.Millisecond
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			json.NewEncoder(w).Encode(providerConfiguration{
+				DeviceAuthorizationEndpoint: "http://" + r.Host + "/device",
+				TokenEndpoint:               "http://" + r.Host + "/token",
+			})
+		case "/device":
+			json.NewEncoder(w).Encode(deviceAuthorization{DeviceCode: "device-code", UserCode: "ABCD-EFGH", Interval: 1})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(tokenResponse{Error: "access_denied", ErrorDescription: "The user denied the request"})
+		}
+	}))
+	defer server.Close()
+
+	_, err := OIDC{ProviderURI: server.URL, ClientID: "cybr", Scope: DefaultScope, Prompt: &bytes.Buffer{}}.deviceCodeFlow()
+	if err == nil || !strings.Contains(err.Error(), "access_denied") {
+		t.Errorf("Expected a denied device authorization to fail. %v", err)
+	}
+}
+
+func TestIdentifier(t *testing.T) {
+	if (OIDC{IDToken: "a.b.c"}).Identifier() == (OIDC{IDToken: "d.e.f"}).Identifier() {
+		t.Errorf("Expected different ID tokens to have different identifiers")
+	}
+	if (OIDC{ClientID: "cybr"}).Identifier() != "device/cybr" {
+		t.Errorf("Expected the device code flow to be identified by its client ID")
+	}
+}
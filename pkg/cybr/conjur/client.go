@@ -1,7 +1,6 @@
 package conjur
 
 import (
-	"encoding/base64"
 	"fmt"
 	"io"
 	"net/http"
@@ -72,11 +71,6 @@ func getClientFromEnvironmentVariable() (*conjurapi.Client, *authn.LoginPair, er
 
 	authnURL := helpersauthn.GetAuthURL(envApplianceURL, "authn", "")
 
-	apiKey, err := Login(authnURL, envAccount, envLogin, []byte(envAPIKey), envCertFile)
-	if err != nil {
-		return &conjurapi.Client{}, &authn.LoginPair{}, err
-	}
-
 	config := conjurapi.Config{
 		Account:      envAccount,
 		ApplianceURL: envApplianceURL,
@@ -84,38 +78,82 @@ func getClientFromEnvironmentVariable() (*conjurapi.Client, *authn.LoginPair, er
 	}
 	loginPair := authn.LoginPair{
 		Login:  envLogin,
-		APIKey: string(apiKey),
+		APIKey: envAPIKey,
 	}
 
+	// The API key is only exchanged when no access token is cached
+	client, err := newRefreshingClient(config, tokenCacheKey(authnURL, envAccount, envLogin), "", func() ([]byte, error) {
+		apiKey, err := Login(authnURL, envAccount, envLogin, []byte(envAPIKey), envCertFile)
+		if err != nil {
+			return nil, err
+		}
+		loginPair.APIKey = string(apiKey)
+		return authenticateWithKey(config, loginPair)
+	})
+	if err != nil {
+		return &conjurapi.Client{}, &authn.LoginPair{}, err
+	}
+	return client, &loginPair, nil
+}
+
+// authenticateWithKey returns a new access token for the login and api key of loginPair
+func authenticateWithKey(config conjurapi.Config, loginPair authn.LoginPair) ([]byte, error) {
 	client, err := conjurapi.NewClientFromKey(config, loginPair)
-	return client, &loginPair, err
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := client.Authenticate(loginPair)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to authenticate to conjur. %s", err)
+	}
+	return token, nil
 }
 
-func getClientFromAuthenticator() (*conjurapi.Client, *authn.LoginPair, error) {
+// authenticatorEnvKeys lists the environment variables each authenticator requires besides
+// CONJUR_ACCOUNT, CONJUR_APPLIANCE_URL and CONJUR_AUTHENTICATOR. authn-jwt and authn-oidc read
+// the host ID from a claim of the token, and authn-gcp has no service ID
+var authenticatorEnvKeys = map[string][]string{
+	"authn-iam":   {envLoginKey, envAuthnServiceIDKey, envAwsTypeKey},
+	"authn-jwt":   {envAuthnServiceIDKey},
+	"authn-k8s":   {envLoginKey, envAuthnServiceIDKey},
+	"authn-oidc":  {envAuthnServiceIDKey},
+	"authn-azure": {envLoginKey, envAuthnServiceIDKey},
+	"authn-gcp":   {envLoginKey},
+}
+
+// validateAuthenticatorEnvironment returns the environment variables missing for the authenticator
+func validateAuthenticatorEnvironment(authenticator string) string {
 	errMsg := ""
 	errMsg = validateEnvironmentConfig(envAccount, envAccountKey, errMsg)
 	errMsg = validateEnvironmentConfig(envApplianceURL, envApplianceURLKey, errMsg)
-	errMsg = validateEnvironmentConfig(envLogin, envLoginKey, errMsg)
-	errMsg = validateEnvironmentConfig(envAuthenticator, envAuthenticatorKey, errMsg)
-	errMsg = validateEnvironmentConfig(envAuthnServiceID, envAuthnServiceIDKey, errMsg)
-	if envAuthenticator == "authn-iam" {
-		errMsg = validateEnvironmentConfig(envAwsType, envAwsTypeKey, errMsg)
+	errMsg = validateEnvironmentConfig(authenticator, envAuthenticatorKey, errMsg)
+
+	authenticator = strings.ToLower(authenticator)
+	for _, key := range authenticatorEnvKeys[authenticator] {
+		errMsg = validateEnvironmentConfig(os.Getenv(key), key, errMsg)
 	}
-	if strings.ToLower(envAwsType) == "cli" {
+	if authenticator == "authn-iam" && strings.ToLower(envAwsType) == "cli" {
 		errMsg = validateEnvironmentConfig(envAwsRole, envAwsRoleKey, errMsg)
 	}
+	return errMsg
+}
 
+func getClientFromAuthenticator() (*conjurapi.Client, *authn.LoginPair, error) {
 	// Partial environment variables were provided so return an error
 	// with a list of the environment variables that were not provided
+	errMsg := validateAuthenticatorEnvironment(envAuthenticator)
 	if errMsg != "" {
 		return &conjurapi.Client{},
 			&authn.LoginPair{},
-			fmt.Errorf("please use cybr conjur logon or provide proper environment variables")
+			fmt.Errorf("please use cybr conjur logon or provide proper environment variables. Missing %s", strings.TrimSuffix(errMsg, ", "))
 	}
 
-	envSSLVerifyBool := false
-	if envSSLVerify == strings.ToLower("true") || envSSLVerify == strings.ToLower("yes") || envSSLVerify == "1" {
-		envSSLVerifyBool = true
+	// SSL verification is only disabled if CONJUR_SSL_VERIFY explicitly turns it off
+	ignoreSSLVerify := false
+	switch strings.ToLower(envSSLVerify) {
+	case "false", "no", "0":
+		ignoreSSLVerify = true
 	}
 
 	config := helpersauthn.Config{
@@ -123,7 +161,8 @@ func getClientFromAuthenticator() (*conjurapi.Client, *authn.LoginPair, error) {
 		ApplianceURL:    envApplianceURL,
 		Login:           envLogin,
 		ServiceID:       envAuthnServiceID,
-		IgnoreSSLVerify: envSSLVerifyBool,
+		CertFile:        envCertFile,
+		IgnoreSSLVerify: ignoreSSLVerify,
 	}
 
 	authenticator, err := authenticators.GetAuthenticator(envAuthenticator, config)
@@ -131,13 +170,24 @@ func getClientFromAuthenticator() (*conjurapi.Client, *authn.LoginPair, error) {
 		return &conjurapi.Client{}, &authn.LoginPair{}, err
 	}
 
-	client, err := authenticator.Authenticate(config)
-
-	return client, nil, err
+	identifier := ""
+	if credential, ok := authenticator.(helpersauthn.Identifier); ok {
+		identifier = credential.Identifier()
+	}
+	authnURL := helpersauthn.GetAuthURL(envApplianceURL, authenticator.Name(), envAuthnServiceID)
+	clientConfig := conjurapi.Config{Account: envAccount, ApplianceURL: envApplianceURL, SSLCertPath: envCertFile}
+	client, err := newRefreshingClient(clientConfig, tokenCacheKey(authnURL, envAccount, envLogin), identifier, func() ([]byte, error) {
+		return authenticator.Authenticate(config)
+	})
+	if err != nil {
+		return &conjurapi.Client{}, nil, err
+	}
+	return client, nil, nil
 }
 
 // GetConjurClient creates a Conjur API client and login pair from environment variables, an authenticator,
-// or .netrc & .conjurrc files
+// or the credential store & .conjurrc files. The access token of the client is cached in the credential
+// store and reused by following calls until shortly before it expires or conjur rejects it
 func GetConjurClient() (*conjurapi.Client, *authn.LoginPair, error) {
 	homeDir, err := GetHomeDirectory()
 	if err != nil {
@@ -168,7 +218,7 @@ func GetConjurClient() (*conjurapi.Client, *authn.LoginPair, error) {
 		return &conjurapi.Client{}, &authn.LoginPair{}, err
 	}
 
-	// If .conjurrc is not empty, attempt to get client from .netrc & .conjurrc files
+	// If .conjurrc is not empty, attempt to get client from the credential store & .conjurrc files
 	config := conjurapi.Config{
 		Account:      account,
 		ApplianceURL: baseURL,
@@ -176,32 +226,30 @@ func GetConjurClient() (*conjurapi.Client, *authn.LoginPair, error) {
 		NetRCPath:    netrcPath,
 	}
 
-	loginPair, err := conjurapi.LoginPairFromNetRC(config)
+	loginPair, err := GetLoginPair(config)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to retrieve credentials from ~/.netrc. %s", err)
+		return nil, nil, err
 	}
 
-	client, err := conjurapi.NewClientFromKey(config, *loginPair)
-	return client, loginPair, err
-}
-
-// sendConjurAuthenticatedHTTPRequest Send a HTTP request with the conjur session token in the authorization header
-// This is used for API endpoints not included in the conjur-api-go SDK (info, whoami, enableauthn)
-func sendConjurAuthenticatedHTTPRequest(client *conjurapi.Client, loginPair *authn.LoginPair, url string, method string, body io.Reader) (*http.Response, error) {
-	sessionToken, err := client.Authenticate(*loginPair)
+	authnURL := helpersauthn.GetAuthURL(baseURL, "authn", "")
+	client, err := newRefreshingClient(config, tokenCacheKey(authnURL, account, loginPair.Login), "", func() ([]byte, error) {
+		return authenticateWithKey(config, *loginPair)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to authenticate to conjur. %s", err)
+		return nil, nil, err
 	}
+	return client, loginPair, nil
+}
 
+// sendConjurAuthenticatedHTTPRequest Send a HTTP request with the conjur access token of the client in the authorization header
+// This is used for API endpoints not included in the conjur-api-go SDK (info, whoami, enableauthn)
+func sendConjurAuthenticatedHTTPRequest(client *conjurapi.Client, url string, method string, body io.Reader) (*http.Response, error) {
 	req, err := http.NewRequest(method, url, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request '%s'. %s", url, err)
 	}
 
-	req.Header.Add("Authorization", fmt.Sprintf("Token token=\"%s\"", base64.StdEncoding.EncodeToString(sessionToken)))
-
-	httpClient := client.GetHttpClient()
-	resp, err := httpClient.Do(req)
+	resp, err := client.SubmitRequest(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send HTTP request. %s", err)
 	}
@@ -0,0 +1,43 @@
+package conjur
+
+import (
+	"os"
+	"testing"
+)
+
+func TestValidateAuthenticatorEnvironment(t *testing.T) {
+	oldAccount, oldApplianceURL := envAccount, envApplianceURL
+	defer func() { envAccount, envApplianceURL = oldAccount, oldApplianceURL }()
+	envAccount, envApplianceURL = "demo", "https://conjur.example.com"
+
+	oldEnv := map[string]string{}
+	for _, key := range []string{envLoginKey, envAuthnServiceIDKey, envAwsTypeKey} {
+		oldEnv[key] = os.Getenv(key)
+		os.Unsetenv(key)
+	}
+	defer func() {
+		for key, value := range oldEnv {
+			os.Setenv(key, value)
+		}
+	}()
+
+	tests := map[string]string{
+		"authn-jwt":   envAuthnServiceIDKey + ", ",
+		"authn-oidc":  envAuthnServiceIDKey + ", ",
+		"authn-gcp":   envLoginKey + ", ",
+		"AUTHN-AZURE": envLoginKey + ", " + envAuthnServiceIDKey + ", ",
+		"authn-iam":   envLoginKey + ", " + envAuthnServiceIDKey + ", " + envAwsTypeKey + ", ",
+		"":            envAuthenticatorKey + ", ",
+	}
+	for authenticator, expected := range tests {
+		errMsg := validateAuthenticatorEnvironment(authenticator)
+		if errMsg != expected {
+			t.Errorf("Expected '%s' to be missing for '%s' but got '%s'", expected, authenticator, errMsg)
+		}
+	}
+
+	os.Setenv(envLoginKey, "host/gcp/instance")
+	if errMsg := validateAuthenticatorEnvironment("authn-gcp"); errMsg != "" {
+		t.Errorf("Expected nothing to be missing for authn-gcp but got '%s'", errMsg)
+	}
+}
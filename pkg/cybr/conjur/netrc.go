@@ -1,27 +1,32 @@
 package conjur
 
 import (
-	"bufio"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
-)
 
-var netrcTemplate string = `machine {{ APPLIANCE_URL }}/authn
-  login {{ USERNAME }}
-  password {{ PASSWORD }}
-`
+	"github.com/cyberark/conjur-api-go/conjurapi"
+	"github.com/cyberark/conjur-api-go/conjurapi/authn"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/authenticators"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/credstore"
+)
 
-// GetNetRcPath returns path to the ~/.conjurrc file os-agnostic
+// GetNetRcPath returns path to the ~/.netrc file os-agnostic
 func GetNetRcPath(homeDir string) string {
 	return filepath.FromSlash(fmt.Sprintf("%s/.netrc", homeDir))
 }
 
-// CreateNetRc create a conjur netrc file
+// credentialKey is the credential store key of the login pair for a conjur appliance url
+func credentialKey(url string) string {
+	return "conjur/" + strings.TrimSuffix(strings.TrimSpace(url), "/")
+}
+
+// CreateNetRc saves the conjur login and api key in the credential store.
+// Older versions wrote these credentials to ~/.netrc in plaintext
 func CreateNetRc(username string, password string) error {
-	// creatr ~/.netrc pas
 	homeDir, err := GetHomeDirectory()
 	if err != nil {
 		return err
@@ -33,25 +38,117 @@ func CreateNetRc(username string, password string) error {
 		return fmt.Errorf("Failed to get appliance url from '%s'. Run 'cam init' to set this file", conjurrcFileName)
 	}
 
-	// create the ~/.netrc file
-	netrcFileName := GetNetRcPath(homeDir)
-	fmt.Print("Replace ~/.netrc file [y]: ")
-	// prompt user
-	reader := bufio.NewReader(os.Stdin)
-	text, _ := reader.ReadString('\n')
-	answer := strings.TrimSpace(text)
-	if answer == "" || answer == "y" {
-		// create the ~/.netrc file
-		netrcContent := strings.Replace(netrcTemplate, "{{ USERNAME }}", username, 1)
-		netrcContent = strings.Replace(netrcContent, "{{ PASSWORD }}", password, 1)
-		netrcContent = strings.Replace(netrcContent, "{{ APPLIANCE_URL }}", url, 1)
-
-		os.Remove(netrcFileName)
-		err = ioutil.WriteFile(netrcFileName, []byte(netrcContent), 0400)
+	return saveLoginPair(url, authn.LoginPair{Login: username, APIKey: password})
+}
+
+func saveLoginPair(url string, loginPair authn.LoginPair) error {
+	store, err := credstore.GetStore()
+	if err != nil {
+		return err
+	}
+
+	content, err := json.Marshal(loginPair)
+	if err != nil {
+		return err
+	}
+
+	err = store.Set(credentialKey(url), string(content))
+	if err != nil {
+		return fmt.Errorf("Failed to save conjur credentials in the %s credential store. %s", store.Name(), err)
+	}
+	return nil
+}
+
+// GetLoginPair returns the conjur login and api key saved for the appliance url in config.
+// Credentials found in a ~/.netrc file written by older versions are migrated to the credential store
+func GetLoginPair(config conjurapi.Config) (*authn.LoginPair, error) {
+	store, err := credstore.GetStore()
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := store.Get(credentialKey(config.ApplianceURL))
+	if err == nil {
+		loginPair := &authn.LoginPair{}
+		err = json.Unmarshal([]byte(content), loginPair)
 		if err != nil {
-			return fmt.Errorf("Failed to write file '%s'. %s", netrcFileName, err)
+			return nil, fmt.Errorf("Failed to parse conjur credentials from the %s credential store. %s", store.Name(), err)
 		}
+		return loginPair, nil
+	}
+	if err != credstore.ErrNotFound {
+		return nil, fmt.Errorf("Failed to retrieve conjur credentials from the %s credential store. %s", store.Name(), err)
 	}
 
-	return err
+	loginPair, err := conjurapi.LoginPairFromNetRC(config)
+	if err != nil {
+		return nil, fmt.Errorf("No conjur credentials found. Run 'cybr conjur logon' first. %s", err)
+	}
+
+	err = saveLoginPair(config.ApplianceURL, *loginPair)
+	if err != nil {
+		return nil, err
+	}
+	err = removeLegacyNetRc(config.NetRCPath, config.ApplianceURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return loginPair, nil
+}
+
+// RemoveCredentials removes the conjur credentials saved for the appliance url, and the access token cached for them
+func RemoveCredentials(url string) error {
+	store, err := credstore.GetStore()
+	if err != nil {
+		return err
+	}
+
+	homeDir, err := GetHomeDirectory()
+	if err != nil {
+		return err
+	}
+
+	content, err := store.Get(credentialKey(url))
+	if err == nil {
+		loginPair := authn.LoginPair{}
+		if json.Unmarshal([]byte(content), &loginPair) == nil {
+			account := strings.TrimSpace(GetAccountFromConjurRc(GetConjurRcPath(homeDir)))
+			authnURL := authenticators.GetAuthURL(strings.TrimSpace(url), "authn", "")
+			err = removeCachedToken(tokenCacheKey(authnURL, account, loginPair.Login))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err = store.Delete(credentialKey(url))
+	if err != nil {
+		return fmt.Errorf("Failed to remove conjur credentials from the %s credential store. %s", store.Name(), err)
+	}
+
+	return removeLegacyNetRc(GetNetRcPath(homeDir), url)
+}
+
+// removeLegacyNetRc removes a ~/.netrc file only if it was written by older versions
+// of cybr, so entries belonging to other tools are never deleted
+func removeLegacyNetRc(netrcPath string, url string) error {
+	content, err := ioutil.ReadFile(netrcPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to read file '%s'. %s", netrcPath, err)
+	}
+
+	netrc := string(content)
+	if strings.Count(netrc, "machine ") != 1 || !strings.HasPrefix(netrc, fmt.Sprintf("machine %s/authn\n", strings.TrimSpace(url))) {
+		return nil
+	}
+
+	err = os.Remove(netrcPath)
+	if err != nil {
+		return fmt.Errorf("Failed to remove file '%s'. %s", netrcPath, err)
+	}
+	return nil
 }
@@ -0,0 +1,371 @@
+package conjur
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// recordAttributes lists the attributes of each record statement. Records are roles and resources
+// created by the policy
+var recordAttributes = map[string][]string{
+	"policy":       {"id", "owner", "annotations", "body"},
+	"user":         {"id", "owner", "annotations", "restricted_to", "public_keys", "uidnumber"},
+	"host":         {"id", "owner", "annotations", "restricted_to"},
+	"group":        {"id", "owner", "annotations", "gidnumber"},
+	"layer":        {"id", "owner", "annotations"},
+	"variable":     {"id", "owner", "annotations", "kind", "mime_type"},
+	"webservice":   {"id", "owner", "annotations"},
+	"host-factory": {"id", "owner", "annotations", "layers"},
+}
+
+// entitlementAttributes lists the required attributes of each entitlement statement. Each
+// attribute may be written in its singular or plural form
+var entitlementAttributes = map[string][][]string{
+	"grant":  {{"role", "roles"}, {"member", "members"}},
+	"revoke": {{"role", "roles"}, {"member", "members"}},
+	"permit": {{"role", "roles"}, {"privilege", "privileges"}, {"resource", "resources"}},
+	"deny":   {{"role", "roles"}, {"privilege", "privileges"}, {"resource", "resources"}},
+	"delete": {{"record"}},
+}
+
+// referenceAttributes are the attributes referring to records, e.g. role: !group admins
+var referenceAttributes = map[string]bool{
+	"owner": true, "layers": true, "record": true,
+	"role": true, "roles": true, "member": true, "members": true, "resource": true, "resources": true,
+}
+
+// PolicyRecord is a role or resource declared or deleted by a policy
+type PolicyRecord struct {
+	Kind string `json:"kind"`
+	ID   string `json:"id"`
+	// Namespace is the path of the policies the record is nested in within the document
+	Namespace string `json:"namespace,omitempty"`
+	Line      int    `json:"line"`
+}
+
+// Policy is a parsed Conjur policy document
+type Policy struct {
+	Records   []PolicyRecord `json:"records"`
+	Deletions []PolicyRecord `json:"deletions"`
+}
+
+// PolicyError is an error of a policy statement
+type PolicyError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+func (e PolicyError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// PolicyErrors are all errors found in a policy document
+type PolicyErrors []PolicyError
+
+func (e PolicyErrors) Error() string {
+	messages := []string{}
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+// ResourceID returns the fully qualified ID of the record once loaded into branch of account,
+// e.g. the variable db/password of the branch apps is demo:variable:apps/db/password. Users
+// of a policy other than root are suffixed with the policy, e.g. alice@apps, and records
+// without an ID take the ID of their policy
+func (r PolicyRecord) ResourceID(account string, branch string) string {
+	path := strings.Trim(branch, "/")
+	if path == "root" {
+		path = ""
+	}
+	if r.Namespace != "" {
+		path = strings.TrimPrefix(path+"/"+r.Namespace, "/")
+	}
+
+	id := r.ID
+	if strings.HasPrefix(id, "/") {
+		id = strings.TrimPrefix(id, "/")
+		path = ""
+	}
+
+	switch {
+	case id == "":
+		id = path
+	case path == "":
+	case r.Kind == "user":
+		id = id + "@" + strings.ReplaceAll(path, "/", "-")
+	default:
+		id = path + "/" + id
+	}
+	return fmt.Sprintf("%s:%s:%s", account, strings.ReplaceAll(r.Kind, "-", "_"), id)
+}
+
+// policyParser collects the records and the errors of a policy document
+type policyParser struct {
+	policy   *Policy
+	errors   PolicyErrors
+	declared map[string]int
+}
+
+func (p *policyParser) addError(node *yaml.Node, format string, args ...interface{}) {
+	p.errors = append(p.errors, PolicyError{Line: node.Line, Message: fmt.Sprintf(format, args...)})
+}
+
+// resolve returns the node an alias refers to
+func resolve(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
+
+// getKind returns the statement kind of a node tagged with e.g. !variable
+func getKind(node *yaml.Node) string {
+	if !strings.HasPrefix(node.Tag, "!") || strings.HasPrefix(node.Tag, "!!") {
+		return ""
+	}
+	return strings.TrimPrefix(node.Tag, "!")
+}
+
+// ParsePolicy parses a Conjur policy document and validates its statements. All errors of the
+// document are returned as PolicyErrors with the line of each statement
+func ParsePolicy(content []byte) (*Policy, error) {
+	document := yaml.Node{}
+	err := yaml.Unmarshal(content, &document)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid YAML. %s", err)
+	}
+
+	parser := &policyParser{policy: &Policy{}, declared: map[string]int{}}
+	if len(document.Content) > 0 {
+		parser.parseStatements(resolve(document.Content[0]), "")
+	}
+
+	if len(parser.errors) > 0 {
+		sort.SliceStable(parser.errors, func(i, j int) bool { return parser.errors[i].Line < parser.errors[j].Line })
+		return nil, parser.errors
+	}
+	return parser.policy, nil
+}
+
+// parseStatements parses a sequence of statements, either the document or the body of a policy
+func (p *policyParser) parseStatements(node *yaml.Node, namespace string) {
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return
+	}
+	if node.Kind != yaml.SequenceNode {
+		p.addError(node, "Expected a list of statements")
+		return
+	}
+
+	for _, statement := range node.Content {
+		statement = resolve(statement)
+		// Nested lists of statements, usually anchored to be referred to, are flattened
+		if statement.Kind == yaml.SequenceNode {
+			p.parseStatements(statement, namespace)
+			continue
+		}
+		p.parseStatement(statement, namespace)
+	}
+}
+
+// parseStatement parses a record or an entitlement statement
+func (p *policyParser) parseStatement(node *yaml.Node, namespace string) {
+	kind := getKind(node)
+	if kind == "" {
+		p.addError(node, "Statement must be tagged with its type, e.g. !variable or !permit")
+		return
+	}
+
+	if _, ok := recordAttributes[kind]; ok {
+		p.parseRecord(node, kind, namespace)
+		return
+	}
+	if _, ok := entitlementAttributes[kind]; ok {
+		p.parseEntitlement(node, kind, namespace)
+		return
+	}
+	p.addError(node, "Unknown statement '!%s'", kind)
+}
+
+// getAttributes returns the attributes of a statement by name. Unknown and duplicate attributes
+// are reported
+func (p *policyParser) getAttributes(node *yaml.Node, kind string, allowed []string) map[string]*yaml.Node {
+	attributes := map[string]*yaml.Node{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], resolve(node.Content[i+1])
+		known := false
+		for _, name := range allowed {
+			known = known || key.Value == name
+		}
+		switch {
+		case !known:
+			p.addError(key, "Unknown attribute '%s' of '!%s'. Expected one of %s", key.Value, kind, strings.Join(allowed, ", "))
+		case attributes[key.Value] != nil:
+			p.addError(key, "Duplicate attribute '%s' of '!%s'", key.Value, kind)
+		default:
+			attributes[key.Value] = value
+		}
+	}
+	return attributes
+}
+
+// parseRecord parses a record, either a mapping of attributes or its ID, e.g. - !variable password
+func (p *policyParser) parseRecord(node *yaml.Node, kind string, namespace string) {
+	record := PolicyRecord{Kind: kind, Namespace: namespace, Line: node.Line}
+
+	switch node.Kind {
+	case yaml.ScalarNode:
+		record.ID = node.Value
+	case yaml.MappingNode:
+		attributes := p.getAttributes(node, kind, recordAttributes[kind])
+		for _, name := range recordAttributes[kind] {
+			if value := attributes[name]; value != nil {
+				p.validateAttribute(name, value, kind)
+			}
+		}
+		if id := attributes["id"]; id != nil {
+			record.ID = id.Value
+		}
+		if kind == "host-factory" && attributes["layers"] == nil {
+			p.addError(node, "Missing attribute 'layers' of '!%s'", kind)
+		}
+		if body := attributes["body"]; body != nil && record.ID != "" {
+			p.parseStatements(body, strings.TrimPrefix(namespace+"/"+strings.Trim(record.ID, "/"), "/"))
+		}
+	default:
+		p.addError(node, "Expected the attributes or the ID of '!%s'", kind)
+		return
+	}
+
+	// Other records without an ID take the ID of their policy, e.g. - !layer
+	if record.ID == "" && kind == "policy" {
+		p.addError(node, "Missing attribute 'id' of '!%s'", kind)
+		return
+	}
+
+	key := record.ResourceID("", "root")
+	if line, ok := p.declared[key]; ok {
+		p.addError(node, "Duplicate '!%s' '%s', first declared on line %d", kind, record.ID, line)
+		return
+	}
+	p.declared[key] = record.Line
+	p.policy.Records = append(p.policy.Records, record)
+}
+
+// parseEntitlement parses a grant, revoke, permit, deny or delete statement
+func (p *policyParser) parseEntitlement(node *yaml.Node, kind string, namespace string) {
+	if node.Kind != yaml.MappingNode {
+		p.addError(node, "Expected the attributes of '!%s'", kind)
+		return
+	}
+
+	allowed := []string{}
+	for _, names := range entitlementAttributes[kind] {
+		allowed = append(allowed, names...)
+	}
+	attributes := p.getAttributes(node, kind, allowed)
+
+	for _, names := range entitlementAttributes[kind] {
+		found := []string{}
+		for _, name := range names {
+			if attributes[name] != nil {
+				found = append(found, name)
+			}
+		}
+		if len(found) == 0 {
+			p.addError(node, "Missing attribute '%s' of '!%s'", names[0], kind)
+		} else if len(found) > 1 {
+			p.addError(node, "Attributes '%s' of '!%s' are the same, use one of them", strings.Join(found, "' and '"), kind)
+		}
+	}
+	for _, name := range allowed {
+		if value := attributes[name]; value != nil {
+			p.validateAttribute(name, value, kind)
+		}
+	}
+
+	if record := attributes["record"]; kind == "delete" && record != nil && record.Kind == yaml.ScalarNode && getKind(record) != "" {
+		p.policy.Deletions = append(p.policy.Deletions, PolicyRecord{Kind: getKind(record), ID: record.Value, Namespace: namespace, Line: node.Line})
+	}
+}
+
+// validateAttribute reports an attribute value of the wrong type
+func (p *policyParser) validateAttribute(name string, value *yaml.Node, kind string) {
+	switch {
+	case name == "body":
+		// The statements of the body are parsed with the namespace of the policy
+	case name == "annotations":
+		if value.Kind != yaml.MappingNode {
+			p.addError(value, "Attribute 'annotations' of '!%s' must be a mapping", kind)
+		}
+	case name == "privilege" || name == "privileges" || name == "restricted_to" || name == "public_keys":
+		p.validateScalars(name, value, kind)
+	case referenceAttributes[name]:
+		p.validateReferences(name, value, kind)
+	default:
+		if value.Kind != yaml.ScalarNode || getKind(value) != "" {
+			p.addError(value, "Attribute '%s' of '!%s' must be a string", name, kind)
+		}
+	}
+}
+
+// validateScalars reports a value that is neither a string nor a list of strings
+func (p *policyParser) validateScalars(name string, value *yaml.Node, kind string) {
+	values := []*yaml.Node{value}
+	if value.Kind == yaml.SequenceNode {
+		values = value.Content
+	}
+	for _, v := range values {
+		v = resolve(v)
+		if v.Kind != yaml.ScalarNode || v.Value == "" || getKind(v) != "" {
+			p.addError(v, "Attribute '%s' of '!%s' must be a string or a list of strings", name, kind)
+		}
+	}
+}
+
+// flatten returns the values of a list, including the values of nested lists such as an alias
+// of a list of records, or value itself if it is not a list
+func flatten(value *yaml.Node) []*yaml.Node {
+	value = resolve(value)
+	if value.Kind != yaml.SequenceNode {
+		return []*yaml.Node{value}
+	}
+
+	values := []*yaml.Node{}
+	for _, v := range value.Content {
+		values = append(values, flatten(v)...)
+	}
+	return values
+}
+
+// validateReferences reports a value that does not refer to records, e.g. role: admins instead of
+// role: !group admins. Records may be referred to by their ID or by an alias of the record, and
+// members of a grant may be written as !member with an admin option
+func (p *policyParser) validateReferences(name string, value *yaml.Node, kind string) {
+	values := []*yaml.Node{value}
+	if name != "owner" && name != "record" {
+		values = flatten(value)
+	}
+
+	for _, v := range values {
+		referenceKind := getKind(v)
+		switch {
+		case referenceKind == "member" && (name == "member" || name == "members") && v.Kind == yaml.MappingNode:
+			attributes := p.getAttributes(v, "member", []string{"role", "admin"})
+			if attributes["role"] == nil {
+				p.addError(v, "Missing attribute 'role' of '!member'")
+			} else {
+				p.validateReferences("role", attributes["role"], "member")
+			}
+		case recordAttributes[referenceKind] == nil:
+			p.addError(v, "Attribute '%s' of '!%s' must refer to a record, e.g. !group admins", name, kind)
+		case v.Kind != yaml.ScalarNode && v.Kind != yaml.MappingNode:
+			p.addError(v, "Reference '!%s' in attribute '%s' of '!%s' must be the ID of the record", referenceKind, name, kind)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package conjur
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cyberark/conjur-api-go/conjurapi"
+)
+
+const testPolicy = `- !policy
+  id: apps
+  owner: !group /admins
+  annotations:
+    description: Applications
+  body:
+  - !layer
+  - !host app-01
+  - !user alice
+  - &password
+    !variable
+    id: db/password
+    kind: password
+  - !grant
+    role: !layer
+    member: !host app-01
+  - !permit
+    role: !layer
+    privileges: [ read, execute ]
+    resources:
+    - *password
+- &keys
+  - !variable
+    id: keys/a
+  - !variable keys/b
+- !permit
+  role: !group /admins
+  privilege: read
+  resources: *keys
+- !delete
+  record: !variable old
+`
+
+func TestParsePolicy(t *testing.T) {
+	policy, err := ParsePolicy([]byte(testPolicy))
+	if err != nil {
+		t.Fatalf("Failed to parse policy. %s", err)
+	}
+
+	ids := []string{}
+	for _, record := range policy.Records {
+		ids = append(ids, record.ResourceID("demo", "root"))
+	}
+	expected := []string{"demo:layer:apps", "demo:host:apps/app-01", "demo:user:alice@apps", "demo:variable:apps/db/password", "demo:policy:apps", "demo:variable:keys/a", "demo:variable:keys/b"}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Errorf("Expected records %v but got %v", expected, ids)
+	}
+	if len(policy.Deletions) != 1 || policy.Deletions[0].ResourceID("demo", "team/a") != "demo:variable:team/a/old" {
+		t.Errorf("Expected the deletion of variable old. %v", policy.Deletions)
+	}
+	if policy.Records[1].Line != 8 {
+		t.Errorf("Expected host app-01 on line 8 but got %d", policy.Records[1].Line)
+	}
+}
+
+func TestParsePolicyReportsErrorsWithLines(t *testing.T) {
+	_, err := ParsePolicy([]byte(`- !policy
+  body:
+  - !host
+- !varaible db/password
+- !group
+  id: admins
+  annotation: x
+- !permit
+  role: admins
+  privilege: read
+- !user alice
+- !user alice
+- id: untagged
+`))
+	errors, ok := err.(PolicyErrors)
+	if !ok {
+		t.Fatalf("Expected policy errors but got %v", err)
+	}
+
+	lines := []int{}
+	for _, e := range errors {
+		lines = append(lines, e.Line)
+	}
+	expected := []int{1, 4, 7, 8, 9, 12, 13}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Errorf("Expected errors on lines %v but got:\n%s", expected, err)
+	}
+
+	_, err = ParsePolicy([]byte("- !variable x\n  - bad: ["))
+	if err == nil {
+		t.Errorf("Expected invalid YAML to fail")
+	}
+}
+
+func TestPlanPolicy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/resources/demo" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode([]map[string]interface{}{
+			{"id": "demo:policy:apps", "policy": "demo:policy:root"},
+			{"id": "demo:layer:apps", "policy": "demo:policy:apps"},
+			{"id": "demo:host:apps/app-02", "policy": "demo:policy:apps"},
+			{"id": "demo:variable:apps/old", "policy": "demo:policy:apps"},
+			{"id": "demo:variable:apps/team/key", "policy": "demo:policy:apps/team"},
+			{"id": "demo:variable:other/key", "policy": "demo:policy:other"},
+			{"id": "demo:user:admin"},
+		})
+	}))
+	defer server.Close()
+
+	payload := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf(`{"sub":"admin","iat":%d}`, time.Now().Unix())))
+	token := fmt.Sprintf(`{"protected":"eyJhbGciOiJjb25qdXIub3JnL3Nsb3NpbG8vdjIifQ==","payload":"%s","signature":"c2ln"}`, payload)
+	client, err := conjurapi.NewClientFromToken(conjurapi.Config{Account: "demo", ApplianceURL: server.URL}, token)
+	if err != nil {
+		t.Fatalf("Failed to create client. %s", err)
+	}
+
+	policy, err := ParsePolicy([]byte("- !layer\n- !host app-01\n- !delete\n  record: !variable old\n"))
+	if err != nil {
+		t.Fatalf("Failed to parse policy. %s", err)
+	}
+
+	plan, err := PlanPolicy(client, "apps", policy, conjurapi.PolicyModePut)
+	if err != nil {
+		t.Fatalf("Failed to plan policy. %s", err)
+	}
+	expected := []PolicyChange{
+		{Action: ChangeCreate, Kind: "host", ID: "demo:host:apps/app-01", Line: 2},
+		{Action: ChangeDelete, Kind: "variable", ID: "demo:variable:apps/old", Line: 3},
+		{Action: ChangeDelete, Kind: "host", ID: "demo:host:apps/app-02"},
+		{Action: ChangeDelete, Kind: "variable", ID: "demo:variable:apps/team/key"},
+	}
+	if !reflect.DeepEqual(plan.Changes, expected) {
+		t.Errorf("Expected changes %v but got %v", expected, plan.Changes)
+	}
+	if plan.Complete || !strings.Contains(plan.String(), "cannot see") {
+		t.Errorf("Expected the plan replacing the policy to be incomplete. %s", plan)
+	}
+
+	// Only explicit deletions are planned when the policy is updated
+	plan, err = PlanPolicy(client, "apps", policy, conjurapi.PolicyModePatch)
+	if err != nil || len(plan.Changes) != 2 || len(plan.Deletions()) != 1 || !plan.Complete {
+		t.Errorf("Expected one creation and one deletion. %v %v", plan, err)
+	}
+}
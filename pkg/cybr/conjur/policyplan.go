@@ -0,0 +1,183 @@
+package conjur
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cyberark/conjur-api-go/conjurapi"
+)
+
+const (
+	// ChangeCreate creates a role or resource
+	ChangeCreate = "create"
+	// ChangeDelete deletes a role or resource
+	ChangeDelete = "delete"
+
+	resourcesPageSize = 1000
+)
+
+// PolicyChange is a role or resource created or deleted by loading a policy
+type PolicyChange struct {
+	Action string `json:"action"`
+	Kind   string `json:"kind"`
+	ID     string `json:"id"`
+	Line   int    `json:"line,omitempty"`
+}
+
+// PolicyPlan contains the roles and resources created or deleted by loading a policy into a branch.
+// Conjur only lists the resources visible to the client, so a plan replacing a policy is not
+// complete: it does not contain the deletions of roles and resources the client cannot see
+type PolicyPlan struct {
+	Branch   string         `json:"branch"`
+	Mode     string         `json:"mode"`
+	Complete bool           `json:"complete"`
+	Changes  []PolicyChange `json:"changes"`
+}
+
+// GetPolicyMode returns the policy mode of append, update or replace, as loaded by the
+// append-policy, update-policy and replace-policy commands
+func GetPolicyMode(name string) (conjurapi.PolicyMode, error) {
+	switch strings.ToLower(name) {
+	case "append":
+		return conjurapi.PolicyModePost, nil
+	case "update":
+		return conjurapi.PolicyModePatch, nil
+	case "replace":
+		return conjurapi.PolicyModePut, nil
+	}
+	return 0, fmt.Errorf("Invalid policy mode '%s'. Use append, update or replace", name)
+}
+
+// listResources returns the IDs and the kinds of all resources visible to the client
+func listResources(client *conjurapi.Client) (map[string]map[string]interface{}, error) {
+	resources := map[string]map[string]interface{}{}
+	for offset := 0; ; offset += resourcesPageSize {
+		page, err := client.Resources(&conjurapi.ResourceFilter{Limit: resourcesPageSize, Offset: offset})
+		if err != nil {
+			return nil, fmt.Errorf("Failed to list resources. %s", err)
+		}
+		for _, resource := range page {
+			if id, ok := resource["id"].(string); ok {
+				resources[id] = resource
+			}
+		}
+		if len(page) < resourcesPageSize {
+			return resources, nil
+		}
+	}
+}
+
+// isInBranch returns true if the resource was loaded by the policy of the branch or one of its
+// nested policies. The policy of the branch itself belongs to its parent
+func isInBranch(resource map[string]interface{}, account string, branch string) bool {
+	policy, _ := resource["policy"].(string)
+	branchPolicy := fmt.Sprintf("%s:policy:%s", account, branch)
+	if resource["id"] == branchPolicy || policy == "" {
+		return false
+	}
+	return branch == "root" || policy == branchPolicy || strings.HasPrefix(policy, branchPolicy+"/")
+}
+
+// getResourceKind returns the kind of a fully qualified resource ID, e.g. variable of demo:variable:db/password
+func getResourceKind(id string) string {
+	parts := strings.SplitN(id, ":", 3)
+	if len(parts) < 3 {
+		return ""
+	}
+	return parts[1]
+}
+
+// PlanPolicy compares a policy to the roles and resources of the branch and returns the
+// roles and resources loading it with mode would create or delete. Roles and resources are
+// deleted by !delete statements, and by replacing a policy that no longer declares them
+func PlanPolicy(client *conjurapi.Client, branch string, policy *Policy, mode conjurapi.PolicyMode) (*PolicyPlan, error) {
+	branch = strings.Trim(branch, "/")
+	if branch == "" {
+		branch = "root"
+	}
+	account := client.GetConfig().Account
+
+	resources, err := listResources(client)
+	if err != nil {
+		return nil, err
+	}
+
+	modeNames := map[conjurapi.PolicyMode]string{conjurapi.PolicyModePost: "append", conjurapi.PolicyModePatch: "update", conjurapi.PolicyModePut: "replace"}
+	plan := &PolicyPlan{Branch: branch, Mode: modeNames[mode], Complete: mode != conjurapi.PolicyModePut, Changes: []PolicyChange{}}
+
+	declared := map[string]bool{}
+	for _, record := range policy.Records {
+		id := record.ResourceID(account, branch)
+		declared[id] = true
+		if resources[id] == nil {
+			plan.Changes = append(plan.Changes, PolicyChange{Action: ChangeCreate, Kind: getResourceKind(id), ID: id, Line: record.Line})
+		}
+	}
+
+	deleted := map[string]bool{}
+	for _, record := range policy.Deletions {
+		id := record.ResourceID(account, branch)
+		if resources[id] != nil && !deleted[id] {
+			deleted[id] = true
+			plan.Changes = append(plan.Changes, PolicyChange{Action: ChangeDelete, Kind: getResourceKind(id), ID: id, Line: record.Line})
+		}
+	}
+
+	if mode == conjurapi.PolicyModePut {
+		ids := []string{}
+		for id, resource := range resources {
+			if !declared[id] && !deleted[id] && isInBranch(resource, account, branch) {
+				ids = append(ids, id)
+			}
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			plan.Changes = append(plan.Changes, PolicyChange{Action: ChangeDelete, Kind: getResourceKind(id), ID: id})
+		}
+	}
+
+	return plan, nil
+}
+
+// HasChanges returns true if the plan contains any change
+func (p PolicyPlan) HasChanges() bool {
+	return len(p.Changes) > 0
+}
+
+// Deletions returns the roles and resources the plan deletes
+func (p PolicyPlan) Deletions() []PolicyChange {
+	deletions := []PolicyChange{}
+	for _, change := range p.Changes {
+		if change.Action == ChangeDelete {
+			deletions = append(deletions, change)
+		}
+	}
+	return deletions
+}
+
+// String returns the changes of the plan with a summary
+func (p PolicyPlan) String() string {
+	buffer := &bytes.Buffer{}
+	if !p.HasChanges() {
+		fmt.Fprintf(buffer, "No changes. The policy matches the roles and resources of branch '%s'.\n", p.Branch)
+	} else {
+		for _, change := range p.Changes {
+			fmt.Fprintln(buffer, change.String())
+		}
+		deletions := len(p.Deletions())
+		fmt.Fprintf(buffer, "\nPlan: %d to create, %d to delete.\n", len(p.Changes)-deletions, deletions)
+	}
+
+	if !p.Complete {
+		fmt.Fprintf(buffer, "\nOnly the roles and resources visible to the current identity were compared. Replacing the policy also deletes the roles and resources of branch '%s' it cannot see and the policy does not declare.\n", p.Branch)
+	}
+	return buffer.String()
+}
+
+// String returns a one line description of the change
+func (change PolicyChange) String() string {
+	prefix := map[string]string{ChangeCreate: "+", ChangeDelete: "-"}[change.Action]
+	return fmt.Sprintf("%s %s", prefix, change.ID)
+}
@@ -0,0 +1,254 @@
+package conjur
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cyberark/conjur-api-go/conjurapi"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/credstore"
+)
+
+const (
+	// tokenRefreshMargin is how long before it expires a cached access token is replaced
+	tokenRefreshMargin = time.Minute
+	// defaultTokenLifetime is the lifetime of access tokens without an expiration claim
+	defaultTokenLifetime = 8 * time.Minute
+)
+
+// cachedToken is a conjur access token saved in the credential store. Identifier is the
+// identifier of the credential the token was retrieved with, e.g. of a JWT, so a token is not
+// reused for another credential
+type cachedToken struct {
+	Token      string    `json:"token"`
+	Expires    time.Time `json:"expires"`
+	Identifier string    `json:"identifier,omitempty"`
+}
+
+// tokenCacheKey is the credential store key of the access token of login, authenticated
+// with the authenticator at authnURL. There is one key per login, so the token of a new
+// credential replaces the token of the previous one instead of accumulating in the store
+func tokenCacheKey(authnURL string, account string, login string) string {
+	return fmt.Sprintf("conjur-token/%s/%s/%s", strings.TrimSuffix(strings.TrimSpace(authnURL), "/"), account, login)
+}
+
+// decodeTokenPart decodes a part of an access token, encoded as base64url or base64 with or without padding
+func decodeTokenPart(part string) ([]byte, error) {
+	part = strings.TrimRight(part, "=")
+	content, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		content, err = base64.RawStdEncoding.DecodeString(part)
+	}
+	return content, err
+}
+
+// tokenExpiry returns when an access token expires, from its exp claim or its issue time
+func tokenExpiry(token []byte) (time.Time, error) {
+	parts := struct {
+		Payload string `json:"payload"`
+	}{}
+	err := json.Unmarshal(token, &parts)
+	if err != nil || parts.Payload == "" {
+		return time.Time{}, fmt.Errorf("Failed to parse conjur access token")
+	}
+
+	payload, err := decodeTokenPart(parts.Payload)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Failed to decode conjur access token payload. %s", err)
+	}
+	claims := struct {
+		IssuedAt  int64 `json:"iat"`
+		ExpiresAt int64 `json:"exp"`
+	}{}
+	err = json.Unmarshal(payload, &claims)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Failed to parse conjur access token payload. %s", err)
+	}
+
+	if claims.ExpiresAt != 0 {
+		return time.Unix(claims.ExpiresAt, 0), nil
+	}
+	if claims.IssuedAt == 0 {
+		return time.Time{}, fmt.Errorf("Conjur access token has no issue time")
+	}
+	return time.Unix(claims.IssuedAt, 0).Add(defaultTokenLifetime), nil
+}
+
+// getCachedToken returns the access token cached for key and the credential identifier unless it
+// expires within tokenRefreshMargin
+func getCachedToken(store credstore.Store, key string, identifier string) ([]byte, bool) {
+	content, err := store.Get(key)
+	if err != nil {
+		return nil, false
+	}
+
+	cached := cachedToken{}
+	err = json.Unmarshal([]byte(content), &cached)
+	if err != nil || cached.Token == "" || cached.Identifier != identifier || time.Now().Add(tokenRefreshMargin).After(cached.Expires) {
+		return nil, false
+	}
+	return []byte(cached.Token), true
+}
+
+// cacheToken saves an access token with its expiry and credential identifier in the credential store
+func cacheToken(store credstore.Store, key string, identifier string, token []byte) error {
+	expires, err := tokenExpiry(token)
+	if err != nil {
+		return err
+	}
+
+	content, err := json.Marshal(cachedToken{Token: string(token), Expires: expires, Identifier: identifier})
+	if err != nil {
+		return err
+	}
+	return store.Set(key, string(content))
+}
+
+// getAccessToken returns the access token cached for key and the credential identifier, or
+// authenticates to conjur and caches the new access token. Tokens are only cached when the
+// credential store can be opened, as authenticating again is always possible
+func getAccessToken(key string, identifier string, authenticate func() ([]byte, error)) ([]byte, error) {
+	store, storeErr := credstore.GetStore()
+	if storeErr == nil {
+		if token, ok := getCachedToken(store, key, identifier); ok {
+			return token, nil
+		}
+	}
+
+	token, err := authenticate()
+	if err != nil {
+		return nil, err
+	}
+
+	if storeErr == nil {
+		cacheToken(store, key, identifier, token)
+	}
+	return token, nil
+}
+
+// tokenRefresher sends the requests of a conjur client with an access token it replaces shortly
+// before it expires, or when conjur rejects it, e.g. because a cached token was revoked. The
+// conjur client itself only knows the access token it was created with
+type tokenRefresher struct {
+	key          string
+	identifier   string
+	authenticate func() ([]byte, error)
+	transport    http.RoundTripper
+
+	token []byte
+	lock  sync.Mutex
+}
+
+// currentToken returns the access token of the refresher, replaced when it expires within
+// tokenRefreshMargin or when it is the rejected token
+func (r *tokenRefresher) currentToken(rejected []byte) ([]byte, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if rejected != nil && string(rejected) == string(r.token) {
+		// The cached token may be the one rejected, so it must not be reused
+		removeCachedToken(r.key)
+		r.token = nil
+	}
+	if r.token != nil {
+		expires, err := tokenExpiry(r.token)
+		if err == nil && time.Now().Add(tokenRefreshMargin).Before(expires) {
+			return r.token, nil
+		}
+	}
+
+	token, err := getAccessToken(r.key, r.identifier, r.authenticate)
+	if err != nil {
+		return nil, err
+	}
+	r.token = token
+	return token, nil
+}
+
+// send sends req with token in the authorization header
+func (r *tokenRefresher) send(req *http.Request, token []byte) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", fmt.Sprintf("Token token=\"%s\"", base64.StdEncoding.EncodeToString(token)))
+	return r.transport.RoundTrip(req)
+}
+
+// RoundTrip sends a request of the conjur client and sends it once more with a new access token
+// if it is rejected with 401 Unauthorized. Requests without an access token, such as the
+// authentication itself, are sent unchanged
+func (r *tokenRefresher) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !strings.HasPrefix(req.Header.Get("Authorization"), "Token ") {
+		return r.transport.RoundTrip(req)
+	}
+
+	token, err := r.currentToken(nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := r.send(req, token)
+	if err != nil || response.StatusCode != http.StatusUnauthorized {
+		return response, err
+	}
+
+	// A request body which cannot be read again cannot be sent once more
+	if req.Body != nil && req.GetBody == nil {
+		return response, nil
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return response, nil
+		}
+		req = req.Clone(req.Context())
+		req.Body = body
+	}
+
+	token, err = r.currentToken(token)
+	if err != nil {
+		return response, nil
+	}
+	response.Body.Close()
+	return r.send(req, token)
+}
+
+// newRefreshingClient returns a conjur client authenticated with the access token cached for key
+// and the credential identifier, or authenticating with authenticate. The access token is
+// replaced when it expires or is rejected
+func newRefreshingClient(config conjurapi.Config, key string, identifier string, authenticate func() ([]byte, error)) (*conjurapi.Client, error) {
+	refresher := &tokenRefresher{key: key, identifier: identifier, authenticate: authenticate}
+	token, err := refresher.currentToken(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := conjurapi.NewClientFromToken(config, string(token))
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := *client.GetHttpClient()
+	refresher.transport = httpClient.Transport
+	if refresher.transport == nil {
+		refresher.transport = http.DefaultTransport
+	}
+	httpClient.Transport = refresher
+	client.SetHttpClient(&httpClient)
+	return client, nil
+}
+
+// removeCachedToken removes the access token cached for key
+func removeCachedToken(key string) error {
+	store, err := credstore.GetStore()
+	if err != nil {
+		return err
+	}
+
+	err = store.Delete(key)
+	if err != nil {
+		return fmt.Errorf("Failed to remove conjur access token from the %s credential store. %s", store.Name(), err)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package conjur
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cyberark/conjur-api-go/conjurapi"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/credstore"
+)
+
+// newToken returns a conjur access token with the claims of payload
+func newToken(payload string) []byte {
+	return []byte(fmt.Sprintf(`{"protected":"eyJhbGciOiJjb25qdXIub3JnL3Nsb3NpbG8vdjIifQ==","payload":"%s","signature":"c2ln"}`,
+		base64.RawURLEncoding.EncodeToString([]byte(payload))))
+}
+
+func setTempHome(t *testing.T) {
+	oldEnv := map[string]string{}
+	for _, key := range []string{"HOME", credstore.StoreEnvKey, credstore.PassphraseEnvKey} {
+		oldEnv[key] = os.Getenv(key)
+	}
+	os.Setenv("HOME", t.TempDir())
+	os.Setenv(credstore.StoreEnvKey, credstore.FileBackend)
+	os.Setenv(credstore.PassphraseEnvKey, "passphrase")
+	t.Cleanup(func() {
+		for key, value := range oldEnv {
+			os.Setenv(key, value)
+		}
+	})
+}
+
+func TestTokenExpiry(t *testing.T) {
+	expires, err := tokenExpiry(newToken(`{"sub":"admin","iat":1600000000,"exp":1600000300}`))
+	if err != nil || expires.Unix() != 1600000300 {
+		t.Errorf("Expected the expiration claim. %s %v", expires, err)
+	}
+
+	expires, err = tokenExpiry(newToken(`{"sub":"admin","iat":1600000000}`))
+	if err != nil || !expires.Equal(time.Unix(1600000000, 0).Add(defaultTokenLifetime)) {
+		t.Errorf("Expected the default lifetime from the issue time. %s %v", expires, err)
+	}
+
+	_, err = tokenExpiry([]byte("not a token"))
+	if err == nil {
+		t.Errorf("Expected an invalid token to fail")
+	}
+}
+
+func TestGetAccessTokenCachesUntilExpiry(t *testing.T) {
+	setTempHome(t)
+
+	authentications := 0
+	issuedAt := time.Now().Unix()
+	authenticate := func() ([]byte, error) {
+		authentications++
+		return newToken(fmt.Sprintf(`{"sub":"admin","iat":%d,"n":%d}`, issuedAt, authentications)), nil
+	}
+
+	key := tokenCacheKey("https://conjur.example.com/authn", "demo", "admin")
+	first, err := getAccessToken(key, "", authenticate)
+	if err != nil {
+		t.Fatalf("Failed to get access token. %s", err)
+	}
+	second, err := getAccessToken(key, "", authenticate)
+	if err != nil || string(second) != string(first) || authentications != 1 {
+		t.Errorf("Expected the cached access token to be reused. %d authentications. %v", authentications, err)
+	}
+
+	other, err := getAccessToken(tokenCacheKey("https://conjur.example.com/authn", "demo", "host/app"), "", authenticate)
+	if err != nil || string(other) == string(first) || authentications != 2 {
+		t.Errorf("Expected another login to authenticate. %d authentications. %v", authentications, err)
+	}
+
+	// A token expiring within the refresh margin is replaced
+	issuedAt = time.Now().Add(-defaultTokenLifetime + tokenRefreshMargin/2).Unix()
+	err = removeCachedToken(key)
+	if err != nil {
+		t.Fatalf("Failed to remove cached access token. %s", err)
+	}
+	getAccessToken(key, "", authenticate)
+	getAccessToken(key, "", authenticate)
+	if authentications != 4 {
+		t.Errorf("Expected a token about to expire to be refreshed. %d authentications", authentications)
+	}
+}
+
+func TestGetAccessTokenReplacesTokenOfOtherCredential(t *testing.T) {
+	setTempHome(t)
+
+	authentications := 0
+	authenticate := func() ([]byte, error) {
+		authentications++
+		return newToken(fmt.Sprintf(`{"sub":"host/app","iat":%d,"n":%d}`, time.Now().Unix(), authentications)), nil
+	}
+
+	key := tokenCacheKey("https://conjur.example.com/authn-jwt/k8s", "demo", "")
+	first, _ := getAccessToken(key, "jwt1", authenticate)
+	second, err := getAccessToken(key, "jwt2", authenticate)
+	if err != nil || string(second) == string(first) || authentications != 2 {
+		t.Errorf("Expected another credential to authenticate. %d authentications. %v", authentications, err)
+	}
+
+	store, err := credstore.GetStore()
+	if err != nil {
+		t.Fatalf("Failed to open credential store. %s", err)
+	}
+	if _, ok := getCachedToken(store, key, "jwt1"); ok {
+		t.Errorf("Expected the token of the previous credential to be replaced")
+	}
+	if cached, ok := getCachedToken(store, key, "jwt2"); !ok || string(cached) != string(second) {
+		t.Errorf("Expected the token of the current credential to be cached")
+	}
+}
+
+func TestRefreshingClientReplacesRejectedToken(t *testing.T) {
+	setTempHome(t)
+
+	// conjur-api-go only parses access tokens encoded as base64 with padding
+	issuedAt := time.Now().Unix()
+	token := func(n int) []byte {
+		payload := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf(`{"sub":"admin","iat":%d,"n":%d}`, issuedAt, n)))
+		return []byte(fmt.Sprintf(`{"protected":"eyJhbGciOiJjb25qdXIub3JnL3Nsb3NpbG8vdjIifQ==","payload":"%s","signature":"c2ln"}`, payload))
+	}
+	authentications := 0
+	authenticate := func() ([]byte, error) {
+		authentications++
+		return token(authentications), nil
+	}
+	accepted := fmt.Sprintf(`Token token="%s"`, base64.StdEncoding.EncodeToString(token(2)))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != accepted {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("secret"))
+	}))
+	defer server.Close()
+
+	// The cached token was revoked, e.g. by conjur logoff in another shell
+	key := tokenCacheKey(server.URL+"/authn", "demo", "admin")
+	getAccessToken(key, "", authenticate)
+
+	client, err := newRefreshingClient(conjurapi.Config{Account: "demo", ApplianceURL: server.URL}, key, "", authenticate)
+	if err != nil {
+		t.Fatalf("Failed to create client. %s", err)
+	}
+	secret, err := client.RetrieveSecret("db/password")
+	if err != nil || string(secret) != "secret" || authentications != 2 {
+		t.Fatalf("Expected the request to be sent again with a new token. %d authentications. %v", authentications, err)
+	}
+
+	cached, _ := getAccessToken(key, "", authenticate)
+	if !strings.Contains(accepted, base64.StdEncoding.EncodeToString(cached)) || authentications != 2 {
+		t.Errorf("Expected the new token to replace the cached token. %d authentications", authentications)
+	}
+}
@@ -8,14 +8,14 @@ import (
 
 // Whoami gets current user info logged in to Conjur
 func Whoami() (map[string]interface{}, error) {
-	client, loginPair, err := GetConjurClient()
+	client, _, err := GetConjurClient()
 	if err != nil {
 		return nil, fmt.Errorf("Failed to initialize conjur client. %s", err)
 	}
 
 	config := client.GetConfig()
 	url := fmt.Sprintf("%s/whoami", config.ApplianceURL)
-	resp, err := sendConjurAuthenticatedHTTPRequest(client, loginPair, url, "GET", nil)
+	resp, err := sendConjurAuthenticatedHTTPRequest(client, url, "GET", nil)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to get logged in user. %s", err)
 	}
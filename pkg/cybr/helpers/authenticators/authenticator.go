@@ -1,7 +1,13 @@
 package authenticators
 
 import (
-	"github.com/cyberark/conjur-api-go/conjurapi"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
 )
 
 // Config holds the configuration for the Conjur authenticator
@@ -10,14 +16,21 @@ type Config struct {
 	ApplianceURL    string
 	Login           string
 	ServiceID       string
+	CertFile        string
 	IgnoreSSLVerify bool
 }
 
-// Authenticator is used to retrieve the Conjur client.
+// Authenticator is used to retrieve a Conjur access token.
 // This is required because authn uses username and password to authenticate to Conjur, while authn-iam uses a token.
 type Authenticator interface {
 	Name() string
-	Authenticate(config Config) (*conjurapi.Client, error)
+	Authenticate(config Config) ([]byte, error)
+}
+
+// Identifier is implemented by authenticators whose credential, rather than the login, determines
+// the Conjur identity. A cached access token is only reused for the credential with the same identifier
+type Identifier interface {
+	Identifier() string
 }
 
 // GetAuthURL returns a proper LDAP Authentication authn_url for the ~/.conjurrc file
@@ -31,3 +44,81 @@ func GetAuthURL(baseURL string, authType string, serviceID string) string {
 	}
 	return authURL
 }
+
+// ReadCertFile returns the content of the PEM encoded certificate file of the Conjur service, or
+// nothing if no file is configured
+func ReadCertFile(certFile string) ([]byte, error) {
+	if certFile == "" {
+		return nil, nil
+	}
+
+	cert, err := ioutil.ReadFile(certFile)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read Conjur certificate file '%s'. %s", certFile, err)
+	}
+	return cert, nil
+}
+
+// NewTLSConfig returns a TLS configuration trusting cert, a PEM encoded certificate, or the system
+// certificates if cert is empty. Certificates are not verified at all if ignoreSSLVerify is true
+func NewTLSConfig(ignoreSSLVerify bool, cert []byte) (*tls.Config, error) {
+	if ignoreSSLVerify {
+		return &tls.Config{InsecureSkipVerify: true}, nil
+	}
+
+	// If not certificate provided do not create a certifictae pool
+	if len(cert) == 0 {
+		return &tls.Config{}, nil
+	}
+
+	// certificate is provided so create pool and append to TLSClientConfig
+	pool := x509.NewCertPool()
+	ok := pool.AppendCertsFromPEM(cert)
+	if !ok {
+		return nil, fmt.Errorf("Can't append Conjur SSL cert")
+	}
+	return &tls.Config{RootCAs: pool}, nil
+}
+
+// NewHTTPSClient returns an HTTP client using the TLS configuration of NewTLSConfig
+func NewHTTPSClient(ignoreSSLVerify bool, cert []byte) (*http.Client, error) {
+	tlsConfig, err := NewTLSConfig(ignoreSSLVerify, cert)
+	if err != nil {
+		return nil, err
+	}
+
+	tr := &http.Transport{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: tlsConfig,
+	}
+	return &http.Client{Transport: tr, Timeout: time.Second * 10}, nil
+}
+
+// PostAuthenticate sends form to the Conjur authenticate url and returns the access token
+func PostAuthenticate(config Config, authenticateURL string, form url.Values) ([]byte, error) {
+	cert, err := ReadCertFile(config.CertFile)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := NewHTTPSClient(config.IgnoreSSLVerify, cert)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create a new HTTPS client. %s", err)
+	}
+
+	response, err := client.PostForm(authenticateURL, form)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to establish connection to Conjur at url '%s'. %s", authenticateURL, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != 200 {
+		return nil, fmt.Errorf("Failed to authenticate to Conjur. Received status code '%v'", response.StatusCode)
+	}
+
+	accessToken, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read Conjur Access Token %s", err)
+	}
+	return accessToken, nil
+}
@@ -0,0 +1,58 @@
+package credstore
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+
+	"filippo.io/age"
+)
+
+// The encrypted file backend uses the age v1 format (https://age-encryption.org/v1)
+// with a single scrypt recipient so the file can also be opened with 'age -d'
+
+// ageMaxWorkFactor is the highest scrypt work factor accepted when decrypting
+const ageMaxWorkFactor = 22
+
+var errIncorrectPassphrase = errors.New("incorrect passphrase")
+
+func ageEncrypt(plaintext []byte, passphrase string, workFactor int) ([]byte, error) {
+	recipient, err := age.NewScryptRecipient(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	recipient.SetWorkFactor(workFactor)
+
+	buffer := &bytes.Buffer{}
+	writer, err := age.Encrypt(buffer, recipient)
+	if err != nil {
+		return nil, err
+	}
+	_, err = writer.Write(plaintext)
+	if err != nil {
+		return nil, err
+	}
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func ageDecrypt(data []byte, passphrase string) ([]byte, error) {
+	identity, err := age.NewScryptIdentity(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	identity.SetMaxWorkFactor(ageMaxWorkFactor)
+
+	reader, err := age.Decrypt(bytes.NewReader(data), identity)
+	var noMatch *age.NoIdentityMatchError
+	if errors.As(err, &noMatch) {
+		return nil, errIncorrectPassphrase
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(reader)
+}
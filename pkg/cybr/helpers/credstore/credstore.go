@@ -0,0 +1,91 @@
+package credstore
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/util"
+)
+
+const (
+	// StoreEnvKey is the environment variable used to select the credential store backend
+	StoreEnvKey = "CYBR_CREDENTIAL_STORE"
+	// PassphraseEnvKey is the environment variable holding the passphrase of the encrypted file backend
+	PassphraseEnvKey = "CYBR_STORE_PASSPHRASE"
+
+	// SecretServiceBackend stores credentials in the freedesktop Secret Service (GNOME Keyring, KWallet)
+	SecretServiceBackend = "secret-service"
+	// FileBackend stores credentials in a passphrase protected file
+	FileBackend = "file"
+	// PlaintextBackend stores credentials in a file only readable by the current user
+	PlaintextBackend = "plaintext"
+)
+
+// ErrNotFound is returned when no credential is stored for the requested key
+var ErrNotFound = errors.New("credential not found")
+
+// Store persists secrets such as session tokens and API keys outside of the
+// plaintext configuration files
+type Store interface {
+	// Name of the backend
+	Name() string
+	// Get returns the secret stored for key or ErrNotFound
+	Get(key string) (string, error)
+	// Set creates or replaces the secret stored for key
+	Set(key string, secret string) error
+	// Delete removes the secret stored for key. Deleting a missing key is not an error
+	Delete(key string) error
+}
+
+// GetStore returns the credential store selected by the CYBR_CREDENTIAL_STORE
+// environment variable. When not set, the Secret Service is used if available
+// and the plaintext file otherwise. The encrypted file prompts for a passphrase,
+// so it is only used when selected
+func GetStore() (Store, error) {
+	backend := strings.ToLower(strings.TrimSpace(os.Getenv(StoreEnvKey)))
+	if backend == "" {
+		backend = PlaintextBackend
+		if SecretServiceAvailable() {
+			backend = SecretServiceBackend
+		}
+	}
+
+	switch backend {
+	case SecretServiceBackend:
+		return NewSecretService(), nil
+	case FileBackend:
+		return NewFile()
+	case PlaintextBackend:
+		return NewPlaintext()
+	}
+
+	return nil, fmt.Errorf("Invalid credential store '%s'. Valid values: %s, %s, %s", backend, SecretServiceBackend, FileBackend, PlaintextBackend)
+}
+
+// SecretServiceAvailable returns true if a D-Bus session and the secret-tool command are available
+func SecretServiceAvailable() bool {
+	if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+		return false
+	}
+	_, err := exec.LookPath(secretToolCommand)
+	return err == nil
+}
+
+// GetConfigDir returns the ~/.cybr directory and creates it if it does not exist
+func GetConfigDir() (string, error) {
+	userHome, err := util.GetUserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("ACL error. %s", err)
+	}
+
+	configDir := filepath.Join(userHome, ".cybr")
+	err = util.CreatePrivateDir(configDir)
+	if err != nil {
+		return "", err
+	}
+	return configDir, nil
+}
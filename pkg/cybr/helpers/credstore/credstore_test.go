@@ -0,0 +1,186 @@
+package credstore_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/credstore"
+)
+
+// secretToolStandIn mimics the store, lookup and clear commands of secret-tool
+// by saving each item as a file in the directory the script is located in
+const secretToolStandIn = `#!/bin/sh
+dir=$(dirname "$0")
+cmd=$1
+shift
+if [ "$cmd" = "store" ]; then
+	shift 2
+fi
+item="$dir/item_$(echo "$@" | tr ' /' '__')"
+case "$cmd" in
+	store) cat > "$item" ;;
+	lookup) [ -f "$item" ] || exit 1; cat "$item" ;;
+	clear) rm -f "$item" ;;
+	*) echo "unknown command $cmd" >&2; exit 2 ;;
+esac
+`
+
+func testStore(t *testing.T, store credstore.Store) {
+	_, err := store.Get("pas/default")
+	if err != credstore.ErrNotFound {
+		t.Errorf("Expected ErrNotFound from %s store but got '%v'", store.Name(), err)
+	}
+
+	err = store.Set("pas/default", "token-1")
+	if err != nil {
+		t.Errorf("Failed to set secret in %s store. %s", store.Name(), err)
+	}
+	err = store.Set("pas/default", "token-2\n")
+	if err != nil {
+		t.Errorf("Failed to replace secret in %s store. %s", store.Name(), err)
+	}
+	err = store.Set("cem/cem.config", "cem-token")
+	if err != nil {
+		t.Errorf("Failed to set secret in %s store. %s", store.Name(), err)
+	}
+
+	secret, err := store.Get("pas/default")
+	if err != nil || secret != "token-2\n" {
+		t.Errorf("Expected 'token-2' from %s store but got '%s'. %v", store.Name(), secret, err)
+	}
+
+	err = store.Delete("pas/default")
+	if err != nil {
+		t.Errorf("Failed to delete secret from %s store. %s", store.Name(), err)
+	}
+	err = store.Delete("pas/default")
+	if err != nil {
+		t.Errorf("Deleting a missing secret from %s store should not fail. %s", store.Name(), err)
+	}
+	_, err = store.Get("pas/default")
+	if err != credstore.ErrNotFound {
+		t.Errorf("Expected ErrNotFound from %s store after delete but got '%v'", store.Name(), err)
+	}
+
+	secret, err = store.Get("cem/cem.config")
+	if err != nil || secret != "cem-token" {
+		t.Errorf("Expected 'cem-token' from %s store but got '%s'. %v", store.Name(), secret, err)
+	}
+}
+
+func newFileStore(t *testing.T, passphrase string) *credstore.File {
+	return &credstore.File{
+		Path:       filepath.Join(t.TempDir(), "credentials.age"),
+		WorkFactor: 10,
+		Passphrase: func(confirm bool) (string, error) {
+			return passphrase, nil
+		},
+	}
+}
+
+func TestSecretServiceStore(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("secret-tool stand-in requires a POSIX shell")
+	}
+
+	command := filepath.Join(t.TempDir(), "secret-tool")
+	err := ioutil.WriteFile(command, []byte(secretToolStandIn), 0700)
+	if err != nil {
+		t.Fatalf("Failed to write secret-tool stand-in. %s", err)
+	}
+
+	store := credstore.NewSecretService()
+	store.Command = command
+	testStore(t, store)
+}
+
+func TestSecretServiceStoreCommandFailure(t *testing.T) {
+	store := credstore.NewSecretService()
+	store.Command = filepath.Join(t.TempDir(), "missing-secret-tool")
+
+	_, err := store.Get("pas/default")
+	if err == nil || err == credstore.ErrNotFound {
+		t.Errorf("Expected an error when secret-tool cannot be executed but got '%v'", err)
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	store := newFileStore(t, "correct horse battery staple")
+	testStore(t, store)
+
+	content, err := ioutil.ReadFile(store.Path)
+	if err != nil {
+		t.Fatalf("Failed to read encrypted file. %s", err)
+	}
+	if !strings.HasPrefix(string(content), "age-encryption.org/v1\n-> scrypt ") {
+		t.Errorf("Encrypted file is not in the age scrypt format")
+	}
+	if strings.Contains(string(content), "cem-token") {
+		t.Errorf("Encrypted file contains the secret in plaintext")
+	}
+
+	info, err := os.Stat(store.Path)
+	if err == nil && runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+		t.Errorf("Encrypted file should have permissions 0600 but has %s", info.Mode().Perm())
+	}
+}
+
+func TestFileStoreIncorrectPassphrase(t *testing.T) {
+	store := newFileStore(t, "passphrase")
+	err := store.Set("pas/default", "token")
+	if err != nil {
+		t.Fatalf("Failed to set secret in file store. %s", err)
+	}
+
+	store.Passphrase = func(confirm bool) (string, error) {
+		return "incorrect", nil
+	}
+	_, err = store.Get("pas/default")
+	if err == nil || !strings.Contains(err.Error(), "incorrect passphrase") {
+		t.Errorf("Expected incorrect passphrase error but got '%v'", err)
+	}
+}
+
+func TestPlaintextStore(t *testing.T) {
+	warning := &bytes.Buffer{}
+	store := &credstore.Plaintext{Path: filepath.Join(t.TempDir(), "credentials.json"), Warning: warning}
+	testStore(t, store)
+
+	if strings.Count(warning.String(), "Warning:") != 1 {
+		t.Errorf("Expected one warning when the file is created but got '%s'", warning.String())
+	}
+
+	info, err := os.Stat(store.Path)
+	if err == nil && runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+		t.Errorf("Plaintext file should have permissions 0600 but has %s", info.Mode().Perm())
+	}
+}
+
+func TestGetStoreDefaultsToPlaintext(t *testing.T) {
+	for _, key := range []string{"HOME", "DBUS_SESSION_BUS_ADDRESS", credstore.StoreEnvKey} {
+		defer os.Setenv(key, os.Getenv(key))
+	}
+	os.Setenv("HOME", t.TempDir())
+	os.Unsetenv("DBUS_SESSION_BUS_ADDRESS")
+	os.Unsetenv(credstore.StoreEnvKey)
+
+	store, err := credstore.GetStore()
+	if err != nil || store.Name() != credstore.PlaintextBackend {
+		t.Errorf("Expected the plaintext store without the Secret Service but got %v. %v", store, err)
+	}
+}
+
+func TestGetStoreInvalidBackend(t *testing.T) {
+	os.Setenv(credstore.StoreEnvKey, "invalid")
+	defer os.Unsetenv(credstore.StoreEnvKey)
+
+	_, err := credstore.GetStore()
+	if err == nil {
+		t.Errorf("Credential store 'invalid' is not valid however it was accepted")
+	}
+}
@@ -0,0 +1,194 @@
+package credstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	terminal "golang.org/x/term"
+)
+
+// DefaultWorkFactor is the scrypt work factor (log2 of N) used when encrypting the credential file
+const DefaultWorkFactor = 16
+
+// cachedPassphrase avoids prompting more than once per command
+var cachedPassphrase string
+
+// File stores credentials as JSON in a file encrypted with a passphrase
+type File struct {
+	// Path of the encrypted file
+	Path string
+	// WorkFactor is the scrypt work factor used when encrypting
+	WorkFactor int
+	// Passphrase returns the passphrase of the file. confirm is true when the
+	// file is about to be created
+	Passphrase func(confirm bool) (string, error)
+}
+
+// NewFile returns an encrypted file store located at ~/.cybr/credentials.age
+func NewFile() (*File, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return &File{
+		Path:       filepath.Join(configDir, "credentials.age"),
+		WorkFactor: DefaultWorkFactor,
+		Passphrase: readPassphrase,
+	}, nil
+}
+
+func readPassphrase(confirm bool) (string, error) {
+	if passphrase := os.Getenv(PassphraseEnvKey); passphrase != "" {
+		return passphrase, nil
+	}
+	if cachedPassphrase != "" {
+		return cachedPassphrase, nil
+	}
+	if !terminal.IsTerminal(int(os.Stdin.Fd())) {
+		return "", fmt.Errorf("%s must be set to unlock the credential store when not running in a terminal", PassphraseEnvKey)
+	}
+
+	fmt.Fprint(os.Stderr, "Enter credential store passphrase: ")
+	passphrase, err := terminal.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("An error occurred trying to read passphrase from Stdin. %s", err)
+	}
+	if len(passphrase) == 0 {
+		return "", fmt.Errorf("Provided passphrase is empty")
+	}
+
+	if confirm {
+		fmt.Fprint(os.Stderr, "Confirm credential store passphrase: ")
+		confirmation, err := terminal.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("An error occurred trying to read passphrase from Stdin. %s", err)
+		}
+		if string(confirmation) != string(passphrase) {
+			return "", fmt.Errorf("Provided passphrases do not match")
+		}
+	}
+
+	cachedPassphrase = string(passphrase)
+	return cachedPassphrase, nil
+}
+
+// Name of the backend
+func (f *File) Name() string {
+	return FileBackend
+}
+
+func (f *File) load() (map[string]string, string, error) {
+	credentials := map[string]string{}
+
+	data, err := ioutil.ReadFile(f.Path)
+	if os.IsNotExist(err) {
+		return credentials, "", nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("Failed to read credential store '%s'. %s", f.Path, err)
+	}
+
+	passphrase, err := f.Passphrase(false)
+	if err != nil {
+		return nil, "", err
+	}
+
+	plaintext, err := ageDecrypt(data, passphrase)
+	if err != nil {
+		return nil, "", fmt.Errorf("Failed to decrypt credential store '%s'. %s", f.Path, err)
+	}
+
+	err = json.Unmarshal(plaintext, &credentials)
+	if err != nil {
+		return nil, "", fmt.Errorf("Failed to parse credential store '%s'. %s", f.Path, err)
+	}
+
+	return credentials, passphrase, nil
+}
+
+func (f *File) save(credentials map[string]string, passphrase string) error {
+	plaintext, err := json.Marshal(credentials)
+	if err != nil {
+		return err
+	}
+
+	workFactor := f.WorkFactor
+	if workFactor == 0 {
+		workFactor = DefaultWorkFactor
+	}
+	data, err := ageEncrypt(plaintext, passphrase, workFactor)
+	if err != nil {
+		return fmt.Errorf("Failed to encrypt credential store. %s", err)
+	}
+
+	// Write to a temporary file first so an interrupted write never corrupts the store
+	tmpPath := f.Path + ".tmp"
+	err = ioutil.WriteFile(tmpPath, data, 0600)
+	if err != nil {
+		return fmt.Errorf("Failed to write credential store '%s'. %s", tmpPath, err)
+	}
+	err = os.Rename(tmpPath, f.Path)
+	if err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("Failed to write credential store '%s'. %s", f.Path, err)
+	}
+
+	return nil
+}
+
+// Get returns the secret stored for key or ErrNotFound
+func (f *File) Get(key string) (string, error) {
+	credentials, _, err := f.load()
+	if err != nil {
+		return "", err
+	}
+
+	secret, ok := credentials[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+// Set creates or replaces the secret stored for key
+func (f *File) Set(key string, secret string) error {
+	credentials, passphrase, err := f.load()
+	if err != nil {
+		return err
+	}
+
+	// The file does not exist yet
+	if passphrase == "" {
+		passphrase, err = f.Passphrase(true)
+		if err != nil {
+			return err
+		}
+	}
+
+	credentials[key] = secret
+	return f.save(credentials, passphrase)
+}
+
+// Delete removes the secret stored for key
+func (f *File) Delete(key string) error {
+	if _, err := os.Stat(f.Path); os.IsNotExist(err) {
+		return nil
+	}
+
+	credentials, passphrase, err := f.load()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := credentials[key]; !ok {
+		return nil
+	}
+	delete(credentials, key)
+	return f.save(credentials, passphrase)
+}
@@ -0,0 +1,118 @@
+package credstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Plaintext stores credentials as JSON in a file only readable by the current user, like the
+// configuration files of previous versions. It is used when no other backend is available, so
+// commands keep working without a keyring or a passphrase
+type Plaintext struct {
+	// Path of the file
+	Path string
+	// Warning receives a warning when the file is created
+	Warning io.Writer
+}
+
+// NewPlaintext returns a plaintext file store located at ~/.cybr/credentials.json
+func NewPlaintext() (*Plaintext, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Plaintext{
+		Path:    filepath.Join(configDir, "credentials.json"),
+		Warning: os.Stderr,
+	}, nil
+}
+
+// Name of the backend
+func (p *Plaintext) Name() string {
+	return PlaintextBackend
+}
+
+func (p *Plaintext) load() (map[string]string, error) {
+	credentials := map[string]string{}
+	content, err := ioutil.ReadFile(p.Path)
+	if os.IsNotExist(err) {
+		return credentials, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read credential store '%s'. %s", p.Path, err)
+	}
+
+	err = json.Unmarshal(content, &credentials)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse credential store '%s'. %s", p.Path, err)
+	}
+	return credentials, nil
+}
+
+func (p *Plaintext) save(credentials map[string]string) error {
+	if _, err := os.Stat(p.Path); os.IsNotExist(err) && p.Warning != nil {
+		fmt.Fprintf(p.Warning, "Warning: The Secret Service is not available. Credentials are saved unencrypted in '%s'. Set %s=%s to encrypt them with a passphrase.\n", p.Path, StoreEnvKey, FileBackend)
+	}
+
+	data, err := json.Marshal(credentials)
+	if err != nil {
+		return fmt.Errorf("Failed to encode credential store. %s", err)
+	}
+
+	// Write to a temporary file first so an interrupted write never corrupts the store
+	tmpPath := p.Path + ".tmp"
+	err = ioutil.WriteFile(tmpPath, data, 0600)
+	if err != nil {
+		return fmt.Errorf("Failed to write credential store '%s'. %s", tmpPath, err)
+	}
+	err = os.Rename(tmpPath, p.Path)
+	if err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("Failed to write credential store '%s'. %s", p.Path, err)
+	}
+	return nil
+}
+
+// Get returns the secret stored for key or ErrNotFound
+func (p *Plaintext) Get(key string) (string, error) {
+	credentials, err := p.load()
+	if err != nil {
+		return "", err
+	}
+
+	secret, ok := credentials[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+// Set creates or replaces the secret stored for key
+func (p *Plaintext) Set(key string, secret string) error {
+	credentials, err := p.load()
+	if err != nil {
+		return err
+	}
+
+	credentials[key] = secret
+	return p.save(credentials)
+}
+
+// Delete removes the secret stored for key
+func (p *Plaintext) Delete(key string) error {
+	credentials, err := p.load()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := credentials[key]; !ok {
+		return nil
+	}
+	delete(credentials, key)
+	return p.save(credentials)
+}
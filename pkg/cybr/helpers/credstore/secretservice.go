@@ -0,0 +1,92 @@
+package credstore
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+const (
+	secretToolCommand = "secret-tool"
+	secretServiceName = "cybr-cli"
+)
+
+// SecretService stores credentials in the freedesktop Secret Service using the
+// secret-tool command provided by libsecret
+type SecretService struct {
+	// Command is the secret-tool executable
+	Command string
+	// Service is the value of the 'service' attribute set on every item
+	Service string
+}
+
+// NewSecretService returns a Secret Service store using secret-tool from PATH
+func NewSecretService() *SecretService {
+	return &SecretService{
+		Command: secretToolCommand,
+		Service: secretServiceName,
+	}
+}
+
+// Name of the backend
+func (s *SecretService) Name() string {
+	return SecretServiceBackend
+}
+
+func (s *SecretService) run(stdin string, args ...string) (string, error) {
+	cmd := exec.Command(s.Command, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && stderr.Len() == 0 && stdout.Len() == 0 {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("Failed to run '%s %s'. %s %s", s.Command, args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
+
+func (s *SecretService) attributes(key string) []string {
+	return []string{"service", s.Service, "account", key}
+}
+
+// Get returns the secret stored for key or ErrNotFound
+func (s *SecretService) Get(key string) (string, error) {
+	args := append([]string{"lookup"}, s.attributes(key)...)
+	secret, err := s.run("", args...)
+	if err != nil {
+		return "", err
+	}
+	if secret == "" {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+// Set creates or replaces the secret stored for key
+func (s *SecretService) Set(key string, secret string) error {
+	args := append([]string{"store", "--label", fmt.Sprintf("%s: %s", s.Service, key)}, s.attributes(key)...)
+	_, err := s.run(secret, args...)
+	if err == ErrNotFound {
+		return fmt.Errorf("Failed to store '%s' in the Secret Service", key)
+	}
+	return err
+}
+
+// Delete removes the secret stored for key
+func (s *SecretService) Delete(key string) error {
+	args := append([]string{"clear"}, s.attributes(key)...)
+	_, err := s.run("", args...)
+	if err == ErrNotFound {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,103 @@
+package httpjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// requestIDHeaders are the response headers checked for an identifier of the failed request
+var requestIDHeaders = []string{"X-Request-Id", "X-Correlation-Id", "Request-Id"}
+
+// APIError is returned when a request receives a non-2xx status code. The error code,
+// message and details are decoded from the response body when it contains them
+type APIError struct {
+	StatusCode int         `json:"statusCode"`
+	ErrorCode  string      `json:"errorCode,omitempty"`
+	Message    string      `json:"message,omitempty"`
+	Details    interface{} `json:"details,omitempty"`
+	RequestID  string      `json:"requestID,omitempty"`
+	Method     string      `json:"method,omitempty"`
+	URL        string      `json:"url,omitempty"`
+}
+
+// errorBody contains the error fields returned by PAS (ErrorCode, ErrorMessage, Details),
+// Identity (Message) and OAuth2 (error, error_description) endpoints
+type errorBody struct {
+	ErrorCode        string          `json:"ErrorCode"`
+	ErrorMessage     string          `json:"ErrorMessage"`
+	Message          string          `json:"Message"`
+	Details          json.RawMessage `json:"Details"`
+	Error            string          `json:"error"`
+	ErrorDescription string          `json:"error_description"`
+}
+
+func (e *APIError) Error() string {
+	message := fmt.Sprintf("Received non-200 status code '%d'", e.StatusCode)
+	switch {
+	case e.ErrorCode != "" && e.Message != "":
+		message = fmt.Sprintf("%s. %s: %s", message, e.ErrorCode, e.Message)
+	case e.ErrorCode != "":
+		message = fmt.Sprintf("%s. %s", message, e.ErrorCode)
+	case e.Message != "":
+		message = fmt.Sprintf("%s. %s", message, e.Message)
+	}
+	return message
+}
+
+// newAPIError reads the body of res to create an APIError. The body is replaced so
+// it can still be read by the caller
+func newAPIError(res *http.Response) *APIError {
+	apiError := &APIError{
+		StatusCode: res.StatusCode,
+	}
+	if res.Request != nil {
+		apiError.Method = res.Request.Method
+		apiError.URL = res.Request.URL.String()
+	}
+	for _, header := range requestIDHeaders {
+		if requestID := res.Header.Get(header); requestID != "" {
+			apiError.RequestID = requestID
+			break
+		}
+	}
+
+	if res.Body == nil {
+		return apiError
+	}
+	content, _ := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	res.Body = ioutil.NopCloser(bytes.NewReader(content))
+
+	body := errorBody{}
+	err := json.Unmarshal(content, &body)
+	if err != nil {
+		// Only plain text bodies are meaningful, HTML error pages are ignored
+		text := strings.TrimSpace(string(content))
+		if text != "" && !strings.HasPrefix(text, "<") && len(text) <= 512 {
+			apiError.Message = text
+		}
+		return apiError
+	}
+
+	apiError.ErrorCode = body.ErrorCode
+	apiError.Message = body.ErrorMessage
+	if apiError.Message == "" {
+		apiError.Message = body.Message
+	}
+	if apiError.ErrorCode == "" && body.Error != "" {
+		apiError.ErrorCode = body.Error
+		apiError.Message = body.ErrorDescription
+	}
+	if len(body.Details) > 0 && string(body.Details) != "null" {
+		var details interface{}
+		if json.Unmarshal(body.Details, &details) == nil {
+			apiError.Details = details
+		}
+	}
+
+	return apiError
+}
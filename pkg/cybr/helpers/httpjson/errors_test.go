@@ -0,0 +1,53 @@
+package httpjson_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/httpjson"
+)
+
+func TestAPIErrorDecodedFromPASResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "abc-123")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"ErrorCode":"PASWS167E","ErrorMessage":"There are some invalid parameters","Details":[{"ErrorCode":"CAWS00001E"}]}`))
+	}))
+	defer server.Close()
+
+	_, err := testTransport().Get(context.Background(), false, server.URL+"/passwordvault/api/Accounts/1", "", nil)
+
+	var apiError *httpjson.APIError
+	if !errors.As(err, &apiError) {
+		t.Fatalf("Expected an APIError but got '%v'", err)
+	}
+	if apiError.StatusCode != 404 || apiError.ErrorCode != "PASWS167E" || apiError.Message != "There are some invalid parameters" {
+		t.Errorf("Invalid APIError decoded. %+v", apiError)
+	}
+	if apiError.RequestID != "abc-123" || apiError.Method != http.MethodGet || apiError.Details == nil {
+		t.Errorf("Invalid APIError request details decoded. %+v", apiError)
+	}
+	if err.Error() != "Received non-200 status code '404'. PASWS167E: There are some invalid parameters" {
+		t.Errorf("Invalid APIError message '%s'", err)
+	}
+}
+
+func TestAPIErrorWithoutBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	_, err := testTransport().SendRequestRawWithHeaders(context.Background(), server.URL, http.MethodPost, http.Header{}, nil, nil)
+
+	var apiError *httpjson.APIError
+	if !errors.As(err, &apiError) || apiError.StatusCode != 403 {
+		t.Fatalf("Expected an APIError with status code 403 but got '%v'", err)
+	}
+	if err.Error() != "Received non-200 status code '403'" {
+		t.Errorf("Invalid APIError message '%s'", err)
+	}
+}
@@ -2,14 +2,14 @@ package httpjson
 
 import (
 	"bytes"
-	"crypto/tls"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"mime"
 	"net/http"
 	"strings"
-	"time"
 
 	"github.com/infamousjoeg/cybr-cli/pkg/logger"
 )
@@ -56,67 +56,80 @@ func logRequest(req *http.Request, logger logger.Logger) {
 	req.Body = ioutil.NopCloser(bytes.NewReader([]byte(body)))
 }
 
-func getResponse(identity bool, url string, method string, token string, body interface{}, insecureTLS bool, logger logger.Logger) (http.Response, error) {
-	if insecureTLS {
-		http.DefaultTransport.(*http.Transport).TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-	} else {
-		http.DefaultTransport.(*http.Transport).TLSClientConfig = &tls.Config{InsecureSkipVerify: false}
-	}
-	httpClient := http.Client{
-		Timeout: time.Second * 30, // Maximum of 30 secs
-	}
-	var bodyReader io.ReadCloser
-	var res *http.Response
-
+func (t *Transport) getResponse(ctx context.Context, identity bool, url string, method string, token string, body interface{}, logger logger.Logger) (http.Response, error) {
 	content, err := bodyToBytes(body)
 	if err != nil {
-		return *res, err
+		return http.Response{}, err
 	}
 
-	bodyReader = ioutil.NopCloser(bytes.NewReader(content))
-
 	// create the request
-	req, err := http.NewRequest(method, url, bodyReader)
+	req, err := http.NewRequest(method, url, bytes.NewReader(content))
 	if err != nil {
-		return *res, fmt.Errorf("Failed to create new request. %s", err)
+		return http.Response{}, fmt.Errorf("Failed to create new request. %s", err)
 	}
 
 	// attach the header
 	req.Header = make(http.Header)
 	req.Header.Add("Content-Type", "application/json")
+	return t.sendRequest(ctx, identity, req, token, logger)
+}
+
+// sendRequest adds the authorization headers of token to req and sends it. If the token is
+// rejected and the transport can reauthenticate, req is sent once more with a new token
+func (t *Transport) sendRequest(ctx context.Context, identity bool, req *http.Request, token string, logger logger.Logger) (http.Response, error) {
+	token = t.renewedToken(token)
+	res, err := t.sendRequestWithToken(ctx, identity, req, token, logger)
+	if err == nil || token == "" || t.Reauthenticate == nil || res.StatusCode != http.StatusUnauthorized {
+		return res, err
+	}
+	res.Body.Close()
+
+	if logger != nil && logger.Enabled() {
+		logger.Writef("Received status code '%d'. Reauthenticating\n", res.StatusCode)
+	}
+	renewed, renewErr := t.renewToken(ctx, token)
+	if renewErr != nil {
+		return http.Response{}, fmt.Errorf("%w. Failed to reauthenticate. %s", err, renewErr)
+	}
+	return t.sendRequestWithToken(ctx, identity, req, renewed, logger)
+}
+
+func (t *Transport) sendRequestWithToken(ctx context.Context, identity bool, req *http.Request, token string, logger logger.Logger) (http.Response, error) {
 	if identity {
-		req.Header.Add("X-IDAP-NATIVE-CLIENT", "true")
+		req.Header.Set("X-IDAP-NATIVE-CLIENT", "true")
 	}
 	// if token is provided, add header Authorization
 	if token != "" {
 		if identity {
-			req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
 		} else {
-			req.Header.Add("Authorization", token)
+			req.Header.Set("Authorization", token)
 		}
 	}
 
 	logRequest(req, logger)
 	// send request
-	res, err = httpClient.Do(req)
+	res, err := t.Do(ctx, req, logger)
 	if err != nil {
-		return http.Response{}, fmt.Errorf("Failed to send request. %s", err)
+		return http.Response{}, fmt.Errorf("Failed to send request. %w", err)
 	}
 
 	if res.StatusCode >= 300 {
-		return *res, fmt.Errorf("Received non-200 status code '%d'", res.StatusCode)
+		return *res, newAPIError(res)
 	}
 
 	return *res, err
 }
 
 // SendRequest is an http request and get response as serialized json map[string]interface{}
-func SendRequest(identity bool, url string, method string, token string, body interface{}, insecureTLS bool, logger logger.Logger) (map[string]interface{}, error) {
-	res, err := getResponse(identity, url, method, token, body, insecureTLS, logger)
+func (t *Transport) SendRequest(ctx context.Context, identity bool, url string, method string, token string, body interface{}, logger logger.Logger) (map[string]interface{}, error) {
+	res, err := t.getResponse(ctx, identity, url, method, token, body, logger)
 
-	if err != nil && strings.Contains(err.Error(), "Failed to send request") {
+	// No response was received
+	if err != nil && res.Body == nil {
 		return nil, err
 	}
+	defer res.Body.Close()
 	if res.StatusCode == 204 {
 		return nil, nil
 	}
@@ -126,24 +139,28 @@ func SendRequest(identity bool, url string, method string, token string, body in
 	var data map[string]interface{}
 	decodeError := decoder.Decode(&data)
 
-	// No error and no body returned
-	if decodeError == io.EOF {
-		return nil, nil
+	// No body or no JSON body returned with an invalid status code
+	if decodeError == io.EOF || (decodeError != nil && err != nil) {
+		return nil, err
 	}
 
 	if decodeError != nil {
-		return nil, fmt.Errorf("Failed to decode response body. %s", err)
+		return nil, fmt.Errorf("Failed to decode response body. %s", decodeError)
 	}
 
 	return data, err
 }
 
 // SendRequestRaw is an http request and get response as byte[]
-func SendRequestRaw(identity bool, url string, method string, token string, body interface{}, insecureTLS bool, logger logger.Logger) ([]byte, error) {
-	res, err := getResponse(identity, url, method, token, body, insecureTLS, logger)
+func (t *Transport) SendRequestRaw(ctx context.Context, identity bool, url string, method string, token string, body interface{}, logger logger.Logger) ([]byte, error) {
+	res, err := t.getResponse(ctx, identity, url, method, token, body, logger)
 	if err != nil {
+		if res.Body != nil {
+			res.Body.Close()
+		}
 		return nil, err
 	}
+	defer res.Body.Close()
 
 	content, err := ioutil.ReadAll(res.Body)
 	if err != nil {
@@ -153,25 +170,14 @@ func SendRequestRaw(identity bool, url string, method string, token string, body
 }
 
 // SendRequestRawWithHeaders is an http request and get response as byte[]
-func SendRequestRawWithHeaders(url, method string, headers http.Header, body interface{}, insecureTLS bool, logger logger.Logger) ([]byte, error) {
-	if insecureTLS {
-		http.DefaultTransport.(*http.Transport).TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-	} else {
-		http.DefaultTransport.(*http.Transport).TLSClientConfig = &tls.Config{InsecureSkipVerify: false}
-	}
-	httpClient := http.Client{
-		Timeout: time.Second * 30, // Maximum of 30 secs
-	}
-
-	var res *http.Response
-
+func (t *Transport) SendRequestRawWithHeaders(ctx context.Context, url, method string, headers http.Header, body interface{}, logger logger.Logger) ([]byte, error) {
 	content, err := bodyToBytes(body)
 	if err != nil {
 		return []byte(""), err
 	}
 
 	// create the request
-	req, err := http.NewRequest(method, url, bytes.NewBuffer(content))
+	req, err := http.NewRequest(method, url, bytes.NewReader(content))
 	if err != nil {
 		return []byte(""), fmt.Errorf("Failed to create new request. %s", err)
 	}
@@ -182,13 +188,14 @@ func SendRequestRawWithHeaders(url, method string, headers http.Header, body int
 	logRequest(req, logger)
 
 	// send request
-	res, err = httpClient.Do(req)
+	res, err := t.Do(ctx, req, logger)
 	if err != nil {
-		return []byte(""), fmt.Errorf("Failed to send request. %s", err)
+		return []byte(""), fmt.Errorf("Failed to send request. %w", err)
 	}
+	defer res.Body.Close()
 
 	if res.StatusCode >= 300 {
-		return []byte(""), fmt.Errorf("Received non-200 status code '%d'", res.StatusCode)
+		return []byte(""), newAPIError(res)
 	}
 
 	content, err = ioutil.ReadAll(res.Body)
@@ -198,26 +205,89 @@ func SendRequestRawWithHeaders(url, method string, headers http.Header, body int
 	return content, err
 }
 
+// Download is an http request whose response body is written to w. The file name
+// of the Content-Disposition header is returned, or an empty string if there is none
+func (t *Transport) Download(ctx context.Context, identity bool, url string, method string, token string, body interface{}, w io.Writer, logger logger.Logger) (string, error) {
+	res, err := t.getResponse(ctx, identity, url, method, token, body, logger)
+	if err != nil {
+		if res.Body != nil {
+			res.Body.Close()
+		}
+		return "", err
+	}
+	defer res.Body.Close()
+
+	_, err = io.Copy(w, res.Body)
+	if err != nil {
+		return "", fmt.Errorf("Failed to read body. %s", err)
+	}
+
+	_, params, err := mime.ParseMediaType(res.Header.Get("Content-Disposition"))
+	if err != nil {
+		return "", nil
+	}
+	return params["filename"], nil
+}
+
+// Get a get request and get response as serialized json map[string]interface{}
+func (t *Transport) Get(ctx context.Context, identity bool, url string, token string, logger logger.Logger) (map[string]interface{}, error) {
+	return t.SendRequest(ctx, identity, url, http.MethodGet, token, "", logger)
+}
+
+// Post a post request and get response as serialized json map[string]interface{}
+func (t *Transport) Post(ctx context.Context, identity bool, url string, token string, body interface{}, logger logger.Logger) (map[string]interface{}, error) {
+	return t.SendRequest(ctx, identity, url, http.MethodPost, token, body, logger)
+}
+
+// Put a put request and get response as serialized json map[string]interface{}
+func (t *Transport) Put(ctx context.Context, identity bool, url string, token string, body interface{}, logger logger.Logger) (map[string]interface{}, error) {
+	return t.SendRequest(ctx, identity, url, http.MethodPut, token, body, logger)
+}
+
+// Patch a patch request and get response as serialized json map[string]interface{}
+func (t *Transport) Patch(ctx context.Context, identity bool, url string, token string, body interface{}, logger logger.Logger) (map[string]interface{}, error) {
+	return t.SendRequest(ctx, identity, url, http.MethodPatch, token, body, logger)
+}
+
+// Delete a delete request and get response as serialized json map[string]interface{}
+func (t *Transport) Delete(ctx context.Context, identity bool, url string, token string, logger logger.Logger) (map[string]interface{}, error) {
+	return t.SendRequest(ctx, identity, url, http.MethodDelete, token, "", logger)
+}
+
+// SendRequest is an http request and get response as serialized json map[string]interface{}.
+// The shared transport for the TLS verification mode is used
+func SendRequest(identity bool, url string, method string, token string, body interface{}, insecureTLS bool, logger logger.Logger) (map[string]interface{}, error) {
+	return SharedTransport(insecureTLS).SendRequest(context.Background(), identity, url, method, token, body, logger)
+}
+
+// SendRequestRaw is an http request and get response as byte[].
+// The shared transport for the TLS verification mode is used
+func SendRequestRaw(identity bool, url string, method string, token string, body interface{}, insecureTLS bool, logger logger.Logger) ([]byte, error) {
+	return SharedTransport(insecureTLS).SendRequestRaw(context.Background(), identity, url, method, token, body, logger)
+}
+
+// SendRequestRawWithHeaders is an http request and get response as byte[].
+// The shared transport for the TLS verification mode is used
+func SendRequestRawWithHeaders(url, method string, headers http.Header, body interface{}, insecureTLS bool, logger logger.Logger) ([]byte, error) {
+	return SharedTransport(insecureTLS).SendRequestRawWithHeaders(context.Background(), url, method, headers, body, logger)
+}
+
 // Get a get request and get response as serialized json map[string]interface{}
 func Get(identity bool, url string, token string, insecureTLS bool, logger logger.Logger) (map[string]interface{}, error) {
-	response, err := SendRequest(identity, url, http.MethodGet, token, "", insecureTLS, logger)
-	return response, err
+	return SharedTransport(insecureTLS).Get(context.Background(), identity, url, token, logger)
 }
 
 // Post a post request and get response as serialized json map[string]interface{}
 func Post(identity bool, url string, token string, body interface{}, insecureTLS bool, logger logger.Logger) (map[string]interface{}, error) {
-	response, err := SendRequest(identity, url, http.MethodPost, token, body, insecureTLS, logger)
-	return response, err
+	return SharedTransport(insecureTLS).Post(context.Background(), identity, url, token, body, logger)
 }
 
 // Put a put request and get response as serialized json map[string]interface{}
 func Put(identity bool, url string, token string, body interface{}, insecureTLS bool, logger logger.Logger) (map[string]interface{}, error) {
-	response, err := SendRequest(identity, url, http.MethodPut, token, body, insecureTLS, logger)
-	return response, err
+	return SharedTransport(insecureTLS).Put(context.Background(), identity, url, token, body, logger)
 }
 
 // Delete a delete request and get response as serialized json map[string]interface{}
 func Delete(identity bool, url string, token string, insecureTLS bool, logger logger.Logger) (map[string]interface{}, error) {
-	response, err := SendRequest(identity, url, http.MethodDelete, token, "", insecureTLS, logger)
-	return response, err
+	return SharedTransport(insecureTLS).Delete(context.Background(), identity, url, token, logger)
 }
@@ -0,0 +1,276 @@
+package httpjson
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/http/httptrace"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/infamousjoeg/cybr-cli/pkg/logger"
+)
+
+// Default settings of a Transport
+const (
+	DefaultTimeout       = 30 * time.Second
+	DefaultMaxRetries    = 3
+	DefaultMinBackoff    = 500 * time.Millisecond
+	DefaultMaxBackoff    = 10 * time.Second
+	DefaultMaxRetryAfter = 60 * time.Second
+)
+
+var (
+	sharedTransports     = map[bool]*Transport{}
+	sharedTransportsLock sync.Mutex
+)
+
+// Transport sends HTTP requests over a pool of reusable connections. Requests that
+// are throttled (429), hit an unavailable server (502, 503) or have their connection
+// reset are retried with exponential backoff and jitter. Requests that are not idempotent,
+// such as POST and PATCH, may already have been processed, so they are only retried when
+// throttled, asked to retry later with a 503 and Retry-After, or when their connection
+// failed before the request was written
+type Transport struct {
+	// Timeout of a single attempt, including reading the response body
+	Timeout time.Duration
+	// MaxRetries is the number of retries after the first attempt
+	MaxRetries int
+	// MinBackoff is the delay before the first retry, doubled on every following retry
+	MinBackoff time.Duration
+	// MaxBackoff caps the delay between two retries
+	MaxBackoff time.Duration
+	// MaxRetryAfter caps the delay requested by a Retry-After response header
+	MaxRetryAfter time.Duration
+	// Reauthenticate returns a new token when a request sent with a token is rejected with
+	// 401 Unauthorized. The request is sent once more with the new token, which also replaces
+	// the rejected token in the following requests
+	Reauthenticate func(ctx context.Context) (string, error)
+
+	transport     *http.Transport
+	renewedTokens map[string]string
+	tokensLock    sync.Mutex
+	renewLock     sync.Mutex
+}
+
+// NewTransport returns a Transport with the default settings
+func NewTransport(insecureTLS bool) *Transport {
+	return NewTransportWithTLSConfig(&tls.Config{InsecureSkipVerify: insecureTLS})
+}
+
+// NewTransportWithTLSConfig returns a Transport with the default settings using the provided TLS configuration
+func NewTransportWithTLSConfig(tlsConfig *tls.Config) *Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	return &Transport{
+		Timeout:       DefaultTimeout,
+		MaxRetries:    DefaultMaxRetries,
+		MinBackoff:    DefaultMinBackoff,
+		MaxBackoff:    DefaultMaxBackoff,
+		MaxRetryAfter: DefaultMaxRetryAfter,
+		transport:     transport,
+	}
+}
+
+// SharedTransport returns the process wide Transport for the TLS verification mode.
+// It is used when no Transport has been provided so connections are still reused
+func SharedTransport(insecureTLS bool) *Transport {
+	sharedTransportsLock.Lock()
+	defer sharedTransportsLock.Unlock()
+
+	transport, ok := sharedTransports[insecureTLS]
+	if !ok {
+		transport = NewTransport(insecureTLS)
+		sharedTransports[insecureTLS] = transport
+	}
+	return transport
+}
+
+// renewedToken returns the token replacing token after it was rejected, or token itself
+func (t *Transport) renewedToken(token string) string {
+	t.tokensLock.Lock()
+	defer t.tokensLock.Unlock()
+
+	if renewed, ok := t.renewedTokens[token]; ok {
+		return renewed
+	}
+	return token
+}
+
+// renewToken returns a new token replacing the rejected token. Requests rejected at the same
+// time wait for a single call to Reauthenticate, which may send requests of its own
+func (t *Transport) renewToken(ctx context.Context, token string) (string, error) {
+	t.renewLock.Lock()
+	defer t.renewLock.Unlock()
+
+	if renewed := t.renewedToken(token); renewed != token {
+		return renewed, nil
+	}
+
+	renewed, err := t.Reauthenticate(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	t.tokensLock.Lock()
+	defer t.tokensLock.Unlock()
+	if t.renewedTokens == nil {
+		t.renewedTokens = map[string]string{}
+	}
+	t.renewedTokens[token] = renewed
+	return renewed, nil
+}
+
+func isRetryableStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests ||
+		statusCode == http.StatusBadGateway ||
+		statusCode == http.StatusServiceUnavailable
+}
+
+// isIdempotent returns true if sending a request with method more than once has the same
+// effect as sending it once
+func isIdempotent(method string) bool {
+	switch method {
+	case "", http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// shouldRetry returns true if the attempt failed transiently and sending the request again
+// cannot duplicate its effect. wrote is true if the request was written to the connection
+func shouldRetry(method string, res *http.Response, err error, wrote bool) bool {
+	if err != nil {
+		return isRetryableError(err) && (isIdempotent(method) || !wrote)
+	}
+	if isIdempotent(method) {
+		return isRetryableStatus(res.StatusCode)
+	}
+	return res.StatusCode == http.StatusTooManyRequests ||
+		(res.StatusCode == http.StatusServiceUnavailable && res.Header.Get("Retry-After") != "")
+}
+
+func isRetryableError(err error) bool {
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF) ||
+		strings.Contains(err.Error(), "connection reset by peer")
+}
+
+// parseRetryAfter returns the delay of a Retry-After header in either seconds or HTTP-date format
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		delay := date.Sub(now)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
+
+// backoff returns the delay before the given retry. Half of the delay is random
+// so concurrent clients do not retry at the same time
+func (t *Transport) backoff(retry int) time.Duration {
+	delay := t.MinBackoff
+	for i := 0; i < retry && delay < t.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > t.MaxBackoff {
+		delay = t.MaxBackoff
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+func (t *Transport) retryDelay(retry int, res *http.Response) time.Duration {
+	if res != nil {
+		if delay, ok := parseRetryAfter(res.Header.Get("Retry-After"), time.Now()); ok {
+			if delay > t.MaxRetryAfter {
+				delay = t.MaxRetryAfter
+			}
+			return delay
+		}
+	}
+	return t.backoff(retry)
+}
+
+// Do sends the request and retries it when the response or error is transient.
+// The request body must be replayable, which is the case for requests created
+// with http.NewRequest and a bytes.Buffer, bytes.Reader or strings.Reader body
+func (t *Transport) Do(ctx context.Context, req *http.Request, logger logger.Logger) (*http.Response, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	httpClient := &http.Client{
+		Transport: t.transport,
+		Timeout:   t.Timeout,
+	}
+
+	for retry := 0; ; retry++ {
+		var wrote int32
+		trace := &httptrace.ClientTrace{
+			WroteRequest: func(info httptrace.WroteRequestInfo) {
+				if info.Err == nil {
+					atomic.StoreInt32(&wrote, 1)
+				}
+			},
+		}
+		attempt := req.WithContext(httptrace.WithClientTrace(ctx, trace))
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("Failed to read request body. %s", err)
+			}
+			attempt.Body = body
+		}
+
+		res, err := httpClient.Do(attempt)
+		if retry >= t.MaxRetries || ctx.Err() != nil {
+			return res, err
+		}
+		if !shouldRetry(req.Method, res, err, atomic.LoadInt32(&wrote) == 1) {
+			return res, err
+		}
+
+		delay := t.retryDelay(retry, res)
+		if res != nil {
+			io.Copy(ioutil.Discard, res.Body)
+			res.Body.Close()
+		}
+		if logger != nil && logger.Enabled() {
+			if err != nil {
+				logger.Writef("Request failed. %s. Retrying in %s\n", err, delay)
+			} else {
+				logger.Writef("Received status code '%d'. Retrying in %s\n", res.StatusCode, delay)
+			}
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package httpjson_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/httpjson"
+)
+
+func testTransport() *httpjson.Transport {
+	transport := httpjson.NewTransport(false)
+	transport.MinBackoff = time.Millisecond
+	transport.MaxBackoff = 5 * time.Millisecond
+	return transport
+}
+
+func TestTransportRetriesTransientStatusCodes(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if string(body) != `{"name":"value"}` {
+			t.Errorf("Request body was not replayed on attempt %d. '%s'", attempts, body)
+		}
+		switch atomic.AddInt32(&attempts, 1) {
+		case 1:
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusBadGateway)
+		case 3:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.Write([]byte(`{"result":"ok"}`))
+		}
+	}))
+	defer server.Close()
+
+	response, err := testTransport().Put(context.Background(), false, server.URL, "", map[string]string{"name": "value"}, nil)
+	if err != nil {
+		t.Errorf("Failed to send request. %s", err)
+	}
+	if response["result"] != "ok" || attempts != 4 {
+		t.Errorf("Expected successful response after 4 attempts but got %v after %d attempts", response, attempts)
+	}
+}
+
+func TestTransportStopsAfterMaxRetries(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	transport := testTransport()
+	transport.MaxRetries = 2
+	_, err := transport.Get(context.Background(), false, server.URL, "", nil)
+	if err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("Expected status code 503 error but got '%v'", err)
+	}
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts but got %d", attempts)
+	}
+}
+
+func TestTransportDoesNotRetryOtherStatusCodes(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	_, err := testTransport().Get(context.Background(), false, server.URL, "", nil)
+	if err == nil || attempts != 1 {
+		t.Errorf("Expected a single failed attempt but got %d attempts. %v", attempts, err)
+	}
+}
+
+func TestTransportRespectsRetryAfter(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	start := time.Now()
+	_, err := testTransport().Get(context.Background(), false, server.URL, "", nil)
+	if err != nil {
+		t.Errorf("Failed to send request. %s", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("Retry-After of 1 second was not respected. Retried after %s", elapsed)
+	}
+}
+
+func TestTransportContextCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := testTransport().Get(ctx, false, server.URL, "", nil)
+	if err == nil {
+		t.Errorf("Expected an error when the context is cancelled")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Request was not cancelled with the context. Returned after %s", elapsed)
+	}
+}
+
+func TestTransportRetriesConnectionReset(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	_, err := testTransport().Get(context.Background(), false, server.URL, "", nil)
+	if err != nil || attempts != 2 {
+		t.Errorf("Expected a successful retry after the connection was closed but got %d attempts. %v", attempts, err)
+	}
+}
+
+func TestTransportDoesNotRetryProcessedPost(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&attempts, 1) {
+		case 1:
+			w.WriteHeader(http.StatusBadGateway)
+		case 2:
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+		case 3:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer server.Close()
+
+	transport := testTransport()
+	for i, expected := range []string{"502", "EOF", "503"} {
+		_, err := transport.Post(context.Background(), false, server.URL, "", map[string]string{"name": "value"}, nil)
+		if err == nil || !strings.Contains(err.Error(), expected) || atomic.LoadInt32(&attempts) != int32(i+1) {
+			t.Errorf("Expected POST not to be retried after %s but got %d attempts. %v", expected, atomic.LoadInt32(&attempts), err)
+		}
+	}
+
+	// Throttled requests were not processed, so they are retried
+	transport.MaxRetries = 1
+	transport.Patch(context.Background(), false, server.URL, "", map[string]string{"name": "value"}, nil)
+	if atomic.LoadInt32(&attempts) != 5 {
+		t.Errorf("Expected a throttled PATCH to be retried but got %d attempts", atomic.LoadInt32(&attempts))
+	}
+}
+
+func TestTransportReauthenticatesRejectedToken(t *testing.T) {
+	var renewals int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.Method == http.MethodPost && string(body) != `{"name":"value"}` {
+			t.Errorf("Request body was not replayed. '%s'", body)
+		}
+		if r.Header.Get("Authorization") != "new-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"ErrorCode":"PASWS006E","ErrorMessage":"Your session expired"}`))
+			return
+		}
+		w.Write([]byte(`{"result":"ok"}`))
+	}))
+	defer server.Close()
+
+	transport := testTransport()
+	transport.Reauthenticate = func(ctx context.Context) (string, error) {
+		atomic.AddInt32(&renewals, 1)
+		return "new-token", nil
+	}
+
+	for i := 0; i < 2; i++ {
+		response, err := transport.Post(context.Background(), false, server.URL, "expired-token", map[string]string{"name": "value"}, nil)
+		if err != nil || response["result"] != "ok" {
+			t.Errorf("Expected a successful response with the new token. %v %v", response, err)
+		}
+	}
+	if renewals != 1 {
+		t.Errorf("Expected the rejected token to be renewed once but got %d renewals", renewals)
+	}
+
+	transport.Reauthenticate = func(ctx context.Context) (string, error) {
+		return "", errors.New("invalid credentials")
+	}
+	_, err := transport.Get(context.Background(), false, server.URL, "other-token", nil)
+	var apiError *httpjson.APIError
+	if !errors.As(err, &apiError) || apiError.StatusCode != http.StatusUnauthorized || !strings.Contains(err.Error(), "invalid credentials") {
+		t.Errorf("Expected the rejected request and the reauthentication to fail. %v", err)
+	}
+}
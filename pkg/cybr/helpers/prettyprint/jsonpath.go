@@ -0,0 +1,646 @@
+package prettyprint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// field is a key and value of a JSON object
+type field struct {
+	Key   string
+	Value interface{}
+}
+
+// object is a JSON object that keeps the order of its keys
+type object []field
+
+// MarshalJSON writes the object with its keys in the original order
+func (o object) MarshalJSON() ([]byte, error) {
+	buffer := bytes.NewBufferString("{")
+	for i, f := range o {
+		if i > 0 {
+			buffer.WriteString(",")
+		}
+		key, err := json.Marshal(f.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(f.Value)
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(key)
+		buffer.WriteString(":")
+		buffer.Write(value)
+	}
+	buffer.WriteString("}")
+	return buffer.Bytes(), nil
+}
+
+// get returns the value of key. If the key does not exist a case-insensitive match is
+// returned since PAS does not always use the same casing as the response structs
+func (o object) get(key string) (interface{}, bool) {
+	for _, f := range o {
+		if f.Key == key {
+			return f.Value, true
+		}
+	}
+	for _, f := range o {
+		if strings.EqualFold(f.Key, key) {
+			return f.Value, true
+		}
+	}
+	return nil, false
+}
+
+// toValue converts obj to its JSON representation made of object, []interface{},
+// string, json.Number, bool and nil values
+func toValue(obj interface{}) (interface{}, error) {
+	content, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	return decodeValue(decoder)
+}
+
+func decodeValue(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return token, nil
+	}
+
+	switch delim {
+	case '{':
+		o := object{}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			o = append(o, field{Key: key.(string), Value: value})
+		}
+		_, err = decoder.Token()
+		return o, err
+	case '[':
+		a := []interface{}{}
+		for decoder.More() {
+			value, err := decodeValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			a = append(a, value)
+		}
+		_, err = decoder.Token()
+		return a, err
+	}
+	return nil, fmt.Errorf("Unexpected JSON delimiter '%s'", delim)
+}
+
+type stepKind int
+
+const (
+	stepField stepKind = iota
+	stepWildcard
+	stepRecursive
+	stepIndex
+	stepSlice
+	stepFilter
+)
+
+// step is a single element of a JSONPath expression, e.g. '.name', '[0]' or '[?(@.active==true)]'
+type step struct {
+	kind     stepKind
+	name     string
+	index    int
+	start    *int
+	end      *int
+	filter   *path
+	operator string
+	operand  interface{}
+}
+
+// path is a parsed JSONPath expression such as '.value[*].name'
+type path struct {
+	root  bool
+	steps []step
+}
+
+func parsePath(expression string) (*path, error) {
+	p := &path{}
+	expression = strings.TrimSpace(expression)
+	i := 0
+	if strings.HasPrefix(expression, "$") {
+		p.root = true
+		i++
+	} else if strings.HasPrefix(expression, "@") {
+		i++
+	}
+
+	for i < len(expression) {
+		switch {
+		case strings.HasPrefix(expression[i:], ".."):
+			i += 2
+			name, n := readName(expression[i:])
+			if n == 0 {
+				return nil, fmt.Errorf("Invalid JSONPath '%s'. A name is required after '..'", expression)
+			}
+			p.steps = append(p.steps, step{kind: stepRecursive, name: name})
+			i += n
+		case expression[i] == '.':
+			i++
+			if strings.HasPrefix(expression[i:], "*") {
+				p.steps = append(p.steps, step{kind: stepWildcard})
+				i++
+				continue
+			}
+			name, n := readName(expression[i:])
+			if n > 0 {
+				p.steps = append(p.steps, step{kind: stepField, name: name})
+			}
+			i += n
+		case expression[i] == '[':
+			end := findClosingBracket(expression, i)
+			if end < 0 {
+				return nil, fmt.Errorf("Invalid JSONPath '%s'. Missing ']'", expression)
+			}
+			s, err := parseBracket(expression[i+1 : end])
+			if err != nil {
+				return nil, fmt.Errorf("Invalid JSONPath '%s'. %s", expression, err)
+			}
+			p.steps = append(p.steps, s)
+			i = end + 1
+		default:
+			name, n := readName(expression[i:])
+			if n == 0 {
+				return nil, fmt.Errorf("Invalid JSONPath '%s'. Unexpected character '%c'", expression, expression[i])
+			}
+			p.steps = append(p.steps, step{kind: stepField, name: name})
+			i += n
+		}
+	}
+	return p, nil
+}
+
+// readName returns the field name at the start of s
+func readName(s string) (string, int) {
+	n := strings.IndexAny(s, ".[ ")
+	if n < 0 {
+		n = len(s)
+	}
+	return s[:n], n
+}
+
+// findClosingBracket returns the index of the ']' matching the '[' at start, ignoring quoted text
+func findClosingBracket(s string, start int) int {
+	depth := 0
+	var quote byte
+	for i := start; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func parseBracket(content string) (step, error) {
+	content = strings.TrimSpace(content)
+	switch {
+	case content == "*":
+		return step{kind: stepWildcard}, nil
+	case strings.HasPrefix(content, "?(") && strings.HasSuffix(content, ")"):
+		return parseFilter(content[2 : len(content)-1])
+	case isQuoted(content):
+		return step{kind: stepField, name: content[1 : len(content)-1]}, nil
+	case strings.Contains(content, ":"):
+		parts := strings.SplitN(content, ":", 2)
+		s := step{kind: stepSlice}
+		for i, part := range parts {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			value, err := strconv.Atoi(part)
+			if err != nil {
+				return step{}, fmt.Errorf("Invalid slice '[%s]'", content)
+			}
+			if i == 0 {
+				s.start = &value
+			} else {
+				s.end = &value
+			}
+		}
+		return s, nil
+	}
+
+	index, err := strconv.Atoi(content)
+	if err != nil {
+		return step{}, fmt.Errorf("Invalid index '[%s]'", content)
+	}
+	return step{kind: stepIndex, index: index}, nil
+}
+
+var filterOperators = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+// parseFilter parses a filter such as '@.memberType=="User"' or '@.active'
+func parseFilter(expression string) (step, error) {
+	left := expression
+	s := step{kind: stepFilter}
+	for _, operator := range filterOperators {
+		i := strings.Index(expression, operator)
+		if i < 0 {
+			continue
+		}
+		left = expression[:i]
+		s.operator = operator
+		right := strings.TrimSpace(expression[i+len(operator):])
+		switch {
+		case isQuoted(right):
+			s.operand = right[1 : len(right)-1]
+		case right == "true" || right == "false":
+			s.operand = right == "true"
+		case right == "null":
+			s.operand = nil
+		default:
+			_, err := strconv.ParseFloat(right, 64)
+			if err != nil {
+				return step{}, fmt.Errorf("Invalid filter value '%s'", right)
+			}
+			s.operand = json.Number(right)
+		}
+		break
+	}
+
+	filter, err := parsePath(left)
+	if err != nil {
+		return step{}, err
+	}
+	s.filter = filter
+	return s, nil
+}
+
+func isQuoted(s string) bool {
+	return len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0]
+}
+
+// evaluate returns all values matching the path. current is used for relative paths
+func (p *path) evaluate(root interface{}, current interface{}) []interface{} {
+	values, _ := p.find(root, current)
+	return values
+}
+
+// find returns all values matching the path like evaluate, and an error if a field of the
+// path is missing from all values, like kubectl does for a misspelled field
+func (p *path) find(root interface{}, current interface{}) ([]interface{}, error) {
+	values := []interface{}{current}
+	if p.root {
+		values = []interface{}{root}
+	}
+
+	var err error
+	for _, s := range p.steps {
+		next := []interface{}{}
+		for _, value := range values {
+			next = append(next, s.apply(root, value)...)
+		}
+		if err == nil && len(next) == 0 && len(values) > 0 && s.kind == stepField {
+			err = fmt.Errorf("'%s' is not found", s.name)
+		}
+		values = next
+	}
+	return values, err
+}
+
+func (s step) apply(root interface{}, value interface{}) []interface{} {
+	results := []interface{}{}
+	switch s.kind {
+	case stepField:
+		if o, ok := value.(object); ok {
+			if v, ok := o.get(s.name); ok {
+				results = append(results, v)
+			}
+		}
+	case stepWildcard:
+		results = append(results, children(value)...)
+	case stepRecursive:
+		for _, child := range children(value) {
+			if s.name == "*" {
+				results = append(results, child)
+			}
+			results = append(results, s.apply(root, child)...)
+		}
+		if o, ok := value.(object); ok && s.name != "*" {
+			if v, ok := o.get(s.name); ok {
+				results = append([]interface{}{v}, results...)
+			}
+		}
+	case stepIndex:
+		if a, ok := value.([]interface{}); ok {
+			index := s.index
+			if index < 0 {
+				index += len(a)
+			}
+			if index >= 0 && index < len(a) {
+				results = append(results, a[index])
+			}
+		}
+	case stepSlice:
+		if a, ok := value.([]interface{}); ok {
+			start, end := 0, len(a)
+			if s.start != nil {
+				start = normalizeIndex(*s.start, len(a))
+			}
+			if s.end != nil {
+				end = normalizeIndex(*s.end, len(a))
+			}
+			if start < end {
+				results = append(results, a[start:end]...)
+			}
+		}
+	case stepFilter:
+		for _, child := range children(value) {
+			if s.matches(root, child) {
+				results = append(results, child)
+			}
+		}
+	}
+	return results
+}
+
+func normalizeIndex(index int, length int) int {
+	if index < 0 {
+		index += length
+	}
+	if index < 0 {
+		return 0
+	}
+	if index > length {
+		return length
+	}
+	return index
+}
+
+func children(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case object:
+		values := []interface{}{}
+		for _, f := range v {
+			values = append(values, f.Value)
+		}
+		return values
+	case []interface{}:
+		return v
+	}
+	return nil
+}
+
+func (s step) matches(root interface{}, value interface{}) bool {
+	results := s.filter.evaluate(root, value)
+	if s.operator == "" {
+		return len(results) > 0 && results[0] != nil && results[0] != false
+	}
+	if len(results) == 0 {
+		return false
+	}
+
+	left := results[0]
+	leftNumber, leftIsNumber := left.(json.Number)
+	rightNumber, rightIsNumber := s.operand.(json.Number)
+	if leftIsNumber && rightIsNumber {
+		l, _ := leftNumber.Float64()
+		r, _ := rightNumber.Float64()
+		switch s.operator {
+		case "==":
+			return l == r
+		case "!=":
+			return l != r
+		case "<":
+			return l < r
+		case "<=":
+			return l <= r
+		case ">":
+			return l > r
+		case ">=":
+			return l >= r
+		}
+	}
+
+	switch s.operator {
+	case "==":
+		return equalValues(left, s.operand)
+	case "!=":
+		return !equalValues(left, s.operand)
+	}
+
+	l, r := formatValue(left), formatValue(s.operand)
+	switch s.operator {
+	case "<":
+		return l < r
+	case "<=":
+		return l <= r
+	case ">":
+		return l > r
+	case ">=":
+		return l >= r
+	}
+	return false
+}
+
+func equalValues(left interface{}, right interface{}) bool {
+	switch r := right.(type) {
+	case nil:
+		return left == nil
+	case bool:
+		l, ok := left.(bool)
+		return ok && l == r
+	case string:
+		l, ok := left.(string)
+		return ok && l == r
+	}
+	return formatValue(left) == formatValue(right)
+}
+
+// formatValue returns the text representation of a value. Strings are not quoted and
+// objects and arrays are written as compact JSON
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	content, _ := json.Marshal(value)
+	return string(content)
+}
+
+// templateNode is a literal text, a JSONPath expression or a range of a JSONPath template
+type templateNode struct {
+	text     string
+	path     *path
+	children []templateNode
+	isRange  bool
+}
+
+// jsonPathTemplate is a kubectl style JSONPath template, e.g. '{.value[*].id}' or
+// '{range .value[*]}{.id}{"\t"}{.name}{"\n"}{end}'
+type jsonPathTemplate struct {
+	nodes []templateNode
+}
+
+func parseJSONPathTemplate(template string) (*jsonPathTemplate, error) {
+	if !strings.Contains(template, "{") {
+		template = "{" + template + "}"
+	}
+
+	stack := [][]templateNode{{}}
+	ranges := []*path{}
+	for len(template) > 0 {
+		start := strings.Index(template, "{")
+		if start < 0 {
+			stack[len(stack)-1] = append(stack[len(stack)-1], templateNode{text: template})
+			break
+		}
+		if start > 0 {
+			stack[len(stack)-1] = append(stack[len(stack)-1], templateNode{text: template[:start]})
+		}
+
+		end := findClosingBrace(template, start)
+		if end < 0 {
+			return nil, fmt.Errorf("Invalid JSONPath template. Missing '}'")
+		}
+		action := strings.TrimSpace(template[start+1 : end])
+		template = template[end+1:]
+
+		switch {
+		case strings.HasPrefix(action, "range "):
+			p, err := parsePath(strings.TrimPrefix(action, "range "))
+			if err != nil {
+				return nil, err
+			}
+			stack = append(stack, []templateNode{})
+			ranges = append(ranges, p)
+		case action == "end":
+			if len(ranges) == 0 {
+				return nil, fmt.Errorf("Invalid JSONPath template. '{end}' without '{range}'")
+			}
+			node := templateNode{path: ranges[len(ranges)-1], children: stack[len(stack)-1], isRange: true}
+			stack = stack[:len(stack)-1]
+			ranges = ranges[:len(ranges)-1]
+			stack[len(stack)-1] = append(stack[len(stack)-1], node)
+		case isQuoted(action):
+			text := action[1 : len(action)-1]
+			if action[0] == '"' {
+				unquoted, err := strconv.Unquote(action)
+				if err != nil {
+					return nil, fmt.Errorf("Invalid JSONPath template string %s. %s", action, err)
+				}
+				text = unquoted
+			}
+			stack[len(stack)-1] = append(stack[len(stack)-1], templateNode{text: text})
+		default:
+			p, err := parsePath(action)
+			if err != nil {
+				return nil, err
+			}
+			stack[len(stack)-1] = append(stack[len(stack)-1], templateNode{path: p})
+		}
+	}
+
+	if len(ranges) > 0 {
+		return nil, fmt.Errorf("Invalid JSONPath template. '{range}' without '{end}'")
+	}
+	return &jsonPathTemplate{nodes: stack[0]}, nil
+}
+
+// findClosingBrace returns the index of the '}' matching the '{' at start, ignoring quoted text
+func findClosingBrace(s string, start int) int {
+	var quote byte
+	for i := start + 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '}':
+			return i
+		}
+	}
+	return -1
+}
+
+func (t *jsonPathTemplate) execute(buffer *bytes.Buffer, root interface{}) error {
+	return executeNodes(buffer, t.nodes, root, root)
+}
+
+func executeNodes(buffer *bytes.Buffer, nodes []templateNode, root interface{}, current interface{}) error {
+	for _, node := range nodes {
+		switch {
+		case node.isRange:
+			items, err := node.path.find(root, current)
+			if err != nil {
+				return err
+			}
+			if len(items) == 1 {
+				if a, ok := items[0].([]interface{}); ok {
+					items = a
+				}
+			}
+			for _, item := range items {
+				err = executeNodes(buffer, node.children, root, item)
+				if err != nil {
+					return err
+				}
+			}
+		case node.path != nil:
+			values, err := node.path.find(root, current)
+			if err != nil {
+				return err
+			}
+			for i, value := range values {
+				if i > 0 {
+					buffer.WriteString(" ")
+				}
+				buffer.WriteString(formatValue(value))
+			}
+		default:
+			buffer.WriteString(node.text)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package prettyprint
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// FormatJSON prints the indented JSON returned by PrintJSON
+	FormatJSON = "json"
+	// FormatYAML prints YAML
+	FormatYAML = "yaml"
+	// FormatTable prints the default columns of a resource
+	FormatTable = "table"
+	// FormatWide prints the default and additional columns of a resource
+	FormatWide = "wide"
+	// FormatCSV prints the default and additional columns of a resource as comma separated values
+	FormatCSV = "csv"
+	// FormatJSONPath prints the result of a JSONPath template, e.g. 'jsonpath={.value[*].id}'
+	FormatJSONPath = "jsonpath"
+	// FormatGoTemplate prints the result of a Go template, e.g. 'go-template={{range .value}}{{.id}}{{end}}'
+	FormatGoTemplate = "go-template"
+)
+
+// OutputFormats contains the supported output formats
+var OutputFormats = []string{FormatJSON, FormatYAML, FormatTable, FormatWide, FormatCSV, FormatJSONPath + "=<template>", FormatGoTemplate + "=<template>"}
+
+// Printer prints objects in one of the supported output formats
+type Printer struct {
+	Format   string
+	jsonPath *jsonPathTemplate
+	template *template.Template
+}
+
+// NewPrinter returns a printer for an output format. The jsonpath and go-template
+// formats are followed by '=' and the template
+func NewPrinter(output string) (*Printer, error) {
+	format, tmpl := output, ""
+	if i := strings.Index(output, "="); i >= 0 {
+		format, tmpl = output[:i], output[i+1:]
+	}
+	format = strings.ToLower(strings.TrimSpace(format))
+
+	printer := &Printer{Format: format}
+	switch format {
+	case "":
+		printer.Format = FormatJSON
+	case FormatJSON, FormatYAML, FormatTable, FormatWide, FormatCSV:
+	case "yml":
+		printer.Format = FormatYAML
+	case FormatJSONPath:
+		if tmpl == "" {
+			return nil, fmt.Errorf("A template is required, e.g. '%s={.id}'", FormatJSONPath)
+		}
+		jsonPath, err := parseJSONPathTemplate(tmpl)
+		if err != nil {
+			return nil, err
+		}
+		printer.jsonPath = jsonPath
+	case FormatGoTemplate:
+		if tmpl == "" {
+			return nil, fmt.Errorf("A template is required, e.g. '%s={{.id}}'", FormatGoTemplate)
+		}
+		t, err := template.New("output").Parse(tmpl)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid Go template. %s", err)
+		}
+		printer.template = t
+	default:
+		return nil, fmt.Errorf("Invalid output format '%s'. Valid formats are: %s", output, strings.Join(OutputFormats, ", "))
+	}
+	return printer, nil
+}
+
+// Print writes obj to w. The table describes the columns used by the table, wide and csv
+// formats. When table is nil, the columns are the fields of the first item
+func (p *Printer) Print(w io.Writer, obj interface{}, table *Table) error {
+	if p.Format == FormatJSON {
+		content, err := json.MarshalIndent(obj, "", "    ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(content))
+		return err
+	}
+
+	if p.Format == FormatGoTemplate {
+		return p.printGoTemplate(w, obj)
+	}
+
+	value, err := toValue(obj)
+	if err != nil {
+		return err
+	}
+
+	switch p.Format {
+	case FormatYAML:
+		content, err := yaml.Marshal(toYAML(value))
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(content)
+		return err
+	case FormatJSONPath:
+		buffer := &bytes.Buffer{}
+		err = p.jsonPath.execute(buffer, value)
+		if err != nil {
+			return fmt.Errorf("Failed to execute JSONPath template. %s", err)
+		}
+		if buffer.Len() > 0 && !bytes.HasSuffix(buffer.Bytes(), []byte("\n")) {
+			buffer.WriteString("\n")
+		}
+		_, err = w.Write(buffer.Bytes())
+		return err
+	}
+
+	headers, rows, err := table.rows(value, p.Format != FormatTable)
+	if err != nil {
+		return err
+	}
+
+	if p.Format == FormatCSV {
+		writer := csv.NewWriter(w)
+		writer.Write(headers)
+		writer.WriteAll(rows)
+		return writer.Error()
+	}
+
+	writer := tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(writer, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		for i := range row {
+			row[i] = strings.NewReplacer("\t", " ", "\n", " ").Replace(row[i])
+		}
+		fmt.Fprintln(writer, strings.Join(row, "\t"))
+	}
+	return writer.Flush()
+}
+
+func (p *Printer) printGoTemplate(w io.Writer, obj interface{}) error {
+	content, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	var data interface{}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	err = decoder.Decode(&data)
+	if err != nil {
+		return err
+	}
+
+	buffer := &bytes.Buffer{}
+	err = p.template.Execute(buffer, data)
+	if err != nil {
+		return fmt.Errorf("Failed to execute Go template. %s", err)
+	}
+	if buffer.Len() > 0 && !bytes.HasSuffix(buffer.Bytes(), []byte("\n")) {
+		buffer.WriteString("\n")
+	}
+	_, err = w.Write(buffer.Bytes())
+	return err
+}
+
+// toYAML converts a value returned by toValue to a value that keeps the order of
+// object keys and the type of numbers when marshalled to YAML
+func toYAML(value interface{}) interface{} {
+	switch v := value.(type) {
+	case object:
+		m := yaml.MapSlice{}
+		for _, f := range v {
+			m = append(m, yaml.MapItem{Key: f.Key, Value: toYAML(f.Value)})
+		}
+		return m
+	case []interface{}:
+		a := make([]interface{}, len(v))
+		for i, item := range v {
+			a[i] = toYAML(item)
+		}
+		return a
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	}
+	return value
+}
@@ -0,0 +1,145 @@
+package prettyprint_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/responses"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api/shared"
+	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/prettyprint"
+)
+
+var accounts = responses.ListAccount{
+	Value: []responses.GetAccount{
+		{
+			ID:               "12_3",
+			Name:             "Operating System-WinDomain-root",
+			UserName:         "root",
+			SafeName:         "Safe1",
+			PlatformID:       "WinDomain",
+			SecretManagement: shared.SecretManagement{Status: "success"},
+		},
+		{
+			ID:       "12_4",
+			Name:     "Database-MySQL-admin",
+			UserName: "admin",
+			SafeName: "Safe2",
+		},
+	},
+	Count: 2,
+}
+
+func print(t *testing.T, output string, obj interface{}, table *prettyprint.Table) string {
+	printer, err := prettyprint.NewPrinter(output)
+	if err != nil {
+		t.Fatalf("Failed to create printer for '%s'. %s", output, err)
+	}
+
+	buffer := &bytes.Buffer{}
+	err = printer.Print(buffer, obj, table)
+	if err != nil {
+		t.Fatalf("Failed to print '%s'. %s", output, err)
+	}
+	return buffer.String()
+}
+
+func TestPrintTable(t *testing.T) {
+	out := print(t, "table", accounts, prettyprint.AccountsTable)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected a header and 2 rows but got '%s'", out)
+	}
+	if !strings.HasPrefix(lines[0], "ID ") || strings.Contains(lines[0], "STATUS") {
+		t.Errorf("Invalid header '%s'", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "12_3 ") || !strings.Contains(lines[1], "   root   ") {
+		t.Errorf("Invalid row '%s'", lines[1])
+	}
+
+	out = print(t, "wide", accounts, prettyprint.AccountsTable)
+	if !strings.Contains(out, "STATUS") || !strings.Contains(out, "success") {
+		t.Errorf("Expected wide columns but got '%s'", out)
+	}
+}
+
+func TestPrintTableSingleItem(t *testing.T) {
+	out := print(t, "table", accounts.Value[1], prettyprint.AccountsTable)
+	if !strings.Contains(out, "12_4") || strings.Count(out, "\n") != 2 {
+		t.Errorf("Expected a single row but got '%s'", out)
+	}
+}
+
+func TestPrintCSV(t *testing.T) {
+	out := print(t, "csv", accounts, prettyprint.AccountsTable)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if lines[0] != "ID,NAME,USERNAME,ADDRESS,SAFE,PLATFORM,SECRET TYPE,AUTOMATIC,STATUS,LAST MODIFIED,CREATED" {
+		t.Errorf("Invalid CSV header '%s'", lines[0])
+	}
+	if lines[2] != "12_4,Database-MySQL-admin,admin,,Safe2,,,false,,,0" {
+		t.Errorf("Invalid CSV row '%s'", lines[2])
+	}
+}
+
+func TestPrintYAMLKeepsOrder(t *testing.T) {
+	out := print(t, "yaml", accounts.Value[0], nil)
+	if !strings.HasPrefix(out, "categoryModificationTime: 0\nid: \"12_3\"\nname: Operating System-WinDomain-root\n") {
+		t.Errorf("Invalid YAML '%s'", out)
+	}
+}
+
+func TestPrintJSONPath(t *testing.T) {
+	tests := map[string]string{
+		"jsonpath={.value[*].id}":                                           "12_3 12_4\n",
+		"jsonpath=.count":                                                   "2\n",
+		"jsonpath={.value[-1].userName}":                                    "admin\n",
+		"jsonpath={.value[0]..status}":                                      "success\n",
+		"jsonpath={.value[?(@.safeName==\"Safe2\")].name}":                  "Database-MySQL-admin\n",
+		"jsonpath={range .value[*]}{.id}{\"\\t\"}{.safeName}{\"\\n\"}{end}": "12_3\tSafe1\n12_4\tSafe2\n",
+	}
+
+	for output, expected := range tests {
+		out := print(t, output, accounts, nil)
+		if out != expected {
+			t.Errorf("Expected '%s' to print '%q' but got '%q'", output, expected, out)
+		}
+	}
+}
+
+func TestPrintJSONPathNotFound(t *testing.T) {
+	for _, output := range []string{"jsonpath={.value[*].usrName}", "jsonpath={range .values[*]}{.id}{end}", "jsonpath={.count.id}"} {
+		printer, err := prettyprint.NewPrinter(output)
+		if err != nil {
+			t.Fatalf("Failed to create printer for '%s'. %s", output, err)
+		}
+		buffer := &bytes.Buffer{}
+		err = printer.Print(buffer, accounts, nil)
+		if err == nil || !strings.Contains(err.Error(), "is not found") {
+			t.Errorf("Expected '%s' to fail with not found but got %v", output, err)
+		}
+		if buffer.Len() > 0 {
+			t.Errorf("Expected '%s' to print nothing but got '%q'", output, buffer.String())
+		}
+	}
+
+	out := print(t, "jsonpath={.value[?(@.safeName==\"Safe3\")].name}", accounts, nil)
+	if out != "" {
+		t.Errorf("Expected a filter without matches to print nothing but got '%q'", out)
+	}
+}
+
+func TestPrintGoTemplate(t *testing.T) {
+	out := print(t, "go-template={{range .value}}{{.id}} {{end}}", accounts, nil)
+	if out != "12_3 12_4 \n" {
+		t.Errorf("Invalid Go template output '%q'", out)
+	}
+}
+
+func TestNewPrinterInvalidFormat(t *testing.T) {
+	for _, output := range []string{"xml", "jsonpath=", "jsonpath={range .value}", "go-template={{.id"} {
+		_, err := prettyprint.NewPrinter(output)
+		if err == nil {
+			t.Errorf("Expected an error for output format '%s'", output)
+		}
+	}
+}
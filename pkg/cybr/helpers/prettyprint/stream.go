@@ -0,0 +1,62 @@
+package prettyprint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// JSONListWriter prints a JSON object containing a list while the items of the list
+// are being retrieved. The output is formatted the same way as PrintJSON
+type JSONListWriter struct {
+	w     io.Writer
+	key   string
+	count int
+}
+
+// NewJSONListWriter returns a JSONListWriter printing the items under key
+func NewJSONListWriter(w io.Writer, key string) *JSONListWriter {
+	return &JSONListWriter{
+		w:   w,
+		key: key,
+	}
+}
+
+// Write prints an item of the list
+func (j *JSONListWriter) Write(item interface{}) error {
+	content, err := json.MarshalIndent(item, "        ", "    ")
+	if err != nil {
+		return err
+	}
+
+	if j.count == 0 {
+		_, err = fmt.Fprintf(j.w, "{\n    %q: [\n        %s", j.key, content)
+	} else {
+		_, err = fmt.Fprintf(j.w, ",\n        %s", content)
+	}
+	j.count++
+	return err
+}
+
+// Close ends the list and the object. The number of items written is printed under countKey if not empty
+func (j *JSONListWriter) Close(countKey string) error {
+	var err error
+	if j.count == 0 {
+		_, err = fmt.Fprintf(j.w, "{\n    %q: []", j.key)
+	} else {
+		_, err = fmt.Fprint(j.w, "\n    ]")
+	}
+	if err != nil {
+		return err
+	}
+
+	if countKey != "" {
+		_, err = fmt.Fprintf(j.w, ",\n    %q: %d", countKey, j.count)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintln(j.w, "\n}")
+	return err
+}
@@ -0,0 +1,623 @@
+package prettyprint
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Column is a column of the table, wide and csv output formats
+type Column struct {
+	Header string
+	// Path is the JSONPath of the value relative to an item, e.g. '.secretManagement.status'.
+	// An empty path is the item itself
+	Path string
+}
+
+// Table describes how a resource is printed by the table, wide and csv output formats
+type Table struct {
+	// Items is the JSONPath of the list of items, e.g. '.value'. If it does not match,
+	// the object printed is a single item
+	Items string
+	// Columns are printed by the table, wide and csv output formats
+	Columns []Column
+	// Wide are the additional columns printed by the wide and csv output formats
+	Wide []Column
+}
+
+var (
+	// AccountsTable prints accounts
+	AccountsTable = &Table{
+		Items: ".value",
+		Columns: []Column{
+			{Header: "ID", Path: ".id"},
+			{Header: "NAME", Path: ".name"},
+			{Header: "USERNAME", Path: ".userName"},
+			{Header: "ADDRESS", Path: ".address"},
+			{Header: "SAFE", Path: ".safeName"},
+			{Header: "PLATFORM", Path: ".platformId"},
+		},
+		Wide: []Column{
+			{Header: "SECRET TYPE", Path: ".secretType"},
+			{Header: "AUTOMATIC", Path: ".secretManagement.automaticManagementEnabled"},
+			{Header: "STATUS", Path: ".secretManagement.status"},
+			{Header: "LAST MODIFIED", Path: ".secretManagement.lastModifiedTime"},
+			{Header: "CREATED", Path: ".createdTime"},
+		},
+	}
+
+	// AccountTable prints an account
+	AccountTable = &Table{
+		Columns: AccountsTable.Columns,
+		Wide:    AccountsTable.Wide,
+	}
+
+	// LinkedAccountsTable prints the accounts linked to an account
+	LinkedAccountsTable = &Table{
+		Items: ".LinkedAccounts",
+		Columns: []Column{
+			{Header: "INDEX", Path: ".ExtraPasswordIndex"},
+			{Header: "NAME", Path: ".Name"},
+			{Header: "SAFE", Path: ".SafeName"},
+			{Header: "FOLDER", Path: ".Folder"},
+		},
+	}
+
+	// AccountActivitiesTable prints the activities of an account
+	AccountActivitiesTable = &Table{
+		Items: ".Activities",
+		Columns: []Column{
+			{Header: "DATE", Path: ".Date"},
+			{Header: "USER", Path: ".User"},
+			{Header: "ACTION", Path: ".Action"},
+			{Header: "REASON", Path: ".Reason"},
+		},
+		Wide: []Column{
+			{Header: "ACTION ID", Path: ".ActionID"},
+			{Header: "CLIENT", Path: ".ClientID"},
+			{Header: "ALERT", Path: ".Alert"},
+		},
+	}
+
+	// SecretVersionsTable prints the versions of the secret of an account
+	SecretVersionsTable = &Table{
+		Items: ".Versions",
+		Columns: []Column{
+			{Header: "VERSION", Path: ".versionID"},
+			{Header: "MODIFIED BY", Path: ".modifiedBy"},
+			{Header: "MODIFIED", Path: ".modificationDate"},
+			{Header: "TEMPORARY", Path: ".isTemporary"},
+		},
+	}
+
+	// AccountImportTable prints the results of importing accounts
+	AccountImportTable = &Table{
+		Columns: []Column{
+			{Header: "ROW", Path: ".row"},
+			{Header: "STATUS", Path: ".status"},
+			{Header: "ID", Path: ".id"},
+			{Header: "SAFE", Path: ".safeName"},
+			{Header: "USERNAME", Path: ".userName"},
+			{Header: "ADDRESS", Path: ".address"},
+			{Header: "ERROR", Path: ".error"},
+		},
+		Wide: []Column{
+			{Header: "PLATFORM", Path: ".platformId"},
+			{Header: "NAME", Path: ".name"},
+		},
+	}
+
+	// MoveAccountsTable prints the results of moving accounts
+	MoveAccountsTable = &Table{
+		Columns: []Column{
+			{Header: "SOURCE ID", Path: ".sourceId"},
+			{Header: "STATUS", Path: ".status"},
+			{Header: "ID", Path: ".id"},
+			{Header: "NAME", Path: ".name"},
+			{Header: "SAFE", Path: ".safeName"},
+			{Header: "ERROR", Path: ".error"},
+		},
+	}
+
+	// DiscoveredAccountsTable prints discovered accounts
+	DiscoveredAccountsTable = &Table{
+		Items: ".value",
+		Columns: []Column{
+			{Header: "ID", Path: ".id"},
+			{Header: "USERNAME", Path: ".userName"},
+			{Header: "ADDRESS", Path: ".address"},
+			{Header: "PLATFORM TYPE", Path: ".platformType"},
+			{Header: "PRIVILEGED", Path: ".privileged"},
+			{Header: "DISCOVERED", Path: ".discoveryDateTime"},
+		},
+		Wide: []Column{
+			{Header: "DOMAIN", Path: ".domain"},
+			{Header: "ENABLED", Path: ".accountEnabled"},
+			{Header: "OS GROUPS", Path: ".osGroups"},
+			{Header: "LAST LOGON", Path: ".lastLogonDateTime"},
+			{Header: "DEPENDENCIES", Path: ".numberOfDependencies"},
+		},
+	}
+
+	// OnboardDiscoveredAccountsTable prints the results of onboarding discovered accounts
+	OnboardDiscoveredAccountsTable = &Table{
+		Columns: []Column{
+			{Header: "DISCOVERED ID", Path: ".discoveredId"},
+			{Header: "STATUS", Path: ".status"},
+			{Header: "ID", Path: ".id"},
+			{Header: "USERNAME", Path: ".userName"},
+			{Header: "ADDRESS", Path: ".address"},
+			{Header: "ERROR", Path: ".error"},
+		},
+		Wide: []Column{
+			{Header: "SAFE", Path: ".safeName"},
+		},
+	}
+
+	// SafesTable prints safes
+	SafesTable = &Table{
+		Items: ".value",
+		Columns: []Column{
+			{Header: "NAME", Path: ".SafeName"},
+			{Header: "LOCATION", Path: ".Location"},
+			{Header: "DESCRIPTION", Path: ".Description"},
+		},
+		Wide: []Column{
+			{Header: "URL ID", Path: ".SafeUrlId"},
+		},
+	}
+
+	// SafeMembersTable prints safe members
+	SafeMembersTable = &Table{
+		Items: ".value",
+		Columns: []Column{
+			{Header: "SAFE", Path: ".safeName"},
+			{Header: "MEMBER", Path: ".memberName"},
+			{Header: "TYPE", Path: ".memberType"},
+			{Header: "PREDEFINED", Path: ".isPredefinedUser"},
+		},
+		Wide: []Column{
+			{Header: "MEMBER ID", Path: ".memberId"},
+			{Header: "USE", Path: ".Permissions.UseAccounts"},
+			{Header: "RETRIEVE", Path: ".Permissions.RetrieveAccounts"},
+			{Header: "LIST", Path: ".Permissions.ListAccounts"},
+			{Header: "MANAGE SAFE", Path: ".Permissions.ManageSafe"},
+			{Header: "MANAGE MEMBERS", Path: ".Permissions.ManageSafeMembers"},
+		},
+	}
+
+	// EffectiveSafeMembersTable prints safe members with the users of group members
+	EffectiveSafeMembersTable = &Table{
+		Items: ".value",
+		Columns: []Column{
+			{Header: "SAFE", Path: ".safeName"},
+			{Header: "MEMBER", Path: ".memberName"},
+			{Header: "TYPE", Path: ".memberType"},
+			{Header: "VIA GROUP", Path: ".viaGroup"},
+		},
+		Wide: SafeMembersTable.Wide,
+	}
+
+	// GroupsTable prints groups. Directory groups show the directory they are mapped from
+	GroupsTable = &Table{
+		Items: ".value",
+		Columns: []Column{
+			{Header: "ID", Path: ".id"},
+			{Header: "NAME", Path: ".groupName"},
+			{Header: "TYPE", Path: ".groupType"},
+			{Header: "DIRECTORY", Path: ".directory"},
+			{Header: "LOCATION", Path: ".location"},
+		},
+		Wide: []Column{
+			{Header: "DN", Path: ".dn"},
+			{Header: "DESCRIPTION", Path: ".description"},
+			{Header: "MEMBERS", Path: ".members[*].username"},
+		},
+	}
+
+	// SafePlanTable prints the changes of a safe manifest plan
+	SafePlanTable = &Table{
+		Items: ".changes",
+		Columns: []Column{
+			{Header: "ACTION", Path: ".action"},
+			{Header: "KIND", Path: ".kind"},
+			{Header: "SAFE", Path: ".safeName"},
+			{Header: "MEMBER", Path: ".memberName"},
+		},
+		Wide: []Column{
+			{Header: "FIELDS", Path: ".fields[*].name"},
+		},
+	}
+
+	// AccessRequestTable prints an access request
+	AccessRequestTable = &Table{
+		Columns: accessRequestColumns,
+		Wide:    accessRequestWide,
+	}
+
+	// MyAccessRequestsTable prints the access requests of the logged on user
+	MyAccessRequestsTable = &Table{
+		Items:   ".MyRequests",
+		Columns: accessRequestColumns,
+		Wide:    accessRequestWide,
+	}
+
+	// IncomingAccessRequestsTable prints the access requests the logged on user can confirm
+	IncomingAccessRequestsTable = &Table{
+		Items:   ".IncomingRequests",
+		Columns: append([]Column{{Header: "REQUESTOR", Path: ".RequestorUserName"}}, accessRequestColumns...),
+		Wide:    accessRequestWide,
+	}
+
+	// LiveSessionsTable prints live PSM sessions
+	LiveSessionsTable = &Table{
+		Items: ".LiveSessions",
+		Columns: []Column{
+			{Header: "SESSION ID", Path: ".SessionID"},
+			{Header: "USER", Path: ".User"},
+			{Header: "ACCOUNT", Path: ".AccountUsername"},
+			{Header: "ADDRESS", Path: ".AccountAddress"},
+			{Header: "PROTOCOL", Path: ".ProtocolName"},
+			{Header: "START", Path: ".Start"},
+		},
+		Wide: []Column{
+			{Header: "SAFE", Path: ".SafeName"},
+			{Header: "FROM IP", Path: ".FromIP"},
+			{Header: "REMOTE MACHINE", Path: ".RemoteMachine"},
+			{Header: "CONNECTION COMPONENT", Path: ".ConnectionComponentID"},
+			{Header: "DURATION", Path: ".Duration"},
+		},
+	}
+
+	// RecordingsTable prints PSM recordings
+	RecordingsTable = &Table{
+		Items: ".Recordings",
+		Columns: []Column{
+			{Header: "SESSION ID", Path: ".SessionID"},
+			{Header: "USER", Path: ".User"},
+			{Header: "ACCOUNT", Path: ".AccountUsername"},
+			{Header: "ADDRESS", Path: ".AccountAddress"},
+			{Header: "START", Path: ".Start"},
+			{Header: "DURATION", Path: ".Duration"},
+		},
+		Wide: []Column{
+			{Header: "SAFE", Path: ".SafeName"},
+			{Header: "PROTOCOL", Path: ".ProtocolName"},
+			{Header: "FROM IP", Path: ".FromIP"},
+			{Header: "REMOTE MACHINE", Path: ".RemoteMachine"},
+			{Header: "CONNECTION COMPONENT", Path: ".ConnectionComponentID"},
+		},
+	}
+
+	// RecordingActivitiesTable prints the activities of a PSM recording
+	RecordingActivitiesTable = &Table{
+		Items: ".Activities",
+		Columns: []Column{
+			{Header: "TIME", Path: ".Time"},
+			{Header: "TYPE", Path: ".ActivityType"},
+			{Header: "ACTIVITY", Path: ".Activity"},
+		},
+		Wide: []Column{
+			{Header: "USER", Path: ".User"},
+			{Header: "RISK SCORE", Path: ".RiskScore"},
+		},
+	}
+
+	// UsersTable prints users
+	UsersTable = &Table{
+		Items: ".Users",
+		Columns: []Column{
+			{Header: "ID", Path: ".id"},
+			{Header: "USERNAME", Path: ".username"},
+			{Header: "TYPE", Path: ".userType"},
+			{Header: "SOURCE", Path: ".source"},
+			{Header: "LOCATION", Path: ".location"},
+		},
+		Wide: []Column{
+			{Header: "FIRST NAME", Path: ".personalDetails.firstName"},
+			{Header: "LAST NAME", Path: ".personalDetails.lastName"},
+			{Header: "COMPONENT", Path: ".componentUser"},
+			{Header: "AUTHORIZATIONS", Path: ".vaultAuthorization"},
+		},
+	}
+
+	// ApplicationsTable prints applications
+	ApplicationsTable = &Table{
+		Items: ".application",
+		Columns: []Column{
+			{Header: "APP ID", Path: ".AppID"},
+			{Header: "DESCRIPTION", Path: ".Description"},
+			{Header: "LOCATION", Path: ".Location"},
+			{Header: "DISABLED", Path: ".Disabled"},
+		},
+		Wide: []Column{
+			{Header: "OWNER EMAIL", Path: ".BusinessOwnerEmail"},
+			{Header: "ACCESS FROM", Path: ".AccessPermittedFrom"},
+			{Header: "ACCESS TO", Path: ".AccessPermittedTo"},
+			{Header: "EXPIRATION", Path: ".ExpirationDate"},
+		},
+	}
+
+	// ApplicationAuthenticationMethodsTable prints the authentication methods of an application
+	ApplicationAuthenticationMethodsTable = &Table{
+		Items: ".authentication",
+		Columns: []Column{
+			{Header: "AUTH ID", Path: ".authID"},
+			{Header: "APP ID", Path: ".AppID"},
+			{Header: "TYPE", Path: ".AuthType"},
+			{Header: "VALUE", Path: ".AuthValue"},
+		},
+		Wide: []Column{
+			{Header: "COMMENT", Path: ".Comment"},
+			{Header: "IS FOLDER", Path: ".IsFolder"},
+			{Header: "INTERNAL SCRIPTS", Path: ".AllowInternalScripts"},
+		},
+	}
+
+	// PlatformsTable prints platforms
+	PlatformsTable = &Table{
+		Items: ".Platforms",
+		Columns: []Column{
+			{Header: "ID", Path: ".general.id"},
+			{Header: "NAME", Path: ".general.name"},
+			{Header: "SYSTEM TYPE", Path: ".general.systemType"},
+			{Header: "ACTIVE", Path: ".general.active"},
+		},
+		Wide: []Column{
+			{Header: "TYPE", Path: ".general.platformType"},
+			{Header: "BASE ID", Path: ".general.platformBaseId"},
+			{Header: "DESCRIPTION", Path: ".general.description"},
+		},
+	}
+
+	// PlatformTable prints the details of a single platform
+	PlatformTable = &Table{
+		Columns: []Column{
+			{Header: "ID", Path: ".PlatformID"},
+			{Header: "NAME", Path: ".Details.PolicyName"},
+			{Header: "TYPE", Path: ".Details.PolicyType"},
+			{Header: "ACTIVE", Path: ".Active"},
+		},
+		Wide: []Column{
+			{Header: "SEARCH FOR USAGES", Path: ".Details.SearchForUsages"},
+			{Header: "IMMEDIATE INTERVAL", Path: ".Details.ImmediateInterval"},
+		},
+	}
+
+	// PlatformSummaryTable prints target, dependent, group and rotational group platforms
+	PlatformSummaryTable = &Table{
+		Items: ".Platforms",
+		Columns: []Column{
+			{Header: "ID", Path: ".ID"},
+			{Header: "PLATFORM ID", Path: ".PlatformID"},
+			{Header: "NAME", Path: ".Name"},
+			{Header: "ACTIVE", Path: ".Active"},
+		},
+		Wide: []Column{
+			{Header: "SYSTEM TYPE", Path: ".SystemType"},
+			{Header: "DESCRIPTION", Path: ".Description"},
+		},
+	}
+
+	// ConjurResourcesTable prints Conjur resources
+	ConjurResourcesTable = &Table{
+		Columns: []Column{
+			{Header: "ID", Path: ".id"},
+			{Header: "OWNER", Path: ".owner"},
+			{Header: "POLICY", Path: ".policy"},
+		},
+		Wide: []Column{
+			{Header: "CREATED", Path: ".created_at"},
+			{Header: "ANNOTATIONS", Path: ".annotations[*].name"},
+			{Header: "PERMISSIONS", Path: ".permissions[*].privilege"},
+		},
+	}
+
+	// ConjurResourceIDsTable prints a list of Conjur resource IDs
+	ConjurResourceIDsTable = &Table{
+		Columns: []Column{
+			{Header: "ID"},
+		},
+	}
+
+	// ConjurPolicyPlanTable prints the roles and resources a Conjur policy creates or deletes
+	ConjurPolicyPlanTable = &Table{
+		Items: ".changes",
+		Columns: []Column{
+			{Header: "ACTION", Path: ".action"},
+			{Header: "KIND", Path: ".kind"},
+			{Header: "ID", Path: ".id"},
+		},
+		Wide: []Column{
+			{Header: "LINE", Path: ".line"},
+		},
+	}
+
+	// CEMAccountsTable prints the cloud accounts onboarded to CEM
+	CEMAccountsTable = &Table{
+		Items: ".data",
+		Columns: []Column{
+			{Header: "PLATFORM", Path: ".platform"},
+			{Header: "WORKSPACE IDS", Path: ".accounts[*].workspace_id"},
+		},
+		Wide: []Column{
+			{Header: "WORKSPACE NAMES", Path: ".accounts[*].workspace_name"},
+			{Header: "STATUSES", Path: ".accounts[*].workspace_status"},
+		},
+	}
+
+	// CEMEntitiesTable prints CEM entities
+	CEMEntitiesTable = &Table{
+		Items: ".hits",
+		Columns: []Column{
+			{Header: "ID", Path: ".entityId"},
+			{Header: "NAME", Path: ".entityName"},
+			{Header: "TYPE", Path: ".entityType"},
+			{Header: "PLATFORM", Path: ".platformName"},
+			{Header: "ACCOUNT", Path: ".accountName"},
+			{Header: "RISK", Path: ".riskTotalScore"},
+		},
+		Wide: []Column{
+			{Header: "SHADOW ADMIN", Path: ".isShadowAdmin"},
+			{Header: "FULL ADMIN", Path: ".isFullAdmin"},
+			{Header: "STATUS", Path: ".status"},
+			{Header: "RECOMMENDATIONS", Path: ".recommendations"},
+		},
+	}
+
+	// CEMEntityTable prints the details of a single CEM entity
+	CEMEntityTable = &Table{
+		Columns: []Column{
+			{Header: "ID", Path: ".entity_id"},
+			{Header: "NAME", Path: ".entity_name"},
+			{Header: "TYPE", Path: ".entity_type"},
+			{Header: "PLATFORM", Path: ".platform"},
+			{Header: "ACCOUNT", Path: ".account_name"},
+			{Header: "EXPOSURE", Path: ".exposure_level"},
+		},
+		Wide: []Column{
+			{Header: "ACCOUNT ID", Path: ".account_id"},
+			{Header: "SHADOW ADMIN", Path: ".shadow_admin"},
+			{Header: "ADMIN", Path: ".admin"},
+		},
+	}
+
+	// CEMRecommendationsTable prints the recommendations of a CEM entity
+	CEMRecommendationsTable = &Table{
+		Items: ".recommendations",
+		Columns: []Column{
+			{Header: "CREATED", Path: ".created_date"},
+			{Header: "STATUS", Path: ".status"},
+			{Header: "RECOMMENDATIONS", Path: ".active_recommendations"},
+		},
+		Wide: []Column{
+			{Header: "EXECUTION TIME", Path: ".exec_time"},
+		},
+	}
+
+	// ProfilesTable prints connection profiles
+	ProfilesTable = &Table{
+		Columns: []Column{
+			{Header: "NAME", Path: ".name"},
+			{Header: "ACTIVE", Path: ".active"},
+			{Header: "BASE URL", Path: ".baseURL"},
+			{Header: "AUTH TYPE", Path: ".authType"},
+			{Header: "LOGGED ON", Path: ".loggedOn"},
+		},
+		Wide: []Column{
+			{Header: "TENANT ID", Path: ".tenantID"},
+			{Header: "INSECURE TLS", Path: ".insecureTLS"},
+		},
+	}
+)
+
+var (
+	accessRequestColumns = []Column{
+		{Header: "REQUEST ID", Path: ".RequestID"},
+		{Header: "ACCOUNT ID", Path: ".AccountDetails.AccountID"},
+		{Header: "SAFE", Path: ".SafeName"},
+		{Header: "STATUS", Path: ".StatusTitle"},
+		{Header: "CONFIRMATIONS LEFT", Path: ".ConfirmationsLeft"},
+		{Header: "REASON", Path: ".RequestorReason"},
+	}
+	accessRequestWide = []Column{
+		{Header: "USERNAME", Path: ".AccountDetails.Properties.UserName"},
+		{Header: "ADDRESS", Path: ".AccountDetails.Properties.Address"},
+		{Header: "ACCESS TYPE", Path: ".AccessType"},
+		{Header: "CREATED", Path: ".CreationDate"},
+		{Header: "EXPIRES", Path: ".ExpirationDate"},
+	}
+)
+
+// items returns the items of value matched by the Items path of the table. A list is
+// returned as its items and any other value as a single item
+func (t *Table) items(value interface{}) ([]interface{}, error) {
+	if t != nil && t.Items != "" {
+		p, err := parsePath(t.Items)
+		if err != nil {
+			return nil, err
+		}
+		results := p.evaluate(value, value)
+		if len(results) == 1 {
+			if a, ok := results[0].([]interface{}); ok {
+				return a, nil
+			}
+		}
+		if len(results) > 0 {
+			return results, nil
+		}
+	}
+
+	if a, ok := value.([]interface{}); ok {
+		return a, nil
+	}
+	return []interface{}{value}, nil
+}
+
+// columns returns the columns of the table. Without a table, a column is returned for each
+// field of the first item
+func (t *Table) columns(items []interface{}, wide bool) []Column {
+	if t != nil {
+		columns := append([]Column{}, t.Columns...)
+		if wide {
+			columns = append(columns, t.Wide...)
+		}
+		return columns
+	}
+
+	if len(items) > 0 {
+		if o, ok := items[0].(object); ok {
+			columns := []Column{}
+			for _, f := range o {
+				columns = append(columns, Column{Header: strings.ToUpper(f.Key), Path: "['" + f.Key + "']"})
+			}
+			return columns
+		}
+	}
+	return []Column{{Header: "VALUE"}}
+}
+
+// rows returns the headers and the values of each column for every item
+func (t *Table) rows(value interface{}, wide bool) ([]string, [][]string, error) {
+	items, err := t.items(value)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	columns := t.columns(items, wide)
+	headers := []string{}
+	paths := []*path{}
+	for _, column := range columns {
+		p, err := parsePath(column.Path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Invalid column '%s'. %s", column.Header, err)
+		}
+		headers = append(headers, column.Header)
+		paths = append(paths, p)
+	}
+
+	rows := [][]string{}
+	for _, item := range items {
+		row := []string{}
+		for _, p := range paths {
+			values := []string{}
+			for _, v := range p.evaluate(value, item) {
+				values = append(values, formatCell(v))
+			}
+			row = append(row, strings.Join(values, ","))
+		}
+		rows = append(rows, row)
+	}
+	return headers, rows, nil
+}
+
+// formatCell returns the text of a cell. Lists of values are separated with commas
+func formatCell(value interface{}) string {
+	if a, ok := value.([]interface{}); ok {
+		values := []string{}
+		for _, item := range a {
+			values = append(values, formatValue(item))
+		}
+		return strings.Join(values, ",")
+	}
+	return formatValue(value)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"runtime"
 	"strings"
 	"syscall"
 
@@ -70,3 +71,27 @@ func GetSubDomain(platformURL string) (string, error) {
 
 	return "", fmt.Errorf("Failed to get subdomain from URL. %s", err)
 }
+
+// CreatePrivateDir creates a directory only accessible by the current user. Permissions
+// of an existing directory that is accessible by other users are restricted
+func CreatePrivateDir(dir string) error {
+	info, err := os.Stat(dir)
+	if os.IsNotExist(err) {
+		err = os.MkdirAll(dir, 0700)
+		if err != nil {
+			return fmt.Errorf("Could not create folder %s on local file system. %s", dir, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Could not read folder %s on local file system. %s", dir, err)
+	}
+
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		err = os.Chmod(dir, 0700)
+		if err != nil {
+			return fmt.Errorf("Could not restrict permissions of folder %s. %s", dir, err)
+		}
+	}
+	return nil
+}
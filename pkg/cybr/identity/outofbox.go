@@ -10,7 +10,6 @@ import (
 	"time"
 
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api"
-	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/httpjson"
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/identity/requests"
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/identity/responses"
 )
@@ -24,7 +23,7 @@ func checkURL(client api.Client, req requests.AdvanceAuthentication, c chan stri
 		headers.Add("X-IDAP-NATIVE-CLIENT", "true")
 		headers.Add("Content-Type", "application/json")
 
-		res, err := httpjson.SendRequestRawWithHeaders(url, "POST", headers, req, client.InsecureTLS, client.Logger)
+		res, err := client.GetTransport().SendRequestRawWithHeaders(client.GetContext(), url, "POST", headers, req, client.Logger)
 		if err != nil {
 			log.Fatalf("Failed to reach Identity to check OOB status. %s", err)
 		}
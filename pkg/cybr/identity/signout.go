@@ -5,7 +5,6 @@ import (
 	"net/http"
 
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api"
-	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/httpjson"
 )
 
 // SignOutSession signs out of the current Identity session
@@ -17,9 +16,9 @@ func SignOutSession(c api.Client) error {
 	headers.Add("X-IDAP-NATIVE-CLIENT", "true")
 	headers.Add("Content-Type", "application/json")
 
-	_, err := httpjson.SendRequestRawWithHeaders(url, "POST", headers, nil, c.InsecureTLS, c.Logger)
+	_, err := c.GetTransport().SendRequestRawWithHeaders(c.GetContext(), url, "POST", headers, nil, c.Logger)
 	if err != nil {
-		return fmt.Errorf("Failed to sign out of current session. %s", err)
+		return fmt.Errorf("Failed to sign out of current session. %w", err)
 	}
 
 	return nil
@@ -6,7 +6,6 @@ import (
 	"net/http"
 
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/api"
-	"github.com/infamousjoeg/cybr-cli/pkg/cybr/helpers/httpjson"
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/identity/requests"
 	"github.com/infamousjoeg/cybr-cli/pkg/cybr/identity/responses"
 )
@@ -26,9 +25,9 @@ func StartAuthentication(c api.Client, req requests.StartAuthentication, podFqdn
 	headers.Add("X-IDAP-NATIVE-CLIENT", "true")
 	headers.Add("Content-Type", "application/json")
 
-	res, err := httpjson.SendRequestRawWithHeaders(url, "POST", headers, req, c.InsecureTLS, c.Logger)
+	res, err := c.GetTransport().SendRequestRawWithHeaders(c.GetContext(), url, "POST", headers, req, c.Logger)
 	if err != nil {
-		return &responses.Authentication{}, fmt.Errorf("Failed to start authentication. %s", err)
+		return &responses.Authentication{}, fmt.Errorf("Failed to start authentication. %w", err)
 	}
 
 	StartAuthResponse := &responses.Authentication{}
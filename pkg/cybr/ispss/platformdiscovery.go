@@ -13,13 +13,13 @@ import (
 func PlatformDiscovery(platformURL string) (*responses.PlatformDiscovery, error) {
 	subdomain, err := util.GetSubDomain(platformURL)
 	if err != nil {
-		return &responses.PlatformDiscovery{}, fmt.Errorf("Failed to get subdomain. %s", err)
+		return &responses.PlatformDiscovery{}, fmt.Errorf("Failed to get subdomain. %w", err)
 	}
 
 	url := fmt.Sprintf("https://platform-discovery.cyberark.cloud/api/v2/services/subdomain/%s", subdomain)
 	response, err := httpJson.SendRequestRaw(false, url, "GET", "", nil, false, nil)
 	if err != nil {
-		return &responses.PlatformDiscovery{}, fmt.Errorf("Failed to get platform discovery. %s", err)
+		return &responses.PlatformDiscovery{}, fmt.Errorf("Failed to get platform discovery. %w", err)
 	}
 
 	PlatformDiscoveryResponse := &responses.PlatformDiscovery{}